@@ -0,0 +1,124 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/actions"
+	"github.com/arush-sal/repo-protection-sync/pkg/audit"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+var auditFormat string
+var auditFailOnDrift bool
+var auditCreateIssues bool
+
+// auditCmd reports how every repository complies with the protection of the source
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Reports the compliance of every repository with the protection of the source repository",
+	Long: `Compares the branch protection of every repository of the owner against the
+source repository and writes a compliance matrix of repositories by attribute:
+required reviews, enforce admins, signed commits, force pushes, linear history
+and required status checks.
+
+A repository passes an attribute when it is at least as strict as the source.
+When the forbid section of the configuration file forbids deploy keys with
+write access or self-hosted runners, repositories using them are reported as
+additional attributes. They are only reported, never removed.
+The csv format lists the expected and actual value of every attribute and is
+suitable as compliance evidence. The sarif format reports every non-compliant
+attribute with remediation text, for upload to GitHub code scanning. The json
+format can be passed to apply --from-report to sync only the non-compliant
+repositories.
+
+With --create-issues, every non-compliant repository gets a tracking issue
+describing the differences, so its owners are notified in their usual workflow.
+
+When run in GitHub Actions, drift is also reported as workflow annotations and
+the matrix is added to the step summary.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if owner == "" || repo == "" || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		write, err := auditWriter(auditFormat)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		report, err := executor.Audit(opts)
+		if err != nil {
+			log.Fatalf("Audit failed: %v\n", err)
+		}
+		var written bytes.Buffer
+		if err := write(io.MultiWriter(os.Stdout, &written), report); err != nil {
+			log.Fatalf("Writing audit report: %v\n", err)
+		}
+		if reportUpload != "" {
+			uploadReport("audit."+auditExtensions[auditFormat], written.Bytes())
+		}
+		if actions.Enabled() {
+			if err := actions.AuditSummary(os.Stderr, report); err != nil {
+				log.Printf("Error writing the step summary: %v\n", err)
+			}
+		}
+		if auditCreateIssues {
+			failures, err := executor.FileIssues(opts, report)
+			if err != nil {
+				log.Fatalf("Filing tracking issues failed: %v\n", err)
+			}
+			if len(failures) > 0 {
+				log.Printf("Failed to file tracking issues in %d repositories\n", len(failures))
+			}
+		}
+		if auditFailOnDrift && !report.Compliant() {
+			os.Exit(2)
+		}
+	},
+}
+
+// auditExtensions are the file extensions of the report formats.
+var auditExtensions = map[string]string{"table": "txt", "csv": "csv", "sarif": "sarif", "json": "json"}
+
+// auditWriter returns the writer of the requested report format.
+func auditWriter(format string) (func(w io.Writer, r audit.Report) error, error) {
+	switch format {
+	case "table":
+		return audit.WriteTable, nil
+	case "csv":
+		return audit.WriteCSV, nil
+	case "sarif":
+		return audit.WriteSARIF, nil
+	case "json":
+		return audit.WriteJSON, nil
+	}
+	return nil, fmt.Errorf("unsupported audit format %q", format)
+}
+
+func init() {
+	auditCmd.Flags().StringVarP(&auditFormat, "format", "f", "table", "Report format (table, csv, sarif, json)")
+	auditCmd.Flags().BoolVar(&auditCreateIssues, "create-issues", false, "File or update a tracking issue in every non-compliant repository, and close it once the repository complies")
+	auditCmd.Flags().BoolVar(&auditFailOnDrift, "fail-on-drift", false, "Exit with status 2 when a repository is not compliant")
+	rootCmd.AddCommand(auditCmd)
+}
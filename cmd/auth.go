@@ -0,0 +1,124 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/keychain"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var loginDevice bool
+var loginClientID string
+
+// authCmd groups the commands managing the stored token
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Stores the GitHub token in the keychain of the operating system",
+}
+
+// authLoginCmd stores a token in the keychain
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Stores a token in the keychain, used when no token is given",
+	Long: `Reads a token from stdin, checks it with GitHub, and stores it in the login
+keychain on macOS or the Secret Service of the desktop (through secret-tool)
+on Linux, for the instance of --api-url. Commands run without --token,
+GITHUB_TOKEN or GitHub App credentials then use the stored token, so it
+doesn't sit in plaintext in shell profiles or configuration files.
+
+With --device, no token has to be created by hand: the GitHub device flow of
+the OAuth App given with --client-id prints a code to enter in the browser,
+and stores the token GitHub issues once the code is authorized.`,
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		creds := credentials()
+		if loginDevice {
+			if loginClientID == "" {
+				log.Fatalln("--device requires the --client-id of an OAuth App with the device flow enabled")
+			}
+			token, err := executor.DeviceLogin(context.Background(), creds, loginClientID, os.Stderr)
+			if err != nil {
+				log.Fatalf("Device login failed: %v\n", err)
+			}
+			creds.Token = token
+		} else if githubToken == "" {
+			fmt.Fprintf(os.Stderr, "Paste a token for %s: ", creds.Host())
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				log.Fatalf("Reading the token: %v\n", err)
+			}
+			creds.Token = strings.TrimSpace(line)
+		}
+		login(creds)
+	},
+}
+
+// login checks the token of creds and stores it in the keychain.
+func login(creds executor.Credentials) {
+	if creds.Token == "" {
+		log.Fatalln("No token to store")
+	}
+	user, err := executor.Login(creds, transportOptions)
+	if err != nil {
+		log.Fatalf("GitHub rejected the token: %v\n", err)
+	}
+	if err := keychain.Set(context.Background(), creds.Host(), creds.Token); err != nil {
+		log.Fatalf("Storing the token: %v\n", err)
+	}
+	log.Printf("Logged in to %s as %s\n", creds.Host(), user)
+}
+
+// authLogoutCmd removes the stored token
+var authLogoutCmd = &cobra.Command{
+	Use:    "logout",
+	Short:  "Removes the token stored by auth login",
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		host := credentials().Host()
+		if err := keychain.Delete(context.Background(), host); err != nil {
+			log.Fatalf("Removing the token: %v\n", err)
+		}
+		log.Printf("Logged out of %s\n", host)
+	},
+}
+
+// storedToken returns the token auth login stored for host, empty when
+// there is none.
+func storedToken(host string) string {
+	token, err := keychain.Get(context.Background(), host)
+	if err != nil {
+		logging.Debugf("No stored token for %s: %v\n", host, err)
+		return ""
+	}
+	return token
+}
+
+func init() {
+	authLoginCmd.Flags().BoolVar(&loginDevice, "device", false, "Authenticate in the browser with the OAuth device flow instead of pasting a token")
+	authLoginCmd.Flags().StringVar(&loginClientID, "client-id", "", "Client ID of the OAuth App the device flow authenticates with")
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	rootCmd.AddCommand(authCmd)
+}
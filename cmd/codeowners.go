@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+var codeownersTemplate string
+var codeownersFailOnFindings bool
+
+// codeownersCmd checks that code owner reviews can actually be enforced
+var codeownersCmd = &cobra.Command{
+	Use:   "codeowners",
+	Short: "Reports repositories whose required code owner reviews have no effect",
+	Long: `Checks that every repository whose policy requires reviews from code owners
+has a CODEOWNERS file, and that GitHub can parse it. Without a CODEOWNERS file
+the requirement is silently ignored.
+
+With --template, a pull request adding the given file as .github/CODEOWNERS is
+opened in every repository without one.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if owner == "" || (repo == "" && len(cfg.Policies) == 0) || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		var template []byte
+		if codeownersTemplate != "" {
+			var err error
+			if template, err = os.ReadFile(codeownersTemplate); err != nil {
+				log.Fatalf("Reading the CODEOWNERS template: %v\n", err)
+			}
+		}
+
+		result, err := executor.CheckCodeowners(opts, template)
+		if err != nil {
+			log.Fatalf("CODEOWNERS check failed: %v\n", err)
+		}
+		for _, name := range result.Missing {
+			fmt.Printf("%s\tmissing\n", name)
+		}
+		invalid := make([]string, 0, len(result.Invalid))
+		for name := range result.Invalid {
+			invalid = append(invalid, name)
+		}
+		sort.Strings(invalid)
+		for _, name := range invalid {
+			for _, e := range result.Invalid[name] {
+				fmt.Printf("%s\tinvalid\t%s\n", name, e)
+			}
+		}
+		for _, name := range result.Failed {
+			fmt.Printf("%s\tunreadable\n", name)
+		}
+		if codeownersFailOnFindings && !result.OK() {
+			os.Exit(2)
+		}
+	},
+}
+
+func init() {
+	codeownersCmd.Flags().StringVar(&codeownersTemplate, "template", "", "Open a pull request adding this file as CODEOWNERS to every repository without one")
+	codeownersCmd.Flags().BoolVar(&codeownersFailOnFindings, "fail-on-findings", false, "Exit with status 2 when a repository has no valid CODEOWNERS file")
+	rootCmd.AddCommand(codeownersCmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+// ownerOptional lets a command run without --owner, which every command
+// requires otherwise. Required flags are only checked after the PreRun hooks.
+func ownerOptional(cmd *cobra.Command, args []string) {
+	cmd.Flags().SetAnnotation("owner", cobra.BashCompOneRequiredFlag, []string{"false"})
+}
+
+// completeRepos completes --repo with the names of the repositories of the
+// owner. It completes nothing without an owner or credentials, as the
+// completion must not prompt or fail.
+func completeRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if githubToken == "" && appID == 0 {
+		githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+	opts := options()
+	if owner == "" || opts.Credentials.Validate() != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := executor.RepoNames(opts)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// initCompletion sets up the completion command, whose scripts are generated
+// without an owner, and the completion of --repo and --source.
+func initCompletion() {
+	rootCmd.RegisterFlagCompletionFunc("repo", completeRepos)
+	rootCmd.RegisterFlagCompletionFunc("source", completeRepos)
+	rootCmd.InitDefaultCompletionCmd()
+	for _, c := range rootCmd.Commands() {
+		if c.Name() != "completion" {
+			continue
+		}
+		c.Long += `
+When a token is given with --token or GITHUB_TOKEN, the --repo flag completes
+with the repositories of the owner given on the command line.`
+		for _, shell := range c.Commands() {
+			shell.PreRun = ownerOptional
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/daemon"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+var syncInterval time.Duration
+var listenAddress string
+
+// daemonCmd keeps syncing the organization on an interval
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keeps syncing the protection of the source repository on an interval, with health and status endpoints",
+	Long: `Syncs the protection of the source repository onto the repositories of the
+owner every --interval, until it is interrupted, and serves over HTTP on
+--listen:
+
+  /healthz  200 while the daemon works, 503 once a sync hangs for more than
+            three intervals
+  /readyz   200 once a sync succeeded, 503 before
+  /status   the outcome of the last sync as JSON, with the summary of every
+            policy applied
+
+A failing sync is logged and reported on /status, and the next one runs on
+schedule. Dry runs, estimates and interactive runs aren't available, and a
+canary needs --canary-wait since nobody is there to confirm it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case dryRun || estimate || interactive:
+			log.Fatalln("--dry-run, --estimate and --interactive are not available in daemon mode")
+		case (canary.Count > 0 || canary.Percent > 0) && canary.Wait == 0:
+			log.Fatalln("A canary in daemon mode requires --canary-wait")
+		case syncInterval <= 0:
+			log.Fatalln("--interval must be positive")
+		}
+		opts := runOptions(cmd)
+		applyOptions(&opts)
+		runDaemon(opts)
+	},
+}
+
+// runDaemon syncs on the interval and serves the endpoints until SIGINT or
+// SIGTERM, which also cancel the running sync.
+func runDaemon(opts executor.Options) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	d := daemon.New(syncInterval, func(ctx context.Context) ([]notify.Summary, error) {
+		return executor.Sync(ctx, opts)
+	})
+	server := &http.Server{Addr: listenAddress, Handler: d.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Serving on %s: %v\n", listenAddress, err)
+		}
+	}()
+	log.Printf("Syncing every %v, serving health and status on %s\n", syncInterval, listenAddress)
+
+	d.Run(ctx)
+	shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		log.Printf("Error stopping the server: %v\n", err)
+	}
+}
+
+func init() {
+	daemonCmd.Flags().DurationVar(&syncInterval, "interval", time.Hour, "Time between the start of two syncs")
+	daemonCmd.Flags().StringVar(&listenAddress, "listen", ":8080", "Address to serve /healthz, /readyz and /status on")
+	addSyncFlags(daemonCmd.Flags())
+	addProtectionFlags(daemonCmd.Flags())
+	daemonCmd.MarkFlagsMutuallyExclusive("canary", "canary-percent")
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+// describeCmd prints the effective protection of a repository
+var describeCmd = &cobra.Command{
+	Use:   "describe <owner/repo>",
+	Short: "Prints the effective branch protection and rulesets of a repository",
+	Long: `Reads the classic branch protection of the default branch of a repository, its
+rulesets and the rulesets it inherits from its organization, and prints the
+effective policy merged from them: every requirement a pull request or push to
+the default branch must meet, with the protection and rulesets imposing it.
+This tells why a pull request is blocked when several layers apply.
+
+A repository of the --owner can be named without its owner.`,
+	Args:   cobra.ExactArgs(1),
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		repoOwner, name := owner, args[0]
+		if i := strings.Index(name, "/"); i >= 0 {
+			repoOwner, name = name[:i], name[i+1:]
+		}
+		opts := options()
+		if repoOwner == "" || name == "" || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		if err := executor.Describe(opts, repoOwner, name, os.Stdout); err != nil {
+			log.Fatalf("Describe failed: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+var e2eTargets int
+var e2eKeep bool
+
+// e2eCmd runs a full sync against temporary repositories in a disposable test organization
+var e2eCmd = &cobra.Command{
+	Use:   "e2e",
+	Short: "Runs an end-to-end sync against temporary repositories in a disposable test organization",
+	Long: `Creates a source repository and a set of target repositories in the organization
+given by --owner, protects the source, syncs its protection onto the targets,
+verifies the result via the GitHub API and deletes the temporary repositories.
+
+Only point this at an organization that is dedicated to testing.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if err := opts.Credentials.Validate(); err != nil {
+			log.Fatalf("Invalid credentials: %v\n", err)
+		}
+		if err := executor.RunE2E(opts, e2eTargets, e2eKeep); err != nil {
+			log.Fatalf("e2e run failed: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	e2eCmd.Flags().IntVar(&e2eTargets, "targets", 2, "Number of temporary target repositories to create")
+	e2eCmd.Flags().BoolVar(&e2eKeep, "keep", false, "Keep the temporary repositories instead of deleting them")
+	rootCmd.AddCommand(e2eCmd)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+var exportAll bool
+
+// exportCmd writes the live protection of the source repository or the whole organization as code
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the branch protection and rulesets of the source repository or the whole organization",
+	Long: `Reads the live branch protection and rulesets and writes them to stdout in the
+requested format, so they can be adopted by infrastructure-as-code tooling.
+
+The terraform format emits github_branch_protection and github_repository_ruleset
+resources for the integrations/github provider, together with the terraform
+import commands needed to adopt the existing objects.
+
+The gitlab format emits the closest GitLab equivalent as JSON: protected
+branches, approval rules and settings, push rules and merge settings, for
+organizations migrating between the platforms. A project of the export can be
+used as the protection of a policy with the gitlab field of the configuration
+file. Rulesets, and the users and teams of push restrictions, aren't exported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if owner == "" || (repo == "" && !exportAll) || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		if err := executor.Export(opts, exportFormat, exportAll, os.Stdout); err != nil {
+			log.Fatalf("Export failed: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "terraform", "Export format (terraform, gitlab)")
+	exportCmd.Flags().BoolVar(&exportAll, "all", false, "Export every repository of the owner instead of only the source repository")
+	rootCmd.AddCommand(exportCmd)
+}
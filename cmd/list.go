@@ -0,0 +1,105 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+var listFormat string
+
+// listCmd prints the protection status of every repository of the owner
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the repositories of the owner with their protection status",
+	Long: `Prints every repository of the owner, or of the App installation, with its
+default branch, whether the branch is protected, the number of rulesets of the
+repository, when it was last pushed to and whether it is archived, to scope a
+sync before running it.
+
+Rulesets inherited from the organization aren't counted. Empty repositories
+have no branch to protect and are shown with a protection of -.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if owner == "" || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		var write func(io.Writer, []executor.RepoStatus) error
+		switch listFormat {
+		case "text":
+			write = writeStatuses
+		case "json":
+			write = func(w io.Writer, statuses []executor.RepoStatus) error {
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+		default:
+			log.Fatalf("Unsupported list format %q\n", listFormat)
+		}
+		statuses, err := executor.List(opts)
+		if err != nil {
+			log.Fatalf("Listing the repositories failed: %v\n", err)
+		}
+		if err := write(os.Stdout, statuses); err != nil {
+			log.Fatalf("Writing the list: %v\n", err)
+		}
+	},
+}
+
+// writeStatuses prints the statuses as a table.
+func writeStatuses(out io.Writer, statuses []executor.RepoStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tDEFAULT BRANCH\tPROTECTED\tRULESETS\tLAST PUSHED\tARCHIVED")
+	for _, s := range statuses {
+		if s.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\terror: %s\t\t\t\n", s.Repo, s.DefaultBranch, s.Error)
+			continue
+		}
+		protected := "-"
+		if s.Protected != nil {
+			protected = yesNo(*s.Protected)
+		}
+		pushed := "never"
+		if !s.Pushed.IsZero() {
+			pushed = s.Pushed.Local().Format(time.DateOnly)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", s.Repo, s.DefaultBranch, protected, s.Rulesets, pushed, yesNo(s.Archived))
+	}
+	return w.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "y"
+	}
+	return "n"
+}
+
+func init() {
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", "text", "Output format (text, json)")
+	rootCmd.AddCommand(listCmd)
+}
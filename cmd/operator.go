@@ -0,0 +1,117 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/arush-sal/repo-protection-sync/pkg/operator"
+	"github.com/spf13/cobra"
+)
+
+var kubectl operator.Kubectl
+var pollInterval, resyncInterval time.Duration
+var printCRD bool
+
+// operatorCmd reconciles RepoProtectionPolicy resources of a Kubernetes cluster
+var operatorCmd = &cobra.Command{
+	Use:   "operator",
+	Short: "Keeps the RepoProtectionPolicy resources of a Kubernetes cluster applied to GitHub",
+	Long: `Runs as a controller for RepoProtectionPolicy resources, so GitHub protection
+is managed from the same GitOps pipeline as the cluster. Every resource names
+an owner and a policy: a source repository or an inline protection, and a
+selector of the repositories it applies to.
+
+The controller reads the resources with kubectl every --poll, applies those
+whose spec changed, and re-applies every resource after --resync to revert
+changes made on GitHub. The outcome is written to the status of the resource:
+the number of targets, the repositories that failed and a Ready condition.
+
+Print the CustomResourceDefinition to install with --print-crd:
+
+  repo-protection-sync operator --print-crd | kubectl apply -f -
+
+The GitHub credentials and the sync flags apply to every resource.`,
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		if printCRD {
+			os.Stdout.Write(operator.CRD)
+			return
+		}
+		switch {
+		case dryRun || estimate || interactive:
+			log.Fatalln("--dry-run, --estimate and --interactive are not available in operator mode")
+		case (canary.Count > 0 || canary.Percent > 0) && canary.Wait == 0:
+			log.Fatalln("A canary in operator mode requires --canary-wait")
+		case pollInterval <= 0 || resyncInterval <= 0:
+			log.Fatalln("--poll and --resync must be positive")
+		}
+		if credentials().Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		opts := options()
+		protectionOptions(&opts)
+		applyOptions(&opts)
+		runOperator(opts)
+	},
+}
+
+// runOperator reconciles the resources until SIGINT or SIGTERM.
+func runOperator(opts executor.Options) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &operator.Controller{
+		Kube:   kubectl,
+		Poll:   pollInterval,
+		Resync: resyncInterval,
+		Reconcile: func(ctx context.Context, owner string, p config.Policy) (notify.Summary, error) {
+			run := opts
+			run.Owner, run.Source = owner, ""
+			runConfig := *opts.Config
+			runConfig.Policies = []config.Policy{p}
+			run.Config = &runConfig
+			summaries, err := executor.Sync(ctx, run)
+			if len(summaries) == 0 {
+				return notify.Summary{}, err
+			}
+			return summaries[0], err
+		},
+	}
+	log.Printf("Reconciling the %s every %v, re-applying them every %v\n", operator.Resource, pollInterval, resyncInterval)
+	c.Run(ctx)
+}
+
+func init() {
+	operatorCmd.Flags().StringVar(&kubectl.Namespace, "namespace", "", "Only reconcile the resources of this namespace (default all namespaces)")
+	operatorCmd.Flags().StringVar(&kubectl.Binary, "kubectl", "kubectl", "kubectl executable used to read the resources and write their status")
+	operatorCmd.Flags().DurationVar(&pollInterval, "poll", 30*time.Second, "Time between two reads of the resources")
+	operatorCmd.Flags().DurationVar(&resyncInterval, "resync", time.Hour, "Re-apply a resource whose spec didn't change after this long")
+	operatorCmd.Flags().BoolVar(&printCRD, "print-crd", false, "Print the CustomResourceDefinition of RepoProtectionPolicy")
+	addSyncFlags(operatorCmd.Flags())
+	addProtectionFlags(operatorCmd.Flags())
+	operatorCmd.MarkFlagsMutuallyExclusive("canary", "canary-percent")
+	rootCmd.AddCommand(operatorCmd)
+}
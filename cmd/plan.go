@@ -0,0 +1,198 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/audit"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/plan"
+	"github.com/arush-sal/repo-protection-sync/pkg/signature"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var planOut string
+var applyPlan string
+var applyReport string
+var verifyTool, verifyKey, verifySignature string
+
+// removeRegoCopy removes the verified copy of the Rego policy of the run.
+var removeRegoCopy func()
+
+// planCmd writes the API mutations a sync would make to a plan file
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Writes the changes a sync would make to a plan file without making them",
+	Long: `Reads the live protection of every target and writes exactly which API
+mutations a sync would make to a plan file, along with a fingerprint of the
+state each one was planned against. Review the plan, then execute it with
+apply --plan.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if owner == "" || (repo == "" && len(cfg.Policies) == 0) || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		protectionOptions(&opts)
+		p, err := executor.Plan(opts)
+		if err != nil {
+			log.Fatalf("Planning failed: %v\n", err)
+		}
+		plan.WriteText(os.Stdout, owner, p.Changes)
+		if err := p.Save(planOut); err != nil {
+			log.Fatalf("Writing the plan: %v\n", err)
+		}
+	},
+}
+
+// applyCmd executes a plan file written by plan, or syncs the repositories a report flagged
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Executes the changes of a plan file, or syncs the repositories a report flagged",
+	Long: `Executes exactly the API mutations of a plan file written by plan. Nothing is
+changed when the live state of any target changed since the plan was created.
+
+With --from-report, the repositories an earlier report flagged are synced like
+sync would: the non-compliant repositories of an audit written with --format
+json or sarif, or the repositories a dry run written with --output json would
+change. This remediates a handful of drifted repositories without a pass over
+the whole organization. The sync flags apply to that sync.
+
+With --verify-signature, the plan or report is only applied when its detached
+signature, as written by sign, verifies against --public-key. So are the
+configuration file and the GitLab files of its policies, and a report is only
+applied with policies defined in the configuration, as the protection of a
+source repository is read live.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file := applyPlan + applyReport
+		data, err := readVerified(file, verifySignature)
+		if err != nil {
+			log.Fatalf("Refusing to apply %s: %v\n", file, err)
+		}
+		if applyReport != "" {
+			runSync(cmd, reportedRepos(applyReport, data))
+			return
+		}
+		opts := options()
+		if owner == "" || applyPlan == "" || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		p, err := plan.Parse(applyPlan, data)
+		if err != nil {
+			log.Fatalf("Reading the plan: %v\n", err)
+		}
+		failures, err := executor.Apply(opts, p)
+		if err != nil {
+			log.Fatalf("Apply refused: %v\n", err)
+		}
+		if len(failures) > 0 {
+			log.Fatalf("Apply failed for %d repositories\n", len(failures))
+		}
+	},
+}
+
+// reportedRepos returns the repositories the report read from path flagged,
+// and exits when there are none.
+func reportedRepos(path string, data []byte) []string {
+	reportOwner, repos, err := audit.ReadDrifted(bytes.NewReader(data))
+	if err != nil {
+		log.Fatalf("Reading the report %s: %v\n", path, err)
+	}
+	if reportOwner != "" && !strings.EqualFold(reportOwner, owner) {
+		log.Fatalf("The report is about %s, not %s\n", reportOwner, owner)
+	}
+	if len(repos) == 0 {
+		log.Println("The report flags no repository, nothing to apply")
+		os.Exit(0)
+	}
+	log.Printf("Syncing the %d repositories the report flags: %s\n", len(repos), strings.Join(repos, ", "))
+	return repos
+}
+
+// readVerified reads a file the run applies. With --verify-signature, the
+// file is only read once its signature, at sig or next to it when sig is
+// empty, verifies, and the bytes verified are returned.
+func readVerified(file, sig string) ([]byte, error) {
+	if verifyTool == "" {
+		return os.ReadFile(file)
+	}
+	if sig == "" {
+		sig = signature.Path(file)
+	}
+	data, err := signature.ReadVerified(context.Background(), verifyTool, file, sig, verifyKey)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Verified the signature of %s\n", file)
+	return data, nil
+}
+
+// readConfig reads the configuration file, the files it refers to and the
+// actor mapping, verifying them with --verify-signature.
+func readConfig(file string) ([]byte, error) {
+	return readVerified(file, "")
+}
+
+// checkSigned exits when --verify-signature is given but a policy of the run
+// reads its protection from a live repository, which no signature covers.
+func checkSigned() {
+	if verifyTool == "" {
+		return
+	}
+	source := repo
+	if len(cfg.Policies) > 0 {
+		source = ""
+		for _, p := range cfg.Policies {
+			if p.Source != "" {
+				source = p.Source
+				break
+			}
+		}
+	}
+	if source != "" {
+		log.Fatalf("Refusing to apply the protection of %s: --verify-signature only applies policies defined inline or with gitlab in the signed configuration file\n", source)
+	}
+}
+
+// addVerifyFlags registers the flags verifying the signatures of the files
+// a run applies.
+func addVerifyFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&verifyTool, "verify-signature", "", "Only apply signed files, verifying their signatures with this tool ("+strings.Join(signature.Tools, ", ")+"): the configuration file, the GitLab files and Rego policy it refers to and the --actor-map file, whose signatures are expected next to them with a .sig suffix")
+	flags.StringVar(&verifyKey, "public-key", "", "Public key the signatures are verified against; for gpg, a keyring or exported key (defaults to the keyring of gpg)")
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planOut, "out", "plan.json", "Path of the plan file to write")
+	addProtectionFlags(planCmd.Flags())
+	applyCmd.Flags().StringVar(&applyPlan, "plan", "", "Path of the plan file to execute")
+	applyCmd.Flags().StringVar(&applyReport, "from-report", "", "Sync only the repositories flagged by this audit report (json, sarif) or dry run (json)")
+	applyCmd.MarkFlagsOneRequired("plan", "from-report")
+	applyCmd.MarkFlagsMutuallyExclusive("plan", "from-report")
+	addVerifyFlags(applyCmd.Flags())
+	applyCmd.Flags().StringVar(&verifySignature, "signature", "", "Path of the signature of the plan or report (defaults to the file with a .sig suffix)")
+	addSyncFlags(applyCmd.Flags())
+	addProtectionFlags(applyCmd.Flags())
+	applyCmd.MarkFlagsMutuallyExclusive("canary", "canary-percent")
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
+}
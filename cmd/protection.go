@@ -0,0 +1,114 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/spf13/pflag"
+)
+
+var onlyFields, skipFields []string
+var enforceAdmins string
+var minReviews int
+var actorMap string
+var dropUnknownActors bool
+var inheritedRulesets string
+
+// addProtectionFlags registers the flags adjusting the protection applied to
+// the targets on the commands that sync or plan it.
+func addProtectionFlags(flags *pflag.FlagSet) {
+	fields := strings.Join(setter.Fields(), ", ")
+	flags.StringSliceVar(&onlyFields, "only", nil, "Only sync these protection fields and keep the others of every target as they are (one of "+fields+", or the aliases reviews and status_checks)")
+	flags.StringSliceVar(&skipFields, "skip", nil, "Sync every protection field but these, keeping them as they are on every target")
+	flags.StringVar(&enforceAdmins, "enforce-admins", "", "Enforce the protection for admins on every target (true), lift it (false) or copy the source (source); unset keeps the setting of every target")
+	flags.IntVar(&minReviews, "min-reviews", 0, "Require at least this many approving reviews on every target, raising the count of the source when it is lower")
+	flags.StringVar(&actorMap, "actor-map", "", "YAML file mapping the users, teams and apps of the restrictions of a source in another organization to those of the targets, instead of the actors section of the config file")
+	flags.StringVar(&inheritedRulesets, "inherited-rulesets", "skip", "What to do with the rulesets the source inherits from its organization: skip them, or materialize them as rulesets of every target")
+	flags.BoolVar(&dropUnknownActors, "drop-unknown-actors", false, "Drop restriction users, teams and apps that don't exist in the target organization, and apps not installed on a target, instead of failing")
+}
+
+// protectionOptions sets the protection adjustments of the flags on opts.
+func protectionOptions(opts *executor.Options) {
+	var fields map[string]bool
+	var err error
+	if len(onlyFields) > 0 || len(skipFields) > 0 {
+		fields, err = setter.SelectFields(onlyFields, skipFields)
+		if err != nil {
+			log.Fatalf("%v\n", err)
+		}
+	}
+
+	// Toggling the admin enforcement of many repositories is disruptive, so
+	// it is only synced when asked for explicitly
+	switch enforceAdmins {
+	case "":
+		if fields == nil {
+			fields, _ = setter.SelectFields(nil, []string{"enforce_admins"})
+		}
+		delete(fields, "enforce_admins")
+	case "source":
+	case "true", "false":
+		enabled := enforceAdmins == "true"
+		opts.EnforceAdmins = &enabled
+		if fields != nil {
+			fields["enforce_admins"] = true
+		}
+	default:
+		log.Fatalf("--enforce-admins must be true, false or source, not %q\n", enforceAdmins)
+	}
+	if minReviews < 0 || minReviews > 6 {
+		log.Fatalf("--min-reviews must be between 0 and 6, not %d\n", minReviews)
+	}
+	if minReviews > 0 {
+		opts.MinReviews = minReviews
+		if fields != nil {
+			fields["required_pull_request_reviews"] = true
+		}
+	}
+	if fields != nil && len(fields) == 0 {
+		// Only enforce_admins can be dropped from the fields of --only
+		if len(onlyFields) > 0 {
+			log.Fatalf("--only enforce_admins needs --enforce-admins, the admin enforcement is only synced when asked for explicitly\n")
+		}
+		log.Fatalf("--only and --skip leave no protection field to sync\n")
+	}
+	opts.Fields = fields
+
+	if actorMap != "" {
+		mapping, err := config.LoadActorsWith(actorMap, readConfig)
+		if err != nil {
+			log.Fatalf("Error reading the actor mapping: %v\n", err)
+		}
+		mapping.DropUnknown = mapping.DropUnknown || opts.Config.Actors.DropUnknown
+		opts.Config.Actors = mapping
+	}
+	if dropUnknownActors {
+		opts.Config.Actors.DropUnknown = true
+	}
+
+	switch inheritedRulesets {
+	case "skip":
+	case "materialize":
+		opts.MaterializeInherited = true
+	default:
+		log.Fatalf("--inherited-rulesets must be skip or materialize, not %q\n", inheritedRulesets)
+	}
+}
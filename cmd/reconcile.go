@@ -0,0 +1,63 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+var reconcileOptions executor.ReconcileOptions
+
+// reconcileCmd detects changes made to the source outside its policy file
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Reports changes made to the source since its policy file was exported",
+	Long: `Compares a policy file exported from the --repo source with
+export --format gitlab to the live protection of the source, and prints the
+settings changed on the source since. The live protection goes through the
+same conversion, so the settings the file can't hold aren't reported.
+
+With --pr, a drifted source gets a pull request updating the policy file in
+the repository it is versioned in, to keep the change; closing it and syncing
+the policy reverts the source instead. Without --pr, the command exits with
+status 2 when the source drifted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options()
+		if owner == "" || repo == "" || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		drifted, err := executor.Reconcile(opts, reconcileOptions, os.Stdout)
+		if err != nil {
+			log.Fatalf("Reconcile failed: %v\n", err)
+		}
+		if drifted && reconcileOptions.Repo == "" {
+			os.Exit(2)
+		}
+	},
+}
+
+func init() {
+	reconcileCmd.Flags().StringVar(&reconcileOptions.File, "file", "", "Policy file exported from the source with export --format gitlab")
+	reconcileCmd.Flags().StringVar(&reconcileOptions.Repo, "pr", "", "Open a pull request updating the policy file in this repository, as owner/repo or a repository of the --owner, when the source drifted")
+	reconcileCmd.Flags().StringVar(&reconcileOptions.Path, "path", "", "Path of the policy file in the --pr repository (defaults to --file)")
+	reconcileCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(reconcileCmd)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotDir string
+var takeSnapshots bool
+var rollbackTo string
+var listRuns bool
+
+// rollbackCmd restores the protection of repositories from their snapshots
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback --to <timestamp|run-id> [repo...]",
+	Short: "Restores the branch protection and rulesets of repositories to their state at a previous time or run",
+	Long: `Before a sync or apply changes a repository, its branch protection and rulesets
+are saved as a snapshot in the snapshot directory. Rollback restores the state
+the repositories of the owner were in at a timestamp, such as 2024-05-01T12:00,
+or before a run, named by the ID --list shows. Only the given repositories are
+restored when some are named.
+
+Rulesets the repositories didn't have back then are deleted. Repository
+settings synced with the --sync-* flags aren't part of the snapshots. The
+current state is saved before restoring, so a rollback can be rolled back too.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := snapshotStore()
+		if store == nil {
+			log.Fatalln("Rollback needs a snapshot directory; pass --snapshot-dir")
+		}
+		if listRuns {
+			if err := writeRuns(store); err != nil {
+				log.Fatalf("Listing the runs failed: %v\n", err)
+			}
+			return
+		}
+		opts := options()
+		if owner == "" || rollbackTo == "" || opts.Credentials.Validate() != nil {
+			cmd.Help()
+			os.Exit(1)
+		}
+		opts.Snapshots = store
+		if err := executor.Rollback(opts, rollbackTo, args); err != nil {
+			log.Fatalf("Rollback failed: %v\n", err)
+		}
+	},
+}
+
+// snapshotStore returns the store of the snapshots: --snapshot-dir, or the
+// snapshot directory of the tool in the user config directory. It is nil
+// when there is no such directory.
+func snapshotStore() *snapshot.Store {
+	if snapshotDir != "" {
+		return &snapshot.Store{Dir: snapshotDir}
+	}
+	dir, err := config.SnapshotDir()
+	if err != nil {
+		return nil
+	}
+	return &snapshot.Store{Dir: dir}
+}
+
+// writeRuns prints the runs that took snapshots of the repositories of the
+// owner.
+func writeRuns(store *snapshot.Store) error {
+	runs, err := store.Runs(owner)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tSTARTED\tREPOSITORIES")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", run.ID, run.Started.Local().Format(time.RFC3339), strings.Join(run.Repos, ","))
+	}
+	return w.Flush()
+}
+
+func init() {
+	rollbackCmd.Flags().StringVar(&rollbackTo, "to", "", "Timestamp, such as 2024-05-01T12:00 in local time, or ID of the run to roll back to")
+	rollbackCmd.Flags().BoolVar(&listRuns, "list", false, "List the runs that took snapshots of the repositories of the owner instead of rolling back")
+	rollbackCmd.MarkFlagsOneRequired("to", "list")
+	rollbackCmd.MarkFlagsMutuallyExclusive("to", "list")
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -16,28 +16,419 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/arush-sal/repo-protection-sync/pkg/budget"
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
 	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/inventory"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/plan"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/snapshot"
+	"github.com/arush-sal/repo-protection-sync/pkg/transport"
+	"github.com/arush-sal/repo-protection-sync/pkg/upload"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var owner, repo, githubToken string
+var extraTokens []string
+var appID, installationID int64
+var privateKeyFile string
+var extraApps appsFlag
+var logOptions logging.Options
+var configFile string
+var cfg = new(config.Config)
+var preflightChecks bool
+var transportOptions transport.Options
+var properties map[string]string
+var filter getter.Filter
+var team string
+var canary executor.Canary
+var interactive bool
+var syncMergeSettings bool
+var syncSecuritySettings bool
+var syncActionsSettings bool
+var syncEnvironments bool
+var syncWebhooks bool
+var syncLabels, pruneLabels bool
+var syncAutolinks bool
+var dryRun bool
+var sourceStatus bool
+var estimate bool
+var onError string
+var repoTimeout time.Duration
+var abortAfter int
+var maxWait time.Duration
+var output string
+var useCache bool
+var repoCacheTTL time.Duration
+var refresh bool
+var apiURL string
+var providerName string
+var auditLog, auditLogUpload string
+var reportUpload string
+var replayDir string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "repo-protection-sync",
 	Short: "Applies a GitHub branch protection ruleset from a source repository to all repositories in an organization",
-	Run: func(cmd *cobra.Command, args []string) {
-		if owner == "" || repo == "" || githubToken == "" {
-			cmd.Help()
-			os.Exit(1)
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := logging.Setup(logOptions); err != nil {
+			return err
+		}
+		if configFile == "" {
+			path, err := config.DefaultFile()
+			if err != nil {
+				return err
+			}
+			if path != "" {
+				logging.Debugf("Using the configuration file %s\n", path)
+			}
+			configFile = path
+		}
+		if useCache && transportOptions.CacheDir == "" {
+			dir, err := config.CacheDir()
+			if err != nil {
+				return fmt.Errorf("locating the cache directory: %w", err)
+			}
+			transportOptions.CacheDir = dir
+		}
+		if auditLog != "" {
+			transportOptions.Audit = &transport.AuditLog{Path: auditLog}
+		}
+		if replayDir != "" {
+			replay, err := transport.NewReplay(replayDir)
+			if err != nil {
+				return err
+			}
+			transportOptions.Replay = replay
+		}
+		if configFile != "" {
+			loaded, err := config.LoadWith(configFile, readConfig)
+			if err != nil {
+				return err
+			}
+			cfg = loaded
 		}
-		executor.Run(owner, repo, githubToken)
+		if verifyTool != "" && cfg.Rego.Enabled() {
+			// opa reads the policy itself, so it evaluates a verified copy
+			cleanup, err := cfg.Rego.ReadPolicyWith(readConfig)
+			if err != nil {
+				return fmt.Errorf("reading the Rego policy: %w", err)
+			}
+			removeRegoCopy = cleanup
+		}
+		transportOptions.UserAgent = userAgent()
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if removeRegoCopy != nil {
+			removeRegoCopy()
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runSync(cmd, nil)
 	},
 }
 
+// runSync syncs the source repository onto the given targets, or onto every
+// repository of the owner when no targets are given.
+func runSync(cmd *cobra.Command, targets []string) {
+	checkSigned()
+	opts := runOptions(cmd)
+	opts.Targets = targets
+	if providerName != "github" && (dryRun || estimate) {
+		log.Fatalf("--dry-run and --estimate are only available with the github provider\n")
+	}
+	if dryRun {
+		runDryRun(opts)
+		return
+	}
+	if estimate {
+		runEstimate(opts)
+		return
+	}
+	if output != "text" {
+		log.Fatalf("--output %s requires --dry-run\n", output)
+	}
+	applyOptions(&opts)
+	executor.Run(opts)
+}
+
+// runOptions checks that the command has what a run needs and assembles its
+// options, with the protection and concurrency flags applied.
+func runOptions(cmd *cobra.Command) executor.Options {
+	creds := credentials()
+	if owner == "" || (repo == "" && len(cfg.Policies) == 0) || creds.Validate() != nil {
+		cmd.Help()
+		os.Exit(1)
+	}
+	if cmd.Flags().Changed("repo-timeout") {
+		cfg.Concurrency.RepoTimeout = repoTimeout
+	}
+	if cmd.Flags().Changed("abort-after") {
+		cfg.Concurrency.AbortAfter = abortAfter
+	}
+	if cmd.Flags().Changed("max-wait") {
+		cfg.Concurrency.MaxWait = maxWait
+	}
+	opts := options()
+	protectionOptions(&opts)
+	return opts
+}
+
+// applyOptions sets the options only a run that applies the policies uses.
+func applyOptions(opts *executor.Options) {
+	opts.Canary = canary
+	opts.Canary.Confirm = confirmCanary
+	if auditLogUpload != "" && auditLog == "" {
+		log.Fatalln("--audit-log-upload requires --audit-log")
+	}
+	opts.AuditLogUpload = auditLogUpload
+	syncOptions(opts)
+	failurePolicy, err := setter.ParseFailurePolicy(onError)
+	if err != nil {
+		log.Fatalf("--on-error: %v\n", err)
+	}
+	opts.OnError = failurePolicy
+}
+
+// syncOptions sets the options of the sync run given on the command line.
+func syncOptions(opts *executor.Options) {
+	opts.Preflight = preflightChecks
+	opts.Interactive = interactive
+	opts.SyncMergeSettings = syncMergeSettings
+	opts.SyncSecuritySettings = syncSecuritySettings
+	opts.SyncActionsSettings = syncActionsSettings
+	opts.SyncEnvironments = syncEnvironments
+	opts.SyncWebhooks = syncWebhooks
+	opts.SyncLabels = syncLabels || pruneLabels
+	opts.PruneLabels = pruneLabels
+	opts.SyncAutolinks = syncAutolinks
+	opts.SourceStatus = sourceStatus
+}
+
+// runDryRun prints the changes a sync would make without making them. Logs
+// go to stderr, so the JSON output on stdout can be piped to other tools.
+func runDryRun(opts executor.Options) {
+	write := func(w io.Writer, p *plan.Plan) error { return plan.WriteText(w, owner, p.Changes) }
+	switch output {
+	case "text":
+	case "json":
+		write = func(w io.Writer, p *plan.Plan) error { return plan.WriteJSON(w, p.Changes) }
+	default:
+		log.Fatalf("Unsupported output format %q\n", output)
+	}
+	if syncMergeSettings || syncSecuritySettings || syncActionsSettings || syncEnvironments || syncWebhooks || syncLabels || pruneLabels || syncAutolinks {
+		log.Println("Repository settings are not part of the dry run, only branch protection and rulesets")
+	}
+
+	p, err := executor.Plan(opts)
+	if err != nil {
+		log.Fatalf("Dry run failed: %v\n", err)
+	}
+	var report bytes.Buffer
+	if err := write(io.MultiWriter(os.Stdout, &report), p); err != nil {
+		log.Fatalf("Writing the dry run: %v\n", err)
+	}
+	if reportUpload != "" {
+		uploadReport("plan."+output, report.Bytes())
+	}
+}
+
+// uploadReport uploads a report of the run below --report-upload. A failed
+// upload doesn't fail the run, whose report was already written.
+func uploadReport(name string, data []byte) {
+	if err := upload.Data(context.Background(), reportUpload, upload.Name(time.Now(), name), data); err != nil {
+		log.Printf("Error uploading the report: %v\n", err)
+	}
+}
+
+// runEstimate prints the number of API requests a sync would send and how
+// they fit in the rate limit left, without syncing.
+func runEstimate(opts executor.Options) {
+	syncOptions(&opts)
+	estimate, rate, err := executor.Estimate(opts)
+	if err != nil {
+		log.Fatalf("Estimating the run failed: %v\n", err)
+	}
+	if err := budget.Write(os.Stdout, estimate, rate); err != nil {
+		log.Fatalf("Writing the estimate: %v\n", err)
+	}
+}
+
+// confirmCanary asks on the terminal whether to continue the rollout past
+// the canary cohort.
+func confirmCanary(cohort, remaining int) bool {
+	fmt.Fprintf(os.Stderr, "Canary applied to %d repositories. Sync the remaining %d? [y/N] ", cohort, remaining)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// addSyncFlags registers the flags of the sync run on the root and sync commands.
+func addSyncFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&preflightChecks, "preflight", false, "Run permission preflight checks and report repositories without an active admin")
+	flags.IntVar(&canary.Count, "canary", 0, "Sync this many repositories first and only continue once they succeeded")
+	flags.Float64Var(&canary.Percent, "canary-percent", 0, "Sync this percentage of the repositories first and only continue once they succeeded")
+	flags.DurationVar(&canary.Wait, "canary-wait", 0, "Continue after the canary once this duration elapsed, instead of asking for confirmation")
+	flags.BoolVar(&sourceStatus, "source-status", false, "Set a repo-protection-sync commit status on the default branch of every source, telling whether its policy was rolled out, linked to the workflow run in GitHub Actions")
+	flags.BoolVar(&interactive, "interactive", false, "Show the changes for every repository and ask whether to apply them")
+	flags.BoolVar(&syncMergeSettings, "sync-merge-settings", false, "Also copy the pull request merge settings (merge methods, auto-merge, branch deletion, commit messages) of the source")
+	flags.BoolVar(&syncSecuritySettings, "sync-security-settings", false, "Also match the secret scanning, push protection, advanced security and Dependabot alert and update settings of the source")
+	flags.BoolVar(&syncActionsSettings, "sync-actions-settings", false, "Also copy the allowed actions, default workflow permissions and fork pull request approval policy of the source")
+	flags.BoolVar(&syncEnvironments, "sync-environments", false, "Also create the deployment environments of the source and copy their reviewers, wait timer and branch policy")
+	flags.BoolVar(&syncWebhooks, "sync-webhooks", false, "Also copy the webhooks of the source, matched by URL; secrets are read from repo_webhooks.secrets in the config file")
+	flags.BoolVar(&syncLabels, "sync-labels", false, "Also create and update the issue labels of the source")
+	flags.BoolVar(&pruneLabels, "prune-labels", false, "Sync the labels and delete those the source doesn't have, removing them from issues and pull requests")
+	flags.BoolVar(&syncAutolinks, "sync-autolinks", false, "Also copy the autolink references of the source, such as ticket links")
+	flags.DurationVar(&repoTimeout, "repo-timeout", 0, "Give up on a repository whose sync takes longer than this, not counting its confirmation with --interactive (default 5m, overrides concurrency.repo_timeout)")
+	flags.StringVar(&onError, "on-error", "continue", "What a failing repository does to the sync: fail stops at the first failure, continue keeps going, threshold=N% stops once more than N% of the repositories failed")
+	flags.IntVar(&abortAfter, "abort-after", 0, "Abort the run once this many repositories in a row failed with the same error; negative never aborts (default 10, overrides concurrency.abort_after)")
+	flags.DurationVar(&maxWait, "max-wait", 0, "Fail the remaining repositories instead of waiting longer than this for the rate limit to reset (default no limit, overrides concurrency.max_wait)")
+	flags.BoolVar(&dryRun, "dry-run", false, "Print the changes to branch protection and rulesets without making them")
+	flags.StringVar(&auditLogUpload, "audit-log-upload", "", "Upload the --audit-log file after the sync to this s3://, gs:// or az:// URL, with the credentials of the aws, gcloud or az tool; a URL ending with / keeps the file name")
+	flags.BoolVar(&estimate, "estimate", false, "Print the number of API requests the sync would send and the rate limit left for them, without syncing")
+	flags.StringVar(&output, "output", "text", "Format of the dry run output (text, json); json is printed on stdout with logs on stderr")
+}
+
+// options assembles the executor options shared by all commands.
+func options() executor.Options {
+	return executor.Options{
+		Owner:        owner,
+		Source:       repo,
+		Credentials:  credentials(),
+		Config:       cfg,
+		Transport:    transportOptions,
+		Properties:   properties,
+		Filter:       filter,
+		Team:         team,
+		ReportUpload: reportUpload,
+		Provider:     providerName,
+		Snapshots:    runSnapshots(),
+		Inventory:    repoCache(),
+	}
+}
+
+// repoCache returns the cache of the repositories listed, nil when
+// --repo-cache-ttl is zero.
+func repoCache() *inventory.Cache {
+	if repoCacheTTL <= 0 {
+		return nil
+	}
+	dir := transportOptions.CacheDir
+	if dir == "" {
+		d, err := config.CacheDir()
+		if err != nil {
+			logging.Debugf("No user cache directory, not caching the repositories; pass --cache-dir to cache them\n")
+			return nil
+		}
+		dir = d
+	}
+	return &inventory.Cache{Dir: filepath.Join(dir, "repos"), TTL: repoCacheTTL, Refresh: refresh}
+}
+
+// runSnapshots returns where the run saves the state of the repositories it
+// changes, nil when it doesn't.
+func runSnapshots() *snapshot.Store {
+	if !takeSnapshots {
+		return nil
+	}
+	store := snapshotStore()
+	if store == nil {
+		logging.Debugf("No user config directory, not taking snapshots; pass --snapshot-dir to take them\n")
+	}
+	return store
+}
+
+// timeFlag is a time.Time flag taking the timestamps snapshot.ParseTime
+// accepts.
+type timeFlag time.Time
+
+func (f *timeFlag) String() string {
+	if time.Time(*f).IsZero() {
+		return ""
+	}
+	return time.Time(*f).Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(s string) error {
+	t, ok := snapshot.ParseTime(s)
+	if !ok {
+		return fmt.Errorf("%q is not a date or RFC 3339 timestamp", s)
+	}
+	*f = timeFlag(t)
+	return nil
+}
+
+func (f *timeFlag) Type() string { return "time" }
+
+// appsFlag is a repeatable flag of App installations given as
+// APP_ID:INSTALLATION_ID:PRIVATE_KEY_FILE.
+type appsFlag []executor.AppInstallation
+
+func (f *appsFlag) String() string {
+	apps := make([]string, 0, len(*f))
+	for _, app := range *f {
+		apps = append(apps, fmt.Sprintf("%d:%d:%s", app.AppID, app.InstallationID, app.PrivateKeyFile))
+	}
+	return strings.Join(apps, ",")
+}
+
+func (f *appsFlag) Set(s string) error {
+	app, err := executor.ParseAppInstallation(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, app)
+	return nil
+}
+
+func (f *appsFlag) Type() string { return "app" }
+
+// credentials assembles the authentication details passed on the command line.
+func credentials() executor.Credentials {
+	creds := executor.Credentials{
+		Token:          githubToken,
+		ExtraTokens:    extraTokens,
+		AppID:          appID,
+		InstallationID: installationID,
+		PrivateKeyFile: privateKeyFile,
+		ExtraApps:      extraApps,
+		BaseURL:        apiURL,
+	}
+	if creds.Token == "" && !creds.IsApp() && providerName == "github" {
+		creds.Token = keychainToken(creds.Host())
+	}
+	return creds
+}
+
+// keychainToken looks the stored token up once per run.
+var keychainToken = func() func(string) string {
+	var once sync.Once
+	var token string
+	return func(host string) string {
+		once.Do(func() { token = storedToken(host) })
+		return token
+	}
+}()
+
 func Execute() {
+	initCompletion()
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -47,8 +438,53 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&owner, "owner", "o", "", "GitHub repo owner")
 	rootCmd.MarkPersistentFlagRequired("owner")
-	rootCmd.PersistentFlags().StringVarP(&repo, "repo", "r", "", "GitHub template repo for using the ruleset from")
-	rootCmd.MarkPersistentFlagRequired("repo")
-	rootCmd.PersistentFlags().StringVarP(&githubToken, "token", "t", "", "GitHub token for authentication")
-	rootCmd.MarkPersistentFlagRequired("token")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to the YAML configuration file (defaults to repo-protection-sync/config.yaml in the user config directory, when present)")
+	rootCmd.PersistentFlags().StringVarP(&repo, "repo", "r", "", "GitHub template repo for using the ruleset from; auto uses the baseline repository of the owner (.github unless baseline_repo names another)")
+	rootCmd.PersistentFlags().StringVar(&repo, "source", "", "Same as --repo, as in --source auto")
+	rootCmd.PersistentFlags().StringVarP(&githubToken, "token", "t", "", "GitHub token for authentication, defaulting to the token stored with auth login; with --provider, the Gitea token or the Bitbucket access token or username:app-password")
+	rootCmd.PersistentFlags().StringArrayVar(&extraTokens, "extra-token", nil, "Further GitHub token to rotate with --token once the rate limit of a token runs out, so a large organization is synced in one pass (repeatable)")
+	rootCmd.PersistentFlags().Int64Var(&appID, "app-id", 0, "GitHub App ID, to authenticate as an App installation instead of using a token")
+	rootCmd.PersistentFlags().Int64Var(&installationID, "installation-id", 0, "GitHub App installation ID")
+	rootCmd.PersistentFlags().StringVar(&privateKeyFile, "private-key", "", "Path to the GitHub App private key (PEM)")
+	rootCmd.PersistentFlags().Var(&extraApps, "extra-app", "Installation of a further GitHub App on the same repositories, as APP_ID:INSTALLATION_ID:PRIVATE_KEY_FILE, to rotate with --app-id once the rate limit of an installation runs out (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "", "Address of a GitHub Enterprise Server instance, such as https://github.example.com (github.com when empty), of the Gitea instance, or of the Bitbucket API")
+	rootCmd.PersistentFlags().StringVar(&providerName, "provider", "github", "Forge of the owner ("+strings.Join(executor.Providers, ", ")+"); providers other than github only sync the branch protection of the source")
+	rootCmd.MarkFlagsMutuallyExclusive("repo", "source")
+	rootCmd.MarkFlagsMutuallyExclusive("token", "app-id")
+	rootCmd.MarkFlagsRequiredTogether("app-id", "installation-id", "private-key")
+	rootCmd.PersistentFlags().StringToStringVar(&properties, "property", nil, "Only target repositories whose custom property has the given value, as key=value (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&team, "team", "", "Only target the repositories this team of the organization, given by its slug, has admin or maintain access to")
+	rootCmd.PersistentFlags().Var((*timeFlag)(&filter.PushedSince), "pushed-since", "Only target repositories pushed to at or after this time, as 2006-01-02 or an RFC 3339 timestamp")
+	rootCmd.PersistentFlags().Var((*timeFlag)(&filter.PushedBefore), "pushed-before", "Only target repositories last pushed to before this time, such as dormant ones, as 2006-01-02 or an RFC 3339 timestamp")
+	rootCmd.PersistentFlags().StringSliceVar(&filter.Languages, "language", nil, "Only target repositories whose primary language is one of these, such as go,python")
+	rootCmd.PersistentFlags().IntVar(&filter.MinSize, "min-size", 0, "Only target repositories of at least this size, in kilobytes")
+	rootCmd.PersistentFlags().IntVar(&filter.MaxSize, "max-size", 0, "Only target repositories of at most this size, in kilobytes (no limit when 0)")
+	rootCmd.PersistentFlags().StringVar(&transportOptions.CacheDir, "cache-dir", "", "Directory for the ETag cache of protection and ruleset reads; a sync doesn't rewrite the protection of a repository that is unchanged and up to date (disabled when empty)")
+	rootCmd.PersistentFlags().BoolVar(&useCache, "cache", false, "Cache protection and ruleset reads in the user cache directory, unless --cache-dir is given")
+	rootCmd.PersistentFlags().DurationVar(&repoCacheTTL, "repo-cache-ttl", 0, "Reuse the repositories of the owner listed by a previous run for this long, below the cache directory (disabled when 0)")
+	rootCmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "List the repositories of the owner again instead of using the cached ones")
+	rootCmd.PersistentFlags().StringVar(&auditLog, "audit-log", "", "Append every write to the API, with its request and hashes of the resource before and after, to this JSONL file")
+	rootCmd.PersistentFlags().StringVar(&reportUpload, "report-upload", "", "Upload the reports of the run (sync summaries, dry runs, audits) below this s3://bucket/prefix, gs://bucket/prefix or az://account/container/prefix URL, with the credentials of the aws, gcloud or az tool")
+	rootCmd.PersistentFlags().StringVar(&snapshotDir, "snapshot-dir", "", "Directory of the snapshots of the repositories taken before changing them, for rollback (defaults to repo-protection-sync/snapshots in the user config directory)")
+	rootCmd.PersistentFlags().BoolVar(&takeSnapshots, "snapshots", true, "Save the branch protection and rulesets of every repository before changing them, so the run can be rolled back")
+	rootCmd.PersistentFlags().Float64Var(&transportOptions.RequestsPerSecond, "rps", 0, "Send at most this many API requests per second, whatever the concurrency (unlimited when 0)")
+	addSyncFlags(rootCmd.Flags())
+	addProtectionFlags(rootCmd.Flags())
+	rootCmd.MarkFlagsMutuallyExclusive("canary", "canary-percent")
+	rootCmd.MarkFlagsMutuallyExclusive("dry-run", "estimate")
+
+	rootCmd.PersistentFlags().StringVar(&logOptions.File, "log-file", "", "Write logs to this file instead of stderr")
+	rootCmd.PersistentFlags().IntVar(&logOptions.MaxSizeMB, "log-max-size", 100, "Maximum size in megabytes of the log file before it is rotated")
+	rootCmd.PersistentFlags().IntVar(&logOptions.MaxBackups, "log-max-backups", 3, "Maximum number of rotated log files to keep (0 keeps all)")
+	rootCmd.PersistentFlags().IntVar(&logOptions.MaxAgeDays, "log-max-age", 28, "Maximum number of days to keep rotated log files (0 keeps them forever)")
+	rootCmd.PersistentFlags().BoolVar(&logOptions.Compress, "log-compress", false, "Gzip rotated log files")
+	rootCmd.PersistentFlags().BoolVar(&logOptions.Syslog, "syslog", false, "Send logs to the local syslog daemon instead of stderr")
+	rootCmd.PersistentFlags().BoolVar(&logOptions.Journald, "journald", false, "Send logs to the systemd journal instead of stderr")
+	rootCmd.PersistentFlags().BoolVarP(&logOptions.Quiet, "quiet", "q", false, "Only log warnings and errors; reports, diffs and JSON output on stdout are unaffected")
+	rootCmd.PersistentFlags().BoolVar(&transportOptions.DebugHTTP, "debug-http", false, "Log every API request and response in full, bodies included, with tokens and secrets redacted")
+	rootCmd.PersistentFlags().StringVar(&transportOptions.Record, "record", "", "Save every API request and response to this directory, with tokens and secrets redacted")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Answer API requests from the responses saved with --record instead of sending them; any --token works")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().BoolVarP(&logOptions.Verbose, "verbose", "v", false, "Also log the outcome of every API request")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
 }
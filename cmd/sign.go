@@ -0,0 +1,56 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/signature"
+	"github.com/spf13/cobra"
+)
+
+var signTool, signKey, signOut string
+
+// signCmd signs a policy file
+var signCmd = &cobra.Command{
+	Use:   "sign <file>",
+	Short: "Signs a plan, configuration or exported policy file for --verify-signature",
+	Long: `Writes a detached signature of a plan file, a configuration file, or of any
+policy file, with cosign, minisign or gpg, which must be installed. Pipelines
+passing --verify-signature to sync or apply only execute the files signed this
+way, so only the policies approved through the signing step are applied.`,
+	Args:   cobra.ExactArgs(1),
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		out := signOut
+		if out == "" {
+			out = signature.Path(args[0])
+		}
+		if err := signature.Sign(context.Background(), signTool, args[0], out, signKey); err != nil {
+			log.Fatalf("Signing failed: %v\n", err)
+		}
+		log.Printf("Wrote the signature of %s to %s\n", args[0], out)
+	},
+}
+
+func init() {
+	signCmd.Flags().StringVar(&signTool, "tool", "cosign", "Signing tool ("+strings.Join(signature.Tools, ", ")+")")
+	signCmd.Flags().StringVar(&signKey, "key", "", "Private key to sign with; for gpg, the user ID of the key (defaults to the default key)")
+	signCmd.Flags().StringVar(&signOut, "signature", "", "Path of the signature to write (defaults to the file with a .sig suffix)")
+	rootCmd.AddCommand(signCmd)
+}
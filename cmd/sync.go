@@ -0,0 +1,82 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+var syncSelf bool
+
+// syncCmd applies the protection of the source repository to the organization or to a single repository
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Applies the protection of the source repository to the organization, or only to the current repository with --self",
+	Long: `Applies the branch protection and rulesets of the source repository to every
+repository of the owner.
+
+With --self, only the repository the command runs for is synced: the repository
+of the current GitHub Actions workflow, or the origin remote of the clone in the
+working directory, whose owner --owner defaults to. This lets teams apply the
+central policy to their own repository with a token that only administers that
+repository: a personal access token, or GitHub App credentials, with admin
+rights on it. The GITHUB_TOKEN of a workflow can't be granted the
+Administration permission, so it won't do.
+
+With --verify-signature, the configuration file and the GitLab files of its
+policies are only applied when their detached signatures, as written by sign,
+verify against --public-key. Only the policies they define inline or with
+gitlab are applied then, as the protection of a source repository is read live.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if syncSelf {
+			ownerOptional(cmd, args)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !syncSelf {
+			runSync(cmd, nil)
+			return
+		}
+
+		selfOwner, selfRepo, err := helpers.DetectRepository(".")
+		if err != nil {
+			log.Fatalf("Cannot determine the current repository: %v\n", err)
+		}
+		if owner == "" {
+			owner = selfOwner
+		} else if !strings.EqualFold(selfOwner, owner) {
+			log.Fatalf("The current repository belongs to %s, not to %s\n", selfOwner, owner)
+		}
+		if selfRepo == repo {
+			log.Fatalf("The current repository %s is the policy source itself\n", selfRepo)
+		}
+		runSync(cmd, []string{selfRepo})
+	},
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncSelf, "self", false, "Only sync the repository the command runs in")
+	addSyncFlags(syncCmd.Flags())
+	addVerifyFlags(syncCmd.Flags())
+	addProtectionFlags(syncCmd.Flags())
+	syncCmd.MarkFlagsMutuallyExclusive("canary", "canary-percent")
+	syncCmd.MarkFlagsMutuallyExclusive("dry-run", "estimate")
+	rootCmd.AddCommand(syncCmd)
+}
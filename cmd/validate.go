@@ -0,0 +1,74 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/validate"
+	"github.com/spf13/cobra"
+)
+
+var validateFile string
+var printSchema bool
+
+// validateCmd checks a configuration file before a run
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Checks a configuration file for syntax errors, unknown fields and settings that can't work",
+	Long: `Reads the configuration file given with --file, or with --config, and reports
+syntax errors, unknown fields and settings that parse but can't work as
+intended: an inline protection with fields the API doesn't have or that
+contradict each other, notification URLs that aren't URLs, or worker counts
+that don't add up. It exits with a non-zero status on any problem, so policies
+can be linted in CI.
+
+With --schema, the JSON Schema of the configuration file is printed instead,
+for editors to complete and check the YAML as it is written.`,
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		if printSchema {
+			os.Stdout.Write(config.Schema)
+			return
+		}
+		path := validateFile
+		if path == "" {
+			path = configFile
+		}
+		if path == "" {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		loaded, err := config.Load(path)
+		if err != nil {
+			log.Fatalf("%v\n", err)
+		}
+		if err := validate.Config(loaded); err != nil {
+			log.Fatalf("%s:\n%v\n", path, err)
+		}
+		fmt.Printf("%s is valid\n", path)
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validateFile, "file", "f", "", "Configuration file to check (defaults to the --config file)")
+	validateCmd.Flags().BoolVar(&printSchema, "schema", false, "Print the JSON Schema of the configuration file")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/compat"
+	"github.com/arush-sal/repo-protection-sync/pkg/executor"
+	"github.com/spf13/cobra"
+)
+
+// version is set at build time with -ldflags "-X .../cmd.version=v1.2.3".
+var version = ""
+
+// versionCmd prints the versions of the tool and go-github
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Prints the version and the sync features the API supports",
+	Long: `Prints the version of the tool and of the go-github library it was built
+with.
+
+Given credentials, it also reads the meta endpoint of the API, or of the GitHub
+Enterprise Server instance given with --api-url, and reports which sync
+features the instance supports: rulesets, custom properties and push
+protection.`,
+	PreRun: ownerOptional,
+	Run: func(cmd *cobra.Command, args []string) {
+		tool, library := versions()
+		fmt.Printf("repo-protection-sync %s\n", tool)
+		fmt.Printf("go-github %s\n", library)
+
+		opts := options()
+		if opts.Credentials.Validate() != nil {
+			return
+		}
+		report, err := executor.Probe(opts)
+		if err != nil {
+			log.Fatalf("Probing the API failed: %v\n", err)
+		}
+		if err := compat.Write(os.Stdout, report); err != nil {
+			log.Fatalf("Writing the report: %v\n", err)
+		}
+	},
+}
+
+// versions returns the version of the tool and of go-github, read from the
+// build information when the version wasn't set at build time.
+func versions() (tool, library string) {
+	tool, library = version, "unknown"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		if tool == "" {
+			tool = "unknown"
+		}
+		return tool, library
+	}
+	if tool == "" {
+		tool = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/google/go-github/v59" {
+			library = dep.Version
+		}
+	}
+	return tool, library
+}
+
+// userAgent returns the User-Agent of the API requests: the one of the
+// configuration, or the name and version of the tool.
+func userAgent() string {
+	if cfg.UserAgent != "" {
+		return cfg.UserAgent
+	}
+	tool, _ := versions()
+	return "repo-protection-sync/" + tool
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
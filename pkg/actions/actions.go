@@ -0,0 +1,154 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/audit"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+// Enabled reports whether the tool is running in a GitHub Actions workflow.
+func Enabled() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// RunURL returns the address of the workflow run the tool is running in,
+// empty outside GitHub Actions.
+func RunURL() string {
+	server, repo, id := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID")
+	if !Enabled() || server == "" || repo == "" || id == "" {
+		return ""
+	}
+	return server + "/" + repo + "/actions/runs/" + id
+}
+
+// Annotate writes a workflow command creating an annotation of the given
+// level (error, warning or notice). The runner picks up workflow commands on
+// stderr too, which keeps them out of reports written to stdout.
+func Annotate(w io.Writer, level, title, message string) {
+	fmt.Fprintf(w, "::%s title=%s::%s\n", level, escapeProperty(title), escapeData(message))
+}
+
+// SyncSummary annotates every failed repository of a sync run and appends
+// the run summary to the step summary.
+func SyncSummary(w io.Writer, s notify.Summary) error {
+	for _, f := range s.Failures {
+		Annotate(w, "error", "Sync failed for "+f.Repo, f.Error)
+	}
+	for _, repo := range s.Orphaned {
+		Annotate(w, "warning", "No active admin on "+repo, "The repository has no active admin to act on its protection.")
+	}
+
+	var b strings.Builder
+	if s.Policy != "" {
+		fmt.Fprintf(&b, "## Branch protection policy %s\n\n", s.Policy)
+	} else {
+		fmt.Fprintf(&b, "## Branch protection sync from %s/%s\n\n", s.Owner, s.Source)
+	}
+	fmt.Fprintf(&b, "Synced to %d repositories, %d failed.\n", s.Targets, len(s.Failures))
+	if len(s.Failures) > 0 {
+		b.WriteString("\n| Repository | Error |\n|---|---|\n")
+		for _, f := range s.Failures {
+			fmt.Fprintf(&b, "| %s | %s |\n", f.Repo, escapeCell(f.Error))
+		}
+	}
+	if len(s.Orphaned) > 0 {
+		fmt.Fprintf(&b, "\nRepositories without an active admin: %s\n", strings.Join(s.Orphaned, ", "))
+	}
+	if len(s.Empty) > 0 {
+		fmt.Fprintf(&b, "\nEmpty repositories awaiting a first push: %s\n", strings.Join(s.Empty, ", "))
+	}
+	if len(s.UnsupportedPlan) > 0 {
+		fmt.Fprintf(&b, "\nRepositories without branch protection on the current plan: %s\n", strings.Join(s.UnsupportedPlan, ", "))
+	}
+	if len(s.OptedOut) > 0 {
+		fmt.Fprintf(&b, "\nRepositories that opted out: %s\n", strings.Join(s.OptedOut, ", "))
+	}
+	if len(s.Held) > 0 {
+		Annotate(w, "warning", "Rollout stopped after the canary", fmt.Sprintf("%d repositories were not synced.", len(s.Held)))
+		fmt.Fprintf(&b, "\nRepositories held back after the canary: %s\n", strings.Join(s.Held, ", "))
+	}
+	if s.RunID != "" {
+		fmt.Fprintf(&b, "\nRun `%s`\n", s.RunID)
+	}
+	return appendSummary(b.String())
+}
+
+// AuditSummary annotates every non-compliant attribute of an audit and
+// appends the compliance matrix to the step summary.
+func AuditSummary(w io.Writer, r audit.Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Branch protection audit against %s/%s\n\n", r.Owner, r.Source)
+	attributes := r.Attributes()
+	fmt.Fprintf(&b, "| Repository | %s |\n|---|%s\n", strings.Join(attributes, " | "), strings.Repeat("---|", len(attributes)))
+
+	for _, row := range r.Rows {
+		if row.Error != "" {
+			Annotate(w, "warning", "Audit failed for "+row.Repo, row.Error)
+			fmt.Fprintf(&b, "| %s | %s |\n", row.Repo, escapeCell("error: "+row.Error))
+			continue
+		}
+		cells := make([]string, 0, len(row.Findings))
+		for _, f := range row.Findings {
+			if f.Pass {
+				cells = append(cells, "✅")
+				continue
+			}
+			Annotate(w, "error", fmt.Sprintf("%s drifted on %s", f.Attribute, row.Repo),
+				fmt.Sprintf("Expected %q, found %q.", f.Expected, f.Actual))
+			cells = append(cells, "❌ "+escapeCell(f.Actual))
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", row.Repo, strings.Join(cells, " | "))
+	}
+	if r.RunID != "" {
+		fmt.Fprintf(&b, "\nRun `%s`\n", r.RunID)
+	}
+	return appendSummary(b.String())
+}
+
+// appendSummary appends markdown to the file named by GITHUB_STEP_SUMMARY.
+func appendSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(markdown + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+func TestAnnotate(t *testing.T) {
+	var buf bytes.Buffer
+	Annotate(&buf, "error", "a: b, c", "100%\nfailed")
+	if got, want := buf.String(), "::error title=a%3A b%2C c::100%25%0Afailed\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSyncSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	s := notify.NewSummary("octo", "source", time.Now(), 3, map[string]error{"api": errors.New("422 | invalid")})
+	var buf bytes.Buffer
+	if err := SyncSummary(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "::error title=Sync failed for api::") {
+		t.Errorf("unexpected annotations %q", buf.String())
+	}
+	md, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(md), "| api | 422 \\| invalid |") {
+		t.Errorf("unexpected summary:\n%s", md)
+	}
+}
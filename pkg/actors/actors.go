@@ -0,0 +1,147 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Resolve prepares the push and dismissal restrictions of protection for
+// the target organization org: the actors are renamed with the mapping of
+// cfg and, when the source is in another organization or actors were
+// renamed, checked to exist. Unknown actors fail the run unless
+// cfg.DropUnknown is set, in which case they are dropped with a warning.
+func Resolve(ctx context.Context, lookup ghclient.ActorLookup, org string, crossOrg bool, protection *types.BranchProtection, cfg config.Actors) error {
+	if protection == nil || (!crossOrg && !cfg.Mapped()) {
+		return nil
+	}
+	r := &resolver{lookup: lookup, org: org, cfg: cfg, known: make(map[string]bool)}
+
+	if br := protection.Restrictions; br != nil {
+		if err := r.restrictions(ctx, "push restrictions", br); err != nil {
+			return err
+		}
+	}
+	if reviews := protection.Reviews; reviews != nil && reviews.DismissalRestrictions != nil {
+		if err := r.restrictions(ctx, "dismissal restrictions", reviews.DismissalRestrictions); err != nil {
+			return err
+		}
+	}
+
+	if len(r.unknown) > 0 {
+		return fmt.Errorf("actors unknown in %s: %s; map them in the actors section of the configuration or pass --drop-unknown-actors", org, strings.Join(r.unknown, ", "))
+	}
+	return nil
+}
+
+type resolver struct {
+	lookup  ghclient.ActorLookup
+	org     string
+	cfg     config.Actors
+	known   map[string]bool
+	unknown []string
+}
+
+// restrictions renames and checks the actors of one kind of restriction.
+func (r *resolver) restrictions(ctx context.Context, kind string, a *types.Actors) error {
+	var err error
+	if a.Users, err = r.keep(ctx, kind, "user", r.cfg.Users, a.Users); err != nil {
+		return err
+	}
+	if a.Teams, err = r.keep(ctx, kind, "team", r.cfg.Teams, a.Teams); err != nil {
+		return err
+	}
+	if a.Apps, err = r.keep(ctx, kind, "app", r.cfg.Apps, a.Apps); err != nil {
+		return err
+	}
+	// Node IDs are those of the source organization.
+	a.AppNodeIDs = nil
+	return nil
+}
+
+// keep renames the actors of a type and keeps those that exist. Names with
+// template variables are only known per target and are kept unchecked.
+func (r *resolver) keep(ctx context.Context, kind, actorType string, mapping map[string]string, names []string) ([]string, error) {
+	var kept []string
+	for _, name := range names {
+		name = rename(mapping, name)
+		if setter.IsTemplate(name) {
+			kept = append(kept, name)
+			continue
+		}
+		ok, err := r.exists(ctx, kind, actorType, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			kept = append(kept, name)
+		}
+	}
+	return kept, nil
+}
+
+func (r *resolver) exists(ctx context.Context, kind, actorType, name string) (bool, error) {
+	key := actorType + ":" + name
+	ok, seen := r.known[key]
+	if !seen {
+		var err error
+		switch actorType {
+		case "user":
+			_, _, err = r.lookup.GetUser(ctx, name)
+		case "team":
+			_, _, err = r.lookup.GetTeamBySlug(ctx, r.org, name)
+		case "app":
+			_, _, err = r.lookup.GetApp(ctx, name)
+		}
+		var ghErr *github.ErrorResponse
+		switch {
+		case err == nil:
+			ok = true
+		case errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound:
+		default:
+			return false, fmt.Errorf("looking up %s %s: %w", actorType, name, err)
+		}
+		r.known[key] = ok
+	}
+
+	if !ok {
+		if r.cfg.DropUnknown {
+			log.Printf("Warning: dropping %s %s from the %s, it doesn't exist in %s\n", actorType, name, kind, r.org)
+		} else if !seen {
+			r.unknown = append(r.unknown, actorType+" "+name)
+		}
+	}
+	return ok, nil
+}
+
+// rename returns the target name of an actor, the source name when unmapped.
+func rename(mapping map[string]string, name string) string {
+	if target, ok := mapping[name]; ok {
+		return target
+	}
+	return name
+}
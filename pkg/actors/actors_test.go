@@ -0,0 +1,109 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actors
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+var notFound = &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+
+func sourceProtection() *types.BranchProtection {
+	return &types.BranchProtection{
+		Restrictions: &types.Actors{
+			Users: []string{"alice"},
+			Teams: []string{"core", "legacy"},
+			Apps:  []string{"bot"},
+		},
+		Reviews: &types.Reviews{
+			DismissalRestrictions: &types.Actors{Teams: []string{"core"}},
+		},
+	}
+}
+
+func expectLookups(lookup *mocks.MockActorLookup) {
+	lookup.EXPECT().GetUser(gomock.Any(), "alice").Return(&github.User{}, nil, nil)
+	lookup.EXPECT().GetTeamBySlug(gomock.Any(), "target", "platform").Return(&github.Team{}, nil, nil)
+	lookup.EXPECT().GetTeamBySlug(gomock.Any(), "target", "legacy").Return(nil, nil, notFound)
+	lookup.EXPECT().GetApp(gomock.Any(), "bot").Return(&github.App{}, nil, nil)
+}
+
+func TestResolveDropsUnknown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lookup := mocks.NewMockActorLookup(ctrl)
+	expectLookups(lookup)
+
+	p := sourceProtection()
+	cfg := config.Actors{Teams: map[string]string{"core": "platform"}, DropUnknown: true}
+	if err := Resolve(context.Background(), lookup, "target", true, p, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.Restrictions.Teams; !reflect.DeepEqual(got, []string{"platform"}) {
+		t.Errorf("got push teams %v, want [platform]", got)
+	}
+	if got := p.Reviews.DismissalRestrictions.Teams; !reflect.DeepEqual(got, []string{"platform"}) {
+		t.Errorf("got dismissal teams %v, want [platform]", got)
+	}
+	if len(p.Restrictions.Users) != 1 || len(p.Restrictions.Apps) != 1 {
+		t.Errorf("known users and apps should be kept, got %+v", p.Restrictions)
+	}
+}
+
+func TestResolveFailsOnUnknown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lookup := mocks.NewMockActorLookup(ctrl)
+	expectLookups(lookup)
+
+	cfg := config.Actors{Teams: map[string]string{"core": "platform"}}
+	err := Resolve(context.Background(), lookup, "target", true, sourceProtection(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "actors unknown in target: team legacy;") {
+		t.Errorf("got %v, want the unknown team to be reported", err)
+	}
+}
+
+func TestResolveSameOrg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lookup := mocks.NewMockActorLookup(ctrl)
+
+	// Without a mapping nothing is looked up for a source in the same organization
+	if err := Resolve(context.Background(), lookup, "octo", false, sourceProtection(), config.Actors{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestResolveKeepsTemplates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lookup := mocks.NewMockActorLookup(ctrl)
+
+	// Names expanded per target can't be looked up
+	p := &types.BranchProtection{Restrictions: &types.Actors{Teams: []string{"${repo}-maintainers"}}}
+	if err := Resolve(context.Background(), lookup, "target", true, p, config.Actors{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.Restrictions.Teams; !reflect.DeepEqual(got, []string{"${repo}-maintainers"}) {
+		t.Errorf("got teams %v, want the template kept", got)
+	}
+}
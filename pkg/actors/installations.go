@@ -0,0 +1,155 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actors
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// Installed returns a request transform that checks the apps of the push
+// restrictions of each target against the apps installed on it, as GitHub
+// rejects restrictions naming an app without access to the repository.
+// Apps that aren't installed fail the target unless drop is set, in which
+// case they are removed with a warning. Installations that can't be listed
+// are assumed to cover the target.
+func Installed(lister ghclient.AppInstallationLister, owner string, drop bool) func(context.Context, *github.Repository, *github.ProtectionRequest) error {
+	c := &installations{lister: lister, owner: owner, repos: make(map[int64]map[string]bool)}
+	return func(ctx context.Context, repo *github.Repository, req *github.ProtectionRequest) error {
+		br := req.Restrictions
+		if br == nil || len(br.Apps) == 0 {
+			return nil
+		}
+
+		kept, missing := []string{}, []string(nil)
+		for _, slug := range br.Apps {
+			ok, err := c.installed(ctx, slug, repo.GetName())
+			if err != nil {
+				return err
+			}
+			if ok {
+				kept = append(kept, slug)
+			} else {
+				missing = append(missing, slug)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if !drop {
+			return fmt.Errorf("push restrictions name apps not installed on %s: %s; install them or pass --drop-unknown-actors", repo.GetName(), strings.Join(missing, ", "))
+		}
+		log.Printf("Warning: dropping apps not installed on %s from the push restrictions: %s\n", repo.GetName(), strings.Join(missing, ", "))
+		// the restrictions may be shared with the source and other targets
+		restrictions := *br
+		restrictions.Apps = kept
+		req.Restrictions = &restrictions
+		return nil
+	}
+}
+
+// installations caches the app installations of an organization and the
+// repositories of the installations limited to selected ones.
+type installations struct {
+	lister ghclient.AppInstallationLister
+	owner  string
+
+	mu     sync.Mutex
+	loaded bool
+	bySlug map[string]*github.Installation
+	repos  map[int64]map[string]bool
+}
+
+func (c *installations) installed(ctx context.Context, slug, repo string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		bySlug, err := c.list(ctx)
+		if err != nil {
+			log.Printf("Warning: can't list the apps installed in %s, not checking the apps of the push restrictions: %v\n", c.owner, err)
+		}
+		c.bySlug = bySlug
+		c.loaded = true
+	}
+	if c.bySlug == nil {
+		return true, nil
+	}
+
+	inst, ok := c.bySlug[slug]
+	if !ok {
+		return false, nil
+	}
+	if inst.GetRepositorySelection() == "all" {
+		return true, nil
+	}
+
+	repos, seen := c.repos[inst.GetID()]
+	if !seen {
+		var err error
+		repos, err = c.listRepos(ctx, inst.GetID())
+		if err != nil {
+			log.Printf("Warning: can't list the repositories of the %s app, assuming it is installed on them: %v\n", slug, err)
+		}
+		c.repos[inst.GetID()] = repos
+	}
+	if repos == nil {
+		return true, nil
+	}
+	return repos[strings.ToLower(repo)], nil
+}
+
+func (c *installations) list(ctx context.Context) (map[string]*github.Installation, error) {
+	bySlug := make(map[string]*github.Installation)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.lister.ListInstallations(ctx, c.owner, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range page.Installations {
+			bySlug[inst.GetAppSlug()] = inst
+		}
+		if resp.NextPage == 0 {
+			return bySlug, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *installations) listRepos(ctx context.Context, id int64) (map[string]bool, error) {
+	repos := make(map[string]bool)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.lister.ListUserRepos(ctx, id, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range page.Repositories {
+			repos[strings.ToLower(repo.GetName())] = true
+		}
+		if resp.NextPage == 0 {
+			return repos, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
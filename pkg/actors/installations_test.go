@@ -0,0 +1,96 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actors
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func expectInstallations(lister *mocks.MockAppInstallationLister) {
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	lister.EXPECT().ListInstallations(gomock.Any(), "org", gomock.Any()).Return(&github.OrganizationInstallations{
+		Installations: []*github.Installation{
+			{ID: github.Int64(1), AppSlug: github.String("deployer"), RepositorySelection: github.String("all")},
+			{ID: github.Int64(2), AppSlug: github.String("releaser"), RepositorySelection: github.String("selected")},
+		},
+	}, ok, nil)
+	lister.EXPECT().ListUserRepos(gomock.Any(), int64(2), gomock.Any()).Return(&github.ListRepositories{
+		Repositories: []*github.Repository{{Name: github.String("api")}},
+	}, ok, nil)
+}
+
+func restrictedRequest() *github.ProtectionRequest {
+	return &github.ProtectionRequest{Restrictions: &github.BranchRestrictionsRequest{
+		Users: []string{}, Teams: []string{"core"}, Apps: []string{"deployer", "releaser", "gone"},
+	}}
+}
+
+func TestInstalledFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lister := mocks.NewMockAppInstallationLister(ctrl)
+	expectInstallations(lister)
+
+	transform := Installed(lister, "org", false)
+	err := transform(context.Background(), &github.Repository{Name: github.String("web")}, restrictedRequest())
+	if err == nil || !strings.Contains(err.Error(), "not installed on web: releaser, gone") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the installations are listed once for every target
+	if err := transform(context.Background(), &github.Repository{Name: github.String("API")}, restrictedRequest()); err == nil || !strings.Contains(err.Error(), ": gone;") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInstalledDrops(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lister := mocks.NewMockAppInstallationLister(ctrl)
+	expectInstallations(lister)
+
+	req := restrictedRequest()
+	source := req.Restrictions
+	if err := Installed(lister, "org", true)(context.Background(), &github.Repository{Name: github.String("web")}, req); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(req.Restrictions.Apps, []string{"deployer"}) {
+		t.Errorf("apps = %v", req.Restrictions.Apps)
+	}
+	if len(source.Apps) != 3 {
+		t.Errorf("the restrictions of the source were modified: %v", source.Apps)
+	}
+}
+
+func TestInstalledUnlisted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lister := mocks.NewMockAppInstallationLister(ctrl)
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	lister.EXPECT().ListInstallations(gomock.Any(), "org", gomock.Any()).Return(nil, nil, forbidden)
+
+	req := restrictedRequest()
+	if err := Installed(lister, "org", false)(context.Background(), &github.Repository{Name: github.String("web")}, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Restrictions.Apps) != 3 {
+		t.Errorf("apps = %v", req.Restrictions.Apps)
+	}
+}
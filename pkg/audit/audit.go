@@ -0,0 +1,180 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+)
+
+// Attributes lists the protection attributes checked by the audit, in the
+// order they appear in the reports.
+var Attributes = []string{
+	"reviews_required",
+	"enforce_admins",
+	"signed_commits",
+	"force_pushes",
+	"linear_history",
+	"status_checks",
+}
+
+// Report is the compliance matrix of every target against the source baseline.
+type Report struct {
+	// RunID identifies the run of the audit.
+	RunID  string `json:"run_id,omitempty"`
+	Owner  string `json:"owner"`
+	Source string `json:"source"`
+	Rows   []Row  `json:"rows"`
+}
+
+// Row holds the findings of a single repository. Error is set instead of
+// Findings when the protection of the repository couldn't be read.
+type Row struct {
+	Repo     string    `json:"repo"`
+	Branch   string    `json:"branch,omitempty"`
+	Findings []Finding `json:"findings,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Finding compares one attribute of a repository against the baseline.
+type Finding struct {
+	Attribute string `json:"attribute"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual"`
+	Pass      bool   `json:"pass"`
+}
+
+// Compliant reports whether every attribute of the repository passed.
+func (r Row) Compliant() bool {
+	if r.Error != "" {
+		return false
+	}
+	for _, f := range r.Findings {
+		if !f.Pass {
+			return false
+		}
+	}
+	return true
+}
+
+// Attributes returns the attributes checked in the report: the protection
+// attributes, followed by the forbidden features scanned for, if any.
+func (r Report) Attributes() []string {
+	for _, row := range r.Rows {
+		if row.Error == "" {
+			attributes := make([]string, 0, len(row.Findings))
+			for _, f := range row.Findings {
+				attributes = append(attributes, f.Attribute)
+			}
+			return attributes
+		}
+	}
+	return Attributes
+}
+
+// Compliant reports whether every repository of the report passed.
+func (r Report) Compliant() bool {
+	for _, row := range r.Rows {
+		if !row.Compliant() {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate compares the protection of a target, nil when its branch is
+// unprotected, against the baseline. A target passes an attribute when it is
+// at least as strict as the baseline.
+func Evaluate(baseline, actual *types.BranchProtection) []Finding {
+	wantReviews, haveReviews := approvals(baseline), approvals(actual)
+	wantChecks, haveChecks := statusChecks(baseline), statusChecks(actual)
+
+	return []Finding{
+		{
+			Attribute: "reviews_required",
+			Expected:  strconv.Itoa(wantReviews),
+			Actual:    strconv.Itoa(haveReviews),
+			Pass:      haveReviews >= wantReviews,
+		},
+		enabled("enforce_admins", baseline != nil && baseline.EnforceAdmins, actual != nil && actual.EnforceAdmins),
+		enabled("signed_commits", baseline != nil && baseline.SignedCommits, actual != nil && actual.SignedCommits),
+		enabled("force_pushes", !allowForcePushes(baseline), !allowForcePushes(actual)),
+		enabled("linear_history", baseline != nil && baseline.LinearHistory, actual != nil && actual.LinearHistory),
+		{
+			Attribute: "status_checks",
+			Expected:  strings.Join(wantChecks, ","),
+			Actual:    strings.Join(haveChecks, ","),
+			Pass:      subset(wantChecks, haveChecks),
+		},
+	}
+}
+
+// enabled compares an attribute that only needs to be enabled on the target
+// when it is enabled on the baseline.
+func enabled(attribute string, want, have bool) Finding {
+	return Finding{
+		Attribute: attribute,
+		Expected:  state(want),
+		Actual:    state(have),
+		Pass:      have || !want,
+	}
+}
+
+func state(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// allowForcePushes reports whether force pushes are allowed. They are on an
+// unprotected branch.
+func allowForcePushes(p *types.BranchProtection) bool {
+	return p == nil || p.AllowForcePushes
+}
+
+func approvals(p *types.BranchProtection) int {
+	if p != nil && p.Reviews != nil {
+		return p.Reviews.RequiredApprovals
+	}
+	return 0
+}
+
+func statusChecks(p *types.BranchProtection) []string {
+	if p == nil || p.StatusChecks == nil {
+		return nil
+	}
+	contexts := append([]string(nil), p.StatusChecks.Contexts...)
+	sort.Strings(contexts)
+	return contexts
+}
+
+// subset reports whether every value of want is in have.
+func subset(want, have []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, v := range have {
+		set[v] = true
+	}
+	for _, v := range want {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
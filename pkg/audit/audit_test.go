@@ -0,0 +1,153 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+)
+
+func baseline() *types.BranchProtection {
+	return &types.BranchProtection{
+		Reviews:       &types.Reviews{RequiredApprovals: 2},
+		EnforceAdmins: true,
+		LinearHistory: true,
+		StatusChecks:  &types.StatusChecks{Contexts: []string{"lint", "ci"}},
+	}
+}
+
+func results(findings []Finding) map[string]bool {
+	m := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		m[f.Attribute] = f.Pass
+	}
+	return m
+}
+
+func TestEvaluate(t *testing.T) {
+	stricter := baseline()
+	stricter.Reviews.RequiredApprovals = 3
+	stricter.SignedCommits = true
+	stricter.StatusChecks.Contexts = []string{"ci", "lint", "e2e"}
+	for attribute, pass := range results(Evaluate(baseline(), stricter)) {
+		if !pass {
+			t.Errorf("stricter target failed %s", attribute)
+		}
+	}
+
+	weaker := baseline()
+	weaker.Reviews.RequiredApprovals = 1
+	weaker.AllowForcePushes = true
+	weaker.StatusChecks.Contexts = []string{"ci"}
+	got := results(Evaluate(baseline(), weaker))
+	want := map[string]bool{
+		"reviews_required": false,
+		"enforce_admins":   true,
+		"signed_commits":   true,
+		"force_pushes":     false,
+		"linear_history":   true,
+		"status_checks":    false,
+	}
+	for attribute, pass := range want {
+		if got[attribute] != pass {
+			t.Errorf("%s: got pass=%v, want %v", attribute, got[attribute], pass)
+		}
+	}
+
+	findings := Evaluate(baseline(), nil)
+	if len(findings) != len(Attributes) {
+		t.Fatalf("got %d findings, want %d", len(findings), len(Attributes))
+	}
+	for i, f := range findings {
+		if f.Attribute != Attributes[i] {
+			t.Errorf("finding %d is %s, want %s", i, f.Attribute, Attributes[i])
+		}
+	}
+	if (Row{Findings: findings}).Compliant() {
+		t.Error("an unprotected branch should not be compliant")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r := Report{Rows: []Row{
+		{Repo: "good", Branch: "main", Findings: Evaluate(baseline(), baseline())},
+		{Repo: "broken", Error: "boom"},
+	}}
+
+	var table bytes.Buffer
+	if err := WriteTable(&table, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "error: boom") || strings.Count(table.String(), "pass") != len(Attributes) {
+		t.Errorf("unexpected table:\n%s", table.String())
+	}
+
+	var csv bytes.Buffer
+	if err := WriteCSV(&csv, r); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 1+len(Attributes)+1 {
+		t.Errorf("got %d CSV lines:\n%s", len(lines), csv.String())
+	}
+	if lines[len(lines)-1] != "broken,,,,,error: boom" {
+		t.Errorf("unexpected error record %q", lines[len(lines)-1])
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	weaker := baseline()
+	weaker.EnforceAdmins = false
+	r := Report{RunID: "run-1", Owner: "octo", Source: "source", Rows: []Row{
+		{Repo: "good", Branch: "main", Findings: Evaluate(baseline(), baseline())},
+		{Repo: "drifted", Branch: "main", Findings: Evaluate(baseline(), weaker)},
+		{Repo: "broken", Error: "boom"},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", log)
+	}
+	if details := log.Runs[0].AutomationDetails; details == nil || details.ID != "repo-protection-sync/run-1" {
+		t.Errorf("unexpected automation details %+v", details)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	if results[0].RuleID != "enforce_admins" || !strings.Contains(results[0].Message.Text, "octo/drifted@main") {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+	if results[1].RuleID != "unreadable" || results[1].Locations[0].LogicalLocations[0].FullyQualifiedName != "octo/broken" {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+	for _, rule := range log.Runs[0].Tool.Driver.Rules {
+		if rule.Help.Text == "" {
+			t.Errorf("rule %s has no remediation", rule.ID)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/google/go-github/v59/github"
+)
+
+// IssueLabel labels the tracking issues filed by the audit.
+const IssueLabel = "repo-protection-sync"
+
+// FileIssues files a tracking issue in every non-compliant repository of the
+// report describing how it differs from the source, or updates the one filed
+// by a previous audit. The tracking issue of a repository that complies again
+// is closed. Repositories whose protection couldn't be read are left alone.
+// The errors are returned keyed by repository name.
+func FileIssues(ctx context.Context, client ghclient.IssueManager, r Report) map[string]error {
+	failures := make(map[string]error)
+	title := issueTitle(r)
+
+	for _, row := range r.Rows {
+		if row.Error != "" {
+			continue
+		}
+		existing, err := findIssue(ctx, client, r.Owner, row.Repo, title)
+		if err != nil {
+			failures[row.Repo] = err
+			continue
+		}
+
+		switch {
+		case row.Compliant() && existing != nil:
+			_, _, err = client.Edit(ctx, r.Owner, row.Repo, existing.GetNumber(), &github.IssueRequest{State: github.String("closed")})
+			if err == nil {
+				logging.Infof("Closed tracking issue %s/%s#%d\n", r.Owner, row.Repo, existing.GetNumber())
+			}
+		case row.Compliant():
+		case existing != nil:
+			// The run footer alone does not warrant an edit.
+			body := issueBody(r, row)
+			if !strings.HasPrefix(existing.GetBody(), body) {
+				body += runFooter(r)
+				_, _, err = client.Edit(ctx, r.Owner, row.Repo, existing.GetNumber(), &github.IssueRequest{Body: &body})
+				if err == nil {
+					logging.Infof("Updated tracking issue %s/%s#%d\n", r.Owner, row.Repo, existing.GetNumber())
+				}
+			}
+		default:
+			var issue *github.Issue
+			issue, _, err = client.Create(ctx, r.Owner, row.Repo, &github.IssueRequest{
+				Title:  &title,
+				Body:   github.String(issueBody(r, row) + runFooter(r)),
+				Labels: &[]string{IssueLabel},
+			})
+			if err == nil {
+				logging.Infof("Filed tracking issue %s/%s#%d\n", r.Owner, row.Repo, issue.GetNumber())
+			}
+		}
+		if err != nil {
+			log.Printf("Error filing tracking issue in %s/%s: %v\n", r.Owner, row.Repo, err)
+			failures[row.Repo] = err
+		}
+	}
+	return failures
+}
+
+// findIssue returns the open tracking issue of a repository, if any.
+func findIssue(ctx context.Context, client ghclient.IssueManager, owner, repo, title string) (*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      []string{IssueLabel},
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := client.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if issue.GetTitle() == title && !issue.IsPullRequest() {
+				return issue, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func issueTitle(r Report) string {
+	return fmt.Sprintf("Branch protection differs from %s/%s", r.Owner, r.Source)
+}
+
+func issueBody(r Report, row Row) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The branch protection of `%s` differs from the policy defined by [%s/%s](https://github.com/%s/%s).\n\n",
+		row.Branch, r.Owner, r.Source, r.Owner, r.Source)
+	b.WriteString("| Attribute | Expected | Actual |\n|---|---|---|\n")
+	var remediation []string
+	for _, f := range row.Findings {
+		if f.Pass {
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", f.Attribute, cell(f.Expected), cell(f.Actual))
+		remediation = append(remediation, fmt.Sprintf("- **%s**: %s", f.Attribute, rules[f.Attribute].remediation))
+	}
+	fmt.Fprintf(&b, "\nTo fix:\n%s\n\n", strings.Join(remediation, "\n"))
+	b.WriteString("_This issue is maintained by repo-protection-sync and is closed once the repository complies._\n")
+	return b.String()
+}
+
+// runFooter names the run that last wrote an issue.
+func runFooter(r Report) string {
+	if r.RunID == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nLast updated by run `%s`.\n", r.RunID)
+}
+
+// cell renders a value in a Markdown table cell.
+func cell(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return "`" + value + "`"
+}
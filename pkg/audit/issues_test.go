@@ -0,0 +1,74 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFileIssues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	issues := mocks.NewMockIssueManager(ctrl)
+
+	weaker := baseline()
+	weaker.EnforceAdmins = false
+	r := Report{RunID: "run-2", Owner: "octo", Source: "source", Rows: []Row{
+		{Repo: "new-drift", Branch: "main", Findings: Evaluate(baseline(), weaker)},
+		{Repo: "old-drift", Branch: "main", Findings: Evaluate(baseline(), weaker)},
+		{Repo: "same-drift", Branch: "main", Findings: Evaluate(baseline(), weaker)},
+		{Repo: "fixed", Branch: "main", Findings: Evaluate(baseline(), baseline())},
+		{Repo: "clean", Branch: "main", Findings: Evaluate(baseline(), baseline())},
+		{Repo: "unreadable", Error: "boom"},
+	}}
+	title := issueTitle(r)
+	open := func(number int) []*github.Issue {
+		return []*github.Issue{{Number: github.Int(number), Title: github.String(title), Body: github.String("stale")}}
+	}
+	last := &github.Response{}
+
+	issues.EXPECT().ListByRepo(gomock.Any(), "octo", "new-drift", gomock.Any()).Return(nil, last, nil)
+	issues.EXPECT().Create(gomock.Any(), "octo", "new-drift", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, req *github.IssueRequest) (*github.Issue, *github.Response, error) {
+			if req.GetTitle() != title || !strings.Contains(req.GetBody(), "| enforce_admins | `enabled` | `disabled` |") ||
+				!strings.HasSuffix(req.GetBody(), "Last updated by run `run-2`.\n") {
+				t.Errorf("unexpected issue %s:\n%s", req.GetTitle(), req.GetBody())
+			}
+			return &github.Issue{Number: github.Int(1)}, nil, nil
+		})
+
+	issues.EXPECT().ListByRepo(gomock.Any(), "octo", "old-drift", gomock.Any()).Return(open(7), last, nil)
+	issues.EXPECT().Edit(gomock.Any(), "octo", "old-drift", 7, gomock.Any()).Return(&github.Issue{}, nil, nil)
+
+	// Only the run footer differs, so the issue is left alone.
+	unchanged := issueBody(r, r.Rows[2]) + runFooter(Report{RunID: "run-1"})
+	issues.EXPECT().ListByRepo(gomock.Any(), "octo", "same-drift", gomock.Any()).
+		Return([]*github.Issue{{Number: github.Int(5), Title: github.String(title), Body: &unchanged}}, last, nil)
+
+	issues.EXPECT().ListByRepo(gomock.Any(), "octo", "fixed", gomock.Any()).Return(open(3), last, nil)
+	issues.EXPECT().Edit(gomock.Any(), "octo", "fixed", 3, &github.IssueRequest{State: github.String("closed")}).Return(&github.Issue{}, nil, nil)
+
+	issues.EXPECT().ListByRepo(gomock.Any(), "octo", "clean", gomock.Any()).Return(nil, last, nil)
+
+	if failures := FileIssues(context.Background(), issues, r); len(failures) != 0 {
+		t.Errorf("unexpected failures %v", failures)
+	}
+}
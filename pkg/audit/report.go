@@ -0,0 +1,99 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ReadDrifted returns the repositories a report flags: the non-compliant
+// repositories of an audit written in the json or sarif format, or the
+// repositories a dry run written in the json format would change. The owner
+// is empty when the report doesn't name it.
+func ReadDrifted(r io.Reader) (string, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	// A dry run is an array of changes
+	if bytes.HasPrefix(data, []byte("[")) {
+		var changes []struct {
+			Repo string `json:"repo"`
+		}
+		if err := json.Unmarshal(data, &changes); err != nil {
+			return "", nil, err
+		}
+		var repos []string
+		for _, c := range changes {
+			repos = append(repos, c.Repo)
+		}
+		return "", unique(repos), nil
+	}
+
+	var report struct {
+		Report
+		Runs []sarifRun `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return "", nil, err
+	}
+	switch {
+	case report.Runs != nil:
+		var owner string
+		var repos []string
+		for _, run := range report.Runs {
+			for _, result := range run.Results {
+				for _, loc := range result.Locations {
+					for _, logical := range loc.LogicalLocations {
+						if o, repo, ok := strings.Cut(logical.FullyQualifiedName, "/"); ok {
+							owner = o
+							repos = append(repos, repo)
+						}
+					}
+				}
+			}
+		}
+		return owner, unique(repos), nil
+	case report.Rows != nil:
+		var repos []string
+		for _, row := range report.Rows {
+			if !row.Compliant() {
+				repos = append(repos, row.Repo)
+			}
+		}
+		return report.Owner, unique(repos), nil
+	}
+	return "", nil, errors.New("not an audit report in the json or sarif format, nor a dry run in the json format")
+}
+
+// unique sorts names and drops duplicates.
+func unique(names []string) []string {
+	sort.Strings(names)
+	kept := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
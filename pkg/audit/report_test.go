@@ -0,0 +1,58 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadDrifted(t *testing.T) {
+	weaker := baseline()
+	weaker.EnforceAdmins = false
+	r := Report{Owner: "octo", Source: "source", Rows: []Row{
+		{Repo: "good", Branch: "main", Findings: Evaluate(baseline(), baseline())},
+		{Repo: "drifted", Branch: "main", Findings: Evaluate(baseline(), weaker)},
+		{Repo: "broken", Error: "boom"},
+	}}
+	want := []string{"broken", "drifted"}
+
+	for name, write := range map[string]func(*bytes.Buffer, Report) error{
+		"json":  func(b *bytes.Buffer, r Report) error { return WriteJSON(b, r) },
+		"sarif": func(b *bytes.Buffer, r Report) error { return WriteSARIF(b, r) },
+	} {
+		var buf bytes.Buffer
+		if err := write(&buf, r); err != nil {
+			t.Fatal(err)
+		}
+		owner, repos, err := ReadDrifted(&buf)
+		if err != nil || owner != "octo" || !reflect.DeepEqual(repos, want) {
+			t.Errorf("%s: got %s, %v, %v, want octo and %v", name, owner, repos, err, want)
+		}
+	}
+
+	dryRun := `[{"repo":"api","action":"update"},{"repo":"web","action":"create"},{"repo":"api","action":"create"}]`
+	owner, repos, err := ReadDrifted(strings.NewReader(dryRun))
+	if err != nil || owner != "" || !reflect.DeepEqual(repos, []string{"api", "web"}) {
+		t.Errorf("dry run: got %q, %v, %v", owner, repos, err)
+	}
+
+	if _, _, err := ReadDrifted(strings.NewReader(`{"name":"x"}`)); err == nil {
+		t.Error("an unknown document was read as a report")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// rules describes every attribute as a SARIF rule, including how to fix a
+// non-compliant repository.
+var rules = map[string]struct {
+	description string
+	remediation string
+}{
+	"reviews_required": {
+		"Pull requests need the required number of approving reviews",
+		"Raise the required approving review count in the branch protection of the default branch to at least the expected value.",
+	},
+	"enforce_admins": {
+		"Branch protection applies to administrators",
+		"Enable \"Do not allow bypassing the above settings\" in the branch protection of the default branch.",
+	},
+	"signed_commits": {
+		"Commits must be signed",
+		"Enable \"Require signed commits\" in the branch protection of the default branch.",
+	},
+	"force_pushes": {
+		"Force pushes are blocked",
+		"Disable \"Allow force pushes\" in the branch protection of the default branch.",
+	},
+	"linear_history": {
+		"Merge commits are not allowed",
+		"Enable \"Require linear history\" in the branch protection of the default branch.",
+	},
+	"status_checks": {
+		"Required status checks must pass before merging",
+		"Add the missing status checks to the required status checks of the default branch.",
+	},
+	"write_deploy_keys": {
+		"Deploy keys are read-only",
+		"Remove the deploy keys with write access, or replace them with read-only keys or a GitHub App.",
+	},
+	"self_hosted_runners": {
+		"No self-hosted runners are registered to the repository",
+		"Remove the self-hosted runners registered to the repository and use GitHub-hosted or organization runner groups instead.",
+	},
+	"unreadable": {
+		"The protection of the repository can be read",
+		"Grant the token administration read access to the repository, or push a first commit to an empty repository.",
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool               `json:"tool"`
+	AutomationDetails *sarifAutomationDetails `json:"automationDetails,omitempty"`
+	Results           []sarifResult           `json:"results"`
+}
+
+type sarifAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	Help             sarifMessage `json:"help"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	LogicalLocations []sarifLogical        `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogical struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes every non-compliant attribute of the report as a SARIF
+// 2.1.0 result, so it can be uploaded to GitHub code scanning or other
+// security dashboards. Compliant attributes are omitted.
+func WriteSARIF(w io.Writer, r Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "repo-protection-sync",
+			InformationURI: "https://github.com/arush-sal/repo-protection-sync",
+		}},
+		Results: []sarifResult{},
+	}
+	if r.RunID != "" {
+		run.AutomationDetails = &sarifAutomationDetails{ID: "repo-protection-sync/" + r.RunID}
+	}
+	for _, id := range append(r.Attributes(), "unreadable") {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: rules[id].description},
+			Help:             sarifMessage{Text: rules[id].remediation},
+		})
+	}
+
+	for _, row := range r.Rows {
+		if row.Error != "" {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    "unreadable",
+				Level:     "warning",
+				Message:   sarifMessage{Text: fmt.Sprintf("The protection of %s/%s could not be read: %s. %s", r.Owner, row.Repo, row.Error, rules["unreadable"].remediation)},
+				Locations: location(r.Owner, row.Repo),
+			})
+			continue
+		}
+		for _, f := range row.Findings {
+			if f.Pass {
+				continue
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID: f.Attribute,
+				Level:  "error",
+				Message: sarifMessage{Text: fmt.Sprintf("%s of %s/%s@%s is %q, expected %q as on %s/%s. %s",
+					f.Attribute, r.Owner, row.Repo, row.Branch, f.Actual, f.Expected, r.Owner, r.Source, rules[f.Attribute].remediation)},
+				Locations: location(r.Owner, row.Repo),
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}})
+}
+
+// location points a result at the repository. Protection has no file, so the
+// repository URL stands in for the artifact.
+func location(owner, repo string) []sarifLocation {
+	return []sarifLocation{{
+		PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: fmt.Sprintf("https://github.com/%s/%s", owner, repo)}},
+		LogicalLocations: []sarifLogical{{Name: repo, FullyQualifiedName: owner + "/" + repo, Kind: "module"}},
+	}}
+}
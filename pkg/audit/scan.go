@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// Scan checks a repository for the features the baseline forbids and returns
+// a finding for each of them. It only reads; violations are reported
+// alongside the protection drift but never removed.
+func Scan(ctx context.Context, client *ghclient.Client, owner, repo string, forbid config.Forbid) ([]Finding, error) {
+	var findings []Finding
+	if forbid.WriteDeployKeys {
+		keys, err := writeDeployKeys(ctx, client.Repositories, owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("listing deploy keys: %w", err)
+		}
+		findings = append(findings, forbidden("write_deploy_keys", keys))
+	}
+	if forbid.SelfHostedRunners {
+		runners, err := selfHostedRunners(ctx, client.Actions, owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("listing self-hosted runners: %w", err)
+		}
+		findings = append(findings, forbidden("self_hosted_runners", runners))
+	}
+	return findings, nil
+}
+
+// forbidden reports the names found for a forbidden feature.
+func forbidden(attribute string, found []string) Finding {
+	actual := "none"
+	if len(found) > 0 {
+		actual = strings.Join(found, ",")
+	}
+	return Finding{Attribute: attribute, Expected: "none", Actual: actual, Pass: len(found) == 0}
+}
+
+// writeDeployKeys returns the titles of the deploy keys with write access.
+func writeDeployKeys(ctx context.Context, client ghclient.KeyLister, owner, repo string) ([]string, error) {
+	var titles []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		keys, resp, err := client.ListKeys(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if !key.GetReadOnly() {
+				titles = append(titles, key.GetTitle())
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return titles, nil
+}
+
+// selfHostedRunners returns the names of the runners registered to the
+// repository. Organization runners shared with it are not included.
+func selfHostedRunners(ctx context.Context, client ghclient.RunnerLister, owner, repo string) ([]string, error) {
+	var names []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		runners, resp, err := client.ListRunners(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, runner := range runners.Runners {
+			names = append(names, runner.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return names, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	runners := mocks.NewMockRunnerLister(ctrl)
+	client := &ghclient.Client{Repositories: repos, Actions: runners}
+
+	repos.EXPECT().ListKeys(gomock.Any(), "octo", "api", gomock.Any()).Return([]*github.Key{
+		{Title: github.String("mirror"), ReadOnly: github.Bool(true)},
+		{Title: github.String("release-bot"), ReadOnly: github.Bool(false)},
+	}, &github.Response{}, nil)
+	runners.EXPECT().ListRunners(gomock.Any(), "octo", "api", gomock.Any()).Return(&github.Runners{}, &github.Response{}, nil)
+
+	findings, err := Scan(context.Background(), client, "octo", "api", config.Forbid{WriteDeployKeys: true, SelfHostedRunners: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Finding{
+		{Attribute: "write_deploy_keys", Expected: "none", Actual: "release-bot", Pass: false},
+		{Attribute: "self_hosted_runners", Expected: "none", Actual: "none", Pass: true},
+	}
+	if len(findings) != len(want) || findings[0] != want[0] || findings[1] != want[1] {
+		t.Errorf("got %+v, want %+v", findings, want)
+	}
+}
+
+func TestWriteTableScannedAttributes(t *testing.T) {
+	findings := append(Evaluate(&types.BranchProtection{}, &types.BranchProtection{}), forbidden("self_hosted_runners", []string{"build-1"}))
+	report := Report{Owner: "octo", Source: "template", Rows: []Row{
+		{Repo: "empty", Error: "empty repository"},
+		{Repo: "api", Branch: "main", Findings: findings},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	header := strings.SplitN(buf.String(), "\n", 2)[0]
+	if !strings.HasSuffix(strings.TrimSpace(header), "self_hosted_runners") {
+		t.Errorf("scanned attribute missing from header %q", header)
+	}
+	if !strings.Contains(buf.String(), "FAIL (build-1)") {
+		t.Errorf("runner not reported:\n%s", buf.String())
+	}
+}
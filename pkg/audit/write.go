@@ -0,0 +1,81 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteTable writes the report as a repository by attribute matrix.
+func WriteTable(w io.Writer, r Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "REPOSITORY")
+	for _, attribute := range r.Attributes() {
+		fmt.Fprintf(tw, "\t%s", attribute)
+	}
+	fmt.Fprintln(tw)
+
+	for _, row := range r.Rows {
+		fmt.Fprint(tw, row.Repo)
+		if row.Error != "" {
+			fmt.Fprintf(tw, "\terror: %s\n", row.Error)
+			continue
+		}
+		for _, f := range row.Findings {
+			if f.Pass {
+				fmt.Fprint(tw, "\tpass")
+			} else {
+				fmt.Fprintf(tw, "\tFAIL (%s)", f.Actual)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as indented JSON, which apply --from-report
+// reads back.
+func WriteJSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the report with one record per repository and attribute,
+// including the expected and actual values.
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"repository", "branch", "attribute", "expected", "actual", "result"})
+	for _, row := range r.Rows {
+		if row.Error != "" {
+			cw.Write([]string{row.Repo, row.Branch, "", "", "", "error: " + row.Error})
+			continue
+		}
+		for _, f := range row.Findings {
+			result := "fail"
+			if f.Pass {
+				result = "pass"
+			}
+			cw.Write([]string{row.Repo, row.Branch, f.Attribute, f.Expected, f.Actual, result})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
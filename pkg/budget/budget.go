@@ -0,0 +1,138 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package budget
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// pageSize is the number of repositories listed per request.
+const pageSize = 100
+
+// Run describes the work of a sync, as far as it determines the number of
+// API requests.
+type Run struct {
+	// Repos is the number of target repositories.
+	Repos int
+	// Rulesets is the number of rulesets synced to every target.
+	Rulesets int
+	// Steps is the number of repository settings modules, each reading and
+	// writing a setting of every target.
+	Steps int
+	// Preflight reads the collaborators of every target.
+	Preflight bool
+	// DropMissingChecks reads the check runs reported on every target.
+	DropMissingChecks bool
+	// KeepUnselected, Interactive and SkipUnchanged read the protection of
+	// every target.
+	KeepUnselected bool
+	Interactive    bool
+	SkipUnchanged  bool
+}
+
+// Estimate is the number of API requests of a run, by kind.
+type Estimate struct {
+	Lists  int
+	Gets   int
+	Writes int
+}
+
+// Total is the number of requests of every kind.
+func (e Estimate) Total() int {
+	return e.Lists + e.Gets + e.Writes
+}
+
+// Add returns the sum of two estimates.
+func (e Estimate) Add(other Estimate) Estimate {
+	return Estimate{Lists: e.Lists + other.Lists, Gets: e.Gets + other.Gets, Writes: e.Writes + other.Writes}
+}
+
+// Listing estimates the requests listing repos repositories.
+func Listing(repos int) Estimate {
+	return Estimate{Lists: repos/pageSize + 1}
+}
+
+// Count estimates the requests of syncing r. It assumes every ruleset and
+// setting has to be written, so the actual run sends at most as many.
+func Count(r Run) Estimate {
+	var perRepo Estimate
+	// Applying the protection, then requiring signed commits
+	perRepo.Writes += 2
+	if r.Rulesets > 0 {
+		perRepo.Lists++
+		perRepo.Writes += r.Rulesets
+	}
+	perRepo.Gets += r.Steps
+	perRepo.Writes += r.Steps
+	if r.Preflight {
+		perRepo.Lists++
+	}
+	if r.DropMissingChecks {
+		// The default branch, then its check runs
+		perRepo.Gets++
+		perRepo.Lists++
+	}
+	if r.KeepUnselected {
+		perRepo.Gets++
+	}
+	if r.Interactive {
+		perRepo.Gets++
+	}
+	if r.SkipUnchanged {
+		perRepo.Gets++
+	}
+
+	return Estimate{
+		Lists:  perRepo.Lists * r.Repos,
+		Gets:   perRepo.Gets * r.Repos,
+		Writes: perRepo.Writes * r.Repos,
+	}
+}
+
+// Resets returns how many times the rate limit has to reset before a run of
+// total requests completes, starting with rate.
+func Resets(total int, rate github.Rate) int {
+	if total <= rate.Remaining || rate.Limit <= 0 {
+		return 0
+	}
+	return (total - rate.Remaining + rate.Limit - 1) / rate.Limit
+}
+
+// Write prints the estimate and how it fits in the rate limit.
+func Write(w io.Writer, e Estimate, rate github.Rate) error {
+	fmt.Fprintf(w, "Estimated API requests: %d (%d lists, %d reads, %d writes)\n", e.Total(), e.Lists, e.Gets, e.Writes)
+	fmt.Fprintf(w, "Rate limit: %d of %d left, resetting at %s\n", rate.Remaining, rate.Limit, rate.Reset.Format(time.Kitchen))
+	var err error
+	if resets := Resets(e.Total(), rate); resets > 0 {
+		_, err = fmt.Fprintf(w, "The run exceeds the rate limit left and will wait for it to reset %d time(s), about %v\n", resets, Wait(resets, rate).Round(time.Minute))
+	} else {
+		_, err = fmt.Fprintln(w, "The run fits in the rate limit left")
+	}
+	return err
+}
+
+// Wait is the time spent waiting for the given number of resets, which are
+// an hour apart after the first.
+func Wait(resets int, rate github.Rate) time.Duration {
+	if resets == 0 {
+		return 0
+	}
+	return time.Until(rate.Reset.Time) + time.Duration(resets-1)*time.Hour
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package budget
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name string
+		run  Run
+		want Estimate
+	}{
+		{"protection only", Run{Repos: 10}, Estimate{Writes: 20}},
+		{"rulesets", Run{Repos: 10, Rulesets: 2}, Estimate{Lists: 10, Writes: 40}},
+		{"settings", Run{Repos: 3, Steps: 2}, Estimate{Gets: 6, Writes: 12}},
+		{"reads", Run{Repos: 2, Preflight: true, DropMissingChecks: true, KeepUnselected: true, Interactive: true}, Estimate{Lists: 4, Gets: 6, Writes: 4}},
+		{"no repos", Run{Rulesets: 3, Steps: 1}, Estimate{}},
+	}
+	for _, tt := range tests {
+		if got := Count(tt.run); got != tt.want {
+			t.Errorf("%s: Count() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestListing(t *testing.T) {
+	for repos, want := range map[int]int{0: 1, 99: 1, 100: 2, 250: 3} {
+		if got := Listing(repos).Lists; got != want {
+			t.Errorf("Listing(%d) = %d lists, want %d", repos, got, want)
+		}
+	}
+}
+
+func TestResets(t *testing.T) {
+	rate := github.Rate{Limit: 5000, Remaining: 1000}
+	tests := []struct {
+		total int
+		want  int
+	}{
+		{500, 0},
+		{1000, 0},
+		{1001, 1},
+		{6000, 1},
+		{6001, 2},
+	}
+	for _, tt := range tests {
+		if got := Resets(tt.total, rate); got != tt.want {
+			t.Errorf("Resets(%d) = %d, want %d", tt.total, got, tt.want)
+		}
+	}
+	if got := Resets(10, github.Rate{}); got != 0 {
+		t.Errorf("Resets() without a limit = %d, want 0", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	reset := github.Timestamp{Time: time.Now().Add(30 * time.Minute)}
+	tests := []struct {
+		remaining int
+		want      string
+	}{
+		{5000, "fits in the rate limit left"},
+		{10, "reset 1 time(s)"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		rate := github.Rate{Limit: 5000, Remaining: tt.remaining, Reset: reset}
+		if err := Write(&buf, Estimate{Lists: 1, Gets: 2, Writes: 30}, rate); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "Estimated API requests: 33 (1 lists, 2 reads, 30 writes)") || !strings.Contains(out, tt.want) {
+			t.Errorf("Write() with %d left = %q, want it to contain %q", tt.remaining, out, tt.want)
+		}
+	}
+}
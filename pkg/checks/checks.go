@@ -0,0 +1,146 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package checks
+
+import (
+	"context"
+	"log"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// Transform returns a request transform that renames the required status
+// check contexts of each target according to cfg and, when enabled, drops
+// the contexts that were not reported on the target's default branch.
+func Transform(client *ghclient.Client, owner string, cfg config.StatusChecks) func(context.Context, *github.Repository, *github.ProtectionRequest) error {
+	return func(ctx context.Context, repo *github.Repository, req *github.ProtectionRequest) error {
+		rsc := req.RequiredStatusChecks
+		if rsc == nil {
+			return nil
+		}
+
+		mapped := Remap(rsc, cfg.ContextMapFor(repo.GetName()))
+
+		if cfg.DropMissingChecks {
+			observed, err := Observed(ctx, client.Repositories, client.Checks, owner, repo.GetName(), repo.GetDefaultBranch())
+			if err != nil {
+				return err
+			}
+			var dropped []string
+			mapped, dropped = Filter(mapped, observed)
+			for _, name := range dropped {
+				log.Printf("Dropping required status check %q from %s: not reported on %s\n", name, repo.GetName(), repo.GetDefaultBranch())
+			}
+		}
+
+		if len(mapped.Contexts) == 0 && len(mapped.Checks) == 0 {
+			log.Printf("No required status checks left for %s, not requiring any\n", repo.GetName())
+			req.RequiredStatusChecks = nil
+			return nil
+		}
+		req.RequiredStatusChecks = mapped
+		return nil
+	}
+}
+
+// Remap returns a copy of rsc with its contexts renamed according to mapping.
+func Remap(rsc *github.RequiredStatusChecks, mapping map[string]string) *github.RequiredStatusChecks {
+	rename := func(name string) string {
+		if to, ok := mapping[name]; ok {
+			return to
+		}
+		return name
+	}
+
+	out := &github.RequiredStatusChecks{Strict: rsc.Strict}
+	for _, name := range rsc.Contexts {
+		out.Contexts = append(out.Contexts, rename(name))
+	}
+	for _, check := range rsc.Checks {
+		renamed := rename(check.Context)
+		mapped := &github.RequiredStatusCheck{Context: renamed}
+		// the app that reports a renamed check is likely a different one
+		if renamed == check.Context {
+			mapped.AppID = check.AppID
+		}
+		out.Checks = append(out.Checks, mapped)
+	}
+	return out
+}
+
+// Filter splits the contexts of rsc into those present in observed, which
+// are returned as a copy, and the dropped ones.
+func Filter(rsc *github.RequiredStatusChecks, observed map[string]bool) (*github.RequiredStatusChecks, []string) {
+	var dropped []string
+	out := &github.RequiredStatusChecks{Strict: rsc.Strict}
+	for _, name := range rsc.Contexts {
+		if observed[name] {
+			out.Contexts = append(out.Contexts, name)
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+	for _, check := range rsc.Checks {
+		if observed[check.Context] {
+			out.Checks = append(out.Checks, check)
+		} else if !contains(dropped, check.Context) {
+			dropped = append(dropped, check.Context)
+		}
+	}
+	return out, dropped
+}
+
+// Observed returns the names of the check runs and commit statuses recently
+// reported for ref.
+func Observed(ctx context.Context, statuses ghclient.StatusReader, checks ghclient.CheckRunLister, owner, repo, ref string) (map[string]bool, error) {
+	observed := make(map[string]bool)
+
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := checks.ListCheckRunsForRef(ctx, owner, repo, ref, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs.CheckRuns {
+			observed[run.GetName()] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	combined, _, err := statuses.GetCombinedStatus(ctx, owner, repo, ref, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range combined.Statuses {
+		observed[status.GetContext()] = true
+	}
+
+	return observed, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
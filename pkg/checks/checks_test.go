@@ -0,0 +1,98 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package checks
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+func TestRemap(t *testing.T) {
+	src := &github.RequiredStatusChecks{
+		Strict:   true,
+		Contexts: []string{"template-ci", "lint"},
+		Checks:   []*github.RequiredStatusCheck{{Context: "template-ci", AppID: github.Int64(15368)}, {Context: "lint", AppID: github.Int64(1)}},
+	}
+
+	got := Remap(src, map[string]string{"template-ci": "build"})
+
+	if !reflect.DeepEqual(got.Contexts, []string{"build", "lint"}) {
+		t.Errorf("got contexts %v", got.Contexts)
+	}
+	if got.Checks[0].Context != "build" || got.Checks[0].AppID != nil {
+		t.Errorf("renamed check kept its app: %+v", got.Checks[0])
+	}
+	if got.Checks[1].AppID == nil || *got.Checks[1].AppID != 1 {
+		t.Errorf("unchanged check lost its app: %+v", got.Checks[1])
+	}
+	if src.Contexts[0] != "template-ci" {
+		t.Error("source contexts were modified in place")
+	}
+}
+
+func TestTransformDropsMissingChecks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	runs := mocks.NewMockCheckRunLister(ctrl)
+	client := &ghclient.Client{Repositories: repos, Checks: runs}
+
+	runs.EXPECT().ListCheckRunsForRef(gomock.Any(), "octo", "target", "main", gomock.Any()).
+		Return(&github.ListCheckRunsResults{CheckRuns: []*github.CheckRun{{Name: github.String("build")}}}, okResponse(), nil)
+	repos.EXPECT().GetCombinedStatus(gomock.Any(), "octo", "target", "main", gomock.Any()).
+		Return(&github.CombinedStatus{Statuses: []*github.RepoStatus{{Context: github.String("ci/jenkins")}}}, okResponse(), nil)
+
+	cfg := config.StatusChecks{
+		ContextMap:        map[string]string{"template-ci": "build"},
+		DropMissingChecks: true,
+	}
+	req := &github.ProtectionRequest{RequiredStatusChecks: &github.RequiredStatusChecks{
+		Strict:   true,
+		Contexts: []string{"template-ci", "ci/jenkins", "deploy-preview"},
+	}}
+	repo := &github.Repository{Name: github.String("target"), DefaultBranch: github.String("main")}
+
+	if err := Transform(client, "octo", cfg)(context.Background(), repo, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"build", "ci/jenkins"}; !reflect.DeepEqual(req.RequiredStatusChecks.Contexts, want) {
+		t.Errorf("got contexts %v, want %v", req.RequiredStatusChecks.Contexts, want)
+	}
+}
+
+func TestContextMapFor(t *testing.T) {
+	cfg := config.StatusChecks{
+		ContextMap: map[string]string{"a": "x", "b": "y"},
+		Repos:      map[string]config.RepoStatusChecks{"special": {ContextMap: map[string]string{"b": "z"}}},
+	}
+	if got := cfg.ContextMapFor("special"); got["a"] != "x" || got["b"] != "z" {
+		t.Errorf("got %v", got)
+	}
+	if got := cfg.ContextMapFor("other"); got["b"] != "y" {
+		t.Errorf("got %v", got)
+	}
+}
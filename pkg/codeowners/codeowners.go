@@ -0,0 +1,151 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package codeowners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+	"github.com/google/go-github/v59/github"
+)
+
+// Branch is the branch proposing a CODEOWNERS file is pushed to.
+const Branch = "repo-protection-sync/codeowners"
+
+// Path is where a proposed CODEOWNERS file is added.
+const Path = ".github/CODEOWNERS"
+
+// Result collects the findings of the CODEOWNERS check.
+type Result struct {
+	// Missing lists the repositories without a CODEOWNERS file, where
+	// requiring code owner reviews has no effect.
+	Missing []string
+	// Invalid holds the syntax errors of the CODEOWNERS file of each
+	// repository whose file has any. Lines with errors are ignored by GitHub.
+	Invalid map[string][]string
+	// Failed lists the repositories that couldn't be checked.
+	Failed []string
+}
+
+// OK reports whether every repository has a valid CODEOWNERS file.
+func (r Result) OK() bool {
+	return len(r.Missing) == 0 && len(r.Invalid) == 0 && len(r.Failed) == 0
+}
+
+// Check verifies that every repository has a CODEOWNERS file GitHub can
+// parse, and logs a report section for every finding.
+func Check(ctx context.Context, client ghclient.ContentManager, owner string, repos []*github.Repository) Result {
+	result := Result{Invalid: make(map[string][]string)}
+	for _, repo := range repos {
+		name := repo.GetName()
+		codeownersErrors, _, err := client.GetCodeownersErrors(ctx, owner, name, nil)
+		if isNotFound(err) {
+			result.Missing = append(result.Missing, name)
+			continue
+		}
+		if err != nil {
+			log.Printf("CODEOWNERS: could not check %s/%s: %v\n", owner, name, err)
+			result.Failed = append(result.Failed, name)
+			continue
+		}
+		for _, e := range codeownersErrors.Errors {
+			result.Invalid[name] = append(result.Invalid[name], fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Kind))
+		}
+	}
+
+	if len(result.Missing) > 0 {
+		log.Printf("CODEOWNERS: %d repositories have no CODEOWNERS file:\n", len(result.Missing))
+		for _, name := range result.Missing {
+			log.Printf("  - %s/%s\n", owner, name)
+		}
+	}
+	if len(result.Invalid) > 0 {
+		log.Printf("CODEOWNERS: %d repositories have errors in their CODEOWNERS file:\n", len(result.Invalid))
+		names := make([]string, 0, len(result.Invalid))
+		for name := range result.Invalid {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			log.Printf("  - %s/%s\n", owner, name)
+			for _, e := range result.Invalid[name] {
+				log.Printf("      %s\n", e)
+			}
+		}
+	}
+	return result
+}
+
+// Propose opens a pull request adding the template as the CODEOWNERS file
+// of a repository. It returns nil without an error when the branch of a
+// previous proposal still exists.
+func Propose(ctx context.Context, client *ghclient.Client, owner string, repo *github.Repository, template []byte) (*github.PullRequest, error) {
+	name, base := repo.GetName(), repo.GetDefaultBranch()
+
+	_, _, err := client.Git.GetRef(ctx, owner, name, "heads/"+Branch)
+	if err == nil {
+		return nil, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+
+	head, _, err := client.Git.GetRef(ctx, owner, name, "heads/"+base)
+	if err != nil {
+		return nil, fmt.Errorf("reading branch %s: %w", base, err)
+	}
+	ref := &github.Reference{Ref: github.String("refs/heads/" + Branch), Object: &github.GitObject{SHA: head.GetObject().SHA}}
+	if _, _, err := client.Git.CreateRef(ctx, owner, name, ref); err != nil {
+		return nil, fmt.Errorf("creating branch %s: %w", Branch, err)
+	}
+
+	_, _, err = client.Repositories.CreateFile(ctx, owner, name, Path, &github.RepositoryContentFileOptions{
+		Message: github.String("Add CODEOWNERS"),
+		Content: template,
+		Branch:  github.String(Branch),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding %s: %w", Path, err)
+	}
+
+	body := "The branch protection of this repository requires reviews from code owners, " +
+		"but the repository has no CODEOWNERS file, so the requirement has no effect.\n\n" +
+		"Please adjust the owners in this file before merging."
+	if id := runid.From(ctx); id != "" {
+		body += fmt.Sprintf("\n\nOpened by run `%s`.", id)
+	}
+	pr, _, err := client.PullRequests.Create(ctx, owner, name, &github.NewPullRequest{
+		Title: github.String("Add CODEOWNERS"),
+		Head:  github.String(Branch),
+		Base:  github.String(base),
+		Body:  &body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening the pull request: %w", err)
+	}
+	return pr, nil
+}
+
+func isNotFound(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package codeowners
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func notFound() error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Not Found"}
+}
+
+func TestCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+
+	targets := []*github.Repository{
+		{Name: github.String("owned")},
+		{Name: github.String("unowned")},
+		{Name: github.String("broken")},
+		{Name: github.String("forbidden")},
+	}
+	repos.EXPECT().GetCodeownersErrors(gomock.Any(), "octo", "owned", nil).Return(&github.CodeownersErrors{}, nil, nil)
+	repos.EXPECT().GetCodeownersErrors(gomock.Any(), "octo", "unowned", nil).Return(nil, nil, notFound())
+	repos.EXPECT().GetCodeownersErrors(gomock.Any(), "octo", "broken", nil).Return(&github.CodeownersErrors{Errors: []*github.CodeownersError{
+		{Path: ".github/CODEOWNERS", Line: 3, Column: 7, Kind: "Unknown owner"},
+	}}, nil, nil)
+	repos.EXPECT().GetCodeownersErrors(gomock.Any(), "octo", "forbidden", nil).Return(nil, nil, errors.New("forbidden"))
+
+	result := Check(context.Background(), repos, "octo", targets)
+	if result.OK() {
+		t.Fatal("expected findings")
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "unowned" {
+		t.Errorf("got missing %v", result.Missing)
+	}
+	if got := result.Invalid["broken"]; len(got) != 1 || got[0] != ".github/CODEOWNERS:3:7: Unknown owner" {
+		t.Errorf("got invalid %v", result.Invalid)
+	}
+	if len(result.Invalid) != 1 || len(result.Failed) != 1 || result.Failed[0] != "forbidden" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestPropose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	git := mocks.NewMockRefManager(ctrl)
+	pulls := mocks.NewMockPullRequestCreator(ctrl)
+	client := &ghclient.Client{Repositories: repos, Git: git, PullRequests: pulls}
+	target := &github.Repository{Name: github.String("api"), DefaultBranch: github.String("main")}
+	template := []byte("* @octo/maintainers\n")
+
+	git.EXPECT().GetRef(gomock.Any(), "octo", "api", "heads/"+Branch).Return(nil, nil, notFound())
+	git.EXPECT().GetRef(gomock.Any(), "octo", "api", "heads/main").
+		Return(&github.Reference{Object: &github.GitObject{SHA: github.String("abc")}}, nil, nil)
+	git.EXPECT().CreateRef(gomock.Any(), "octo", "api", &github.Reference{
+		Ref:    github.String("refs/heads/" + Branch),
+		Object: &github.GitObject{SHA: github.String("abc")},
+	}).Return(&github.Reference{}, nil, nil)
+	repos.EXPECT().CreateFile(gomock.Any(), "octo", "api", Path, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _ string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+			if string(opts.Content) != string(template) || opts.GetBranch() != Branch {
+				t.Errorf("unexpected file options %+v", opts)
+			}
+			return &github.RepositoryContentResponse{}, nil, nil
+		})
+	pulls.EXPECT().Create(gomock.Any(), "octo", "api", gomock.Any()).Return(&github.PullRequest{Number: github.Int(4)}, nil, nil)
+
+	pr, err := Propose(context.Background(), client, "octo", target, template)
+	if err != nil || pr.GetNumber() != 4 {
+		t.Errorf("got %v, %v", pr, err)
+	}
+}
+
+func TestProposeAlreadyProposed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	git := mocks.NewMockRefManager(ctrl)
+	client := &ghclient.Client{Git: git}
+
+	git.EXPECT().GetRef(gomock.Any(), "octo", "api", "heads/"+Branch).Return(&github.Reference{}, nil, nil)
+
+	pr, err := Propose(context.Background(), client, "octo", &github.Repository{Name: github.String("api")}, nil)
+	if pr != nil || err != nil {
+		t.Errorf("got %v, %v, want nothing to be proposed", pr, err)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package color
+
+import (
+	"io"
+	"os"
+)
+
+// ANSI codes of the colors used in terminal output.
+const (
+	Red    = "\x1b[31m"
+	Green  = "\x1b[32m"
+	Yellow = "\x1b[33m"
+	reset  = "\x1b[0m"
+)
+
+// Enabled reports whether output to w should be colored: w is a terminal,
+// NO_COLOR isn't set (https://no-color.org) and TERM isn't dumb.
+func Enabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Wrap colors s with code when on is set.
+func Wrap(on bool, code, s string) string {
+	if !on {
+		return s
+	}
+	return code + s + reset
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package color
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	if Enabled(&bytes.Buffer{}) {
+		t.Error("a buffer isn't a terminal")
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if Enabled(f) {
+		t.Error("a regular file isn't a terminal")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if Enabled(os.Stdout) {
+		t.Error("NO_COLOR must disable color")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if got := Wrap(false, Red, "failed"); got != "failed" {
+		t.Errorf("got %q", got)
+	}
+	if got := Wrap(true, Green, "applied"); got != "\x1b[32mapplied\x1b[0m" {
+		t.Errorf("got %q", got)
+	}
+}
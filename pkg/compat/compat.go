@@ -0,0 +1,109 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+)
+
+// Feature is a part of the sync that depends on the API of the target
+// instance.
+type Feature struct {
+	Name string
+	// MinServer is the oldest GitHub Enterprise Server release whose API
+	// supports the feature. github.com always supports it.
+	MinServer string
+}
+
+// Features are the sync features that GitHub Enterprise Server gained over
+// time.
+var Features = []Feature{
+	{Name: "rulesets", MinServer: "3.11"},
+	{Name: "custom properties", MinServer: "3.13"},
+	{Name: "push protection", MinServer: "3.8"},
+}
+
+// Report describes the instance behind an API and the features it supports.
+type Report struct {
+	// Server is the GitHub Enterprise Server release, empty for github.com.
+	Server    string
+	Supported map[string]bool
+}
+
+// Probe reads the meta endpoint of the API to tell which features the
+// instance supports. GitHub Enterprise Server reports its release in the
+// X-GitHub-Enterprise-Version header of every response.
+func Probe(ctx context.Context, meta ghclient.MetaReader) (*Report, error) {
+	_, resp, err := meta.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading the API meta information: %w", err)
+	}
+	report := &Report{Supported: map[string]bool{}}
+	if resp != nil {
+		report.Server = resp.Header.Get("X-GitHub-Enterprise-Version")
+	}
+	for _, f := range Features {
+		report.Supported[f.Name] = report.Server == "" || atLeast(report.Server, f.MinServer)
+	}
+	return report, nil
+}
+
+// Write prints the instance and whether it supports every feature.
+func Write(w io.Writer, r *Report) error {
+	instance := "github.com"
+	if r.Server != "" {
+		instance = "GitHub Enterprise Server " + r.Server
+	}
+	if _, err := fmt.Fprintf(w, "API: %s\n", instance); err != nil {
+		return err
+	}
+	for _, f := range Features {
+		status := "supported"
+		if !r.Supported[f.Name] {
+			status = "unsupported, requires GitHub Enterprise Server " + f.MinServer
+		}
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", f.Name, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// atLeast reports whether the release version is min or newer, comparing
+// the numeric components in order.
+func atLeast(version, min string) bool {
+	have, want := strings.Split(version, "."), strings.Split(min, ".")
+	for i, w := range want {
+		if i >= len(have) {
+			return false
+		}
+		h, err := strconv.Atoi(have[i])
+		if err != nil {
+			return false
+		}
+		n, _ := strconv.Atoi(w)
+		if h != n {
+			return h > n
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func metaResponse(server string) *github.Response {
+	header := http.Header{}
+	if server != "" {
+		header.Set("X-GitHub-Enterprise-Version", server)
+	}
+	return &github.Response{Response: &http.Response{StatusCode: 200, Header: header}}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		server string
+		want   map[string]bool
+	}{
+		{"", map[string]bool{"rulesets": true, "custom properties": true, "push protection": true}},
+		{"3.14.2", map[string]bool{"rulesets": true, "custom properties": true, "push protection": true}},
+		{"3.11.0", map[string]bool{"rulesets": true, "custom properties": false, "push protection": true}},
+		{"3.7.9", map[string]bool{"rulesets": false, "custom properties": false, "push protection": false}},
+	}
+	for _, tt := range tests {
+		ctrl := gomock.NewController(t)
+		meta := mocks.NewMockMetaReader(ctrl)
+		meta.EXPECT().Get(gomock.Any()).Return(&github.APIMeta{}, metaResponse(tt.server), nil)
+
+		report, err := Probe(context.Background(), meta)
+		if err != nil {
+			t.Fatalf("Probe(%q) failed: %v", tt.server, err)
+		}
+		if report.Server != tt.server {
+			t.Errorf("Probe(%q).Server = %q", tt.server, report.Server)
+		}
+		for name, want := range tt.want {
+			if report.Supported[name] != want {
+				t.Errorf("Probe(%q) supports %s = %v, want %v", tt.server, name, report.Supported[name], want)
+			}
+		}
+	}
+}
+
+func TestProbeError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	meta := mocks.NewMockMetaReader(ctrl)
+	meta.EXPECT().Get(gomock.Any()).Return(nil, nil, errors.New("unreachable"))
+
+	if _, err := Probe(context.Background(), meta); err == nil {
+		t.Error("Probe succeeded on an unreachable API")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := &Report{Server: "3.10.1", Supported: map[string]bool{"push protection": true}}
+	var buf bytes.Buffer
+	if err := Write(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"API: GitHub Enterprise Server 3.10.1",
+		"rulesets: unsupported, requires GitHub Enterprise Server 3.11",
+		"push protection: supported",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Write() = %q, want it to contain %q", out, want)
+		}
+	}
+}
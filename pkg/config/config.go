@@ -0,0 +1,394 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/gitlab"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the optional configuration file of the tool.
+type Config struct {
+	Notifications Notifications `yaml:"notifications"`
+	StatusChecks  StatusChecks  `yaml:"status_checks"`
+	Concurrency   Concurrency   `yaml:"concurrency"`
+	OptOut        OptOut        `yaml:"opt_out"`
+	RepoWebhooks  RepoWebhooks  `yaml:"repo_webhooks"`
+	Forbid        Forbid        `yaml:"forbid"`
+	Actors        Actors        `yaml:"actors"`
+	Rego          Rego          `yaml:"rego"`
+	// BaselineRepo is the source --source auto uses. Defaults to .github.
+	BaselineRepo string `yaml:"baseline_repo"`
+	// UserAgent replaces the repo-protection-sync/<version> User-Agent of the
+	// API requests. The run ID is still appended.
+	UserAgent string `yaml:"user_agent"`
+	// Policies applies several baselines in one run. When empty, the
+	// protection of the --repo source is applied to every target.
+	Policies []Policy `yaml:"policies"`
+}
+
+// Notifications configures where run summaries are posted.
+type Notifications struct {
+	// OnlyOnFailure suppresses notifications for runs without failures.
+	OnlyOnFailure bool      `yaml:"only_on_failure"`
+	Slack         *Slack    `yaml:"slack"`
+	Webhooks      []Webhook `yaml:"webhooks"`
+	// ResultWebhooks receive an event per repository as soon as it is
+	// synced, rather than the summary once the run is over.
+	ResultWebhooks []Webhook `yaml:"result_webhooks"`
+}
+
+// Slack configures a Slack incoming webhook.
+type Slack struct {
+	WebhookURL string `yaml:"webhook_url"`
+	// Channel overrides the default channel of the webhook.
+	Channel string `yaml:"channel"`
+	// RateLimit also posts when a run waits for the rate limit to reset.
+	RateLimit bool `yaml:"rate_limit"`
+}
+
+// Webhook configures a generic HTTP endpoint receiving the run summary as JSON.
+type Webhook struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// StatusChecks adjusts the required status checks copied from the source,
+// whose contexts are often named after workflows that only exist there.
+type StatusChecks struct {
+	// ContextMap renames source contexts for every target.
+	ContextMap map[string]string `yaml:"context_map"`
+	// Repos holds per-target context maps, applied on top of ContextMap.
+	Repos map[string]RepoStatusChecks `yaml:"repos"`
+	// DropMissingChecks drops contexts that haven't been reported on the
+	// target's default branch recently, so protection stays satisfiable.
+	DropMissingChecks bool `yaml:"drop_missing_checks"`
+}
+
+// RepoStatusChecks holds the status check settings of a single target.
+type RepoStatusChecks struct {
+	ContextMap map[string]string `yaml:"context_map"`
+}
+
+// ContextMapFor returns the context map to use for the given target.
+func (s StatusChecks) ContextMapFor(repo string) map[string]string {
+	override, ok := s.Repos[repo]
+	if !ok || len(override.ContextMap) == 0 {
+		return s.ContextMap
+	}
+	merged := make(map[string]string, len(s.ContextMap)+len(override.ContextMap))
+	for from, to := range s.ContextMap {
+		merged[from] = to
+	}
+	for from, to := range override.ContextMap {
+		merged[from] = to
+	}
+	return merged
+}
+
+// Enabled reports whether any status check adjustment is configured.
+func (s StatusChecks) Enabled() bool {
+	return len(s.ContextMap) > 0 || len(s.Repos) > 0 || s.DropMissingChecks
+}
+
+// Actors translates the users, teams and apps of the push and dismissal
+// restrictions of the source to those of the target organization, for a
+// source in another organization given as owner/repo.
+type Actors struct {
+	// Users, Teams and Apps map source logins and slugs to target ones.
+	Users map[string]string `yaml:"users"`
+	Teams map[string]string `yaml:"teams"`
+	Apps  map[string]string `yaml:"apps"`
+	// DropUnknown removes the actors that don't exist in the target
+	// organization instead of failing the run.
+	DropUnknown bool `yaml:"drop_unknown"`
+}
+
+// Mapped reports whether any actor is translated.
+func (a Actors) Mapped() bool {
+	return len(a.Users) > 0 || len(a.Teams) > 0 || len(a.Apps) > 0
+}
+
+// LoadActors reads an actor mapping file with the users, teams and apps
+// sections of Actors.
+func LoadActors(path string) (Actors, error) {
+	return LoadActorsWith(path, os.ReadFile)
+}
+
+// LoadActorsWith is LoadActors reading the file with read, which may verify
+// it first.
+func LoadActorsWith(path string, read func(string) ([]byte, error)) (Actors, error) {
+	var actors Actors
+	data, err := read(path)
+	if err != nil {
+		return actors, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&actors); err != nil && !errors.Is(err, io.EOF) {
+		return actors, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return actors, nil
+}
+
+// Concurrency configures how many repositories are synced in parallel. The
+// worker count scales down linearly between MaxWorkers and MinWorkers as the
+// remaining rate limit drops below ScaleDownBelow, and back up after a reset.
+type Concurrency struct {
+	// MaxWorkers defaults to a tenth of the number of targets.
+	MaxWorkers int `yaml:"max_workers"`
+	// MinWorkers defaults to 1.
+	MinWorkers int `yaml:"min_workers"`
+	// ScaleDownBelow defaults to 1000 remaining requests.
+	ScaleDownBelow int `yaml:"scale_down_below"`
+	// RepoTimeout bounds the sync of a single repository, not counting the
+	// time taken to confirm it with --interactive. Defaults to 5m.
+	RepoTimeout time.Duration `yaml:"repo_timeout"`
+	// AbortAfter stops the run once this many repositories in a row failed
+	// with the same error, such as a 403 for a missing scope. Defaults to
+	// 10; a negative value never aborts.
+	AbortAfter int `yaml:"abort_after"`
+	// MaxWait fails the remaining repositories instead of waiting longer
+	// than this for the rate limit to reset. Zero waits as long as needed.
+	MaxWait time.Duration `yaml:"max_wait"`
+}
+
+// OptOut configures the convention repository owners use to exclude their
+// repository from the sync without editing the central configuration.
+type OptOut struct {
+	// Topic excludes repositories tagged with it. Defaults to no-protection-sync.
+	Topic string `yaml:"topic"`
+	// Property and Value exclude repositories whose custom property Property
+	// is set to Value. Default to protection-sync and disabled.
+	Property string `yaml:"property"`
+	Value    string `yaml:"value"`
+}
+
+// TopicOrDefault returns the opt-out topic.
+func (o OptOut) TopicOrDefault() string {
+	if o.Topic == "" {
+		return "no-protection-sync"
+	}
+	return o.Topic
+}
+
+// PropertyOrDefault returns the opt-out custom property and its value.
+func (o OptOut) PropertyOrDefault() (string, string) {
+	property, value := o.Property, o.Value
+	if property == "" {
+		property = "protection-sync"
+	}
+	if value == "" {
+		value = "disabled"
+	}
+	return property, value
+}
+
+// RepoWebhooks configures the webhooks copied from the source repository.
+type RepoWebhooks struct {
+	// Secrets maps webhook URLs to the secret to set on the targets, since
+	// GitHub never returns the secret of a webhook. Environment variables
+	// in the secrets, such as ${HOOK_SECRET}, are expanded.
+	Secrets map[string]string `yaml:"secrets"`
+}
+
+// Secret returns the expanded secret configured for the webhook URL.
+func (w RepoWebhooks) Secret(url string) (string, bool) {
+	secret, ok := w.Secrets[url]
+	return os.ExpandEnv(secret), ok
+}
+
+// Forbid lists repository features the baseline forbids. The audit reports
+// the repositories using them; nothing is removed.
+type Forbid struct {
+	// WriteDeployKeys forbids deploy keys with write access.
+	WriteDeployKeys bool `yaml:"write_deploy_keys"`
+	// SelfHostedRunners forbids runners registered to the repository.
+	SelfHostedRunners bool `yaml:"self_hosted_runners"`
+}
+
+// Enabled reports whether anything is forbidden.
+func (f Forbid) Enabled() bool {
+	return f.WriteDeployKeys || f.SelfHostedRunners
+}
+
+// Rego delegates the choice of the targets, and of the policy applied to
+// each, to a Rego policy evaluated with the opa command line tool.
+type Rego struct {
+	// Policy is the path of the Rego policy: a file, directory or bundle.
+	Policy string `yaml:"policy"`
+	// Query is the rule evaluating to the decisions, defaulting to
+	// data.repo_protection_sync.decisions.
+	Query string `yaml:"query"`
+	// Binary is the opa executable, looked up on the PATH by default.
+	Binary string `yaml:"binary"`
+}
+
+// Enabled reports whether a Rego policy is configured.
+func (r Rego) Enabled() bool {
+	return r.Policy != ""
+}
+
+// ReadPolicyWith replaces the policy with a private copy read with read,
+// which may verify it first, so opa evaluates the bytes read even when the
+// policy changes in the meantime. Only a single file or bundle can be copied,
+// not a directory. The returned function removes the copy.
+func (r *Rego) ReadPolicyWith(read func(string) ([]byte, error)) (func(), error) {
+	info, err := os.Stat(r.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("the Rego policy %s is a directory, use a single file or bundle", r.Policy)
+	}
+	data, err := read(r.Policy)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "rego-*")
+	if err != nil {
+		return nil, err
+	}
+	// opa tells Rego files from bundles and data files by their name
+	policy := filepath.Join(dir, filepath.Base(r.Policy))
+	if err := os.WriteFile(policy, data, 0o600); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	r.Policy = policy
+	return func() { os.RemoveAll(dir) }, nil
+}
+
+// Policy is a named baseline applied to the repositories its selector matches.
+type Policy struct {
+	Name string `yaml:"name"`
+	// Source is the repository whose protection and rulesets are applied,
+	// given as owner/repo when it is in another organization.
+	Source string `yaml:"source"`
+	// Protection defines the branch protection inline instead of reading it
+	// from Source, in the shape the GitHub API returns branch protection.
+	// In a policy extending one with a source, it overrides the fields of
+	// the protection of the source.
+	Protection map[string]interface{} `yaml:"protection"`
+	// Extends names a policy whose source and protection this one inherits,
+	// overriding the fields it sets. The selector isn't inherited.
+	Extends string `yaml:"extends"`
+	// GitLab is a GitLab protection file, as exported with --format gitlab,
+	// whose protection is read as the inline protection of the policy. A
+	// relative path is relative to the configuration file.
+	GitLab   string   `yaml:"gitlab"`
+	Selector Selector `yaml:"selector"`
+}
+
+// Selector picks the targets of a policy. A repository matches when it
+// satisfies every criterion set; an empty selector matches every repository.
+type Selector struct {
+	// Topics matches repositories tagged with any of the topics.
+	Topics []string `yaml:"topics"`
+	// Properties matches repositories whose custom properties have all the values.
+	Properties map[string]string `yaml:"properties"`
+	// Name matches repository names against a regular expression.
+	Name string `yaml:"name"`
+}
+
+// validate checks the policies for missing or conflicting settings.
+func (c *Config) validate() error {
+	seen := make(map[string]bool, len(c.Policies))
+	for i, p := range c.Policies {
+		if p.Name == "" {
+			return fmt.Errorf("policy %d has no name", i+1)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("policy %q is defined more than once", p.Name)
+		}
+		seen[p.Name] = true
+		if p.Extends == "" && (p.Source == "") == (p.Protection == nil) {
+			return fmt.Errorf("policy %q must set exactly one of source and protection", p.Name)
+		}
+		if _, err := regexp.Compile(p.Selector.Name); err != nil {
+			return fmt.Errorf("policy %q: invalid name selector: %w", p.Name, err)
+		}
+	}
+	return c.resolveExtends()
+}
+
+// importGitLab reads the GitLab protection files of the policies with read
+// into their inline protection, relative to dir.
+func (c *Config) importGitLab(dir string, read func(string) ([]byte, error)) error {
+	for i := range c.Policies {
+		p := &c.Policies[i]
+		if p.GitLab == "" {
+			continue
+		}
+		if p.Protection != nil || (p.Source != "" && p.Extends == "") {
+			return fmt.Errorf("policy %q must set only one of source, protection and gitlab", p.Name)
+		}
+		path := p.GitLab
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		data, err := read(path)
+		if err != nil {
+			return fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		project, err := gitlab.Parse(path, data)
+		if err != nil {
+			return fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		if p.Protection, err = gitlab.Protection(project); err != nil {
+			return fmt.Errorf("policy %q: %s: %w", p.Name, p.GitLab, err)
+		}
+	}
+	return nil
+}
+
+// Load reads the configuration file at path. Unknown fields are rejected so
+// typos don't silently disable a setting.
+func Load(path string) (*Config, error) {
+	return LoadWith(path, os.ReadFile)
+}
+
+// LoadWith is Load reading the configuration file and the GitLab files of its
+// policies with read, which may verify them first.
+func LoadWith(path string, read func(string) ([]byte, error)) (*Config, error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(Config)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := cfg.importGitLab(filepath.Dir(path), read); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
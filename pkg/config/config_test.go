@@ -0,0 +1,235 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+notifications:
+  only_on_failure: true
+  slack:
+    webhook_url: https://hooks.slack.com/services/T/B/X
+  webhooks:
+    - url: https://platform.example.com/hooks/protection
+      headers:
+        Authorization: Bearer abc
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := cfg.Notifications
+	if !n.OnlyOnFailure || n.Slack == nil || n.Slack.WebhookURL == "" {
+		t.Errorf("notifications not parsed: %+v", n)
+	}
+	if len(n.Webhooks) != 1 || n.Webhooks[0].Headers["Authorization"] != "Bearer abc" {
+		t.Errorf("webhooks not parsed: %+v", n.Webhooks)
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	if _, err := Load(writeConfig(t, "")); err != nil {
+		t.Errorf("unexpected error for an empty file: %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	if _, err := Load(writeConfig(t, "notifcations: {}\n")); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestLoadPolicies(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+policies:
+  - name: services
+    source: service-template
+    selector:
+      topics: [service]
+      properties:
+        tier: "1"
+  - name: libraries
+    protection:
+      enforce_admins:
+        enabled: true
+    selector:
+      name: ^lib-
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Policies) != 2 || cfg.Policies[0].Selector.Properties["tier"] != "1" || cfg.Policies[1].Protection == nil {
+		t.Errorf("policies not parsed: %+v", cfg.Policies)
+	}
+
+	invalid := []string{
+		"policies:\n  - source: a\n",
+		"policies:\n  - name: a\n",
+		"policies:\n  - name: a\n    source: a\n    protection: {}\n",
+		"policies:\n  - name: a\n    source: a\n  - name: a\n    source: b\n",
+		"policies:\n  - name: a\n    source: a\n    selector:\n      name: \"(\"\n",
+	}
+	for _, content := range invalid {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("expected an error for:\n%s", content)
+		}
+	}
+}
+
+func TestLoadGitLabPolicy(t *testing.T) {
+	path := writeConfig(t, `
+policies:
+  - name: migrated
+    gitlab: gitlab.json
+  - name: conflicting
+    source: baseline
+    gitlab: gitlab.json
+`)
+	gitlabFile := filepath.Join(filepath.Dir(path), "gitlab.json")
+	project := `{"protected_branches": [{"name": "main", "push_access_level": 0, "merge_access_level": 30, "unprotect_access_level": 40}],
+		"approval_rules": [{"name": "Reviews", "approvals_required": 2}]}`
+	if err := os.WriteFile(gitlabFile, []byte(project), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), `"conflicting" must set only one of`) {
+		t.Fatalf("Load() = %v, want the conflicting policy rejected", err)
+	}
+
+	if err := os.WriteFile(path, []byte("policies:\n  - name: migrated\n    gitlab: gitlab.json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviews, _ := cfg.Policies[0].Protection["required_pull_request_reviews"].(map[string]interface{})
+	if reviews["required_approving_review_count"] != 2.0 {
+		t.Errorf("protection read from the GitLab file = %v", cfg.Policies[0].Protection)
+	}
+}
+
+func TestLoadWith(t *testing.T) {
+	path := writeConfig(t, "policies:\n  - name: migrated\n    gitlab: gitlab.json\n")
+	gitlabFile := filepath.Join(filepath.Dir(path), "gitlab.json")
+	project := `{"protected_branches": [{"name": "main", "push_access_level": 40, "merge_access_level": 30, "unprotect_access_level": 40}]}`
+
+	var read []string
+	_, err := LoadWith(path, func(file string) ([]byte, error) {
+		read = append(read, file)
+		if file == gitlabFile {
+			return []byte(project), nil
+		}
+		return os.ReadFile(file)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The GitLab file is only read through read, it isn't on disk
+	if len(read) != 2 || read[0] != path || read[1] != gitlabFile {
+		t.Errorf("read %v, want the configuration and the GitLab file", read)
+	}
+
+	rejected := errors.New("signature rejected")
+	if _, err := LoadWith(path, func(string) ([]byte, error) { return nil, rejected }); !errors.Is(err, rejected) {
+		t.Errorf("LoadWith() = %v, want the read error", err)
+	}
+}
+
+// signedOnly reads a file like a verified read, failing when it isn't the
+// signed content.
+func signedOnly(signed string) func(string) ([]byte, error) {
+	return func(file string) ([]byte, error) {
+		data, err := os.ReadFile(file)
+		if err == nil && string(data) != signed {
+			err = errors.New("signature rejected")
+		}
+		return data, err
+	}
+}
+
+func TestRegoReadPolicyWith(t *testing.T) {
+	const signed = "package repo_protection_sync\n\ndecisions := {}\n"
+	file := filepath.Join(t.TempDir(), "sync.rego")
+	if err := os.WriteFile(file, []byte(signed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rego := Rego{Policy: file}
+	cleanup, err := rego.ReadPolicyWith(signedOnly(signed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if rego.Policy == file || filepath.Base(rego.Policy) != "sync.rego" {
+		t.Errorf("got policy %s, want a copy of %s", rego.Policy, file)
+	}
+	// The copy keeps the verified bytes when the policy is tampered with
+	if err := os.WriteFile(file, []byte("package repo_protection_sync\n\ndecisions := {\"api\": {\"sync\": false}}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(rego.Policy); string(data) != signed {
+		t.Errorf("the copy reads %q, want the verified policy", data)
+	}
+
+	tampered := Rego{Policy: file}
+	if _, err := tampered.ReadPolicyWith(signedOnly(signed)); err == nil {
+		t.Error("ReadPolicyWith() accepted a tampered policy")
+	}
+	if tampered.Policy != file {
+		t.Errorf("got policy %s after a failed read, want it unchanged", tampered.Policy)
+	}
+
+	dir := Rego{Policy: filepath.Dir(file)}
+	if _, err := dir.ReadPolicyWith(os.ReadFile); err == nil {
+		t.Error("ReadPolicyWith() accepted a directory")
+	}
+}
+
+func TestLoadActorsWith(t *testing.T) {
+	const signed = "users:\n  alice: alice-corp\n"
+	path := writeConfig(t, signed)
+	actors, err := LoadActorsWith(path, signedOnly(signed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actors.Users["alice"] != "alice-corp" {
+		t.Errorf("got users %v", actors.Users)
+	}
+
+	if err := os.WriteFile(path, []byte("users:\n  alice: mallory\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadActorsWith(path, signedOnly(signed)); err == nil {
+		t.Error("LoadActorsWith() accepted a tampered mapping")
+	}
+}
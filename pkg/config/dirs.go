@@ -0,0 +1,69 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// appDir is the directory of the tool within the per-user config and cache
+// directories of the operating system.
+const appDir = "repo-protection-sync"
+
+// DefaultFile returns the path of the configuration file read when none is
+// given: config.yaml in the user config directory, such as
+// ~/.config/repo-protection-sync on Linux, ~/Library/Application Support on
+// macOS and %AppData% on Windows. The path is empty when the file doesn't
+// exist.
+func DefaultFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		// No home directory, as in some containers, means no default file
+		return "", nil
+	}
+	path := filepath.Join(dir, appDir, "config.yaml")
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// CacheDir returns the directory for the caches of the tool in the user
+// cache directory of the operating system.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appDir), nil
+}
+
+// SnapshotDir returns the directory for the snapshots the tool takes before
+// changing a repository. It is in the user config directory rather than the
+// cache directory, as the snapshots can't be recreated.
+func SnapshotDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appDir, "snapshots"), nil
+}
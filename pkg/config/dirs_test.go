@@ -0,0 +1,80 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// setUserDirs points the user config and cache directories at dir.
+func setUserDirs(t *testing.T, dir string) {
+	switch runtime.GOOS {
+	case "windows":
+		t.Setenv("AppData", dir)
+		t.Setenv("LocalAppData", dir)
+	case "darwin", "ios":
+		t.Setenv("HOME", dir)
+	default:
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		t.Setenv("XDG_CACHE_HOME", dir)
+	}
+}
+
+func TestDefaultFile(t *testing.T) {
+	home := t.TempDir()
+	setUserDirs(t, home)
+
+	if path, err := DefaultFile(); path != "" || err != nil {
+		t.Errorf("got %q, %v; want no default file", path, err)
+	}
+
+	dir, _ := os.UserConfigDir()
+	want := filepath.Join(dir, appDir, "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(want), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(want, []byte("policies: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := DefaultFile(); path != want || err != nil {
+		t.Errorf("got %q, %v; want %q", path, err, want)
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	setUserDirs(t, t.TempDir())
+	dir, err := CacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(dir) != appDir {
+		t.Errorf("got %q, want a directory of the tool", dir)
+	}
+}
+
+func TestSnapshotDir(t *testing.T) {
+	setUserDirs(t, t.TempDir())
+	dir, err := SnapshotDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(filepath.Dir(dir)) != appDir {
+		t.Errorf("got %q, want a directory of the tool", dir)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import "fmt"
+
+// resolveExtends replaces the source and protection of every policy
+// extending another with the inherited ones, merged with its own. Bases are
+// resolved first, so a policy may extend one extending a third.
+func (c *Config) resolveExtends() error {
+	index := make(map[string]int, len(c.Policies))
+	for i, p := range c.Policies {
+		index[p.Name] = i
+	}
+
+	resolved := make(map[int]bool, len(c.Policies))
+	visiting := make(map[int]bool)
+	var resolve func(i int) error
+	resolve = func(i int) error {
+		p := &c.Policies[i]
+		if p.Extends == "" || resolved[i] {
+			return nil
+		}
+		if visiting[i] {
+			return fmt.Errorf("policy %q extends itself through %q", p.Name, p.Extends)
+		}
+		visiting[i] = true
+		base, ok := index[p.Extends]
+		if !ok {
+			return fmt.Errorf("policy %q extends the unknown policy %q", p.Name, p.Extends)
+		}
+		if err := resolve(base); err != nil {
+			return err
+		}
+
+		if p.Source == "" {
+			p.Source = c.Policies[base].Source
+		}
+		p.Protection = MergeProtection(c.Policies[base].Protection, p.Protection)
+		resolved[i] = true
+		return nil
+	}
+
+	for i := range c.Policies {
+		if err := resolve(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeProtection returns the protection base with the fields of override
+// replacing its own. Nested objects are merged the same way; lists and other
+// values are replaced as a whole.
+func MergeProtection(base, override map[string]interface{}) map[string]interface{} {
+	if base == nil && override == nil {
+		return nil
+	}
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		nestedBase, baseIsMap := merged[key].(map[string]interface{})
+		nested, isMap := value.(map[string]interface{})
+		if baseIsMap && isMap {
+			merged[key] = MergeProtection(nestedBase, nested)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadExtends(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+policies:
+  - name: docs
+    extends: strict
+    protection:
+      required_pull_request_reviews:
+        required_approving_review_count: 1
+      enforce_admins:
+        enabled: false
+    selector:
+      topics: [docs]
+  - name: strict
+    protection:
+      required_pull_request_reviews:
+        required_approving_review_count: 2
+        require_code_owner_reviews: true
+      enforce_admins:
+        enabled: true
+      required_linear_history:
+        enabled: true
+  - name: services
+    source: service-template
+  - name: canary-services
+    extends: services
+    protection:
+      allow_force_pushes:
+        enabled: false
+  - name: canary-copy
+    extends: canary-services
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	docs := cfg.Policies[0]
+	want := map[string]interface{}{
+		"required_pull_request_reviews": map[string]interface{}{
+			"required_approving_review_count": 1,
+			"require_code_owner_reviews":      true,
+		},
+		"enforce_admins":          map[string]interface{}{"enabled": false},
+		"required_linear_history": map[string]interface{}{"enabled": true},
+	}
+	if !reflect.DeepEqual(docs.Protection, want) {
+		t.Errorf("docs protection = %v, want %v", docs.Protection, want)
+	}
+	if docs.Source != "" || len(docs.Selector.Topics) != 1 {
+		t.Errorf("docs = %+v, want its own selector and no source", docs)
+	}
+	if cfg.Policies[1].Protection["required_pull_request_reviews"].(map[string]interface{})["required_approving_review_count"] != 2 {
+		t.Errorf("the base was modified: %v", cfg.Policies[1].Protection)
+	}
+
+	for _, p := range cfg.Policies[3:] {
+		if p.Source != "service-template" || p.Protection["allow_force_pushes"] == nil {
+			t.Errorf("%s = %+v, want the source of services and the overrides of canary-services", p.Name, p)
+		}
+	}
+
+	invalid := []string{
+		"policies:\n  - name: a\n    extends: b\n",
+		"policies:\n  - name: a\n    extends: b\n  - name: b\n    extends: a\n",
+		"policies:\n  - name: a\n    extends: a\n",
+	}
+	for _, content := range invalid {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("expected an error for:\n%s", content)
+		}
+	}
+}
+
+func TestMergeProtection(t *testing.T) {
+	if got := MergeProtection(nil, nil); got != nil {
+		t.Errorf("MergeProtection(nil, nil) = %v, want nil", got)
+	}
+	base := map[string]interface{}{"required_status_checks": map[string]interface{}{"contexts": []interface{}{"ci", "lint"}, "strict": true}}
+	override := map[string]interface{}{"required_status_checks": map[string]interface{}{"contexts": []interface{}{"ci"}}}
+	want := map[string]interface{}{"required_status_checks": map[string]interface{}{"contexts": []interface{}{"ci"}, "strict": true}}
+	if got := MergeProtection(base, override); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeProtection() = %v, want %v", got, want)
+	}
+}
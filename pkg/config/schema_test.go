@@ -0,0 +1,94 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// schemaNode is the part of a JSON Schema the test walks.
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	Items                *schemaNode            `json:"items"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Defs                 map[string]*schemaNode `json:"$defs"`
+}
+
+// TestSchemaMatchesConfig checks that the schema describes every field of
+// the configuration, and no field it doesn't have.
+func TestSchemaMatchesConfig(t *testing.T) {
+	var root schemaNode
+	if err := json.Unmarshal(Schema, &root); err != nil {
+		t.Fatalf("the schema isn't valid JSON: %v", err)
+	}
+	resolve := func(n *schemaNode) *schemaNode {
+		if name, ok := strings.CutPrefix(n.Ref, "#/$defs/"); ok {
+			return root.Defs[name]
+		}
+		return n
+	}
+
+	var walk func(path string, typ reflect.Type, node *schemaNode)
+	walk = func(path string, typ reflect.Type, node *schemaNode) {
+		node = resolve(node)
+		if node == nil {
+			t.Errorf("%s: dangling reference", path)
+			return
+		}
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		switch {
+		case typ == reflect.TypeOf(time.Duration(0)):
+		case typ.Kind() == reflect.Struct:
+			fields := map[string]bool{}
+			for i := 0; i < typ.NumField(); i++ {
+				name := strings.Split(typ.Field(i).Tag.Get("yaml"), ",")[0]
+				fields[name] = true
+				child, ok := node.Properties[name]
+				if !ok {
+					t.Errorf("%s.%s is missing from the schema", path, name)
+					continue
+				}
+				walk(path+"."+name, typ.Field(i).Type, child)
+			}
+			for name := range node.Properties {
+				if !fields[name] {
+					t.Errorf("%s.%s is in the schema but not in the configuration", path, name)
+				}
+			}
+			if string(node.AdditionalProperties) != "false" {
+				t.Errorf("%s allows unknown fields in the schema", path)
+			}
+		case typ.Kind() == reflect.Slice && node.Items != nil:
+			walk(path+"[]", typ.Elem(), node.Items)
+		case typ.Kind() == reflect.Map && typ.Elem().Kind() == reflect.Struct:
+			var values schemaNode
+			if err := json.Unmarshal(node.AdditionalProperties, &values); err != nil {
+				t.Errorf("%s: the schema doesn't describe the values: %v", path, err)
+				return
+			}
+			walk(path+"[]", typ.Elem(), &values)
+		}
+	}
+	walk("config", reflect.TypeOf(Config{}), &root)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+// SyncFunc runs a sync, returning the summary of every policy applied.
+type SyncFunc func(ctx context.Context) ([]notify.Summary, error)
+
+// Run is the outcome of a sync run by the daemon.
+type Run struct {
+	Started   time.Time        `json:"started"`
+	Finished  time.Time        `json:"finished"`
+	Error     string           `json:"error,omitempty"`
+	Summaries []notify.Summary `json:"summaries"`
+}
+
+// Status is reported by the /status endpoint.
+type Status struct {
+	Started  time.Time `json:"started"`
+	Interval string    `json:"interval"`
+	Runs     int       `json:"runs"`
+	// Syncing is set while a sync runs.
+	Syncing bool `json:"syncing"`
+	LastRun *Run `json:"last_run,omitempty"`
+	// LastSuccess is when the last sync without an error finished.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// Daemon runs a sync at a fixed interval and reports on it over HTTP, for
+// deployments as a reconciler with liveness and readiness probes.
+type Daemon struct {
+	Interval time.Duration
+	Sync     SyncFunc
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New returns a daemon running sync every interval.
+func New(interval time.Duration, sync SyncFunc) *Daemon {
+	return &Daemon{Interval: interval, Sync: sync, status: Status{Started: time.Now(), Interval: interval.String()}}
+}
+
+// Run syncs right away and then every interval, until ctx is done. A sync
+// isn't interrupted by the next tick; ticks during a sync are dropped.
+func (d *Daemon) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		d.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Daemon) runOnce(ctx context.Context) {
+	d.mu.Lock()
+	d.status.Syncing = true
+	d.mu.Unlock()
+
+	run := &Run{Started: time.Now()}
+	summaries, err := d.Sync(ctx)
+	run.Finished = time.Now()
+	run.Summaries = summaries
+	if err != nil {
+		run.Error = err.Error()
+		log.Printf("Sync failed: %v\n", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.Syncing = false
+	d.status.Runs++
+	d.status.LastRun = run
+	if err == nil {
+		finished := run.Finished
+		d.status.LastSuccess = &finished
+	}
+}
+
+// Status returns a copy of the status of the daemon.
+func (d *Daemon) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status
+}
+
+// Handler serves the probes and the status:
+//   - /healthz answers 200 as long as syncs keep completing, and 503 once a
+//     sync has run for more than three intervals, so a stuck daemon is
+//     restarted;
+//   - /readyz answers 200 once a sync completed without an error;
+//   - /status reports the last sync as JSON.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		status := d.Status()
+		since := status.Started
+		if status.LastRun != nil {
+			since = status.LastRun.Finished
+		}
+		if status.Syncing && time.Since(since) > 3*d.Interval {
+			http.Error(w, "sync stuck since "+since.Format(time.RFC3339), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if d.Status().LastSuccess == nil {
+			http.Error(w, "no successful sync yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(d.Status())
+	})
+	return mux
+}
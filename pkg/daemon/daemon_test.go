@@ -0,0 +1,107 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestDaemon(t *testing.T) {
+	fail := true
+	d := New(time.Hour, func(ctx context.Context) ([]notify.Summary, error) {
+		if fail {
+			return nil, errors.New("preflight: bad credentials")
+		}
+		return []notify.Summary{{Owner: "octo", Source: "baseline", Targets: 3}}, nil
+	})
+	h := d.Handler()
+
+	if rec := get(t, h, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("/healthz = %d before the first sync", rec.Code)
+	}
+	if rec := get(t, h, "/readyz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz = %d before the first sync", rec.Code)
+	}
+
+	d.runOnce(context.Background())
+	if rec := get(t, h, "/readyz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz = %d after a failed sync", rec.Code)
+	}
+
+	fail = false
+	d.runOnce(context.Background())
+	if rec := get(t, h, "/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("/readyz = %d after a successful sync", rec.Code)
+	}
+
+	var status Status
+	rec := get(t, h, "/status")
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("/status isn't JSON: %v", err)
+	}
+	if status.Runs != 2 || status.LastRun == nil || status.LastRun.Error != "" || len(status.LastRun.Summaries) != 1 || status.LastSuccess == nil || status.Interval != "1h0m0s" {
+		t.Errorf("/status = %s", rec.Body)
+	}
+}
+
+func TestHealthzStuck(t *testing.T) {
+	d := New(time.Minute, nil)
+	d.status.Started = time.Now().Add(-time.Hour)
+	d.status.Syncing = true
+	if rec := get(t, d.Handler(), "/healthz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/healthz = %d with a sync running for an hour", rec.Code)
+	}
+}
+
+func TestRunStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	d := New(time.Millisecond, func(context.Context) ([]notify.Summary, error) {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		return nil, nil
+	})
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() didn't return once the context was done")
+	}
+	if d.Status().Runs != 3 {
+		t.Errorf("ran %d syncs, want 3", d.Status().Runs)
+	}
+}
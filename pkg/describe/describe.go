@@ -0,0 +1,303 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package describe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Requirement is a rule the default branch is subject to, along with the
+// branch protection and rulesets imposing it.
+type Requirement struct {
+	Name    string   `json:"name"`
+	Value   string   `json:"value"`
+	Sources []string `json:"sources"`
+}
+
+// Effective merges the branch protection and the active rulesets applying
+// to the default branch into the requirements a pull request or push to it
+// must meet. When several sources impose a requirement, the strictest value
+// wins, as GitHub enforces every layer.
+func Effective(rp *types.RepoProtection) []Requirement {
+	e := &effective{index: make(map[string]int)}
+	if bp := rp.BranchProtection; bp != nil {
+		e.protection(bp)
+	}
+	for _, rs := range rulesets(rp) {
+		if rs.Enforcement == "active" && Applies(rs, rp.Branch) {
+			e.ruleset(rs)
+		}
+	}
+	return e.requirements
+}
+
+// Applies reports whether a ruleset targets branch, the default branch.
+func Applies(rs *github.Ruleset, branch string) bool {
+	if target := rs.GetTarget(); target != "" && target != "branch" {
+		return false
+	}
+	if rs.Conditions == nil || rs.Conditions.RefName == nil {
+		return true
+	}
+	return matchesAny(rs.Conditions.RefName.Include, branch) && !matchesAny(rs.Conditions.RefName.Exclude, branch)
+}
+
+func matchesAny(patterns []string, branch string) bool {
+	for _, pattern := range patterns {
+		switch pattern {
+		case "~ALL", "~DEFAULT_BRANCH":
+			return true
+		}
+		pattern = strings.ReplaceAll(strings.TrimPrefix(pattern, "refs/heads/"), "**", "*")
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesets returns the rulesets of the repository followed by those it
+// inherits.
+func rulesets(rp *types.RepoProtection) []*github.Ruleset {
+	return append(append([]*github.Ruleset(nil), rp.Rulesets...), rp.InheritedRulesets...)
+}
+
+// effective accumulates requirements in the order they are first imposed.
+type effective struct {
+	requirements []Requirement
+	index        map[string]int
+	ranks        []int
+}
+
+// add records that source imposes a requirement. A value of a higher rank,
+// such as more required approvals, replaces the current one.
+func (e *effective) add(name, value string, rank int, source string) {
+	key := name
+	if name == statusCheck {
+		key += "\x00" + value
+	}
+	i, ok := e.index[key]
+	if !ok {
+		e.index[key] = len(e.requirements)
+		e.requirements = append(e.requirements, Requirement{Name: name, Value: value, Sources: []string{source}})
+		e.ranks = append(e.ranks, rank)
+		return
+	}
+	r := &e.requirements[i]
+	if rank > e.ranks[i] {
+		r.Value, e.ranks[i] = value, rank
+	}
+	r.Sources = append(r.Sources, source)
+}
+
+func (e *effective) require(name, source string) {
+	e.add(name, "yes", 0, source)
+}
+
+const statusCheck = "Status check"
+
+// protection adds the requirements of classic branch protection.
+func (e *effective) protection(bp *types.BranchProtection) {
+	const source = "branch protection"
+	if r := bp.Reviews; r != nil {
+		e.require("Pull request before merging", source)
+		e.add("Required approvals", strconv.Itoa(r.RequiredApprovals), r.RequiredApprovals, source)
+		if r.CodeOwners {
+			e.require("Code owner review", source)
+		}
+		if r.DismissStale {
+			e.require("Stale approvals dismissed", source)
+		}
+		if r.LastPushApproval {
+			e.require("Approval of the last push", source)
+		}
+		if dr := r.DismissalRestrictions; dr != nil {
+			e.add("Reviews dismissed only by", actors(dr), 0, source)
+		}
+	}
+	if sc := bp.StatusChecks; sc != nil {
+		for _, name := range sc.Names() {
+			e.add(statusCheck, name, 0, source)
+		}
+		if sc.Strict {
+			e.require("Branch up to date before merging", source)
+		}
+	}
+	if bp.ConversationResolution {
+		e.require("Conversations resolved", source)
+	}
+	if bp.SignedCommits {
+		e.require("Signed commits", source)
+	}
+	if bp.LinearHistory {
+		e.require("Linear history", source)
+	}
+	if !bp.AllowForcePushes {
+		e.require("Force pushes blocked", source)
+	}
+	if !bp.AllowDeletions {
+		e.require("Deletion blocked", source)
+	}
+	if bp.BlockCreations {
+		e.require("Creation blocked", source)
+	}
+	if bp.LockBranch {
+		e.require("Pushes blocked", source)
+	}
+	if bp.Restrictions != nil {
+		e.add("Pushes restricted to", actors(bp.Restrictions), 0, source)
+	}
+	if bp.EnforceAdmins {
+		e.require("Enforced for admins", source)
+	}
+}
+
+// ruleset adds the requirements of the rules of an active ruleset.
+func (e *effective) ruleset(rs *github.Ruleset) {
+	source := fmt.Sprintf("ruleset %q", rs.Name)
+	if rs.GetSourceType() == "Organization" {
+		source = fmt.Sprintf("organization ruleset %q", rs.Name)
+	}
+	for _, rule := range rs.Rules {
+		switch rule.Type {
+		case "pull_request":
+			var p github.PullRequestRuleParameters
+			decode(rule, &p)
+			e.require("Pull request before merging", source)
+			e.add("Required approvals", strconv.Itoa(p.RequiredApprovingReviewCount), p.RequiredApprovingReviewCount, source)
+			if p.RequireCodeOwnerReview {
+				e.require("Code owner review", source)
+			}
+			if p.DismissStaleReviewsOnPush {
+				e.require("Stale approvals dismissed", source)
+			}
+			if p.RequireLastPushApproval {
+				e.require("Approval of the last push", source)
+			}
+			if p.RequiredReviewThreadResolution {
+				e.require("Conversations resolved", source)
+			}
+		case "required_status_checks":
+			var p github.RequiredStatusChecksRuleParameters
+			decode(rule, &p)
+			for _, check := range p.RequiredStatusChecks {
+				e.add(statusCheck, check.Context, 0, source)
+			}
+			if p.StrictRequiredStatusChecksPolicy {
+				e.require("Branch up to date before merging", source)
+			}
+		case "required_signatures":
+			e.require("Signed commits", source)
+		case "required_linear_history":
+			e.require("Linear history", source)
+		case "non_fast_forward":
+			e.require("Force pushes blocked", source)
+		case "deletion":
+			e.require("Deletion blocked", source)
+		case "creation":
+			e.require("Creation blocked", source)
+		case "update":
+			e.require("Pushes blocked", source)
+		default:
+			value := "yes"
+			if rule.Parameters != nil {
+				value = string(*rule.Parameters)
+			}
+			e.add("Rule "+rule.Type, value, 0, source)
+		}
+	}
+}
+
+// decode reads the parameters of a rule, leaving v zero without any.
+func decode(rule *github.RepositoryRule, v interface{}) {
+	if rule.Parameters != nil {
+		json.Unmarshal(*rule.Parameters, v)
+	}
+}
+
+// actors renders users, teams and apps, or nobody when there are none.
+func actors(a *types.Actors) string {
+	var names []string
+	names = append(names, a.Users...)
+	for _, team := range a.Teams {
+		names = append(names, "team "+team)
+	}
+	for _, app := range a.Apps {
+		names = append(names, "app "+app)
+	}
+	if len(names) == 0 {
+		return "nobody"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Write renders the protection of owner/repo: whether the default branch has
+// branch protection, every ruleset applying to the repository, and the
+// effective policy merged from them.
+func Write(w io.Writer, owner, repo string, rp *types.RepoProtection) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s/%s, default branch %s\n\n", owner, repo, rp.Branch)
+
+	if rp.BranchProtection != nil {
+		fmt.Fprintf(tw, "Branch protection:\tyes\n")
+	} else {
+		fmt.Fprintf(tw, "Branch protection:\tnone\n")
+	}
+
+	all := rulesets(rp)
+	if len(all) == 0 {
+		fmt.Fprintf(tw, "Rulesets:\tnone\n")
+	} else {
+		fmt.Fprintf(tw, "Rulesets:\n")
+		fmt.Fprintf(tw, "  NAME\tSOURCE\tENFORCEMENT\tAPPLIES TO %s\tBYPASS ACTORS\n", rp.Branch)
+		for _, rs := range all {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%d\n", rs.Name, rs.Source, rs.Enforcement, yesNo(Applies(rs, rp.Branch)), len(rs.BypassActors))
+		}
+	}
+
+	requirements := Effective(rp)
+	fmt.Fprintf(tw, "\nEffective policy on %s:\n", rp.Branch)
+	if len(requirements) == 0 {
+		fmt.Fprintf(tw, "  nothing is required\n")
+	}
+	for _, r := range requirements {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", r.Name, r.Value, strings.Join(r.Sources, ", "))
+	}
+	for _, rs := range all {
+		if rs.Enforcement == "evaluate" && Applies(rs, rp.Branch) {
+			fmt.Fprintf(tw, "\nRuleset %q is in evaluate mode: its rules are reported, not enforced.\n", rs.Name)
+		}
+	}
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package describe
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+func rule(ruleType, params string) *github.RepositoryRule {
+	r := &github.RepositoryRule{Type: ruleType}
+	if params != "" {
+		raw := json.RawMessage(params)
+		r.Parameters = &raw
+	}
+	return r
+}
+
+func protection() *types.RepoProtection {
+	return &types.RepoProtection{
+		Branch: "main",
+		BranchProtection: &types.BranchProtection{
+			Reviews:          &types.Reviews{RequiredApprovals: 1},
+			StatusChecks:     &types.StatusChecks{Contexts: []string{"build"}},
+			AllowForcePushes: true,
+			AllowDeletions:   true,
+		},
+		Rulesets: []*github.Ruleset{{
+			Name:        "main",
+			Source:      "octo/api",
+			Enforcement: "active",
+			Conditions:  &github.RulesetConditions{RefName: &github.RulesetRefConditionParameters{Include: []string{"~DEFAULT_BRANCH"}}},
+			Rules: []*github.RepositoryRule{
+				rule("pull_request", `{"required_approving_review_count":2}`),
+				rule("required_status_checks", `{"required_status_checks":[{"context":"build"},{"context":"test"}]}`),
+			},
+		}, {
+			Name:        "releases",
+			Source:      "octo/api",
+			Enforcement: "active",
+			Conditions:  &github.RulesetConditions{RefName: &github.RulesetRefConditionParameters{Include: []string{"refs/heads/release/*"}}},
+			Rules:       []*github.RepositoryRule{rule("deletion", "")},
+		}},
+		InheritedRulesets: []*github.Ruleset{{
+			Name:        "signing",
+			Source:      "octo",
+			SourceType:  github.String("Organization"),
+			Enforcement: "evaluate",
+			Rules:       []*github.RepositoryRule{rule("required_signatures", "")},
+		}},
+	}
+}
+
+func TestEffective(t *testing.T) {
+	got := Effective(protection())
+	want := []Requirement{
+		{Name: "Pull request before merging", Value: "yes", Sources: []string{"branch protection", `ruleset "main"`}},
+		{Name: "Required approvals", Value: "2", Sources: []string{"branch protection", `ruleset "main"`}},
+		{Name: "Status check", Value: "build", Sources: []string{"branch protection", `ruleset "main"`}},
+		{Name: "Status check", Value: "test", Sources: []string{`ruleset "main"`}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestApplies(t *testing.T) {
+	rs := &github.Ruleset{Conditions: &github.RulesetConditions{RefName: &github.RulesetRefConditionParameters{
+		Include: []string{"refs/heads/ma*"},
+		Exclude: []string{"refs/heads/maint"},
+	}}}
+	if !Applies(rs, "main") || Applies(rs, "maint") || Applies(rs, "dev") {
+		t.Error("the ref conditions were not matched")
+	}
+	if Applies(&github.Ruleset{Target: github.String("tag")}, "main") {
+		t.Error("a tag ruleset applies to a branch")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "octo", "api", protection()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"octo/api, default branch main", "releases", "Effective policy on main:", `Ruleset "signing" is in evaluate mode`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
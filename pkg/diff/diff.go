@@ -0,0 +1,252 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/color"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Delta is a setting whose value differs. An empty From or To means the
+// setting is unset on that side.
+type Delta struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Protections compares the branch protection and rulesets of two
+// repositories, from the current state to the desired one.
+func Protections(from, to *types.RepoProtection) []Delta {
+	if from == nil {
+		from = &types.RepoProtection{}
+	}
+	if to == nil {
+		to = &types.RepoProtection{}
+	}
+	return append(Protection(from.BranchProtection, to.BranchProtection), Rulesets(from.Rulesets, to.Rulesets)...)
+}
+
+// Protection compares two branch protections field by field; nil is an
+// unprotected branch.
+func Protection(from, to *types.BranchProtection) []Delta {
+	return compare(flattenProtection(from), flattenProtection(to))
+}
+
+// Rulesets compares two sets of rulesets matched by name, prefixing the
+// fields of each with rulesets.<name>.
+func Rulesets(from, to []*github.Ruleset) []Delta {
+	byName := func(rulesets []*github.Ruleset) map[string]*github.Ruleset {
+		m := make(map[string]*github.Ruleset, len(rulesets))
+		for _, rs := range rulesets {
+			m[rs.Name] = rs
+		}
+		return m
+	}
+	fromByName, toByName := byName(from), byName(to)
+
+	var names []string
+	for name := range fromByName {
+		names = append(names, name)
+	}
+	for name := range toByName {
+		if _, ok := fromByName[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var deltas []Delta
+	for _, name := range names {
+		for _, d := range Ruleset(fromByName[name], toByName[name]) {
+			d.Field = "rulesets." + name + "." + d.Field
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
+
+// Ruleset compares the settings of two rulesets, ignoring the identifiers
+// specific to a repository; nil is a missing ruleset.
+func Ruleset(from, to *github.Ruleset) []Delta {
+	return compare(flattenRuleset(from), flattenRuleset(to))
+}
+
+// field is a flattened setting, in the order settings are rendered.
+type field struct {
+	name, value string
+}
+
+// compare returns the fields whose value differs, in the order of to
+// followed by those only in from.
+func compare(from, to []field) []Delta {
+	fromValues := make(map[string]string, len(from))
+	for _, f := range from {
+		fromValues[f.name] = f.value
+	}
+	seen := make(map[string]bool, len(to))
+
+	var deltas []Delta
+	for _, f := range to {
+		seen[f.name] = true
+		if fromValues[f.name] != f.value {
+			deltas = append(deltas, Delta{Field: f.name, From: fromValues[f.name], To: f.value})
+		}
+	}
+	for _, f := range from {
+		if !seen[f.name] && f.value != "" {
+			deltas = append(deltas, Delta{Field: f.name, From: f.value})
+		}
+	}
+	return deltas
+}
+
+func flattenProtection(p *types.BranchProtection) []field {
+	if p == nil {
+		return nil
+	}
+	fields := []field{
+		{"enforce_admins", strconv.FormatBool(p.EnforceAdmins)},
+		{"required_linear_history", strconv.FormatBool(p.LinearHistory)},
+		{"allow_force_pushes", strconv.FormatBool(p.AllowForcePushes)},
+		{"allow_deletions", strconv.FormatBool(p.AllowDeletions)},
+		{"required_conversation_resolution", strconv.FormatBool(p.ConversationResolution)},
+		{"block_creations", strconv.FormatBool(p.BlockCreations)},
+		{"lock_branch", strconv.FormatBool(p.LockBranch)},
+		{"allow_fork_syncing", strconv.FormatBool(p.AllowForkSyncing)},
+		{"required_signatures", strconv.FormatBool(p.SignedCommits)},
+	}
+
+	if rsc := p.StatusChecks; rsc != nil {
+		var checks []string
+		for _, c := range rsc.Checks {
+			if c.AppID != nil {
+				checks = append(checks, fmt.Sprintf("%s@%d", c.Context, *c.AppID))
+			} else {
+				checks = append(checks, c.Context)
+			}
+		}
+		fields = append(fields,
+			field{"required_status_checks.strict", strconv.FormatBool(rsc.Strict)},
+			field{"required_status_checks.contexts", list(rsc.Contexts)},
+			field{"required_status_checks.checks", list(checks)},
+		)
+	}
+
+	if prr := p.Reviews; prr != nil {
+		fields = append(fields,
+			field{"required_pull_request_reviews.dismiss_stale_reviews", strconv.FormatBool(prr.DismissStale)},
+			field{"required_pull_request_reviews.require_code_owner_reviews", strconv.FormatBool(prr.CodeOwners)},
+			field{"required_pull_request_reviews.required_approving_review_count", strconv.Itoa(prr.RequiredApprovals)},
+			field{"required_pull_request_reviews.require_last_push_approval", strconv.FormatBool(prr.LastPushApproval)},
+		)
+		if dr := prr.DismissalRestrictions; dr != nil {
+			fields = append(fields, actors("required_pull_request_reviews.dismissal_restrictions", dr)...)
+		}
+	}
+
+	if br := p.Restrictions; br != nil {
+		fields = append(fields, actors("restrictions", br)...)
+	}
+	return fields
+}
+
+func actors(prefix string, a *types.Actors) []field {
+	return []field{
+		{prefix + ".users", list(a.Users)},
+		{prefix + ".teams", list(a.Teams)},
+		{prefix + ".apps", list(a.Apps)},
+	}
+}
+
+func flattenRuleset(rs *github.Ruleset) []field {
+	if rs == nil {
+		return nil
+	}
+	fields := []field{
+		{"target", rs.GetTarget()},
+		{"enforcement", rs.Enforcement},
+		{"bypass_actors", compact(rs.BypassActors)},
+		{"conditions", compact(rs.Conditions)},
+	}
+	for _, rule := range rs.Rules {
+		value := "enabled"
+		if rule.Parameters != nil {
+			value = compact(rule.Parameters)
+		}
+		fields = append(fields, field{"rules." + rule.Type, value})
+	}
+	return fields
+}
+
+// list renders a list of names independent of their order.
+func list(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return "[" + strings.Join(sorted, ",") + "]"
+}
+
+// compact renders a value as compact JSON, empty for a nil value.
+func compact(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if s := string(data); s != "null" {
+		return s
+	}
+	return ""
+}
+
+// Write renders deltas as a unified diff, one removed and one added line
+// per field, indented by indent. Colored marks the lines red and green.
+func Write(w io.Writer, indent string, deltas []Delta, colored bool) error {
+	line := func(sign, field, value, code string) error {
+		_, err := fmt.Fprintln(w, color.Wrap(colored, code, fmt.Sprintf("%s%s %s: %s", indent, sign, field, value)))
+		return err
+	}
+	for _, d := range deltas {
+		if d.From != "" {
+			if err := line("-", d.Field, d.From, color.Red); err != nil {
+				return err
+			}
+		}
+		if d.To != "" {
+			if err := line("+", d.Field, d.To, color.Green); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON renders deltas as an indented JSON array.
+func WriteJSON(w io.Writer, deltas []Delta) error {
+	if deltas == nil {
+		deltas = []Delta{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(deltas)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestProtection(t *testing.T) {
+	from := &types.BranchProtection{
+		StatusChecks: &types.StatusChecks{Contexts: []string{"lint", "build"}},
+		Restrictions: &types.Actors{Teams: []string{"core"}},
+	}
+	to := &types.BranchProtection{
+		EnforceAdmins: true,
+		StatusChecks:  &types.StatusChecks{Contexts: []string{"build", "lint"}},
+		Reviews:       &types.Reviews{RequiredApprovals: 2},
+	}
+
+	want := []Delta{
+		{Field: "enforce_admins", From: "false", To: "true"},
+		{Field: "required_pull_request_reviews.dismiss_stale_reviews", To: "false"},
+		{Field: "required_pull_request_reviews.require_code_owner_reviews", To: "false"},
+		{Field: "required_pull_request_reviews.required_approving_review_count", To: "2"},
+		{Field: "required_pull_request_reviews.require_last_push_approval", To: "false"},
+		{Field: "restrictions.users", From: "[]"},
+		{Field: "restrictions.teams", From: "[core]"},
+		{Field: "restrictions.apps", From: "[]"},
+	}
+	if got := Protection(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if got := Protection(to, to); len(got) != 0 {
+		t.Errorf("got %+v for identical protections", got)
+	}
+}
+
+func TestProtectionsRulesets(t *testing.T) {
+	branch := github.String("branch")
+	from := &types.RepoProtection{Rulesets: []*github.Ruleset{
+		{ID: github.Int64(1), Name: "main", Target: branch, Enforcement: "evaluate", Rules: []*github.RepositoryRule{{Type: "deletion"}}},
+		{ID: github.Int64(2), Name: "legacy", Target: branch, Enforcement: "active"},
+	}}
+	to := &types.RepoProtection{Rulesets: []*github.Ruleset{
+		{ID: github.Int64(7), Name: "main", Target: branch, Enforcement: "active", Rules: []*github.RepositoryRule{{Type: "deletion"}}},
+	}}
+
+	want := []Delta{
+		{Field: "rulesets.legacy.target", From: "branch"},
+		{Field: "rulesets.legacy.enforcement", From: "active"},
+		{Field: "rulesets.main.enforcement", From: "evaluate", To: "active"},
+	}
+	if got := Protections(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	deltas := []Delta{
+		{Field: "enforce_admins", From: "false", To: "true"},
+		{Field: "restrictions.teams", From: "[core]"},
+	}
+
+	var plain bytes.Buffer
+	if err := Write(&plain, "  ", deltas, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "  - enforce_admins: false\n  + enforce_admins: true\n  - restrictions.teams: [core]\n"
+	if plain.String() != want {
+		t.Errorf("got %q, want %q", plain.String(), want)
+	}
+
+	var colored bytes.Buffer
+	Write(&colored, "", deltas[:1], true)
+	if want := "\x1b[31m- enforce_admins: false\x1b[0m\n\x1b[32m+ enforce_admins: true\x1b[0m\n"; colored.String() != want {
+		t.Errorf("got %q, want %q", colored.String(), want)
+	}
+
+	var out bytes.Buffer
+	if err := WriteJSON(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Delta
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded == nil {
+		t.Errorf("got %s, want an empty array", out.String())
+	}
+}
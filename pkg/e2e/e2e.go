@@ -0,0 +1,176 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Harness manages the lifecycle of the temporary repositories created in a
+// disposable test organization during an end-to-end run.
+type Harness struct {
+	client *github.Client
+	org    string
+	prefix string
+	repos  []*github.Repository
+}
+
+// NewHarness returns a Harness that creates its repositories in org. Every
+// repository name is prefixed with a per-run prefix so concurrent runs
+// against the same organization don't collide.
+func NewHarness(client *github.Client, org string) *Harness {
+	return &Harness{
+		client: client,
+		org:    org,
+		prefix: fmt.Sprintf("rps-e2e-%d", time.Now().Unix()),
+	}
+}
+
+// CreateRepo creates a public, initialized repository so that it has a
+// default branch which can be protected.
+func (h *Harness) CreateRepo(ctx context.Context, suffix string) (*github.Repository, error) {
+	name := fmt.Sprintf("%s-%s", h.prefix, suffix)
+	repo, _, err := h.client.Repositories.Create(ctx, h.org, &github.Repository{
+		Name:        github.String(name),
+		Description: github.String("Temporary repository created by repo-protection-sync e2e"),
+		Private:     github.Bool(false),
+		AutoInit:    github.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating repo %s: %w", name, err)
+	}
+	h.repos = append(h.repos, repo)
+	log.Printf("Created temporary repo %s/%s\n", h.org, name)
+	return repo, nil
+}
+
+// ApplyBaseline protects the default branch of the given repository with
+// BaselineProtection so that it can act as the sync source.
+func (h *Harness) ApplyBaseline(ctx context.Context, repo *github.Repository) error {
+	_, _, err := h.client.Repositories.UpdateBranchProtection(ctx, h.org, repo.GetName(), repo.GetDefaultBranch(), BaselineProtection())
+	if err != nil {
+		return fmt.Errorf("applying baseline protection to %s: %w", repo.GetName(), err)
+	}
+	return nil
+}
+
+// Verify fetches the protection of the target repository and compares it
+// against the source protection, returning an error listing every mismatch.
+func (h *Harness) Verify(ctx context.Context, source *types.BranchProtection, target *github.Repository) error {
+	got, _, err := h.client.Repositories.GetBranchProtection(ctx, h.org, target.GetName(), target.GetDefaultBranch())
+	if err != nil {
+		return fmt.Errorf("fetching protection of %s: %w", target.GetName(), err)
+	}
+
+	var mismatches []string
+	for _, d := range diff.Protection(types.NewBranchProtection(got), source) {
+		mismatches = append(mismatches, fmt.Sprintf("%s: want %v, got %v", d.Field, d.To, d.From))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("protection mismatch on %s: %s", target.GetName(), strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// Cleanup deletes every repository created by the harness. Deletion errors
+// are logged and do not stop the remaining repositories from being removed.
+func (h *Harness) Cleanup(ctx context.Context) {
+	for _, repo := range h.repos {
+		if _, err := h.client.Repositories.Delete(ctx, h.org, repo.GetName()); err != nil {
+			log.Printf("Failed to delete temporary repo %s/%s: %v\n", h.org, repo.GetName(), err)
+			continue
+		}
+		log.Printf("Deleted temporary repo %s/%s\n", h.org, repo.GetName())
+	}
+	h.repos = nil
+}
+
+// Run performs a complete end-to-end sync: it creates a source repository
+// with the baseline protection and the requested number of targets, syncs
+// the protection onto the targets and verifies the result via the API.
+// Unless keep is set, all temporary repositories are deleted afterwards.
+func Run(ctx context.Context, client *github.Client, org string, targets int, keep bool) error {
+	h := NewHarness(client, org)
+	if !keep {
+		defer h.Cleanup(ctx)
+	}
+
+	source, err := h.CreateRepo(ctx, "source")
+	if err != nil {
+		return err
+	}
+	if err := h.ApplyBaseline(ctx, source); err != nil {
+		return err
+	}
+
+	repos := make([]*github.Repository, 0, targets)
+	for i := 0; i < targets; i++ {
+		repo, err := h.CreateRepo(ctx, fmt.Sprintf("target-%d", i))
+		if err != nil {
+			return err
+		}
+		repos = append(repos, repo)
+	}
+
+	gh := ghclient.New(client)
+	protections := getter.GetRepoProtections(ctx, gh, org, source.GetName())
+	if _, err := setter.SetRuleset(ctx, gh, org, repos, protections, setter.Options{}); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, repo := range repos {
+		if err := h.Verify(ctx, protections.BranchProtection, repo); err != nil {
+			log.Println(err)
+			failed = append(failed, repo.GetName())
+			continue
+		}
+		log.Printf("Verified protection on %s/%s\n", org, repo.GetName())
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("e2e verification failed for %d of %d repos: %s", len(failed), len(repos), strings.Join(failed, ", "))
+	}
+
+	log.Printf("e2e sync verified on %d repos\n", len(repos))
+	return nil
+}
+
+// BaselineProtection is the protection applied to the e2e source repository.
+func BaselineProtection() *github.ProtectionRequest {
+	return &github.ProtectionRequest{
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          true,
+			RequiredApprovingReviewCount: 1,
+		},
+		EnforceAdmins:                  true,
+		RequireLinearHistory:           github.Bool(true),
+		AllowForcePushes:               github.Bool(false),
+		AllowDeletions:                 github.Bool(false),
+		RequiredConversationResolution: github.Bool(true),
+	}
+}
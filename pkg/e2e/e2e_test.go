@@ -0,0 +1,45 @@
+//go:build e2e
+
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package e2e
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// TestSync runs the full sync against throwaway repositories. It only builds
+// with the e2e tag and needs a token allowed to create and delete
+// repositories in a sandbox organization:
+//
+//	GITHUB_TOKEN=... E2E_ORG=sandbox go test -tags e2e ./pkg/e2e
+func TestSync(t *testing.T) {
+	token, org := os.Getenv("GITHUB_TOKEN"), os.Getenv("E2E_ORG")
+	if token == "" || org == "" {
+		t.Skip("GITHUB_TOKEN and E2E_ORG must be set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	if err := Run(ctx, github.NewClient(nil).WithAuthToken(token), org, 2, false); err != nil {
+		t.Fatal(err)
+	}
+}
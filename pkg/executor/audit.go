@@ -0,0 +1,116 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/audit"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Audit compares the protection of every target against the source, scans
+// it for the features the configuration forbids, and returns the resulting
+// compliance matrix. A target that can't be read is reported with an error
+// instead of failing the audit.
+func Audit(opts Options) (audit.Report, error) {
+	ctx := startRun(context.Background(), &opts)
+	report := audit.Report{RunID: opts.RunID, Owner: opts.Owner, Source: opts.Source}
+
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return report, err
+	}
+	if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+		return report, err
+	}
+	report.Source = opts.Source
+
+	source, err := getter.FetchRepoProtections(ctx, client, opts.Owner, opts.Source)
+	if err != nil {
+		return report, fmt.Errorf("fetching protection of %s/%s: %w", opts.Owner, opts.Source, err)
+	}
+
+	targets, _, err := selectTargets(ctx, client, opts)
+	if err != nil {
+		return report, err
+	}
+
+	bulk := bulkProtections(ctx, client, opts.Owner, targets)
+	for _, repo := range targets {
+		row := audit.Row{Repo: repo.GetName(), Branch: repo.GetDefaultBranch()}
+		if rp, ok := bulk[row.Repo]; ok && rp.Branch == row.Branch {
+			row.Findings = audit.Evaluate(source.BranchProtection, rp.BranchProtection)
+		} else if protection, err := getter.FetchBranchProtection(ctx, client.Repositories, opts.Owner, row.Repo, row.Branch); err != nil {
+			if setter.IsBranchNotFound(err) {
+				row.Error = "empty repository"
+			} else {
+				row.Error = err.Error()
+			}
+		} else {
+			row.Findings = audit.Evaluate(source.BranchProtection, types.NewBranchProtection(protection))
+		}
+		if row.Error == "" && opts.Config.Forbid.Enabled() {
+			findings, err := audit.Scan(ctx, client, opts.Owner, row.Repo, opts.Config.Forbid)
+			if err != nil {
+				row.Error, row.Findings = err.Error(), nil
+			} else {
+				row.Findings = append(row.Findings, findings...)
+			}
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+// bulkProtections fetches the protection of the default branch of the
+// targets through the GraphQL API, a query per batch of repositories rather
+// than a REST call per repository. Targets missing from the result, such as
+// empty repositories, are read through the REST API instead, and so are all
+// of them when the query fails, as it does on GHES versions lacking fields.
+func bulkProtections(ctx context.Context, client *ghclient.Client, owner string, targets []*github.Repository) map[string]*types.RepoProtection {
+	if client.GraphQL == nil || len(targets) == 0 {
+		return nil
+	}
+	names := make([]string, len(targets))
+	for i, repo := range targets {
+		names[i] = repo.GetName()
+	}
+	bulk, err := getter.GetRepoProtectionsBulk(ctx, client.GraphQL, owner, names, getter.DefaultGraphQLBatchSize)
+	if err != nil {
+		logging.Debugf("Reading the protections through GraphQL failed, falling back to REST: %v\n", err)
+		return nil
+	}
+	return bulk
+}
+
+// FileIssues files or updates the tracking issues of the repositories of the
+// report, returning the errors keyed by repository name.
+func FileIssues(opts Options, report audit.Report) (map[string]error, error) {
+	ctx := runid.With(context.Background(), report.RunID)
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	return audit.FileIssues(ctx, client.Issues, report), nil
+}
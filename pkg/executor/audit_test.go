@@ -0,0 +1,58 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBulkProtections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	gql := mocks.NewMockGraphQLQuerier(ctrl)
+	client := &ghclient.Client{GraphQL: gql}
+	targets := []*github.Repository{{Name: github.String("api")}, {Name: github.String("empty")}}
+
+	gql.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, query string, data interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"r0": {"name": "api", "defaultBranchRef": {"name": "main"}, "branchProtectionRules": {"nodes": [{"pattern": "main", "isAdminEnforced": true}]}},
+			"r1": {"name": "empty", "defaultBranchRef": null, "branchProtectionRules": {"nodes": []}}
+		}`), data)
+	})
+	bulk := bulkProtections(context.Background(), client, "octo", targets)
+	if api := bulk["api"]; api == nil || api.Branch != "main" || api.BranchProtection == nil || !api.BranchProtection.EnforceAdmins {
+		t.Errorf("got %+v for api, want its protection", api)
+	}
+	// Audit reads the empty repository through REST, reporting it as empty
+	if empty := bulk["empty"]; empty == nil || empty.Branch != "" {
+		t.Errorf("got %+v for empty, want no default branch", empty)
+	}
+
+	gql.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("Field 'lockBranch' doesn't exist"))
+	if bulk := bulkProtections(context.Background(), client, "octo", targets); bulk != nil {
+		t.Errorf("got %v after a failed query, want the REST fallback", bulk)
+	}
+	if bulk := bulkProtections(context.Background(), &ghclient.Client{}, "octo", targets); bulk != nil {
+		t.Errorf("got %v without a GraphQL client, want the REST fallback", bulk)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/transport"
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v59/github"
+	"golang.org/x/oauth2"
+)
+
+// Credentials holds either a personal access token or the details needed to
+// authenticate as a GitHub App installation.
+type Credentials struct {
+	Token string
+	// ExtraTokens are rotated with Token as the rate limit of each runs out.
+	ExtraTokens []string
+
+	AppID          int64
+	InstallationID int64
+	PrivateKeyFile string
+	// ExtraApps are installations of further Apps on the same repositories,
+	// rotated with the App as the rate limit of each runs out.
+	ExtraApps []AppInstallation
+
+	// BaseURL is the address of a GitHub Enterprise Server instance. The
+	// credentials are for github.com when it is empty.
+	BaseURL string
+}
+
+// AppInstallation identifies the installation of a GitHub App along with the
+// private key of the App.
+type AppInstallation struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKeyFile string
+}
+
+// ParseAppInstallation parses an installation given as
+// APP_ID:INSTALLATION_ID:PRIVATE_KEY_FILE.
+func ParseAppInstallation(s string) (AppInstallation, error) {
+	var app AppInstallation
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return app, fmt.Errorf("%q isn't APP_ID:INSTALLATION_ID:PRIVATE_KEY_FILE", s)
+	}
+	var err error
+	if app.AppID, err = strconv.ParseInt(parts[0], 10, 64); err != nil || app.AppID <= 0 {
+		return app, fmt.Errorf("invalid App ID %q", parts[0])
+	}
+	if app.InstallationID, err = strconv.ParseInt(parts[1], 10, 64); err != nil || app.InstallationID <= 0 {
+		return app, fmt.Errorf("invalid installation ID %q", parts[1])
+	}
+	app.PrivateKeyFile = parts[2]
+	return app, nil
+}
+
+// IsApp reports whether the credentials authenticate as a GitHub App.
+func (c Credentials) IsApp() bool {
+	return c.AppID != 0
+}
+
+// IsFineGrained reports whether the token is a fine-grained personal access
+// token. Those are granted per repository and have no OAuth scopes.
+func (c Credentials) IsFineGrained() bool {
+	return strings.HasPrefix(c.Token, "github_pat_")
+}
+
+// Validate checks that exactly one complete authentication method is set.
+func (c Credentials) Validate() error {
+	switch {
+	case c.IsApp() && c.Token != "":
+		return errors.New("a token and GitHub App credentials are mutually exclusive")
+	case c.IsApp() && len(c.ExtraTokens) > 0:
+		return errors.New("extra tokens can't be pooled with GitHub App credentials")
+	case c.IsApp() && (c.InstallationID == 0 || c.PrivateKeyFile == ""):
+		return errors.New("GitHub App authentication requires an installation ID and a private key file")
+	case !c.IsApp() && len(c.ExtraApps) > 0:
+		return errors.New("extra App installations can only be pooled with GitHub App credentials")
+	case !c.IsApp() && c.Token == "":
+		return errors.New("either a token or GitHub App credentials are required")
+	}
+	return nil
+}
+
+// newClient returns the API client for creds, recording the rate limit of
+// every response in its Rates.
+func newClient(ctx context.Context, creds Credentials, tr transport.Options) (*ghclient.Client, error) {
+	rates := &transport.RateCache{}
+	gc, err := getGitHubClient(ctx, creds, tr, rates)
+	if err != nil {
+		return nil, err
+	}
+	client := ghclient.New(gc)
+	client.Rates = rates
+	if tr.Audit != nil && tr.Audit.Actor == "" {
+		tr.Audit.Actor = auditActor(ctx, client, creds)
+	}
+	return client, nil
+}
+
+// Host names the GitHub instance of the credentials, which their token is
+// stored under.
+func (c Credentials) Host() string {
+	if c.BaseURL == "" {
+		return "github.com"
+	}
+	if u, err := url.Parse(c.BaseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return c.BaseURL
+}
+
+// Login returns the login of the user the token of creds belongs to,
+// failing when GitHub rejects the token.
+func Login(creds Credentials, tr transport.Options) (string, error) {
+	ctx := context.Background()
+	client, err := newClient(ctx, creds, tr)
+	if err != nil {
+		return "", err
+	}
+	user, _, err := client.Actors.GetUser(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	return user.GetLogin(), nil
+}
+
+// auditActor names who the writes of the audit log are made as: the App
+// installation, or the user the token belongs to.
+func auditActor(ctx context.Context, client *ghclient.Client, creds Credentials) string {
+	if creds.IsApp() {
+		actor := fmt.Sprintf("app %d installation %d", creds.AppID, creds.InstallationID)
+		for _, app := range creds.ExtraApps {
+			actor += fmt.Sprintf(", app %d installation %d", app.AppID, app.InstallationID)
+		}
+		return actor
+	}
+	user, _, err := client.Actors.GetUser(ctx, "")
+	if err != nil {
+		log.Printf("Warning: the audit log can't name the user of the token: %v\n", err)
+		return "unknown"
+	}
+	return user.GetLogin()
+}
+
+// getGitHubClient returns a client authenticated with the given credentials,
+// sending its requests through the transport configured by tr. When rates
+// isn't nil it wraps the authenticated transport.
+func getGitHubClient(ctx context.Context, creds Credentials, tr transport.Options, rates *transport.RateCache) (*github.Client, error) {
+	base := transport.New(tr, http.DefaultTransport)
+
+	var hc *http.Client
+	var itrs []*ghinstallation.Transport
+	var pool *transport.TokenPool
+	switch {
+	case creds.IsApp():
+		apps := append([]AppInstallation{{creds.AppID, creds.InstallationID, creds.PrivateKeyFile}}, creds.ExtraApps...)
+		transports := make([]http.RoundTripper, 0, len(apps))
+		for _, app := range apps {
+			itr, err := ghinstallation.NewKeyFromFile(base, app.AppID, app.InstallationID, app.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("App %d: %w", app.AppID, err)
+			}
+			itrs = append(itrs, itr)
+			transports = append(transports, itr)
+		}
+		if len(transports) > 1 {
+			pool = transport.NewTransportPool(transports)
+			hc = &http.Client{Transport: pool}
+		} else {
+			hc = &http.Client{Transport: itrs[0]}
+		}
+	case len(creds.ExtraTokens) > 0:
+		pool = transport.NewTokenPool(base, append([]string{creds.Token}, creds.ExtraTokens...))
+		hc = &http.Client{Transport: pool}
+	default:
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: base})
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: creds.Token})
+		hc = oauth2.NewClient(ctx, ts)
+	}
+
+	if rates != nil {
+		rates.Base = hc.Transport
+		rates.Pool = pool
+		hc.Transport = rates
+	}
+	if tr.Audit != nil {
+		hc.Transport = tr.Audit.Wrap(hc.Transport)
+	}
+	client := github.NewClient(hc)
+	if creds.BaseURL == "" {
+		return client, nil
+	}
+	client, err := client.WithEnterpriseURLs(creds.BaseURL, creds.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the API URL: %w", err)
+	}
+	for _, itr := range itrs {
+		itr.BaseURL = strings.TrimSuffix(client.BaseURL.String(), "/")
+	}
+	return client, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import "testing"
+
+func TestParseAppInstallation(t *testing.T) {
+	app, err := ParseAppInstallation("12:34:keys/app.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app != (AppInstallation{AppID: 12, InstallationID: 34, PrivateKeyFile: "keys/app.pem"}) {
+		t.Errorf("got %+v", app)
+	}
+	for _, s := range []string{"12:34", "12:34:", "app:34:key.pem", "12:0:key.pem"} {
+		if _, err := ParseAppInstallation(s); err == nil {
+			t.Errorf("ParseAppInstallation(%q) accepted it", s)
+		}
+	}
+}
+
+func TestCredentialsValidateExtraApps(t *testing.T) {
+	extra := []AppInstallation{{AppID: 56, InstallationID: 78, PrivateKeyFile: "other.pem"}}
+	app := Credentials{AppID: 12, InstallationID: 34, PrivateKeyFile: "app.pem", ExtraApps: extra}
+	if err := app.Validate(); err != nil {
+		t.Errorf("got %v for pooled Apps", err)
+	}
+	if err := (Credentials{Token: "token", ExtraApps: extra}).Validate(); err == nil {
+		t.Error("pooled Apps with a token")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/google/go-github/v59/github"
+)
+
+// Canary limits the first wave of a sync to a cohort of the targets. The
+// remaining targets are only synced once the cohort succeeded and the
+// rollout was confirmed, or the wait elapsed.
+type Canary struct {
+	// Count is the size of the cohort.
+	Count int
+	// Percent sizes the cohort as a percentage of the targets, rounded up.
+	Percent float64
+	// Wait proceeds automatically after this long instead of asking Confirm.
+	Wait time.Duration
+	// Confirm asks whether to proceed to the remaining targets.
+	Confirm func(cohort, remaining int) bool
+}
+
+// Enabled reports whether a canary cohort is configured.
+func (c Canary) Enabled() bool {
+	return c.Count > 0 || c.Percent > 0
+}
+
+// split divides the targets into the canary cohort and the rest.
+func (c Canary) split(targets []*github.Repository) ([]*github.Repository, []*github.Repository) {
+	size := c.Count
+	if c.Percent > 0 {
+		size = int(math.Ceil(float64(len(targets)) * c.Percent / 100))
+	}
+	if size >= len(targets) {
+		return targets, nil
+	}
+	return targets[:size], targets[size:]
+}
+
+// proceed reports whether the rollout continues past the cohort. It doesn't
+// when ctx is done during the wait.
+func (c Canary) proceed(ctx context.Context, cohort, remaining int) bool {
+	if c.Wait > 0 {
+		logging.Infof("Canary applied to %d repositories, waiting %s before syncing the remaining %d\n", cohort, c.Wait, remaining)
+		timer := time.NewTimer(c.Wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	if c.Confirm != nil {
+		return c.Confirm(cohort, remaining)
+	}
+	return false
+}
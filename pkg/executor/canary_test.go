@@ -0,0 +1,61 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestCanarySplit(t *testing.T) {
+	targets := make([]*github.Repository, 10)
+	for i := range targets {
+		targets[i] = &github.Repository{Name: github.String(fmt.Sprintf("repo-%d", i))}
+	}
+
+	tests := []struct {
+		canary     Canary
+		cohort     int
+		remaining  int
+		proceedsOK bool
+	}{
+		{canary: Canary{Count: 3}, cohort: 3, remaining: 7},
+		{canary: Canary{Percent: 25}, cohort: 3, remaining: 7},
+		{canary: Canary{Count: 20}, cohort: 10, remaining: 0},
+		{canary: Canary{Count: 1, Confirm: func(int, int) bool { return true }}, cohort: 1, remaining: 9, proceedsOK: true},
+	}
+	for _, tt := range tests {
+		cohort, rest := tt.canary.split(targets)
+		if len(cohort) != tt.cohort || len(rest) != tt.remaining {
+			t.Errorf("%+v: got %d+%d, want %d+%d", tt.canary, len(cohort), len(rest), tt.cohort, tt.remaining)
+		}
+		if got := tt.canary.proceed(context.Background(), len(cohort), len(rest)); got != tt.proceedsOK {
+			t.Errorf("%+v: proceed = %v, want %v", tt.canary, got, tt.proceedsOK)
+		}
+	}
+}
+
+func TestCanaryWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if (Canary{Count: 1, Wait: time.Hour}).proceed(ctx, 1, 9) {
+		t.Error("proceeded after the run was cancelled")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"log"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/codeowners"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/policy"
+	"github.com/google/go-github/v59/github"
+)
+
+// CheckCodeowners checks the CODEOWNERS file of every target whose policy
+// requires code owner reviews. With a template, a pull request adding it is
+// opened in every non-empty target without a CODEOWNERS file.
+func CheckCodeowners(opts Options, template []byte) (codeowners.Result, error) {
+	ctx := startRun(context.Background(), &opts)
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return codeowners.Result{}, err
+	}
+	if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+		return codeowners.Result{}, err
+	}
+
+	assignments, _, err := assignPolicies(ctx, client, opts)
+	if err != nil {
+		return codeowners.Result{}, err
+	}
+
+	var targets []*github.Repository
+	for _, a := range assignments {
+		protections, err := policy.Resolve(ctx, client, opts.Owner, a.policy)
+		if err != nil {
+			return codeowners.Result{}, err
+		}
+		if reviews := protections.BranchProtection.Reviews; reviews != nil && reviews.CodeOwners {
+			targets = append(targets, a.targets...)
+		}
+	}
+	if len(targets) == 0 {
+		logging.Infof("No policy requires code owner reviews, nothing to check")
+		return codeowners.Result{}, nil
+	}
+
+	result := codeowners.Check(ctx, client.Repositories, opts.Owner, targets)
+	if template == nil {
+		return result, nil
+	}
+
+	missing := make(map[string]bool, len(result.Missing))
+	for _, name := range result.Missing {
+		missing[name] = true
+	}
+	for _, repo := range targets {
+		if !missing[repo.GetName()] || repo.GetDefaultBranch() == "" {
+			continue
+		}
+		pr, err := codeowners.Propose(ctx, client, opts.Owner, repo, template)
+		switch {
+		case err != nil:
+			log.Printf("Error proposing a CODEOWNERS file to %s: %v\n", repo.GetName(), err)
+		case pr == nil:
+			logging.Infof("A CODEOWNERS file was already proposed to %s\n", repo.GetName())
+		default:
+			logging.Infof("Proposed a CODEOWNERS file to %s: %s\n", repo.GetName(), pr.GetHTMLURL())
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,33 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/compat"
+)
+
+// Probe reports which sync features the API the credentials are for
+// supports.
+func Probe(opts Options) (*compat.Report, error) {
+	ctx := context.Background()
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	return compat.Probe(ctx, client.Meta)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/describe"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+)
+
+// Describe writes the branch protection, the rulesets and the effective
+// policy of the default branch of owner/repo.
+func Describe(opts Options, owner, repo string, w io.Writer) error {
+	ctx := context.Background()
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return err
+	}
+	rp, err := getter.FetchRepoProtections(ctx, client, owner, repo)
+	if err != nil {
+		return fmt.Errorf("fetching protection of %s/%s: %w", owner, repo, err)
+	}
+	return describe.Write(w, owner, repo, rp)
+}
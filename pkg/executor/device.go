@@ -0,0 +1,73 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"golang.org/x/oauth2"
+)
+
+// deviceScopes are the scopes a token of the device flow is requested with:
+// administering repositories, and reading the teams and custom properties
+// of the organization.
+var deviceScopes = []string{"repo", "read:org"}
+
+// DeviceLogin authenticates the user with the OAuth device flow of the
+// OAuth App clientID on the instance of creds: it writes the code to enter
+// and where to w, and returns the token once the user authorized it.
+func DeviceLogin(ctx context.Context, creds Credentials, clientID string, w io.Writer) (string, error) {
+	endpoint, err := deviceEndpoint(creds.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	cfg := &oauth2.Config{ClientID: clientID, Endpoint: endpoint, Scopes: deviceScopes}
+	return deviceToken(ctx, cfg, w)
+}
+
+// deviceEndpoint returns the OAuth endpoints of the GitHub instance at
+// baseURL, github.com when empty.
+func deviceEndpoint(baseURL string) (oauth2.Endpoint, error) {
+	web := "https://github.com"
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Host == "" {
+			return oauth2.Endpoint{}, fmt.Errorf("parsing the API URL %q", baseURL)
+		}
+		web = u.Scheme + "://" + u.Host
+	}
+	return oauth2.Endpoint{
+		AuthURL:       web + "/login/oauth/authorize",
+		TokenURL:      web + "/login/oauth/access_token",
+		DeviceAuthURL: web + "/login/device/code",
+	}, nil
+}
+
+func deviceToken(ctx context.Context, cfg *oauth2.Config, w io.Writer) (string, error) {
+	auth, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return "", fmt.Errorf("requesting a device code: %w", err)
+	}
+	fmt.Fprintf(w, "Open %s and enter the code %s\n", auth.VerificationURI, auth.UserCode)
+	token, err := cfg.DeviceAccessToken(ctx, auth)
+	if err != nil {
+		return "", fmt.Errorf("waiting for the authorization: %w", err)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestDeviceEndpoint(t *testing.T) {
+	endpoint, err := deviceEndpoint("https://github.example.com/api/v3/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.DeviceAuthURL != "https://github.example.com/login/device/code" || endpoint.TokenURL != "https://github.example.com/login/oauth/access_token" {
+		t.Errorf("got endpoint %+v", endpoint)
+	}
+	if endpoint, _ := deviceEndpoint(""); endpoint.DeviceAuthURL != "https://github.com/login/device/code" {
+		t.Errorf("got endpoint %+v for github.com", endpoint)
+	}
+}
+
+func TestDeviceToken(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "Iv1.abc" {
+			t.Errorf("sent client ID %q", r.Form.Get("client_id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/login/device/code":
+			w.Write([]byte(`{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":1}`))
+		case "/login/oauth/access_token":
+			// GitHub reports a pending authorization with a 200 response
+			if polls++; polls == 1 {
+				w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token":"gho_token","token_type":"bearer","scope":"repo,read:org"}`))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &oauth2.Config{ClientID: "Iv1.abc", Endpoint: oauth2.Endpoint{
+		TokenURL:      srv.URL + "/login/oauth/access_token",
+		DeviceAuthURL: srv.URL + "/login/device/code",
+	}}
+	var out bytes.Buffer
+	token, err := deviceToken(context.Background(), cfg, &out)
+	if err != nil || token != "gho_token" {
+		t.Fatalf("deviceToken() = %q, %v", token, err)
+	}
+	if !strings.Contains(out.String(), "ABCD-1234") || polls != 2 {
+		t.Errorf("wrote %q after %d polls", out.String(), polls)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/budget"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/policy"
+	"github.com/google/go-github/v59/github"
+)
+
+// Estimate counts the API requests a sync would send, along with the rate
+// limit left for them. Only the source and the list of targets are read.
+func Estimate(opts Options) (budget.Estimate, github.Rate, error) {
+	ctx := context.Background()
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return budget.Estimate{}, github.Rate{}, err
+	}
+	if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+		return budget.Estimate{}, github.Rate{}, err
+	}
+	assignments, _, err := assignPolicies(ctx, client, opts)
+	if err != nil {
+		return budget.Estimate{}, github.Rate{}, fmt.Errorf("fetching repositories: %w", err)
+	}
+
+	var estimate budget.Estimate
+	var repos int
+	for _, a := range assignments {
+		protections, err := policy.Resolve(ctx, client, opts.Owner, a.policy)
+		if err != nil {
+			return budget.Estimate{}, github.Rate{}, fmt.Errorf("fetching the protection of %s: %w", a.policy.Source, err)
+		}
+		inheritedRulesets(opts, protections)
+		estimate = estimate.Add(budget.Count(budgetRun(opts, len(a.targets), len(protections.Rulesets), settingModules(opts))))
+		repos += len(a.targets)
+	}
+	estimate = estimate.Add(budget.Listing(repos))
+
+	rate, err := coreRate(ctx, client.RateLimit)
+	return estimate, rate, err
+}
+
+// checkBudget warns before syncing the targets of a policy when the rate
+// limit left doesn't cover the requests. The sync then waits for the reset
+// whenever the limit runs out.
+func checkBudget(ctx context.Context, client *ghclient.Client, run budget.Run) {
+	estimate := budget.Count(run)
+	rate, err := coreRate(ctx, client.RateLimit)
+	if err != nil {
+		log.Printf("Warning: not checking the rate limit budget: %v\n", err)
+		return
+	}
+	if resets := budget.Resets(estimate.Total(), rate); resets > 0 {
+		log.Printf("Warning: syncing %d repositories takes about %d API requests but %d are left; the sync will wait for the rate limit to reset %d time(s), about %v\n",
+			run.Repos, estimate.Total(), rate.Remaining, resets, budget.Wait(resets, rate).Round(time.Minute))
+	}
+}
+
+// budgetRun describes a sync of repos targets for the budget estimate.
+func budgetRun(opts Options, repos, rulesets, steps int) budget.Run {
+	return budget.Run{
+		Repos:             repos,
+		Rulesets:          rulesets,
+		Steps:             steps,
+		Preflight:         opts.Preflight,
+		DropMissingChecks: opts.Config.StatusChecks.DropMissingChecks,
+		KeepUnselected:    opts.Fields != nil,
+		Interactive:       opts.Interactive,
+		SkipUnchanged:     opts.Transport.CacheDir != "",
+	}
+}
+
+// settingModules counts the repository settings modules the options enable.
+func settingModules(opts Options) int {
+	n := 0
+	for _, enabled := range []bool{opts.SyncMergeSettings, opts.SyncSecuritySettings, opts.SyncActionsSettings, opts.SyncEnvironments, opts.SyncWebhooks, opts.SyncAutolinks, opts.SyncLabels} {
+		if enabled {
+			n++
+		}
+	}
+	return n
+}
+
+// coreRate reads the core rate limit left.
+func coreRate(ctx context.Context, rl ghclient.RateLimitReader) (github.Rate, error) {
+	limits, _, err := rl.Get(ctx)
+	if err != nil {
+		return github.Rate{}, fmt.Errorf("reading the rate limit: %w", err)
+	}
+	if core := limits.GetCore(); core != nil {
+		return *core, nil
+	}
+	return github.Rate{}, errors.New("the response has no core rate limit")
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBudgetRun(t *testing.T) {
+	opts := Options{
+		Config:            &config.Config{StatusChecks: config.StatusChecks{DropMissingChecks: true}},
+		Fields:            map[string]bool{"required_status_checks": true},
+		SyncLabels:        true,
+		SyncAutolinks:     true,
+		SyncMergeSettings: true,
+	}
+	run := budgetRun(opts, 5, 2, settingModules(opts))
+	if run.Repos != 5 || run.Rulesets != 2 || run.Steps != 3 || !run.DropMissingChecks || !run.KeepUnselected || run.Preflight || run.Interactive {
+		t.Errorf("budgetRun() = %+v", run)
+	}
+}
+
+func TestCoreRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rl := mocks.NewMockRateLimitReader(ctrl)
+	rl.EXPECT().Get(gomock.Any()).Return(&github.RateLimits{Core: &github.Rate{Limit: 5000, Remaining: 42}}, nil, nil)
+	rl.EXPECT().Get(gomock.Any()).Return(&github.RateLimits{}, nil, nil)
+
+	rate, err := coreRate(context.Background(), rl)
+	if err != nil || rate.Remaining != 42 {
+		t.Errorf("coreRate() = %+v, %v, want 42 remaining", rate, err)
+	}
+	if _, err := coreRate(context.Background(), rl); err == nil {
+		t.Error("coreRate() succeeded without a core rate limit")
+	}
+}
@@ -17,34 +17,654 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/arush-sal/repo-protection-sync/pkg/actions"
+	"github.com/arush-sal/repo-protection-sync/pkg/actors"
+	"github.com/arush-sal/repo-protection-sync/pkg/checks"
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/e2e"
 	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/inventory"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/arush-sal/repo-protection-sync/pkg/policy"
+	"github.com/arush-sal/repo-protection-sync/pkg/preflight"
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
 	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/snapshot"
+	"github.com/arush-sal/repo-protection-sync/pkg/transport"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/arush-sal/repo-protection-sync/pkg/upload"
+	"github.com/arush-sal/repo-protection-sync/pkg/validate"
 	"github.com/google/go-github/v59/github"
-	"golang.org/x/oauth2"
 )
 
-func Run(owner, sourceRepo, token string) {
-	ctx := context.Background()
-	client := getGitHubClient(ctx, token)
+// Options configures a sync run.
+type Options struct {
+	// Owner is the user or organization owning the source and the targets.
+	Owner string
+	// Source is the repository whose protection is copied.
+	Source      string
+	Credentials Credentials
+	Config      *config.Config
+	Transport   transport.Options
+	// Preflight runs the permission preflight checks before syncing.
+	Preflight bool
+	// Targets limits the sync to the named repositories instead of every
+	// repository of Owner.
+	Targets []string
+	// Properties limits the targets to the repositories whose custom
+	// properties have the given values.
+	Properties map[string]string
+	// Team limits the targets to the repositories the team with this slug
+	// administers or maintains.
+	Team string
+	// Filter limits the targets by their metadata, such as when they were
+	// last pushed to.
+	Filter getter.Filter
+	// Canary rolls the sync out to a cohort of the targets first.
+	Canary Canary
+	// Interactive shows the changes for every target and asks whether to
+	// apply them.
+	Interactive bool
+	// SyncMergeSettings copies the pull request merge settings of the source.
+	SyncMergeSettings bool
+	// SyncSecuritySettings copies the code security settings and Dependabot
+	// alerts and security updates of the source.
+	SyncSecuritySettings bool
+	// SyncActionsSettings copies the Actions permissions, default workflow
+	// permissions and fork pull request approval policy of the source.
+	SyncActionsSettings bool
+	// SyncEnvironments replicates the deployment environments of the source
+	// and their protection rules.
+	SyncEnvironments bool
+	// SyncWebhooks copies the webhooks of the source, with the secrets of
+	// the configuration file.
+	SyncWebhooks bool
+	// SyncLabels copies the issue labels of the source, and PruneLabels
+	// deletes the labels of the targets the source doesn't have.
+	SyncLabels  bool
+	PruneLabels bool
+	// SyncAutolinks copies the autolink references of the source.
+	SyncAutolinks bool
+	// Fields limits the sync to these protection fields, keeping the others
+	// of every target as they are. Nil syncs every field.
+	Fields map[string]bool
+	// EnforceAdmins overrides the admin enforcement of the source when set.
+	EnforceAdmins *bool
+	// MinReviews raises the approving reviews required on every target to
+	// at least this many.
+	MinReviews int
+	// MaterializeInherited copies the rulesets the source inherits from its
+	// organization onto every target as rulesets of their own.
+	MaterializeInherited bool
+	// OnError decides whether failures stop the sync. Unless it continues,
+	// a policy that can't be applied also stops the remaining policies.
+	OnError setter.FailurePolicy
+	// AuditLogUpload is the s3:// or gs:// URL the audit log of the
+	// transport is uploaded to after the run.
+	AuditLogUpload string
+	// ReportUpload is the s3://, gs:// or az:// URL below which the summary
+	// of every policy applied is uploaded as JSON.
+	ReportUpload string
+	// Provider is the forge of the owner, github when empty. Other
+	// providers only sync the branch protection of the source.
+	Provider string
+	// Snapshots saves the branch protection and rulesets of every target
+	// before changing them, so the run can be rolled back. Nil takes no
+	// snapshots.
+	Snapshots *snapshot.Store
+	// RunID identifies the run in its logs, reports, audit records and
+	// snapshots. A new ID is assigned when empty.
+	RunID string
+	// SourceStatus sets a commit status on the source of every policy
+	// telling whether it was rolled out.
+	SourceStatus bool
+	// Inventory caches the repositories listed between runs. Nil lists them
+	// every time.
+	Inventory *inventory.Cache
+}
+
+// Run syncs the branch protection and rulesets of the source repository
+// onto every other repository owned by owner, or applies every policy of the
+// configuration file onto the repositories it selects. When authenticated as
+// a GitHub App, the targets are limited to the repositories of the
+// installation.
+func Run(opts Options) {
+	if _, err := Sync(context.Background(), opts); err != nil {
+		log.Fatalf("Sync failed: %v\n", err)
+	}
+}
+
+// Sync is Run returning the summary of every policy applied, and an error
+// instead of exiting when the sync failed or some policies weren't fully
+// applied.
+func Sync(ctx context.Context, opts Options) ([]notify.Summary, error) {
+	ctx = startRun(ctx, &opts)
+	if !isGitHub(opts) {
+		return syncProvider(ctx, opts)
+	}
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, fmt.Errorf("creating the GitHub client: %w", err)
+	}
+	if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+		return nil, err
+	}
+	if err := preflightToken(ctx, client, opts); err != nil {
+		return nil, fmt.Errorf("preflight: %w", err)
+	}
 
-	ruleset := getter.GetRepoProtections(ctx, client, owner, sourceRepo)
-	// repos, err := getter.GetAllReposFromOrg(ctx, client, owner)
-	repos := make([]*github.Repository, 1)
-	repo, _, err := client.Repositories.Get(ctx, owner, "5ire-staking")
+	assignments, optedOut, err := assignPolicies(ctx, client, opts)
 	if err != nil {
-		log.Fatalf("Error fetching repositories: %v\n", err)
+		return nil, fmt.Errorf("fetching repositories: %w", err)
+	}
+	if opts.Credentials.IsFineGrained() {
+		var targets []*github.Repository
+		for _, a := range assignments {
+			targets = append(targets, a.targets...)
+		}
+		preflight.ProbeCoverage(ctx, client.Repositories, opts.Owner, targets)
+	}
+
+	defer uploadAuditLog(ctx, opts)
+	var summaries []notify.Summary
+	failed := 0
+	for _, a := range assignments {
+		summary, err := applyPolicy(ctx, client, opts, a.policy, a.targets, optedOut)
+		if err == nil || !summary.Started.IsZero() {
+			summaries = append(summaries, summary)
+		}
+		var partial *reposFailed
+		switch {
+		case err == nil:
+		case errors.As(err, &partial), opts.OnError.OnError == setter.Continue:
+			log.Printf("Error: %v\n", err)
+			failed++
+		default:
+			return summaries, fmt.Errorf("stopping the sync: %w", err)
+		}
+	}
+	if failed > 0 {
+		return summaries, fmt.Errorf("%d of %d policies were not fully applied", failed, len(assignments))
+	}
+	return summaries, nil
+}
+
+// uploadAuditLog uploads the audit log of the run when asked to. The log
+// stays on disk whether or not the upload succeeds.
+func uploadAuditLog(ctx context.Context, opts Options) {
+	if opts.AuditLogUpload == "" || opts.Transport.Audit == nil {
+		return
+	}
+	if err := upload.File(ctx, opts.Transport.Audit.Path, opts.AuditLogUpload); err != nil {
+		log.Printf("Error: %v\n", err)
+	}
+}
+
+// uploadSummary uploads the summary of a policy when asked to.
+func uploadSummary(ctx context.Context, opts Options, summary notify.Summary) {
+	if opts.ReportUpload == "" {
+		return
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err == nil {
+		report := "sync.json"
+		if summary.Policy != "" {
+			report = "sync-" + summary.Policy + ".json"
+		}
+		err = upload.Data(ctx, opts.ReportUpload, upload.Name(summary.Started, report), data)
+	}
+	if err != nil {
+		log.Printf("Error uploading the report: %v\n", err)
+	}
+}
+
+// preflightToken checks the credentials can administer the repositories
+// the run writes to, the targets it is given as with sync --self, and can
+// read the source, which may belong to another owner. The targets of a
+// whole-owner run are only known once listed, so just the scopes of the
+// credentials are checked for them.
+func preflightToken(ctx context.Context, client *ghclient.Client, opts Options) error {
+	if err := preflight.Token(ctx, client.RateLimit, client.Repositories, opts.Owner, opts.Targets...); err != nil {
+		return err
+	}
+	sourceOwner, source := sourceRepo(opts)
+	if source == "" {
+		return nil
+	}
+	return preflight.Readable(ctx, client.Repositories, sourceOwner, source)
+}
+
+// sourceRepo returns the owner and name of the source of the run, or of the
+// first source of the policies.
+func sourceRepo(opts Options) (string, string) {
+	if opts.Source != "" {
+		return policy.SourceRepo(opts.Owner, config.Policy{Source: opts.Source})
+	}
+	for _, p := range opts.Config.Policies {
+		if p.Source != "" {
+			return policy.SourceRepo(opts.Owner, p)
+		}
+	}
+	return "", ""
+}
+
+// assignment is a policy along with the targets it applies to.
+type assignment struct {
+	policy  config.Policy
+	targets []*github.Repository
+}
+
+// assignPolicies selects the targets of every policy of the configuration
+// file, or of the source repository when there are none. It also returns the
+// names of the repositories that opted out.
+func assignPolicies(ctx context.Context, client *ghclient.Client, opts Options) ([]assignment, []string, error) {
+	policies := opts.Config.Policies
+	if len(policies) == 0 {
+		policies = []config.Policy{{Source: opts.Source}}
+	}
+
+	repos, optedOut, err := selectTargets(ctx, client, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var properties map[string]map[string]string
+	if policy.NeedsProperties(policies) {
+		properties, err = getter.GetCustomPropertyValues(ctx, client.Organizations, opts.Owner)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching custom property values: %w", err)
+		}
+	}
+
+	// A repository selected by several policies only gets the first one
+	claimed := make(map[string]string)
+	assignments := make([]assignment, 0, len(policies))
+	for _, p := range policies {
+		a := assignment{policy: p}
+		source := ""
+		if sourceOwner, name := policy.SourceRepo(opts.Owner, p); strings.EqualFold(sourceOwner, opts.Owner) {
+			source = name
+		}
+		for _, repo := range policy.Select(filterTargets(repos, source), p.Selector, properties) {
+			if other, ok := claimed[repo.GetName()]; ok {
+				log.Printf("Repo %s is already covered by policy %s, not applying policy %s\n", repo.GetName(), other, p.Name)
+				continue
+			}
+			claimed[repo.GetName()] = p.Name
+			a.targets = append(a.targets, repo)
+		}
+		assignments = append(assignments, a)
+	}
+
+	if opts.Config.Rego.Enabled() {
+		if properties == nil {
+			properties = regoProperties(ctx, client, opts.Owner)
+		}
+		assignments, err = regoAssign(ctx, opts, repos, properties, claimed, assignments)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return assignments, optedOut, nil
+}
+
+// applyPolicy applies a single policy to its targets and returns the
+// summary it reported. It fails when the policy can't be applied, without a
+// summary, or when the sync was aborted.
+func applyPolicy(ctx context.Context, client *ghclient.Client, opts Options, p config.Policy, targets []*github.Repository, optedOut []string) (notify.Summary, error) {
+	started := time.Now()
+	name := p.Source
+	if p.Name != "" {
+		logging.Infof("Applying policy %s to %d repositories\n", p.Name, len(targets))
+		name = "policy " + p.Name
+	}
+
+	protections, err := policy.Resolve(ctx, client, opts.Owner, p)
+	if err != nil {
+		return notify.Summary{}, fmt.Errorf("fetching the protection of %s: %w", name, err)
+	}
+	if err := validate.Source(protections); err != nil {
+		return notify.Summary{}, fmt.Errorf("%s can't be synced:\n%w", name, err)
+	}
+	if err := resolveActors(ctx, client, opts, p, protections); err != nil {
+		return notify.Summary{}, fmt.Errorf("%s can't be synced: %w", name, err)
+	}
+	inheritedRulesets(opts, protections)
+
+	var findings preflight.Result
+	if opts.Preflight {
+		findings = preflight.Run(ctx, client.Repositories, opts.Owner, targets)
+	}
+
+	setOpts := setterOptions(client, opts)
+	setOpts.Steps, err = settingSteps(ctx, client, opts, p)
+	if err != nil {
+		return notify.Summary{}, fmt.Errorf("fetching the settings of %s: %w", name, err)
+	}
+
+	checkBudget(ctx, client, budgetRun(opts, len(targets), len(protections.Rulesets), len(setOpts.Steps)))
+
+	if opts.Interactive {
+		// Prompts are answered one repository at a time
+		setOpts.Concurrency.MaxWorkers = 1
+		prompt := newInteractive(client.Repositories, opts.Owner, os.Stdin, os.Stderr)
+		setOpts.BeforeApply = append(setOpts.BeforeApply, prompt.BeforeApply)
+	}
+	// Last, so the targets the hooks above skip aren't saved
+	if opts.Snapshots != nil {
+		setOpts.BeforeApply = append(setOpts.BeforeApply, snapshotHook(client, opts))
+	}
+	if len(opts.Config.Notifications.ResultWebhooks) > 0 {
+		events := newResultEvents(client, opts, p)
+		setOpts.BeforeApply = append(setOpts.BeforeApply, events.BeforeApply)
+		setOpts.AfterApply = append(setOpts.AfterApply, events.AfterApply)
+	}
+
+	var mu sync.Mutex
+	var empty, unsupported []string
+	setOpts.AfterApply = append(setOpts.AfterApply, func(_ context.Context, repo *github.Repository, result setter.ApplyResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Empty {
+			empty = append(empty, repo.GetName())
+		}
+		if result.UnsupportedPlan {
+			unsupported = append(unsupported, repo.GetName())
+		}
+	})
+	var waits int
+	var waited time.Duration
+	setOpts.OnRateLimit = func(ctx context.Context, wait setter.RateLimitWait) {
+		mu.Lock()
+		waits++
+		waited += wait.Wait
+		mu.Unlock()
+		announceWait(ctx, opts, wait)
+	}
+
+	var held []string
+	var failures map[string]error
+	var aborted error
+	if opts.Canary.Enabled() {
+		cohort, rest := opts.Canary.split(targets)
+		failures, aborted = syncTargets(ctx, client, opts.Owner, cohort, protections, setOpts)
+		switch {
+		case len(rest) == 0:
+		case len(failures) > 0:
+			log.Printf("Canary failed on %d of %d repositories, not syncing the remaining %d\n", len(failures), len(cohort), len(rest))
+			held = repoNames(rest)
+		case !opts.Canary.proceed(ctx, len(cohort), len(rest)):
+			log.Printf("Rollout stopped after the canary, not syncing the remaining %d repositories\n", len(rest))
+			held = repoNames(rest)
+		default:
+			var more map[string]error
+			more, aborted = syncTargets(ctx, client, opts.Owner, rest, protections, setOpts)
+			for repo, err := range more {
+				failures[repo] = err
+			}
+		}
+	} else {
+		failures, aborted = syncTargets(ctx, client, opts.Owner, targets, protections, setOpts)
+	}
+	// The run didn't apply the policy everywhere, which CI has to notice
+	if len(held) > 0 && aborted == nil {
+		aborted = fmt.Errorf("the canary held back %d repositories", len(held))
+	}
+	if len(failures) > 0 && aborted == nil {
+		aborted = &reposFailed{failed: len(failures), total: len(targets)}
+	}
+
+	sort.Strings(empty)
+	sort.Strings(unsupported)
+	summary := notify.NewSummary(opts.Owner, p.Source, started, len(targets), failures)
+	summary.RunID = opts.RunID
+	summary.Policy = p.Name
+	summary.Orphaned = findings.Orphaned
+	summary.Empty = empty
+	summary.UnsupportedPlan = unsupported
+	summary.OptedOut = optedOut
+	summary.Held = held
+	summary.RateLimitWaits = waits
+	summary.RateLimitWaitSeconds = int(waited.Seconds())
+	notify.Send(ctx, opts.Config.Notifications, summary)
+	uploadSummary(ctx, opts, summary)
+	if opts.SourceStatus {
+		reportSourceStatus(ctx, client, opts.Owner, summary, aborted)
+	}
+	if actions.Enabled() {
+		if err := actions.SyncSummary(os.Stderr, summary); err != nil {
+			log.Printf("Error writing the step summary: %v\n", err)
+		}
+	}
+	return summary, aborted
+}
+
+// reposFailed is the error of a policy some targets of which failed without
+// aborting the run. Whatever the failure policy, the run carries on with the
+// next policies, and fails once they are applied.
+type reposFailed struct {
+	failed, total int
+}
+
+func (e *reposFailed) Error() string {
+	return fmt.Sprintf("%d of %d repositories failed", e.failed, e.total)
+}
+
+// inheritedRulesets materializes the rulesets the source inherits when asked
+// to, and otherwise tells they aren't synced.
+func inheritedRulesets(opts Options, protections *types.RepoProtection) {
+	if opts.MaterializeInherited {
+		policy.MaterializeInherited(protections)
 		return
 	}
-	repos[0] = repo
+	for _, ruleset := range protections.InheritedRulesets {
+		logging.Infof("Not syncing ruleset %q inherited from %s; pass --inherited-rulesets=materialize to copy it\n", ruleset.Name, ruleset.Source)
+	}
+}
 
-	setter.SetRuleset(ctx, client, owner, repos, ruleset)
+// syncTargets syncs the protection to targets and returns the failures keyed
+// by repository, along with the error the sync was aborted with.
+func syncTargets(ctx context.Context, client *ghclient.Client, owner string, targets []*github.Repository, protections *types.RepoProtection, setOpts setter.Options) (map[string]error, error) {
+	results, err := setter.SetRuleset(ctx, client, owner, targets, protections, setOpts)
+	if !logging.Quiet() {
+		writeResults(os.Stderr, results)
+	}
+	return setter.Failures(results), err
 }
 
-func getGitHubClient(ctx context.Context, token string) *github.Client {
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
-	return client
+// resolveActors translates the restrictions of a policy whose source is in
+// another organization, or renamed by the actor mapping, for the targets.
+func resolveActors(ctx context.Context, client *ghclient.Client, opts Options, p config.Policy, protections *types.RepoProtection) error {
+	sourceOwner, _ := policy.SourceRepo(opts.Owner, p)
+	crossOrg := p.Source != "" && !strings.EqualFold(sourceOwner, opts.Owner)
+	return actors.Resolve(ctx, client.Actors, opts.Owner, crossOrg, protections.BranchProtection, opts.Config.Actors)
+}
+
+// setterOptions returns the setter options shared by syncing and planning.
+func setterOptions(client *ghclient.Client, opts Options) setter.Options {
+	setOpts := setter.Options{Concurrency: opts.Config.Concurrency, OnError: opts.OnError, SkipUnchanged: opts.Transport.CacheDir != ""}
+	// First, so the transforms below see the names of the target
+	setOpts.Transforms = append(setOpts.Transforms, setter.Template(opts.Owner))
+	if opts.Config.StatusChecks.Enabled() {
+		setOpts.Transforms = append(setOpts.Transforms, checks.Transform(client, opts.Owner, opts.Config.StatusChecks))
+	}
+	if opts.EnforceAdmins != nil {
+		setOpts.Transforms = append(setOpts.Transforms, setter.EnforceAdmins(*opts.EnforceAdmins))
+	}
+	if opts.MinReviews > 0 {
+		setOpts.Transforms = append(setOpts.Transforms, setter.MinReviews(opts.MinReviews))
+	}
+	setOpts.Transforms = append(setOpts.Transforms, actors.Installed(client.AppInstallations, opts.Owner, opts.Config.Actors.DropUnknown))
+	// Runs last, so an unselected field keeps the value of the target even
+	// when a transform above adjusted it
+	if opts.Fields != nil {
+		setOpts.Transforms = append(setOpts.Transforms, setter.KeepUnselected(client.Repositories, opts.Owner, opts.Fields))
+		setOpts.Fields = opts.Fields
+	}
+	return setOpts
+}
+
+// announceWait tells the workflow and Slack, when enabled, that the sync
+// waits for the rate limit to reset.
+func announceWait(ctx context.Context, opts Options, wait setter.RateLimitWait) {
+	if actions.Enabled() {
+		actions.Annotate(os.Stderr, "warning", "Waiting for the rate limit",
+			fmt.Sprintf("Resuming at %s with %d repositories left.", wait.Resume.UTC().Format(time.RFC3339), wait.Remaining))
+	}
+	notify.RateLimited(ctx, opts.Config.Notifications, opts.Owner, wait.Resume, wait.Remaining)
+}
+
+func repoNames(repos []*github.Repository) []string {
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.GetName())
+	}
+	return names
+}
+
+// selectTargets returns the repositories to sync, along with the names of
+// the repositories that opted out.
+func selectTargets(ctx context.Context, client *ghclient.Client, opts Options) ([]*github.Repository, []string, error) {
+	var repos []*github.Repository
+	var err error
+	if len(opts.Targets) > 0 {
+		repos, err = getRepos(ctx, client.Repositories, opts.Owner, opts.Targets)
+	} else if opts.Team != "" {
+		repos, err = getter.GetTeamRepos(ctx, client.Teams, opts.Owner, opts.Team)
+		if err != nil {
+			err = fmt.Errorf("listing the repositories of team %s: %w", opts.Team, err)
+		}
+	} else {
+		repos, err = listRepos(ctx, client, opts)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(opts.Properties) > 0 {
+		values, err := getter.GetCustomPropertyValues(ctx, client.Organizations, opts.Owner)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching custom property values: %w", err)
+		}
+		repos = getter.FilterByProperties(repos, values, opts.Properties)
+	}
+
+	repos = opts.Filter.Apply(repos)
+
+	targets, optedOut := getter.FilterOptedOut(filterTargets(repos, opts.Source), opts.Config.OptOut)
+	return targets, optedOut, nil
+}
+
+// listRepos lists the repositories the credentials manage: the repositories
+// of the App installation, or every repository of the owner. The listing is
+// reused from the inventory cache while it is fresh.
+func listRepos(ctx context.Context, client *ghclient.Client, opts Options) ([]*github.Repository, error) {
+	key := opts.Owner
+	if opts.Credentials.IsApp() {
+		key += "-installation"
+	}
+	if opts.Inventory != nil {
+		repos, listed, ok, err := opts.Inventory.Load(key)
+		if err != nil {
+			logging.Debugf("Ignoring the repository cache of %s: %v\n", opts.Owner, err)
+		}
+		if ok {
+			logging.Infof("Using the %d repositories of %s listed at %s; pass --refresh to list them again\n", len(repos), opts.Owner, listed.Format(time.RFC3339))
+			return repos, nil
+		}
+	}
+
+	var repos []*github.Repository
+	var err error
+	if opts.Credentials.IsApp() {
+		repos, err = getter.GetAllReposFromInstallation(ctx, client.Apps, opts.Owner)
+	} else {
+		repos, err = getter.GetAllReposFromOrg(ctx, client.Repositories, opts.Owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.Inventory != nil {
+		if err := opts.Inventory.Save(key, repos); err != nil {
+			log.Printf("Not caching the repositories of %s: %v\n", opts.Owner, err)
+		}
+	}
+	return repos, nil
+}
+
+// getRepos fetches the named repositories of owner.
+func getRepos(ctx context.Context, client ghclient.BranchProtectionReader, owner string, names []string) ([]*github.Repository, error) {
+	repos := make([]*github.Repository, 0, len(names))
+	for _, name := range names {
+		repo, _, err := client.Get(ctx, owner, name)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// filterTargets drops the source repository and archived repositories, which
+// are read-only and cannot be protected, from the list of sync targets.
+func filterTargets(repos []*github.Repository, sourceRepo string) []*github.Repository {
+	targets := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if repo.GetName() == sourceRepo || repo.GetArchived() {
+			continue
+		}
+		targets = append(targets, repo)
+	}
+	return targets
+}
+
+// startRun assigns a run ID to opts when it has none, and tags the log lines
+// and the requests sent under the returned context with it.
+func startRun(ctx context.Context, opts *Options) context.Context {
+	if opts.RunID == "" {
+		opts.RunID = runid.New()
+	}
+	logging.SetRunID(opts.RunID)
+	return runid.With(ctx, opts.RunID)
+}
+
+// RunE2E runs an end-to-end sync against temporary repositories created in
+// the test organization given as the owner.
+func RunE2E(opts Options, targets int, keep bool) error {
+	ctx := context.Background()
+	client, err := getGitHubClient(ctx, opts.Credentials, opts.Transport, nil)
+	if err != nil {
+		return err
+	}
+	return e2e.Run(ctx, client, opts.Owner, targets, keep)
+}
+
+// RepoNames lists the names of the repositories of the owner.
+func RepoNames(opts Options) ([]string, error) {
+	ctx := context.Background()
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := listRepos(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.GetName())
+	}
+	return names, nil
 }
@@ -0,0 +1,119 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPreflightToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rl := mocks.NewMockRateLimitReader(ctrl)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{RateLimit: rl, Repositories: repos}
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}
+	rl.EXPECT().Get(gomock.Any()).Return(&github.RateLimits{}, ok, nil).AnyTimes()
+
+	// A whole-owner run only reads the source, under its own owner
+	repos.EXPECT().Get(gomock.Any(), "acme", "template").Return(&github.Repository{}, ok, nil)
+	if err := preflightToken(context.Background(), client, Options{Owner: "octo", Source: "acme/template"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A self-service run probes its target and only reads the source
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}, Message: "Must have admin rights to Repository."}
+	repos.EXPECT().ListTeams(gomock.Any(), "octo", "api", gomock.Any()).Return(nil, ok, nil)
+	repos.EXPECT().Get(gomock.Any(), "octo", "template").Return(&github.Repository{}, ok, nil)
+	if err := preflightToken(context.Background(), client, Options{Owner: "octo", Source: "template", Targets: []string{"api"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// The source of a policy is read the same way
+	repos.EXPECT().Get(gomock.Any(), "acme", "baseline").Return(nil, nil, forbidden)
+	cfg := &config.Config{Policies: []config.Policy{{Source: "acme/baseline"}}}
+	if err := preflightToken(context.Background(), client, Options{Owner: "octo", Config: cfg}); err == nil {
+		t.Error("got no error for a source the credentials can't read")
+	}
+
+	repos.EXPECT().ListTeams(gomock.Any(), "octo", "web", gomock.Any()).Return(nil, nil, forbidden)
+	if err := preflightToken(context.Background(), client, Options{Owner: "octo", Source: "template", Targets: []string{"web"}}); err == nil {
+		t.Error("got no error for a target the credentials can't administer")
+	}
+}
+
+func TestSyncFailsWhenARepositoryFails(t *testing.T) {
+	var puts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+		switch {
+		case r.Method == http.MethodPut && strings.HasSuffix(path, "/branches/main/protection"):
+			puts++
+			if strings.HasPrefix(path, "/repos/octo/web/") {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				io.WriteString(w, `{"message": "Validation Failed"}`)
+				return
+			}
+			io.WriteString(w, `{"enforce_admins": {"enabled": true}}`)
+		case path == "/rate_limit":
+			io.WriteString(w, `{"resources": {}}`)
+		case strings.HasSuffix(path, "/teams"), strings.HasSuffix(path, "/rulesets"):
+			io.WriteString(w, `[]`)
+		case path == "/repos/octo/template/branches/main/protection":
+			io.WriteString(w, `{"enforce_admins": {"enabled": true}}`)
+		case path == "/repos/octo/template/branches/main/protection/required_signatures":
+			io.WriteString(w, `{"enabled": false}`)
+		case strings.Count(path, "/") == 3 && strings.HasPrefix(path, "/repos/octo/"):
+			name := strings.TrimPrefix(path, "/repos/octo/")
+			fmt.Fprintf(w, `{"name": %q, "full_name": "octo/%s", "default_branch": "main", "owner": {"login": "octo"}}`, name, name)
+		default:
+			t.Logf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := Options{
+		Owner:       "octo",
+		Source:      "template",
+		Targets:     []string{"api", "web"},
+		Credentials: Credentials{Token: "token", BaseURL: srv.URL + "/"},
+		Config:      &config.Config{},
+		OnError:     setter.FailurePolicy{OnError: setter.Continue},
+	}
+	summaries, err := Sync(context.Background(), opts)
+	if err == nil {
+		t.Fatal("Sync() succeeded although a repository failed")
+	}
+	if puts != 2 {
+		t.Errorf("got %d PUTs, want both repositories attempted", puts)
+	}
+	if len(summaries) != 1 || len(summaries[0].Failures) != 1 {
+		t.Errorf("got summaries %+v, want web failed", summaries)
+	}
+}
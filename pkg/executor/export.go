@@ -0,0 +1,106 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/export"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/gitlab"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+)
+
+// Export writes the protection of the source repository, or of every
+// repository of the owner when all is set, to w in the requested format.
+// The gitlab format is a single JSON document, holding a list of projects
+// when all is set.
+func Export(opts Options, format string, all bool, w io.Writer) error {
+	var projects []*gitlab.Project
+	write := func(repo string, rp *types.RepoProtection) error { return export.Terraform(w, opts.Owner, repo, rp) }
+	switch format {
+	case "terraform":
+	case "gitlab":
+		write = func(repo string, rp *types.RepoProtection) error {
+			project, err := gitlab.New(repo, rp)
+			if err != nil {
+				return err
+			}
+			projects = append(projects, project)
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+
+	ctx := context.Background()
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return err
+	}
+
+	if !all {
+		if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+			return err
+		}
+		rp, err := getter.FetchRepoProtections(ctx, client, opts.Owner, opts.Source)
+		if err != nil {
+			return fmt.Errorf("fetching protection of %s/%s: %w", opts.Owner, opts.Source, err)
+		}
+		if err := write(opts.Source, rp); err != nil {
+			return err
+		}
+		if format == "gitlab" {
+			return writeJSON(w, projects[0])
+		}
+		return nil
+	}
+
+	repos, err := listRepos(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+		rp, err := getter.FetchRepoProtections(ctx, client, opts.Owner, repo.GetName())
+		if err != nil {
+			log.Printf("Skipping %s/%s: %v\n", opts.Owner, repo.GetName(), err)
+			continue
+		}
+		if format == "gitlab" && rp.BranchProtection == nil {
+			continue
+		}
+		if err := write(repo.GetName(), rp); err != nil {
+			return err
+		}
+	}
+	if format == "gitlab" {
+		return writeJSON(w, projects)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
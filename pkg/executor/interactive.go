@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/color"
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// interactive asks the operator whether to apply the protection to each
+// repository, after showing how it differs from the current protection.
+type interactive struct {
+	client ghclient.BranchProtectionReader
+	owner  string
+	in     *bufio.Reader
+	out    io.Writer
+
+	mu       sync.Mutex
+	applyAll bool
+	quit     bool
+}
+
+func newInteractive(client ghclient.BranchProtectionReader, owner string, in io.Reader, out io.Writer) *interactive {
+	return &interactive{client: client, owner: owner, in: bufio.NewReader(in), out: out}
+}
+
+// BeforeApply is a setter.BeforeApplyHook prompting for every repository
+// until the operator answers apply-all or quit.
+func (i *interactive) BeforeApply(ctx context.Context, repo *github.Repository, desired *github.ProtectionRequest) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	switch {
+	case i.quit:
+		return true, nil
+	case i.applyAll:
+		return false, nil
+	}
+
+	fmt.Fprintf(i.out, "\n%s/%s@%s\n", i.owner, repo.GetName(), repo.GetDefaultBranch())
+	if branch := repo.GetDefaultBranch(); branch != "" {
+		current, err := getter.FetchBranchProtection(ctx, i.client, i.owner, repo.GetName(), branch)
+		if err != nil {
+			fmt.Fprintf(i.out, "  current protection unavailable: %v\n", err)
+		} else if changes := setter.Diff(desired, current); len(changes) == 0 {
+			fmt.Fprintln(i.out, "  branch protection already up to date")
+		} else {
+			deltas := make([]diff.Delta, 0, len(changes))
+			for _, d := range changes {
+				deltas = append(deltas, diff.Delta{Field: d.Field, From: d.Applied, To: d.Requested})
+			}
+			diff.Write(i.out, "  ", deltas, color.Enabled(i.out))
+		}
+	}
+
+	for {
+		fmt.Fprint(i.out, "Apply? [a]pply/[s]kip/apply-a[l]l/[q]uit: ")
+		answer, err := i.in.ReadString('\n')
+		if err != nil && answer == "" {
+			// No more input, treat it as quit rather than applying unattended
+			i.quit = true
+			return true, nil
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "apply", "y", "yes":
+			return false, nil
+		case "s", "skip", "n", "no":
+			return true, nil
+		case "l", "apply-all", "all":
+			i.applyAll = true
+			return false, nil
+		case "q", "quit":
+			i.quit = true
+			return true, nil
+		}
+	}
+}
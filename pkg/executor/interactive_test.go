@@ -0,0 +1,61 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInteractive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reader := mocks.NewMockBranchProtectionReader(ctrl)
+	reader.EXPECT().GetBranchProtection(gomock.Any(), "octo", gomock.Any(), "main").
+		Return(&github.Protection{EnforceAdmins: &github.AdminEnforcement{Enabled: false}}, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil).Times(3)
+
+	var out bytes.Buffer
+	prompt := newInteractive(reader, "octo", strings.NewReader("s\nwhat\na\nl\n"), &out)
+	desired := &github.ProtectionRequest{EnforceAdmins: true}
+	repo := func(name string) *github.Repository {
+		return &github.Repository{Name: github.String(name), DefaultBranch: github.String("main")}
+	}
+
+	want := []bool{true, false, false, false}
+	for i, name := range []string{"skipped", "applied", "all", "after-all"} {
+		skip, err := prompt.BeforeApply(context.Background(), repo(name), desired)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if skip != want[i] {
+			t.Errorf("%s: skip = %v, want %v", name, skip, want[i])
+		}
+	}
+	if !strings.Contains(out.String(), "- enforce_admins: false\n  + enforce_admins: true") {
+		t.Errorf("diff not shown:\n%s", out.String())
+	}
+
+	quitting := newInteractive(reader, "octo", strings.NewReader(""), &out)
+	if skip, _ := quitting.BeforeApply(context.Background(), &github.Repository{Name: github.String("eof")}, desired); !skip {
+		t.Error("end of input should skip the repository")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// listWorkers bounds the repositories whose status is read at once.
+const listWorkers = 10
+
+// RepoStatus is the protection of a repository at a glance.
+type RepoStatus struct {
+	Repo          string `json:"repo"`
+	DefaultBranch string `json:"default_branch"`
+	// Protected is nil for an empty repository, whose default branch
+	// doesn't exist yet.
+	Protected *bool `json:"protected"`
+	// Rulesets counts the rulesets of the repository, not those it inherits.
+	Rulesets int       `json:"rulesets"`
+	Pushed   time.Time `json:"pushed_at"`
+	Archived bool      `json:"archived"`
+	// Error is why the status couldn't be read.
+	Error string `json:"error,omitempty"`
+}
+
+// List returns the protection status of every repository the credentials
+// manage, sorted by name. A repository that can't be read is listed with an
+// error instead of failing the listing.
+func List(opts Options) ([]RepoStatus, error) {
+	ctx := context.Background()
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := listRepos(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]RepoStatus, len(repos))
+	semaphore := make(chan struct{}, listWorkers)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, repo *github.Repository) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			statuses[i] = repoStatus(ctx, client.Repositories, opts.Owner, repo)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Repo < statuses[j].Repo })
+	return statuses, nil
+}
+
+// repoStatus reads the protection of the default branch and the rulesets of
+// repo.
+func repoStatus(ctx context.Context, client ghclient.Repositories, owner string, repo *github.Repository) RepoStatus {
+	status := RepoStatus{
+		Repo:          repo.GetName(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		Pushed:        repo.GetPushedAt().Time,
+		Archived:      repo.GetArchived(),
+	}
+	protection, err := getter.FetchBranchProtection(ctx, client, owner, status.Repo, status.DefaultBranch)
+	switch {
+	case setter.IsBranchNotFound(err):
+	case err != nil:
+		status.Error = err.Error()
+		return status
+	default:
+		protected := protection != nil
+		status.Protected = &protected
+	}
+	rulesets, response, err := client.GetAllRulesets(ctx, owner, status.Repo, false)
+	if err == nil {
+		err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+	}
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Rulesets = len(rulesets)
+	return status
+}
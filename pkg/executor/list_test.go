@@ -0,0 +1,57 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRepoStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockRepositories(ctrl)
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	pushed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := func(name string) *github.Repository {
+		return &github.Repository{Name: github.String(name), DefaultBranch: github.String("main"), PushedAt: &github.Timestamp{Time: pushed}}
+	}
+
+	client.EXPECT().GetBranchProtection(gomock.Any(), "octo", "api", "main").Return(&github.Protection{}, ok, nil)
+	client.EXPECT().GetAllRulesets(gomock.Any(), "octo", "api", false).Return([]*github.Ruleset{{}, {}}, ok, nil)
+	status := repoStatus(context.Background(), client, "octo", repo("api"))
+	if status.Protected == nil || !*status.Protected || status.Rulesets != 2 || !status.Pushed.Equal(pushed) || status.Error != "" {
+		t.Errorf("got %+v, want a protected repository with 2 rulesets", status)
+	}
+
+	client.EXPECT().GetBranchProtection(gomock.Any(), "octo", "open", "main").Return(nil, nil, github.ErrBranchNotProtected)
+	client.EXPECT().GetAllRulesets(gomock.Any(), "octo", "open", false).Return(nil, ok, nil)
+	if status := repoStatus(context.Background(), client, "octo", repo("open")); status.Protected == nil || *status.Protected {
+		t.Errorf("got %+v, want an unprotected repository", status)
+	}
+
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Branch not found"}
+	client.EXPECT().GetBranchProtection(gomock.Any(), "octo", "empty", "main").Return(nil, nil, notFound)
+	client.EXPECT().GetAllRulesets(gomock.Any(), "octo", "empty", false).Return(nil, ok, nil)
+	if status := repoStatus(context.Background(), client, "octo", repo("empty")); status.Protected != nil || status.Error != "" {
+		t.Errorf("got %+v, want an empty repository without a protection status", status)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/plan"
+	"github.com/arush-sal/repo-protection-sync/pkg/policy"
+	"github.com/arush-sal/repo-protection-sync/pkg/preflight"
+	"github.com/arush-sal/repo-protection-sync/pkg/validate"
+)
+
+// Plan computes the API mutations a sync would make without making them.
+func Plan(opts Options) (*plan.Plan, error) {
+	ctx := startRun(context.Background(), &opts)
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+		return nil, err
+	}
+
+	assignments, _, err := assignPolicies(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &plan.Plan{Version: plan.Version, Owner: opts.Owner, RunID: opts.RunID, Created: time.Now().UTC()}
+	for _, a := range assignments {
+		protections, err := policy.Resolve(ctx, client, opts.Owner, a.policy)
+		if err != nil {
+			return nil, err
+		}
+		if err := validate.Source(protections); err != nil {
+			return nil, err
+		}
+		if err := resolveActors(ctx, client, opts, a.policy, protections); err != nil {
+			return nil, err
+		}
+		inheritedRulesets(opts, protections)
+		changes, err := plan.Build(ctx, client, opts.Owner, a.policy.Name, protections, a.targets, setterOptions(client, opts))
+		if err != nil {
+			return nil, err
+		}
+		p.Changes = append(p.Changes, changes...)
+	}
+	return p, nil
+}
+
+// Apply executes a plan created by Plan, returning the errors of the
+// repositories that rejected their changes keyed by repository name.
+func Apply(opts Options, p *plan.Plan) (map[string]error, error) {
+	if !strings.EqualFold(p.Owner, opts.Owner) {
+		return nil, fmt.Errorf("the plan was created for %s, not %s", p.Owner, opts.Owner)
+	}
+	ctx := startRun(context.Background(), &opts)
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Changes) > 0 {
+		if err := preflight.Token(ctx, client.RateLimit, client.Repositories, opts.Owner, p.Changes[0].Repo); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Snapshots != nil {
+		if err := snapshotPlan(ctx, client, opts, p.Repos()); err != nil {
+			return nil, fmt.Errorf("taking snapshots: %w", err)
+		}
+	}
+	return plan.Apply(ctx, client, p)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/plan"
+)
+
+func TestApplyMatchesTheOwnerCaseInsensitively(t *testing.T) {
+	opts := Options{Owner: "Octo", Credentials: Credentials{Token: "token"}}
+	if _, err := Apply(opts, &plan.Plan{Owner: "octo"}); err != nil {
+		t.Errorf("got %v for a plan of the same owner", err)
+	}
+	if _, err := Apply(opts, &plan.Plan{Owner: "acme"}); err == nil {
+		t.Error("applied the plan of another owner")
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/arush-sal/repo-protection-sync/pkg/provider"
+	"github.com/arush-sal/repo-protection-sync/pkg/transport"
+)
+
+// Providers are the forges a sync can target. Only github supports more
+// than branch protection.
+var Providers = []string{"github", "gitea", "bitbucket"}
+
+// isGitHub reports whether the sync targets GitHub, the default provider.
+func isGitHub(opts Options) bool {
+	return opts.Provider == "" || opts.Provider == "github"
+}
+
+// syncProvider syncs the branch protection of the source repository through
+// a provider other than GitHub.
+func syncProvider(ctx context.Context, opts Options) ([]notify.Summary, error) {
+	p, err := newProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Source == AutoSource {
+		return nil, fmt.Errorf("the %s provider has no baseline repository, name the source with --repo", opts.Provider)
+	}
+	if len(opts.Config.Policies) > 0 {
+		return nil, fmt.Errorf("the %s provider only syncs the source repository, not the policies of the configuration file", opts.Provider)
+	}
+	defer uploadAuditLog(ctx, opts)
+	summary, err := provider.Sync(ctx, p, opts.Owner, opts.Source, opts.Targets)
+	if err != nil {
+		return nil, err
+	}
+	summary.RunID = opts.RunID
+	notify.Send(ctx, opts.Config.Notifications, summary)
+	uploadSummary(ctx, opts, summary)
+	if len(summary.Failures) > 0 {
+		return []notify.Summary{summary}, fmt.Errorf("%d of %d repositories were not synced", len(summary.Failures), summary.Targets)
+	}
+	return []notify.Summary{summary}, nil
+}
+
+// newProvider returns the provider of opts, authenticated with the token.
+func newProvider(opts Options) (provider.Provider, error) {
+	switch opts.Provider {
+	case "gitea":
+		if opts.Credentials.BaseURL == "" {
+			return nil, errors.New("the gitea provider requires the URL of the instance")
+		}
+		if opts.Credentials.IsApp() {
+			return nil, errors.New("the gitea provider requires a token")
+		}
+		return provider.Gitea{
+			BaseURL: opts.Credentials.BaseURL,
+			Token:   opts.Credentials.Token,
+			HTTP:    &http.Client{Transport: transport.New(opts.Transport, http.DefaultTransport)},
+		}, nil
+	case "bitbucket":
+		if opts.Credentials.IsApp() {
+			return nil, errors.New("the bitbucket provider requires a token")
+		}
+		return provider.Bitbucket{
+			BaseURL: opts.Credentials.BaseURL,
+			Token:   opts.Credentials.Token,
+			HTTP:    &http.Client{Transport: transport.New(opts.Transport, http.DefaultTransport)},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %q", opts.Provider)
+	}
+}
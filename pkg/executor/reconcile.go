@@ -0,0 +1,98 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/color"
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/gitlab"
+	"github.com/arush-sal/repo-protection-sync/pkg/reconcile"
+)
+
+// ReconcileOptions names the policy file a source is reconciled with.
+type ReconcileOptions struct {
+	// File is the policy file exported from the source with --format gitlab.
+	File string
+	// Repo, as owner/repo, is where the policy file is versioned. When set,
+	// a drifted source gets a pull request updating the file at Path there.
+	Repo string
+	Path string
+}
+
+// Reconcile compares the policy file to the live protection of the source,
+// writes the changes made to the source since the file was exported, and
+// proposes to update the file when asked to. It reports whether the source
+// drifted.
+func Reconcile(opts Options, ro ReconcileOptions, w io.Writer) (bool, error) {
+	ctx := startRun(context.Background(), &opts)
+	file, err := gitlab.Read(ro.File)
+	if err != nil {
+		return false, err
+	}
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return false, err
+	}
+	if err := resolveSource(ctx, client.Repositories, &opts); err != nil {
+		return false, err
+	}
+	live, err := getter.FetchRepoProtections(ctx, client, opts.Owner, opts.Source)
+	if err != nil {
+		return false, fmt.Errorf("fetching protection of %s/%s: %w", opts.Owner, opts.Source, err)
+	}
+	deltas, current, err := reconcile.Drift(file, live)
+	if err != nil {
+		return false, err
+	}
+	if len(deltas) == 0 {
+		log.Printf("%s matches the protection of %s/%s\n", ro.File, opts.Owner, opts.Source)
+		return false, nil
+	}
+	fmt.Fprintf(w, "%s/%s was changed since %s was exported:\n", opts.Owner, opts.Source, ro.File)
+	if err := diff.Write(w, "  ", deltas, color.Enabled(w)); err != nil {
+		return true, err
+	}
+	if ro.Repo == "" {
+		return true, nil
+	}
+
+	repoOwner, repo, ok := strings.Cut(ro.Repo, "/")
+	if !ok {
+		repoOwner, repo = opts.Owner, ro.Repo
+	}
+	path := ro.Path
+	if path == "" {
+		path = ro.File
+	}
+	source := opts.Owner + "/" + opts.Source
+	pr, err := reconcile.Propose(ctx, client, repoOwner, repo, path, source, current)
+	if err != nil {
+		return true, fmt.Errorf("proposing the policy of %s: %w", source, err)
+	}
+	if pr == nil {
+		log.Printf("Branch %s of %s/%s already proposes a policy update\n", reconcile.Branch, repoOwner, repo)
+	} else {
+		log.Printf("Opened %s\n", pr.GetHTMLURL())
+	}
+	return true, nil
+}
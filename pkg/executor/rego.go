@@ -0,0 +1,93 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/policy"
+	"github.com/arush-sal/repo-protection-sync/pkg/rego"
+	"github.com/google/go-github/v59/github"
+)
+
+// regoAssign lets the Rego policy of the configuration decide which of the
+// repos are synced, and with which policy. claimed holds the policy the
+// selectors assigned to each repository; the Rego policy keeps it unless it
+// names another one.
+func regoAssign(ctx context.Context, opts Options, repos []*github.Repository, properties map[string]map[string]string, claimed map[string]string, assignments []assignment) ([]assignment, error) {
+	inputs := make([]rego.Input, 0, len(repos))
+	for _, repo := range repos {
+		inputs = append(inputs, rego.NewInput(repo, properties[repo.GetName()], claimed[repo.GetName()]))
+	}
+	cfg := opts.Config.Rego
+	evaluator := rego.Evaluator{Binary: cfg.Binary, Policy: cfg.Policy, Query: cfg.Query}
+	decisions, err := evaluator.Evaluate(ctx, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating the Rego policy: %w", err)
+	}
+
+	index := make(map[string]int, len(assignments))
+	reassigned := make([]assignment, len(assignments))
+	for i, a := range assignments {
+		index[a.policy.Name] = i
+		reassigned[i].policy = a.policy
+	}
+
+	for _, repo := range repos {
+		name := repo.GetName()
+		decision := decisions[name]
+		if !decision.Sync {
+			if _, ok := claimed[name]; ok {
+				logging.Infof("Not syncing %s as decided by the Rego policy\n", name)
+			}
+			continue
+		}
+		target, selected := decision.Policy, true
+		if target == "" {
+			target, selected = claimed[name]
+		}
+		if !selected {
+			logging.Infof("The Rego policy syncs %s but names no policy and no selector matches it, skipping\n", name)
+			continue
+		}
+		i, ok := index[target]
+		if !ok {
+			return nil, fmt.Errorf("the Rego policy applies the unknown policy %q to %s", target, name)
+		}
+		// The source of a policy isn't one of its targets
+		if sourceOwner, source := policy.SourceRepo(opts.Owner, reassigned[i].policy); strings.EqualFold(sourceOwner, opts.Owner) && source == name {
+			continue
+		}
+		reassigned[i].targets = append(reassigned[i].targets, repo)
+	}
+	return reassigned, nil
+}
+
+// regoProperties reads the custom properties of the repositories for the
+// Rego policy, which gets none when they can't be read, as for a user.
+func regoProperties(ctx context.Context, client *ghclient.Client, owner string) map[string]map[string]string {
+	properties, err := getter.GetCustomPropertyValues(ctx, client.Organizations, owner)
+	if err != nil {
+		log.Printf("Warning: the Rego policy gets no custom properties: %v\n", err)
+	}
+	return properties
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestRegoAssign(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "opa")
+	script := `#!/bin/sh
+cat > /dev/null
+echo '{"result": [{"expressions": [{"value": {
+	"api": {"sync": true},
+	"docs": {"sync": true, "policy": "relaxed"},
+	"legacy": {"sync": false},
+	"orphan": {"sync": true},
+	"baseline": {"sync": true, "policy": "relaxed"}
+}}]}]}'
+`
+	if err := os.WriteFile(binary, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	strict := config.Policy{Name: "strict", Source: "baseline"}
+	relaxed := config.Policy{Name: "relaxed", Source: "docs-template"}
+	opts := Options{Owner: "octo", Config: &config.Config{Rego: config.Rego{Binary: binary, Policy: "policy.rego"}}}
+
+	var repos []*github.Repository
+	for _, name := range []string{"api", "docs", "legacy", "orphan", "unknown", "baseline"} {
+		repos = append(repos, &github.Repository{Name: github.String(name)})
+	}
+	claimed := map[string]string{"api": "strict", "docs": "strict", "legacy": "strict", "unknown": "relaxed"}
+	assignments := []assignment{
+		{policy: strict, targets: repos[:3]},
+		{policy: relaxed, targets: repos[4:5]},
+	}
+
+	got, err := regoAssign(context.Background(), opts, repos, nil, claimed, assignments)
+	if err != nil {
+		t.Fatalf("regoAssign() failed: %v", err)
+	}
+	want := map[string][]string{"strict": {"api"}, "relaxed": {"docs", "baseline"}}
+	for _, a := range got {
+		if names := repoNames(a.targets); len(names) != len(want[a.policy.Name]) || (len(names) > 0 && names[0] != want[a.policy.Name][0]) {
+			t.Errorf("policy %s targets %v, want %v", a.policy.Name, names, want[a.policy.Name])
+		}
+	}
+
+	claimed["orphan"] = "missing"
+	if _, err := regoAssign(context.Background(), opts, repos, nil, claimed, assignments); err == nil {
+		t.Error("regoAssign() accepted an unknown policy")
+	}
+}
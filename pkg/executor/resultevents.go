@@ -0,0 +1,89 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// resultEvents posts the outcome of every target to the result webhooks,
+// with the settings the sync changed: the protection is read before and
+// after applying it.
+type resultEvents struct {
+	client *ghclient.Client
+	opts   Options
+	policy config.Policy
+
+	mu     sync.Mutex
+	before map[string]*types.RepoProtection
+}
+
+func newResultEvents(client *ghclient.Client, opts Options, p config.Policy) *resultEvents {
+	return &resultEvents{client: client, opts: opts, policy: p, before: make(map[string]*types.RepoProtection)}
+}
+
+// BeforeApply reads the protection of repo before it is changed. A failed
+// read only leaves the diff of its event empty.
+func (e *resultEvents) BeforeApply(ctx context.Context, repo *github.Repository, _ *github.ProtectionRequest) (bool, error) {
+	current, err := currentProtection(ctx, e.client, e.opts.Owner, repo)
+	if err != nil {
+		log.Printf("Reading the protection of %s for its result event: %v\n", repo.GetName(), err)
+		return false, nil
+	}
+	e.mu.Lock()
+	e.before[repo.GetName()] = current
+	e.mu.Unlock()
+	return false, nil
+}
+
+// AfterApply posts the event of repo.
+func (e *resultEvents) AfterApply(ctx context.Context, repo *github.Repository, result setter.ApplyResult) {
+	event := notify.RepoEvent{
+		RunID:  e.opts.RunID,
+		Owner:  e.opts.Owner,
+		Repo:   repo.GetName(),
+		Policy: e.policy.Name,
+		Action: string(result.Action()),
+		Diff:   []diff.Delta{},
+	}
+	if result.Err != nil {
+		event.Error = result.Err.Error()
+	}
+
+	e.mu.Lock()
+	before, ok := e.before[repo.GetName()]
+	delete(e.before, repo.GetName())
+	e.mu.Unlock()
+	if ok && result.Action() != setter.ActionSkipped {
+		after, err := currentProtection(ctx, e.client, e.opts.Owner, repo)
+		if err != nil {
+			log.Printf("Reading the protection of %s for its result event: %v\n", repo.GetName(), err)
+		} else {
+			event.Diff = diff.Protections(before, after)
+		}
+	}
+	notify.SendRepoEvent(ctx, e.opts.Config.Notifications, event)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestResultEventsFailure(t *testing.T) {
+	events := make(chan notify.RepoEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e notify.RepoEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("decoding event: %v", err)
+		}
+		events <- e
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Notifications: config.Notifications{ResultWebhooks: []config.Webhook{{URL: srv.URL}}}}
+	hook := newResultEvents(nil, Options{Owner: "octo", RunID: "run-1", Config: cfg}, config.Policy{Name: "go"})
+	// Without the state before the sync, the event has no diff
+	hook.AfterApply(context.Background(), &github.Repository{Name: github.String("api")}, setter.ApplyResult{Err: errors.New("422 Validation Failed")})
+
+	e := <-events
+	if e.Repo != "api" || e.RunID != "run-1" || e.Policy != "go" || e.Action != "failed" || e.Error != "422 Validation Failed" || len(e.Diff) != 0 {
+		t.Errorf("got event %+v", e)
+	}
+}
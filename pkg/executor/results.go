@@ -0,0 +1,49 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/color"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+)
+
+// actionColors marks applied repositories green, skipped ones yellow and
+// failed ones red.
+var actionColors = map[setter.Action]string{
+	setter.ActionCreated: color.Green,
+	setter.ActionUpdated: color.Green,
+	setter.ActionSkipped: color.Yellow,
+	setter.ActionFailed:  color.Red,
+}
+
+// writeResults writes a line per synced repository, colored on a terminal.
+func writeResults(w io.Writer, results []setter.RepoResult) {
+	colored := color.Enabled(w)
+	for _, r := range results {
+		line := fmt.Sprintf("%-8s %s", r.Action, r.Repo)
+		if r.Duration > 0 {
+			line += fmt.Sprintf(" (%s)", r.Duration.Round(time.Millisecond))
+		}
+		if r.Err != nil {
+			line += ": " + r.Err.Error()
+		}
+		fmt.Fprintln(w, color.Wrap(colored, actionColors[r.Action], line))
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+)
+
+func TestWriteResults(t *testing.T) {
+	var out bytes.Buffer
+	writeResults(&out, []setter.RepoResult{
+		{Repo: "api", Action: setter.ActionUpdated, Duration: 1500 * time.Millisecond},
+		{Repo: "web", Action: setter.ActionFailed, Err: errors.New("403 Forbidden")},
+	})
+
+	// A buffer isn't a terminal, so nothing is colored
+	want := "updated  api (1.5s)\nfailed   web: 403 Forbidden\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/policy"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/settings"
+)
+
+// settingSteps returns the steps syncing the repository settings enabled in
+// opts from the source of the policy. Inline policies have no source to copy
+// the settings from, and those of a source in another organization aren't
+// copied.
+func settingSteps(ctx context.Context, client *ghclient.Client, opts Options, p config.Policy) ([]setter.Step, error) {
+	modules := []struct {
+		enabled bool
+		step    func(context.Context, *ghclient.Client, string, string) (setter.Step, error)
+	}{
+		{opts.SyncMergeSettings, settings.Merge},
+		{opts.SyncSecuritySettings, settings.Security},
+		{opts.SyncActionsSettings, settings.Actions},
+		{opts.SyncEnvironments, settings.Environments},
+		{opts.SyncWebhooks, func(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+			return settings.Webhooks(ctx, client, owner, source, opts.Config.RepoWebhooks)
+		}},
+		{opts.SyncAutolinks, settings.Autolinks},
+		{opts.SyncLabels, func(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+			return settings.Labels(ctx, client, owner, source, opts.PruneLabels)
+		}},
+	}
+
+	var steps []setter.Step
+	for _, module := range modules {
+		if !module.enabled {
+			continue
+		}
+		if p.Source == "" {
+			log.Printf("Policy %s has no source repository, not syncing repository settings\n", p.Name)
+			return nil, nil
+		}
+		sourceOwner, source := policy.SourceRepo(opts.Owner, p)
+		if !strings.EqualFold(sourceOwner, opts.Owner) {
+			log.Printf("The source %s is in another organization, not syncing repository settings\n", p.Source)
+			return nil, nil
+		}
+		step, err := module.step(ctx, client, opts.Owner, source)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/snapshot"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// snapshotHook saves the state of every target right before it is changed.
+// A target whose state can't be saved isn't changed, as it couldn't be
+// rolled back.
+func snapshotHook(client *ghclient.Client, opts Options) setter.BeforeApplyHook {
+	return func(ctx context.Context, repo *github.Repository, _ *github.ProtectionRequest) (bool, error) {
+		return false, saveSnapshot(ctx, client, opts, repo)
+	}
+}
+
+// saveSnapshot saves the current branch protection and rulesets of repo.
+func saveSnapshot(ctx context.Context, client *ghclient.Client, opts Options, repo *github.Repository) error {
+	current, err := currentProtection(ctx, client, opts.Owner, repo)
+	if err != nil {
+		return fmt.Errorf("taking a snapshot: %w", err)
+	}
+	snap := snapshot.Snapshot{RunID: opts.RunID, Taken: time.Now(), Owner: opts.Owner, Repo: repo.GetName(), Branch: repo.GetDefaultBranch(),
+		BranchProtection: current.BranchProtection, Rulesets: current.Rulesets}
+	return opts.Snapshots.Save(snap)
+}
+
+// currentProtection reads the branch protection of the default branch of
+// repo, nil when it has none, and its rulesets.
+func currentProtection(ctx context.Context, client *ghclient.Client, owner string, repo *github.Repository) (*types.RepoProtection, error) {
+	current := &types.RepoProtection{}
+	if branch := repo.GetDefaultBranch(); branch != "" {
+		protection, err := getter.FetchBranchProtection(ctx, client.Repositories, owner, repo.GetName(), branch)
+		if err != nil && !setter.IsBranchNotFound(err) {
+			return nil, err
+		}
+		current.BranchProtection = types.NewBranchProtection(protection)
+	}
+	rulesets, err := getter.FetchRulesets(ctx, client.Repositories, owner, repo.GetName())
+	if err != nil {
+		return nil, err
+	}
+	current.Rulesets = rulesets
+	return current, nil
+}
+
+// snapshotPlan saves the state of every repository a plan changes.
+func snapshotPlan(ctx context.Context, client *ghclient.Client, opts Options, repos []string) error {
+	for _, name := range repos {
+		repo, response, err := client.Repositories.Get(ctx, opts.Owner, name)
+		if err == nil {
+			err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+		}
+		if err == nil {
+			err = saveSnapshot(ctx, client, opts, repo)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Rollback restores the branch protection and rulesets of the repositories
+// of the owner, or of repos when given, to their state at to: a timestamp or
+// the ID of a run. The current state of every repository is saved first, so
+// the rollback can be rolled back in turn.
+func Rollback(opts Options, to string, repos []string) error {
+	snapshots, err := opts.Snapshots.Select(opts.Owner, to, repos)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		logging.Infof("No repository of %s was changed since %s, nothing to roll back\n", opts.Owner, to)
+		return nil
+	}
+
+	ctx := startRun(context.Background(), &opts)
+	client, err := newClient(ctx, opts.Credentials, opts.Transport)
+	if err != nil {
+		return fmt.Errorf("creating the GitHub client: %w", err)
+	}
+	failed := 0
+	for _, snap := range snapshots {
+		if err := rollbackRepo(ctx, client, opts, snap); err != nil {
+			log.Printf("Error rolling back repo %s: %v\n", snap.Repo, err)
+			failed++
+			continue
+		}
+		logging.Infof("Rolled back repo %s to its state before run %s\n", snap.Repo, snap.RunID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories were not rolled back", failed, len(snapshots))
+	}
+	return nil
+}
+
+func rollbackRepo(ctx context.Context, client *ghclient.Client, opts Options, snap snapshot.Snapshot) error {
+	repo := &github.Repository{Name: github.String(snap.Repo), DefaultBranch: github.String(snap.Branch)}
+	if err := saveSnapshot(ctx, client, opts, repo); err != nil {
+		return err
+	}
+	return setter.Restore(ctx, client.Repositories, opts.Owner, snap.Repo, snap.Branch, snap.BranchProtection, snap.Rulesets)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/google/go-github/v59/github"
+)
+
+// AutoSource is the --source or --repo value that uses the baseline
+// repository of the owner as the source.
+const AutoSource = "auto"
+
+// defaultBaselineRepo is the baseline repository of an owner when the
+// configuration file names none: the repository holding its community health
+// files.
+const defaultBaselineRepo = ".github"
+
+// resolveSource replaces the auto source of opts with the baseline repository
+// of the owner, failing when the owner has none.
+func resolveSource(ctx context.Context, client ghclient.BranchProtectionReader, opts *Options) error {
+	if opts.Source != AutoSource {
+		return nil
+	}
+	name := opts.Config.BaselineRepo
+	if name == "" {
+		name = defaultBaselineRepo
+	}
+	repo, _, err := client.Get(ctx, opts.Owner, name)
+	var ghErr *github.ErrorResponse
+	switch {
+	case errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s has no baseline repository %s; create it or name another one with baseline_repo", opts.Owner, name)
+	case err != nil:
+		return fmt.Errorf("fetching the baseline repository %s/%s: %w", opts.Owner, name, err)
+	}
+	opts.Source = repo.GetName()
+	logging.Infof("Using the baseline repository %s/%s as the source\n", opts.Owner, opts.Source)
+	return nil
+}
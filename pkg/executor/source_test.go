@@ -0,0 +1,51 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResolveSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockBranchProtectionReader(ctrl)
+
+	opts := Options{Owner: "octo", Source: "template", Config: &config.Config{}}
+	if err := resolveSource(context.Background(), repos, &opts); err != nil || opts.Source != "template" {
+		t.Errorf("got %q, %v, want a named source kept", opts.Source, err)
+	}
+
+	repos.EXPECT().Get(gomock.Any(), "octo", ".github").Return(&github.Repository{Name: github.String(".github")}, nil, nil)
+	opts.Source = AutoSource
+	if err := resolveSource(context.Background(), repos, &opts); err != nil || opts.Source != ".github" {
+		t.Errorf("got %q, %v, want the .github repository", opts.Source, err)
+	}
+
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	repos.EXPECT().Get(gomock.Any(), "octo", "org-baseline").Return(nil, nil, notFound)
+	opts = Options{Owner: "octo", Source: AutoSource, Config: &config.Config{BaselineRepo: "org-baseline"}}
+	if err := resolveSource(context.Background(), repos, &opts); err == nil || !strings.Contains(err.Error(), "no baseline repository org-baseline") {
+		t.Errorf("got %v, want the missing baseline repository reported", err)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/actions"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/google/go-github/v59/github"
+)
+
+// statusContext names the commit statuses the sync reports on its sources.
+const statusContext = "repo-protection-sync"
+
+// reportSourceStatus sets a commit status on the head of the default branch
+// of the source of the summary, telling whether the policy it holds was
+// rolled out. Errors are logged and never fail the run.
+func reportSourceStatus(ctx context.Context, client *ghclient.Client, owner string, summary notify.Summary, aborted error) {
+	if summary.Source == "" {
+		return
+	}
+	if sourceOwner, source, ok := strings.Cut(summary.Source, "/"); ok {
+		owner, summary.Source = sourceOwner, source
+	}
+	if err := createSourceStatus(ctx, client, owner, summary, aborted); err != nil {
+		log.Printf("Error reporting the status of the sync on %s/%s: %v\n", owner, summary.Source, err)
+	}
+}
+
+func createSourceStatus(ctx context.Context, client *ghclient.Client, owner string, summary notify.Summary, aborted error) error {
+	repo, _, err := client.Repositories.Get(ctx, owner, summary.Source)
+	if err != nil {
+		return err
+	}
+	ref, _, err := client.Git.GetRef(ctx, owner, summary.Source, "heads/"+repo.GetDefaultBranch())
+	if err != nil {
+		return err
+	}
+	status := sourceStatus(summary, aborted)
+	_, _, err = client.Repositories.CreateStatus(ctx, owner, summary.Source, ref.GetObject().GetSHA(), status)
+	return err
+}
+
+// sourceStatus builds the commit status reporting the outcome of a policy.
+func sourceStatus(summary notify.Summary, aborted error) *github.RepoStatus {
+	name := statusContext
+	if summary.Policy != "" {
+		name += "/" + summary.Policy
+	}
+	status := &github.RepoStatus{Context: github.String(name)}
+	failed := len(summary.Failures)
+	switch {
+	case aborted != nil:
+		status.State = github.String("error")
+		status.Description = github.String(fmt.Sprintf("Sync aborted, %d of %d repositories failed", failed, summary.Targets))
+	case failed > 0:
+		status.State = github.String("failure")
+		status.Description = github.String(fmt.Sprintf("%d of %d repositories failed", failed, summary.Targets))
+	default:
+		status.State = github.String("success")
+		status.Description = github.String(fmt.Sprintf("Synced to %d repositories", summary.Targets))
+	}
+	if url := actions.RunURL(); url != "" {
+		status.TargetURL = github.String(url)
+	}
+	return status
+}
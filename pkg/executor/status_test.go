@@ -0,0 +1,55 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+func TestSourceStatus(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "octo/rollout")
+	t.Setenv("GITHUB_RUN_ID", "42")
+
+	failures := []notify.Failure{{Repo: "web", Error: "403"}}
+	tests := []struct {
+		name    string
+		summary notify.Summary
+		aborted error
+		context string
+		state   string
+		desc    string
+	}{
+		{"success", notify.Summary{Targets: 3}, nil, "repo-protection-sync", "success", "Synced to 3 repositories"},
+		{"failure", notify.Summary{Policy: "go", Targets: 3, Failures: failures}, nil, "repo-protection-sync/go", "failure", "1 of 3 repositories failed"},
+		{"aborted", notify.Summary{Targets: 3, Failures: failures}, errors.New("aborted"), "repo-protection-sync", "error", "Sync aborted, 1 of 3 repositories failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := sourceStatus(tt.summary, tt.aborted)
+			if status.GetContext() != tt.context || status.GetState() != tt.state || status.GetDescription() != tt.desc {
+				t.Errorf("got %s %s %q", status.GetContext(), status.GetState(), status.GetDescription())
+			}
+			if got := status.GetTargetURL(); got != "https://github.com/octo/rollout/actions/runs/42" {
+				t.Errorf("got target URL %q", got)
+			}
+		})
+	}
+}
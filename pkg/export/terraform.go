@@ -0,0 +1,260 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+var invalidIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// Terraform writes the protection of a repository as github_branch_protection
+// and github_repository_ruleset resources of the integrations/github provider,
+// followed by the terraform import commands that adopt the live objects.
+func Terraform(w io.Writer, owner, repo string, rp *types.RepoProtection) error {
+	var imports []string
+
+	if rp.BranchProtection != nil {
+		name := resourceName(repo, rp.Branch)
+		writeBranchProtection(w, owner, name, repo, rp.Branch, rp.BranchProtection)
+		imports = append(imports, fmt.Sprintf("terraform import github_branch_protection.%s %s:%s", name, repo, rp.Branch))
+	}
+
+	for _, ruleset := range rp.Rulesets {
+		name := resourceName(repo, ruleset.Name)
+		if err := writeRuleset(w, name, repo, ruleset); err != nil {
+			return fmt.Errorf("exporting ruleset %q of %s: %w", ruleset.Name, repo, err)
+		}
+		imports = append(imports, fmt.Sprintf("terraform import github_repository_ruleset.%s %s:%d", name, repo, ruleset.GetID()))
+	}
+
+	if len(imports) > 0 {
+		fmt.Fprintf(w, "# Import the existing objects of %s/%s into the state:\n", owner, repo)
+		for _, cmd := range imports {
+			fmt.Fprintf(w, "#   %s\n", cmd)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func writeBranchProtection(w io.Writer, owner, name, repo, branch string, p *types.BranchProtection) {
+	fmt.Fprintf(w, "resource \"github_branch_protection\" %q {\n", name)
+	fmt.Fprintf(w, "  repository_id = %s\n", quote(repo))
+	fmt.Fprintf(w, "  pattern       = %s\n", quote(branch))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  enforce_admins                  = %t\n", p.EnforceAdmins)
+	fmt.Fprintf(w, "  require_signed_commits          = %t\n", p.SignedCommits)
+	fmt.Fprintf(w, "  required_linear_history         = %t\n", p.LinearHistory)
+	fmt.Fprintf(w, "  require_conversation_resolution = %t\n", p.ConversationResolution)
+	fmt.Fprintf(w, "  allows_deletions                = %t\n", p.AllowDeletions)
+	fmt.Fprintf(w, "  allows_force_pushes             = %t\n", p.AllowForcePushes)
+	fmt.Fprintf(w, "  lock_branch                     = %t\n", p.LockBranch)
+
+	if rsc := p.StatusChecks; rsc != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  required_status_checks {")
+		fmt.Fprintf(w, "    strict   = %t\n", rsc.Strict)
+		fmt.Fprintf(w, "    contexts = %s\n", list(rsc.Contexts))
+		fmt.Fprintln(w, "  }")
+	}
+
+	if prr := p.Reviews; prr != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  required_pull_request_reviews {")
+		fmt.Fprintf(w, "    dismiss_stale_reviews           = %t\n", prr.DismissStale)
+		fmt.Fprintf(w, "    require_code_owner_reviews      = %t\n", prr.CodeOwners)
+		fmt.Fprintf(w, "    required_approving_review_count = %d\n", prr.RequiredApprovals)
+		fmt.Fprintf(w, "    require_last_push_approval      = %t\n", prr.LastPushApproval)
+		if dr := prr.DismissalRestrictions; dr != nil {
+			actors := actorNames(owner, &types.Actors{Users: dr.Users, Teams: dr.Teams})
+			fmt.Fprintf(w, "    restrict_dismissals             = %t\n", len(actors) > 0)
+			fmt.Fprintf(w, "    dismissal_restrictions          = %s\n", list(actors))
+		}
+		fmt.Fprintln(w, "  }")
+	}
+
+	if br := p.Restrictions; br != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  restrict_pushes {")
+		fmt.Fprintf(w, "    blocks_creations = %t\n", p.BlockCreations)
+		fmt.Fprintf(w, "    push_allowances  = %s\n", list(actorNames(owner, br)))
+		fmt.Fprintln(w, "  }")
+	}
+
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+}
+
+func writeRuleset(w io.Writer, name, repo string, rs *github.Ruleset) error {
+	fmt.Fprintf(w, "resource \"github_repository_ruleset\" %q {\n", name)
+	fmt.Fprintf(w, "  name        = %s\n", quote(rs.Name))
+	fmt.Fprintf(w, "  repository  = %s\n", quote(repo))
+	fmt.Fprintf(w, "  target      = %s\n", quote(rs.GetTarget()))
+	fmt.Fprintf(w, "  enforcement = %s\n", quote(rs.Enforcement))
+
+	if c := rs.Conditions; c != nil && c.RefName != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  conditions {")
+		fmt.Fprintln(w, "    ref_name {")
+		fmt.Fprintf(w, "      include = %s\n", list(c.RefName.Include))
+		fmt.Fprintf(w, "      exclude = %s\n", list(c.RefName.Exclude))
+		fmt.Fprintln(w, "    }")
+		fmt.Fprintln(w, "  }")
+	}
+
+	for _, actor := range rs.BypassActors {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  bypass_actors {")
+		fmt.Fprintf(w, "    actor_id    = %d\n", actor.GetActorID())
+		fmt.Fprintf(w, "    actor_type  = %s\n", quote(actor.GetActorType()))
+		fmt.Fprintf(w, "    bypass_mode = %s\n", quote(actor.GetBypassMode()))
+		fmt.Fprintln(w, "  }")
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  rules {")
+	for _, rule := range rs.Rules {
+		if err := writeRule(w, rule); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(w, "  }")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+	return nil
+}
+
+// writeRule writes a single rule of a ruleset inside its rules block.
+func writeRule(w io.Writer, rule *github.RepositoryRule) error {
+	switch rule.Type {
+	case "creation", "deletion", "required_linear_history", "required_signatures", "non_fast_forward":
+		fmt.Fprintf(w, "    %s = true\n", rule.Type)
+	case "update":
+		var params github.UpdateAllowsFetchAndMergeRuleParameters
+		if err := decodeParameters(rule, &params); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "    update = true")
+		fmt.Fprintf(w, "    update_allows_fetch_and_merge = %t\n", params.UpdateAllowsFetchAndMerge)
+	case "required_deployments":
+		var params github.RequiredDeploymentEnvironmentsRuleParameters
+		if err := decodeParameters(rule, &params); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "    required_deployments {")
+		fmt.Fprintf(w, "      required_deployment_environments = %s\n", list(params.RequiredDeploymentEnvironments))
+		fmt.Fprintln(w, "    }")
+	case "pull_request":
+		var params github.PullRequestRuleParameters
+		if err := decodeParameters(rule, &params); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "    pull_request {")
+		fmt.Fprintf(w, "      dismiss_stale_reviews_on_push     = %t\n", params.DismissStaleReviewsOnPush)
+		fmt.Fprintf(w, "      require_code_owner_review         = %t\n", params.RequireCodeOwnerReview)
+		fmt.Fprintf(w, "      require_last_push_approval        = %t\n", params.RequireLastPushApproval)
+		fmt.Fprintf(w, "      required_approving_review_count   = %d\n", params.RequiredApprovingReviewCount)
+		fmt.Fprintf(w, "      required_review_thread_resolution = %t\n", params.RequiredReviewThreadResolution)
+		fmt.Fprintln(w, "    }")
+	case "required_status_checks":
+		var params github.RequiredStatusChecksRuleParameters
+		if err := decodeParameters(rule, &params); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "    required_status_checks {")
+		fmt.Fprintf(w, "      strict_required_status_checks_policy = %t\n", params.StrictRequiredStatusChecksPolicy)
+		for _, check := range params.RequiredStatusChecks {
+			fmt.Fprintln(w, "      required_check {")
+			fmt.Fprintf(w, "        context = %s\n", quote(check.Context))
+			if check.IntegrationID != nil {
+				fmt.Fprintf(w, "        integration_id = %d\n", *check.IntegrationID)
+			}
+			fmt.Fprintln(w, "      }")
+		}
+		fmt.Fprintln(w, "    }")
+	case "commit_message_pattern", "commit_author_email_pattern", "committer_email_pattern", "branch_name_pattern", "tag_name_pattern":
+		var params github.RulePatternParameters
+		if err := decodeParameters(rule, &params); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "    %s {\n", rule.Type)
+		fmt.Fprintf(w, "      operator = %s\n", quote(params.Operator))
+		fmt.Fprintf(w, "      pattern  = %s\n", quote(params.Pattern))
+		if params.Name != nil {
+			fmt.Fprintf(w, "      name     = %s\n", quote(*params.Name))
+		}
+		if params.Negate != nil {
+			fmt.Fprintf(w, "      negate   = %t\n", *params.Negate)
+		}
+		fmt.Fprintln(w, "    }")
+	default:
+		fmt.Fprintf(w, "    # rule %q is not supported by the export and was skipped\n", rule.Type)
+	}
+	return nil
+}
+
+func decodeParameters(rule *github.RepositoryRule, v interface{}) error {
+	if rule.Parameters == nil {
+		return nil
+	}
+	return json.Unmarshal(*rule.Parameters, v)
+}
+
+// actorNames renders users as "/login" and teams as "owner/slug", the actor
+// format the provider expects. Apps are referenced by their node ID.
+func actorNames(owner string, a *types.Actors) []string {
+	actors := make([]string, 0, len(a.Users)+len(a.Teams)+len(a.AppNodeIDs))
+	for _, login := range a.Users {
+		actors = append(actors, "/"+login)
+	}
+	for _, slug := range a.Teams {
+		actors = append(actors, owner+"/"+slug)
+	}
+	return append(actors, a.AppNodeIDs...)
+}
+
+// resourceName builds a terraform identifier from the given parts.
+func resourceName(parts ...string) string {
+	name := invalidIdentifierChars.ReplaceAllString(strings.Join(parts, "_"), "_")
+	name = strings.Trim(strings.ToLower(name), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "r_" + name
+	}
+	return name
+}
+
+// quote renders s as an HCL string literal, escaping template sequences.
+func quote(s string) string {
+	q := fmt.Sprintf("%q", s)
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
+
+func list(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quote(v))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
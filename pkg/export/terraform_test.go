@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestTerraform(t *testing.T) {
+	params := json.RawMessage(`{"required_approving_review_count":2,"dismiss_stale_reviews_on_push":true}`)
+	rp := &types.RepoProtection{
+		Branch: "main",
+		BranchProtection: &types.BranchProtection{
+			EnforceAdmins: true,
+			StatusChecks:  &types.StatusChecks{Strict: true, Contexts: []string{"build ${repo}"}},
+			Restrictions:  &types.Actors{Users: []string{"alice"}, Teams: []string{"core"}},
+		},
+		Rulesets: []*github.Ruleset{{
+			ID:          github.Int64(42),
+			Name:        "Protect main",
+			Target:      github.String("branch"),
+			Enforcement: "active",
+			Rules: []*github.RepositoryRule{
+				{Type: "deletion"},
+				{Type: "pull_request", Parameters: &params},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Terraform(&buf, "octo", "my-repo", rp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`resource "github_branch_protection" "my_repo_main" {`,
+		`enforce_admins                  = true`,
+		`contexts = ["build $${repo}"]`,
+		`push_allowances  = ["/alice", "octo/core"]`,
+		`resource "github_repository_ruleset" "my_repo_protect_main" {`,
+		`deletion = true`,
+		`required_approving_review_count   = 2`,
+		`terraform import github_branch_protection.my_repo_main my-repo:main`,
+		`terraform import github_repository_ruleset.my_repo_protect_main my-repo:42`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	tests := map[string][]string{
+		"repo_main":         {"repo", "main"},
+		"r_1repo_release_x": {"1repo", "release/*x"},
+		"my_repo_v2":        {"My.Repo", "v2"},
+	}
+	for want, parts := range tests {
+		if got := resourceName(parts...); got != want {
+			t.Errorf("resourceName(%v) = %q, want %q", parts, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// FetchEnvironments retrieves the deployment environments of a repository
+// along with their protection rules and custom branch policies.
+func FetchEnvironments(ctx context.Context, client ghclient.EnvironmentManager, owner, repo string) ([]types.Environment, error) {
+	var environments []types.Environment
+	opts := &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := client.ListEnvironments(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, env := range page.Environments {
+			environment := types.Environment{Name: env.GetName(), Protection: environmentProtection(env)}
+			if env.GetDeploymentBranchPolicy().GetCustomBranchPolicies() {
+				environment.BranchPolicies, err = fetchBranchPolicies(ctx, client, owner, repo, environment.Name)
+				if err != nil {
+					return nil, fmt.Errorf("fetching branch policies of environment %q: %w", environment.Name, err)
+				}
+			}
+			environments = append(environments, environment)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return environments, nil
+}
+
+// environmentProtection converts the protection rules of an environment to
+// the request recreating them. Rules the environment lacks are explicitly
+// disabled so applying the request also clears them.
+func environmentProtection(env *github.Environment) *github.CreateUpdateEnvironment {
+	protection := &github.CreateUpdateEnvironment{
+		WaitTimer:              github.Int(0),
+		Reviewers:              []*github.EnvReviewers{},
+		CanAdminsBypass:        env.CanAdminsBypass,
+		DeploymentBranchPolicy: env.DeploymentBranchPolicy,
+		PreventSelfReview:      github.Bool(false),
+	}
+	for _, rule := range env.ProtectionRules {
+		switch rule.GetType() {
+		case "wait_timer":
+			protection.WaitTimer = rule.WaitTimer
+		case "required_reviewers":
+			protection.PreventSelfReview = github.Bool(rule.GetPreventSelfReview())
+			for _, reviewer := range rule.Reviewers {
+				protection.Reviewers = append(protection.Reviewers, envReviewer(reviewer))
+			}
+		}
+	}
+	return protection
+}
+
+// envReviewer converts a required reviewer, a user or a team, to its reference.
+func envReviewer(reviewer *github.RequiredReviewer) *github.EnvReviewers {
+	ref := &github.EnvReviewers{Type: reviewer.Type}
+	switch r := reviewer.Reviewer.(type) {
+	case *github.User:
+		ref.ID = r.ID
+	case *github.Team:
+		ref.ID = r.ID
+	}
+	return ref
+}
+
+func fetchBranchPolicies(ctx context.Context, client ghclient.EnvironmentManager, owner, repo, environment string) ([]*github.DeploymentBranchPolicyRequest, error) {
+	resp, _, err := client.ListDeploymentBranchPolicies(ctx, owner, repo, environment)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*github.DeploymentBranchPolicyRequest, 0, len(resp.BranchPolicies))
+	for _, policy := range resp.BranchPolicies {
+		policies = append(policies, &github.DeploymentBranchPolicyRequest{Name: policy.Name, Type: policy.Type})
+	}
+	return policies, nil
+}
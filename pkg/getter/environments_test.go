@@ -0,0 +1,73 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFetchEnvironments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	envs := mocks.NewMockEnvironmentManager(ctrl)
+
+	envs.EXPECT().ListEnvironments(gomock.Any(), "octo", "template", gomock.Any()).Return(&github.EnvResponse{Environments: []*github.Environment{
+		{
+			Name:                   github.String("production"),
+			DeploymentBranchPolicy: &github.BranchPolicy{ProtectedBranches: github.Bool(false), CustomBranchPolicies: github.Bool(true)},
+			ProtectionRules: []*github.ProtectionRule{
+				{Type: github.String("wait_timer"), WaitTimer: github.Int(30)},
+				{Type: github.String("required_reviewers"), PreventSelfReview: github.Bool(true), Reviewers: []*github.RequiredReviewer{
+					{Type: github.String("Team"), Reviewer: &github.Team{ID: github.Int64(7)}},
+					{Type: github.String("User"), Reviewer: &github.User{ID: github.Int64(9)}},
+				}},
+			},
+		},
+		{Name: github.String("preview")},
+	}}, okResponse(), nil)
+	envs.EXPECT().ListDeploymentBranchPolicies(gomock.Any(), "octo", "template", "production").Return(&github.DeploymentBranchPolicyResponse{
+		BranchPolicies: []*github.DeploymentBranchPolicy{{ID: github.Int64(1), Name: github.String("release/*"), Type: github.String("branch")}},
+	}, okResponse(), nil)
+
+	environments, err := FetchEnvironments(context.Background(), envs, "octo", "template")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(environments) != 2 {
+		t.Fatalf("got %d environments, want 2", len(environments))
+	}
+
+	production := environments[0].Protection
+	if production.GetWaitTimer() != 30 || !production.GetPreventSelfReview() || len(production.Reviewers) != 2 {
+		t.Errorf("unexpected protection %+v", production)
+	}
+	if production.Reviewers[0].GetType() != "Team" || production.Reviewers[0].GetID() != 7 || production.Reviewers[1].GetID() != 9 {
+		t.Errorf("unexpected reviewers %+v", production.Reviewers)
+	}
+	if len(environments[0].BranchPolicies) != 1 || environments[0].BranchPolicies[0].GetName() != "release/*" {
+		t.Errorf("unexpected branch policies %+v", environments[0].BranchPolicies)
+	}
+
+	// Rules missing on the source are cleared on the targets
+	preview := environments[1].Protection
+	if preview.WaitTimer == nil || preview.GetWaitTimer() != 0 || preview.Reviewers == nil || preview.DeploymentBranchPolicy != nil {
+		t.Errorf("unexpected protection %+v", preview)
+	}
+}
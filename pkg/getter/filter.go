@@ -0,0 +1,80 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// Filter narrows the repositories of the inventory down to the targets by
+// their metadata. The zero Filter keeps every repository.
+type Filter struct {
+	// PushedSince and PushedBefore keep the repositories last pushed to in
+	// the window, when set.
+	PushedSince  time.Time
+	PushedBefore time.Time
+	// Languages keeps the repositories whose primary language is one of
+	// them, compared case-insensitively, when set.
+	Languages []string
+	// MinSize and MaxSize bound the size of the repositories in kilobytes,
+	// when set.
+	MinSize int
+	MaxSize int
+}
+
+// Apply returns the repositories matching every criterion of f.
+func (f Filter) Apply(repos []*github.Repository) []*github.Repository {
+	kept := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if f.Match(repo) {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
+
+// Match reports whether repo matches every criterion of f.
+func (f Filter) Match(repo *github.Repository) bool {
+	pushed := repo.GetPushedAt().Time
+	if !f.PushedSince.IsZero() && pushed.Before(f.PushedSince) {
+		return false
+	}
+	if !f.PushedBefore.IsZero() && !pushed.Before(f.PushedBefore) {
+		return false
+	}
+	if len(f.Languages) > 0 && !f.matchLanguage(repo.GetLanguage()) {
+		return false
+	}
+	if f.MinSize > 0 && repo.GetSize() < f.MinSize {
+		return false
+	}
+	if f.MaxSize > 0 && repo.GetSize() > f.MaxSize {
+		return false
+	}
+	return true
+}
+
+func (f Filter) matchLanguage(language string) bool {
+	for _, l := range f.Languages {
+		if strings.EqualFold(l, language) {
+			return true
+		}
+	}
+	return false
+}
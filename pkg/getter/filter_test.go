@@ -0,0 +1,93 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestFilterPushed(t *testing.T) {
+	day := func(d int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)}
+	}
+	repos := []*github.Repository{
+		{Name: github.String("dormant"), PushedAt: day(1)},
+		{Name: github.String("active"), PushedAt: day(10)},
+		{Name: github.String("new"), PushedAt: day(20)},
+		{Name: github.String("empty")},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"none", Filter{}, []string{"dormant", "active", "new", "empty"}},
+		{"since", Filter{PushedSince: day(10).Time}, []string{"active", "new"}},
+		{"before", Filter{PushedBefore: day(10).Time}, []string{"dormant", "empty"}},
+		{"window", Filter{PushedSince: day(5).Time, PushedBefore: day(15).Time}, []string{"active"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repoNames(tt.filter.Apply(repos))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Apply() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterLanguageAndSize(t *testing.T) {
+	repos := []*github.Repository{
+		{Name: github.String("api"), Language: github.String("Go"), Size: github.Int(2048)},
+		{Name: github.String("ml"), Language: github.String("Python"), Size: github.Int(90000)},
+		{Name: github.String("web"), Language: github.String("TypeScript"), Size: github.Int(512)},
+		{Name: github.String("docs")},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"language", Filter{Languages: []string{"go", "python"}}, "api ml"},
+		{"min size", Filter{MinSize: 1024}, "api ml"},
+		{"max size", Filter{MaxSize: 1024}, "web docs"},
+		{"both", Filter{Languages: []string{"go", "typescript"}, MaxSize: 4096}, "api web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(repoNames(tt.filter.Apply(repos)), " "); got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func repoNames(repos []*github.Repository) []string {
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.GetName())
+	}
+	return names
+}
@@ -17,30 +17,33 @@ package getter
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"sync"
 
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
 	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/transport"
 	"github.com/arush-sal/repo-protection-sync/pkg/types"
 	"github.com/google/go-github/v59/github"
 )
 
-// getDefaultBranch retrieves the default branch of a repository.
-func getDefaultBranch(ctx context.Context, client *github.Client, owner, repo string) (string, error) {
-	repository, _, err := client.Repositories.Get(ctx, owner, repo)
+// getRepository retrieves a repository, including its default branch and owner.
+func getRepository(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo string) (*github.Repository, error) {
+	repository, _, err := client.Get(ctx, owner, repo)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return *repository.DefaultBranch, nil
+	return repository, nil
 }
 
-// getBranchProtectionRules retrieves the branch protection rules for a specific repository.
-func getBranchProtection(ctx context.Context, client *github.Client, owner, repo string) (*github.Protection, error) {
-
-	branch, err := getDefaultBranch(ctx, client, owner, repo)
-	if err != nil {
-		return nil, err
-	}
-	protection, response, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+// getBranchProtection retrieves the branch protection rules of a branch.
+func getBranchProtection(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo, branch string) (*github.Protection, error) {
+	protection, response, err := client.GetBranchProtection(ctx, owner, repo, branch)
 	if err != nil {
 		return nil, err
 	}
@@ -48,62 +51,378 @@ func getBranchProtection(ctx context.Context, client *github.Client, owner, repo
 	return protection, helpers.HTTPStatusCodeCheck(response.StatusCode)
 }
 
+// FetchBranchProtection retrieves the protection of a branch, returning nil
+// without an error when the branch isn't protected.
+func FetchBranchProtection(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo, branch string) (*github.Protection, error) {
+	protection, err := getBranchProtection(ctx, client, owner, repo, branch)
+	if errors.Is(err, github.ErrBranchNotProtected) {
+		return nil, nil
+	}
+	return protection, err
+}
+
+// FetchBranchProtectionUnchanged is FetchBranchProtection also reporting
+// whether the protection was answered from the ETag cache, unchanged since it
+// was last read.
+func FetchBranchProtectionUnchanged(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo, branch string) (*github.Protection, bool, error) {
+	protection, response, err := client.GetBranchProtection(ctx, owner, repo, branch)
+	switch {
+	case errors.Is(err, github.ErrBranchNotProtected):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+	return protection, transport.Unchanged(response.Response), helpers.HTTPStatusCodeCheck(response.StatusCode)
+}
+
 // GetRepoProtections retrieves the branch protection rules and ruleset to be applied.
-func GetRepoProtections(ctx context.Context, client *github.Client, owner, repo string) *types.RepoProtection {
+func GetRepoProtections(ctx context.Context, client *ghclient.Client, owner, repo string) *types.RepoProtection {
 	// Get the branch protection rules for the source repository
-	log.Printf("Fetching branch protection rules from %s/%s...\n", owner, repo)
-	rp := new(types.RepoProtection)
-	gp, err := getBranchProtection(ctx, client, owner, repo)
-	// client.Repositories.GetPullRequestReviewEnforcement (ctx context.Context, owner, repo, branch string) (*PullRequestReviewsEnforcement, *Response, error)
-	// GetRequiredStatusChecks(ctx context.Context, owner, repo, branch string) (*RequiredStatusChecks, *Response, error)
+	logging.Infof("Fetching branch protection rules from %s/%s...\n", owner, repo)
+	rp, err := FetchRepoProtections(ctx, client, owner, repo)
 	if err != nil {
 		log.Fatalf("Error fetching branch protection rules: %v\n", err)
 	}
-	rp.BranchProtection = gp
-	rp.Rulesets = GetRulesets(ctx, client, owner, repo)
 	return rp
 }
 
+// FetchRepoProtections retrieves the protection of the default branch and the
+// rulesets of a repository, returning an error instead of exiting so callers
+// iterating over many repositories can carry on.
+func FetchRepoProtections(ctx context.Context, client *ghclient.Client, owner, repo string) (*types.RepoProtection, error) {
+	repository, err := getRepository(ctx, client.Repositories, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	branch := repository.GetDefaultBranch()
+	gp, err := getBranchProtection(ctx, client.Repositories, owner, repo, branch)
+	// client.Repositories.GetPullRequestReviewEnforcement (ctx context.Context, owner, repo, branch string) (*PullRequestReviewsEnforcement, *Response, error)
+	// GetRequiredStatusChecks(ctx context.Context, owner, repo, branch string) (*RequiredStatusChecks, *Response, error)
+	if err != nil {
+		return nil, err
+	}
+	all, err := fetchRulesets(ctx, client.Repositories, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	rulesets, inherited := splitInherited(all)
+	return &types.RepoProtection{
+		Branch:            branch,
+		OwnerType:         repository.GetOwner().GetType(),
+		BranchProtection:  types.NewBranchProtection(gp),
+		Rulesets:          rulesets,
+		InheritedRulesets: inherited,
+	}, nil
+}
+
 // getRuleset retrieves the branch protection rules for a specific repository.
-func GetRulesets(ctx context.Context, client *github.Client, owner, repo string) []*github.Ruleset {
-	rulesets, response, err := client.Repositories.GetAllRulesets(ctx, owner, repo, false)
-	switch {
-	case err != nil:
+func GetRulesets(ctx context.Context, client ghclient.RulesetManager, owner, repo string) []*github.Ruleset {
+	all, err := fetchRulesets(ctx, client, owner, repo)
+	if err != nil {
 		log.Fatalf("Error fetching branch ruleset: %v\n", err)
-	case helpers.HTTPStatusCodeCheck(response.StatusCode) != nil:
-		log.Fatalf("Error fetching branch ruleset: %v\n", helpers.HTTPStatusCodeCheck(response.StatusCode))
 	}
+	rulesets, _ := splitInherited(all)
 	return rulesets
 }
 
-// GetAllReposFromOrg fetches all repositories for the specified GitHub organization.
-func GetAllReposFromOrg(ctx context.Context, client *github.Client, org string) ([]*github.Repository, error) {
-	var allRepos []*github.Repository
-	opts := &github.RepositoryListByOrgOptions{
-		// PerPage can be adjusted to your needs
-		ListOptions: github.ListOptions{PerPage: 100},
+// FetchRulesets retrieves the rulesets defined on a repository, without
+// those it inherits, returning an error instead of exiting.
+func FetchRulesets(ctx context.Context, client ghclient.RulesetManager, owner, repo string) ([]*github.Ruleset, error) {
+	all, err := fetchRulesets(ctx, client, owner, repo)
+	if err != nil {
+		return nil, err
 	}
+	rulesets, _ := splitInherited(all)
+	return rulesets, nil
+}
 
-	// Pagination handling
-	for {
-		repos, resp, err := client.Repositories.ListByOrg(ctx, org, opts)
+// fetchRulesets retrieves the rulesets applying to a repository, including
+// those inherited from its organization. Listing only returns summaries, so
+// every ruleset is fetched again with its rules and conditions.
+func fetchRulesets(ctx context.Context, client ghclient.RulesetManager, owner, repo string) ([]*github.Ruleset, error) {
+	summaries, response, err := client.GetAllRulesets(ctx, owner, repo, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+		return nil, err
+	}
+
+	rulesets := make([]*github.Ruleset, 0, len(summaries))
+	for _, summary := range summaries {
+		var ruleset *github.Ruleset
+		if inherited(summary) {
+			ruleset, response, err = client.GetRuleset(ctx, owner, repo, summary.GetID(), true)
+			if err == nil {
+				err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+			}
+		} else {
+			ruleset, err = FetchRuleset(ctx, client, owner, repo, summary.GetID())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetching ruleset %q: %w", summary.Name, err)
+		}
+		// The full ruleset may not repeat where it is defined
+		if ruleset.SourceType == nil {
+			ruleset.SourceType, ruleset.Source = summary.SourceType, summary.Source
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+	return rulesets, nil
+}
+
+// inherited reports whether a ruleset is defined above the repository.
+func inherited(ruleset *github.Ruleset) bool {
+	return ruleset.SourceType != nil && *ruleset.SourceType != "Repository"
+}
+
+// splitInherited separates the rulesets defined on a repository from those
+// it inherits.
+func splitInherited(all []*github.Ruleset) (own, parents []*github.Ruleset) {
+	for _, ruleset := range all {
+		if inherited(ruleset) {
+			parents = append(parents, ruleset)
+		} else {
+			own = append(own, ruleset)
+		}
+	}
+	return own, parents
+}
+
+// FetchRuleset retrieves a single ruleset of a repository with its rules and
+// conditions.
+func FetchRuleset(ctx context.Context, client ghclient.RulesetManager, owner, repo string, id int64) (*github.Ruleset, error) {
+	ruleset, response, err := client.GetRuleset(ctx, owner, repo, id, false)
+	if err != nil {
+		return nil, err
+	}
+	return ruleset, helpers.HTTPStatusCodeCheck(response.StatusCode)
+}
+
+// pageWorkers bounds the pages of a listing fetched at once.
+const pageWorkers = 8
+
+// GetAllReposFromOrg fetches all repositories for the specified GitHub
+// organization. Once the first page tells the number of pages, the others
+// are fetched concurrently, and the repositories are returned in page order.
+func GetAllReposFromOrg(ctx context.Context, client ghclient.RepoLister, org string) ([]*github.Repository, error) {
+	list := func(ctx context.Context, page int) ([]*github.Repository, *github.Response, error) {
+		return client.ListByOrg(ctx, org, &github.RepositoryListByOrgOptions{
+			ListOptions: github.ListOptions{PerPage: 100, Page: page},
+		})
+	}
+
+	allRepos, resp, err := list(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if resp.LastPage > resp.NextPage && resp.NextPage != 0 {
+		rest, err := listPages(ctx, resp.NextPage, resp.LastPage, list)
+		if err != nil {
+			return nil, err
+		}
+		return append(allRepos, rest...), nil
+	}
+
+	// Without the last page, follow the links one page at a time
+	for resp.NextPage != 0 {
+		var repos []*github.Repository
+		repos, resp, err = list(ctx, resp.NextPage)
 		if err != nil {
 			return nil, err
 		}
 		allRepos = append(allRepos, repos...)
+	}
+	return allRepos, nil
+}
+
+// listPages fetches the pages first to last with at most pageWorkers
+// requests at once, returning their repositories in page order. The first
+// error cancels the pages not fetched yet.
+func listPages(ctx context.Context, first, last int, list func(context.Context, int) ([]*github.Repository, *github.Response, error)) ([]*github.Repository, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]*github.Repository, last-first+1)
+	errs := make([]error, len(pages))
+	semaphore := make(chan struct{}, pageWorkers)
+	var wg sync.WaitGroup
+	for i := range pages {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if ctx.Err() != nil {
+				errs[i] = ctx.Err()
+				return
+			}
+			pages[i], _, errs[i] = list(ctx, first+i)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	var repos []*github.Repository
+	for _, page := range pages {
+		repos = append(repos, page...)
+	}
+	return repos, nil
+}
+
+// firstError returns the first error that isn't the cancellation it caused.
+func firstError(errs []error) error {
+	var canceled error
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, context.Canceled):
+			if canceled == nil {
+				canceled = err
+			}
+		default:
+			return err
+		}
+	}
+	return canceled
+}
+
+// GetAllReposFromInstallation fetches the repositories owned by owner that the
+// authenticated GitHub App installation has been granted access to, so the
+// App's repository selection acts as the management boundary.
+func GetAllReposFromInstallation(ctx context.Context, client ghclient.InstallationRepoLister, owner string) ([]*github.Repository, error) {
+	var allRepos []*github.Repository
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		list, resp, err := client.ListRepos(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range list.Repositories {
+			if strings.EqualFold(repo.GetOwner().GetLogin(), owner) {
+				allRepos = append(allRepos, repo)
+			}
+		}
 		if resp.NextPage == 0 {
 			break
 		}
-		// Update opts.Page to fetch the next page
 		opts.Page = resp.NextPage
 	}
 
 	return allRepos, nil
 }
 
-func getBranchSignedCommitStatus(ctx context.Context, client *github.Client, owner, repo, branch string) bool {
+// GetTeamRepos lists the repositories of org the team with the given slug
+// administers or maintains.
+func GetTeamRepos(ctx context.Context, client ghclient.TeamRepoLister, org, slug string) ([]*github.Repository, error) {
+	var repos []*github.Repository
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		page, resp, err := client.ListTeamReposBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range page {
+			perms := repo.GetPermissions()
+			if perms["admin"] || perms["maintain"] {
+				repos = append(repos, repo)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+// GetCustomPropertyValues fetches the custom property values of every
+// repository of org, keyed by repository name and property name.
+func GetCustomPropertyValues(ctx context.Context, client ghclient.PropertyLister, org string) (map[string]map[string]string, error) {
+	values := make(map[string]map[string]string)
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		page, resp, err := client.ListCustomPropertyValues(ctx, org, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range page {
+			props := make(map[string]string, len(repo.Properties))
+			for _, prop := range repo.Properties {
+				props[prop.PropertyName] = prop.GetValue()
+			}
+			values[repo.RepositoryName] = props
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return values, nil
+}
+
+// FilterByProperties keeps the repositories whose custom properties match
+// every filter, given the values returned by GetCustomPropertyValues.
+func FilterByProperties(repos []*github.Repository, values map[string]map[string]string, filters map[string]string) []*github.Repository {
+	kept := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		props := values[repo.GetName()]
+		match := true
+		for key, value := range filters {
+			if props[key] != value {
+				match = false
+				break
+			}
+		}
+		if match {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
+
+// FilterOptedOut drops the repositories that opted out of the sync through
+// the topic or custom property configured in cfg, returning the names of the
+// dropped repositories alongside the remaining ones.
+func FilterOptedOut(repos []*github.Repository, cfg config.OptOut) ([]*github.Repository, []string) {
+	topic := cfg.TopicOrDefault()
+	property, value := cfg.PropertyOrDefault()
+
+	kept := make([]*github.Repository, 0, len(repos))
+	var optedOut []string
+	for _, repo := range repos {
+		if repo.CustomProperties[property] == value || contains(repo.Topics, topic) {
+			log.Printf("Skipping repo %s, which opted out of protection sync\n", repo.GetName())
+			optedOut = append(optedOut, repo.GetName())
+			continue
+		}
+		kept = append(kept, repo)
+	}
+	return kept, optedOut
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func getBranchSignedCommitStatus(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo, branch string) bool {
 	// GetSignaturesOnProtectedBranch
-	signedCommits, _, err := client.Repositories.GetSignaturesProtectedBranch(ctx, owner, repo, branch)
+	signedCommits, _, err := client.GetSignaturesProtectedBranch(ctx, owner, repo, branch)
 	if err != nil {
 		log.Fatalf("Error fetching branch signed commits check: %v", err)
 	}
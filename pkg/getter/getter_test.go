@@ -0,0 +1,316 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+func TestGetRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reader := mocks.NewMockBranchProtectionReader(ctrl)
+	reader.EXPECT().Get(gomock.Any(), "octo", "source").
+		Return(&github.Repository{DefaultBranch: github.String("main")}, okResponse(), nil)
+
+	repository, err := getRepository(context.Background(), reader, "octo", "source")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch := repository.GetDefaultBranch(); branch != "main" {
+		t.Errorf("got branch %q, want %q", branch, "main")
+	}
+}
+
+func TestGetBranchProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		getErr  error
+		wantErr bool
+	}{
+		{name: "success"},
+		{name: "api error", getErr: errors.New("boom"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reader := mocks.NewMockBranchProtectionReader(ctrl)
+
+			want := &github.Protection{EnforceAdmins: &github.AdminEnforcement{Enabled: true}}
+			if tt.getErr != nil {
+				reader.EXPECT().GetBranchProtection(gomock.Any(), "octo", "source", "main").Return(nil, nil, tt.getErr)
+			} else {
+				reader.EXPECT().GetBranchProtection(gomock.Any(), "octo", "source", "main").Return(want, okResponse(), nil)
+			}
+
+			got, err := getBranchProtection(context.Background(), reader, "octo", "source", "main")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != want {
+				t.Errorf("got protection %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestGetRulesets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rm := mocks.NewMockRulesetManager(ctrl)
+	want := []*github.Ruleset{{ID: github.Int64(1), Name: "main"}, {ID: github.Int64(2), Name: "release"}}
+	rm.EXPECT().GetAllRulesets(gomock.Any(), "octo", "source", true).Return(want, okResponse(), nil)
+	for _, ruleset := range want {
+		rm.EXPECT().GetRuleset(gomock.Any(), "octo", "source", ruleset.GetID(), false).Return(ruleset, okResponse(), nil)
+	}
+
+	got := GetRulesets(context.Background(), rm, "octo", "source")
+	if len(got) != len(want) {
+		t.Fatalf("got %d rulesets, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name {
+			t.Errorf("ruleset %d: got %q, want %q", i, got[i].Name, want[i].Name)
+		}
+	}
+}
+
+func TestGetAllReposFromOrgPaginates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lister := mocks.NewMockRepoLister(ctrl)
+
+	first := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}, NextPage: 2}
+	last := okResponse()
+	gomock.InOrder(
+		lister.EXPECT().ListByOrg(gomock.Any(), "octo", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+				if opts.Page != 0 {
+					t.Errorf("first call requested page %d", opts.Page)
+				}
+				return []*github.Repository{{Name: github.String("a")}, {Name: github.String("b")}}, first, nil
+			}),
+		lister.EXPECT().ListByOrg(gomock.Any(), "octo", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+				if opts.Page != 2 {
+					t.Errorf("second call requested page %d, want 2", opts.Page)
+				}
+				return []*github.Repository{{Name: github.String("c")}}, last, nil
+			}),
+	)
+
+	repos, err := GetAllReposFromOrg(context.Background(), lister, "octo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 3 {
+		t.Fatalf("got %d repos, want 3", len(repos))
+	}
+}
+
+func TestGetAllReposFromOrgError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lister := mocks.NewMockRepoLister(ctrl)
+	lister.EXPECT().ListByOrg(gomock.Any(), "octo", gomock.Any()).Return(nil, nil, errors.New("boom"))
+
+	if _, err := GetAllReposFromOrg(context.Background(), lister, "octo"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestGetRepoProtections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	protection := &github.Protection{EnforceAdmins: &github.AdminEnforcement{Enabled: true}}
+	rulesets := []*github.Ruleset{{ID: github.Int64(1), Name: "main"}}
+
+	repos.EXPECT().Get(gomock.Any(), "octo", "source").
+		Return(&github.Repository{DefaultBranch: github.String("main"), Owner: &github.User{Type: github.String("Organization")}}, okResponse(), nil)
+	repos.EXPECT().GetBranchProtection(gomock.Any(), "octo", "source", "main").Return(protection, okResponse(), nil)
+	repos.EXPECT().GetAllRulesets(gomock.Any(), "octo", "source", true).Return(rulesets, okResponse(), nil)
+	repos.EXPECT().GetRuleset(gomock.Any(), "octo", "source", int64(1), false).Return(rulesets[0], okResponse(), nil)
+
+	rp := GetRepoProtections(context.Background(), &ghclient.Client{Repositories: repos}, "octo", "source")
+	if rp.Branch != "main" {
+		t.Errorf("got branch %q, want %q", rp.Branch, "main")
+	}
+	if rp.OwnerType != "Organization" {
+		t.Errorf("got owner type %q, want %q", rp.OwnerType, "Organization")
+	}
+	if rp.BranchProtection == nil || !rp.BranchProtection.EnforceAdmins {
+		t.Errorf("branch protection not propagated")
+	}
+	if len(rp.Rulesets) != 1 || rp.Rulesets[0].Name != "main" {
+		t.Errorf("got rulesets %+v", rp.Rulesets)
+	}
+}
+
+func TestFetchRepoProtectionsInherited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	summaries := []*github.Ruleset{
+		{ID: github.Int64(1), Name: "main", SourceType: github.String("Repository"), Source: "octo/source"},
+		{ID: github.Int64(2), Name: "org tags", SourceType: github.String("Organization"), Source: "octo"},
+	}
+
+	repos.EXPECT().Get(gomock.Any(), "octo", "source").Return(&github.Repository{DefaultBranch: github.String("main")}, okResponse(), nil)
+	repos.EXPECT().GetBranchProtection(gomock.Any(), "octo", "source", "main").Return(&github.Protection{}, okResponse(), nil)
+	repos.EXPECT().GetAllRulesets(gomock.Any(), "octo", "source", true).Return(summaries, okResponse(), nil)
+	repos.EXPECT().GetRuleset(gomock.Any(), "octo", "source", int64(1), false).Return(&github.Ruleset{ID: github.Int64(1), Name: "main"}, okResponse(), nil)
+	repos.EXPECT().GetRuleset(gomock.Any(), "octo", "source", int64(2), true).Return(&github.Ruleset{ID: github.Int64(2), Name: "org tags"}, okResponse(), nil)
+
+	rp, err := FetchRepoProtections(context.Background(), &ghclient.Client{Repositories: repos}, "octo", "source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rp.Rulesets) != 1 || rp.Rulesets[0].Name != "main" {
+		t.Errorf("got rulesets %+v", rp.Rulesets)
+	}
+	if len(rp.InheritedRulesets) != 1 || rp.InheritedRulesets[0].GetSourceType() != "Organization" || rp.InheritedRulesets[0].Source != "octo" {
+		t.Errorf("got inherited rulesets %+v, want the organization ruleset tagged with its source", rp.InheritedRulesets)
+	}
+}
+
+func TestGetBranchSignedCommitStatus(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		ctrl := gomock.NewController(t)
+		reader := mocks.NewMockBranchProtectionReader(ctrl)
+		reader.EXPECT().GetSignaturesProtectedBranch(gomock.Any(), "octo", "source", "main").
+			Return(&github.SignaturesProtectedBranch{Enabled: github.Bool(enabled)}, okResponse(), nil)
+
+		if got := getBranchSignedCommitStatus(context.Background(), reader, "octo", "source", "main"); got != enabled {
+			t.Errorf("got %v, want %v", got, enabled)
+		}
+	}
+}
+
+func TestGetAllReposFromInstallation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	apps := mocks.NewMockInstallationRepoLister(ctrl)
+
+	owned := func(owner, name string) *github.Repository {
+		return &github.Repository{Name: github.String(name), Owner: &github.User{Login: github.String(owner)}}
+	}
+	first := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}, NextPage: 2}
+	gomock.InOrder(
+		apps.EXPECT().ListRepos(gomock.Any(), &github.ListOptions{PerPage: 100}).
+			Return(&github.ListRepositories{Repositories: []*github.Repository{owned("octo", "a"), owned("other", "b")}}, first, nil),
+		apps.EXPECT().ListRepos(gomock.Any(), &github.ListOptions{PerPage: 100, Page: 2}).
+			Return(&github.ListRepositories{Repositories: []*github.Repository{owned("Octo", "c")}}, okResponse(), nil),
+	)
+
+	repos, err := GetAllReposFromInstallation(context.Background(), apps, "octo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 2 || repos[0].GetName() != "a" || repos[1].GetName() != "c" {
+		t.Errorf("got repos %v, want [a c]", repos)
+	}
+}
+
+func TestGetTeamRepos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	teams := mocks.NewMockTeamRepoLister(ctrl)
+
+	withPerms := func(name string, perms map[string]bool) *github.Repository {
+		return &github.Repository{Name: github.String(name), Permissions: perms}
+	}
+	first := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}, NextPage: 2}
+	gomock.InOrder(
+		teams.EXPECT().ListTeamReposBySlug(gomock.Any(), "octo", "platform", &github.ListOptions{PerPage: 100}).
+			Return([]*github.Repository{withPerms("api", map[string]bool{"admin": true}), withPerms("docs", map[string]bool{"push": true})}, first, nil),
+		teams.EXPECT().ListTeamReposBySlug(gomock.Any(), "octo", "platform", &github.ListOptions{PerPage: 100, Page: 2}).
+			Return([]*github.Repository{withPerms("web", map[string]bool{"maintain": true})}, okResponse(), nil),
+	)
+
+	repos, err := GetTeamRepos(context.Background(), teams, "octo", "platform")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 2 || repos[0].GetName() != "api" || repos[1].GetName() != "web" {
+		t.Errorf("got repos %v, want [api web]", repos)
+	}
+}
+
+func TestFetchBranchProtectionUnprotected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reader := mocks.NewMockBranchProtectionReader(ctrl)
+	reader.EXPECT().GetBranchProtection(gomock.Any(), "octo", "target", "main").Return(nil, nil, github.ErrBranchNotProtected)
+
+	protection, err := FetchBranchProtection(context.Background(), reader, "octo", "target", "main")
+	if err != nil || protection != nil {
+		t.Errorf("got %v, %v; want nil, nil", protection, err)
+	}
+}
+
+func TestFilterOptedOut(t *testing.T) {
+	repos := []*github.Repository{
+		{Name: github.String("kept"), Topics: []string{"go"}},
+		{Name: github.String("topic"), Topics: []string{"go", "no-protection-sync"}},
+		{Name: github.String("property"), CustomProperties: map[string]string{"protection-sync": "disabled"}},
+		{Name: github.String("enabled"), CustomProperties: map[string]string{"protection-sync": "enabled"}},
+	}
+
+	kept, optedOut := FilterOptedOut(repos, config.OptOut{})
+	if len(kept) != 2 || kept[0].GetName() != "kept" || kept[1].GetName() != "enabled" {
+		t.Errorf("got kept %v", kept)
+	}
+	if want := []string{"topic", "property"}; !reflect.DeepEqual(optedOut, want) {
+		t.Errorf("got opted out %v, want %v", optedOut, want)
+	}
+
+	kept, _ = FilterOptedOut(repos, config.OptOut{Topic: "go"})
+	if len(kept) != 1 || kept[0].GetName() != "enabled" {
+		t.Errorf("custom topic: got kept %v", kept)
+	}
+}
+
+func TestFilterByProperties(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lister := mocks.NewMockPropertyLister(ctrl)
+	lister.EXPECT().ListCustomPropertyValues(gomock.Any(), "octo", gomock.Any()).Return([]*github.RepoCustomPropertyValue{
+		{RepositoryName: "payments", Properties: []*github.CustomPropertyValue{
+			{PropertyName: "tier", Value: github.String("1")},
+			{PropertyName: "compliance", Value: github.String("pci")},
+		}},
+		{RepositoryName: "docs", Properties: []*github.CustomPropertyValue{
+			{PropertyName: "tier", Value: github.String("3")},
+		}},
+	}, &github.Response{}, nil)
+
+	values, err := GetCustomPropertyValues(context.Background(), lister, "octo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repos := []*github.Repository{{Name: github.String("payments")}, {Name: github.String("docs")}, {Name: github.String("unset")}}
+	kept := FilterByProperties(repos, values, map[string]string{"tier": "1", "compliance": "pci"})
+	if len(kept) != 1 || kept[0].GetName() != "payments" {
+		t.Errorf("got %v, want only payments", kept)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// DefaultGraphQLBatchSize is the number of repositories fetched per GraphQL
+// query, chosen to stay well below the query node limit.
+const DefaultGraphQLBatchSize = 50
+
+const branchProtectionRuleFields = `
+	pattern
+	isAdminEnforced
+	requiresApprovingReviews
+	requiredApprovingReviewCount
+	dismissesStaleReviews
+	requiresCodeOwnerReviews
+	requireLastPushApproval
+	restrictsReviewDismissals
+	requiresStatusChecks
+	requiresStrictStatusChecks
+	requiredStatusCheckContexts
+	requiresLinearHistory
+	allowsForcePushes
+	allowsDeletions
+	requiresConversationResolution
+	requiresCommitSignatures
+	restrictsPushes
+	blocksCreations
+	lockBranch
+	lockAllowsFetchAndMerge
+	pushAllowances(first: 100) { nodes { actor { ` + allowanceActorFields + ` } } }
+	reviewDismissalAllowances(first: 100) { nodes { actor { ` + allowanceActorFields + ` } } }`
+
+// allowanceActorFields selects the members of the PushAllowanceActor and
+// ReviewDismissalAllowanceActor unions. Unions have no fields of their own,
+// so each member is selected inline.
+const allowanceActorFields = `__typename ... on User { login } ... on Team { slug } ... on App { slug }`
+
+type gqlActor struct {
+	Typename string `json:"__typename"`
+	Login    string `json:"login"`
+	Slug     string `json:"slug"`
+}
+
+type gqlActorConnection struct {
+	Nodes []struct {
+		Actor gqlActor `json:"actor"`
+	} `json:"nodes"`
+}
+
+type gqlBranchProtectionRule struct {
+	Pattern                        string             `json:"pattern"`
+	IsAdminEnforced                bool               `json:"isAdminEnforced"`
+	RequiresApprovingReviews       bool               `json:"requiresApprovingReviews"`
+	RequiredApprovingReviewCount   int                `json:"requiredApprovingReviewCount"`
+	DismissesStaleReviews          bool               `json:"dismissesStaleReviews"`
+	RequiresCodeOwnerReviews       bool               `json:"requiresCodeOwnerReviews"`
+	RequireLastPushApproval        bool               `json:"requireLastPushApproval"`
+	RestrictsReviewDismissals      bool               `json:"restrictsReviewDismissals"`
+	RequiresStatusChecks           bool               `json:"requiresStatusChecks"`
+	RequiresStrictStatusChecks     bool               `json:"requiresStrictStatusChecks"`
+	RequiredStatusCheckContexts    []string           `json:"requiredStatusCheckContexts"`
+	RequiresLinearHistory          bool               `json:"requiresLinearHistory"`
+	AllowsForcePushes              bool               `json:"allowsForcePushes"`
+	AllowsDeletions                bool               `json:"allowsDeletions"`
+	RequiresConversationResolution bool               `json:"requiresConversationResolution"`
+	RequiresCommitSignatures       bool               `json:"requiresCommitSignatures"`
+	RestrictsPushes                bool               `json:"restrictsPushes"`
+	BlocksCreations                bool               `json:"blocksCreations"`
+	LockBranch                     bool               `json:"lockBranch"`
+	LockAllowsFetchAndMerge        bool               `json:"lockAllowsFetchAndMerge"`
+	PushAllowances                 gqlActorConnection `json:"pushAllowances"`
+	ReviewDismissalAllowances      gqlActorConnection `json:"reviewDismissalAllowances"`
+}
+
+type gqlRepository struct {
+	Name             string `json:"name"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	BranchProtectionRules struct {
+		Nodes []gqlBranchProtectionRule `json:"nodes"`
+	} `json:"branchProtectionRules"`
+}
+
+// GetRepoProtectionsBulk fetches the default branch protection of many
+// repositories through the GraphQL API, batching batchSize repositories per
+// query instead of issuing several REST calls per repository. The result is
+// keyed by repository name; repositories without a rule covering their
+// default branch map to a RepoProtection with a nil BranchProtection.
+// Rulesets are not part of the bulk fetch.
+func GetRepoProtectionsBulk(ctx context.Context, client ghclient.GraphQLQuerier, owner string, repos []string, batchSize int) (map[string]*types.RepoProtection, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultGraphQLBatchSize
+	}
+
+	result := make(map[string]*types.RepoProtection, len(repos))
+	for start := 0; start < len(repos); start += batchSize {
+		end := start + batchSize
+		if end > len(repos) {
+			end = len(repos)
+		}
+
+		fetched, err := queryBranchProtectionRules(ctx, client, owner, repos[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range fetched {
+			rp := &types.RepoProtection{}
+			if repo.DefaultBranchRef != nil {
+				rp.Branch = repo.DefaultBranchRef.Name
+				if rule := matchingRule(repo.BranchProtectionRules.Nodes, rp.Branch); rule != nil {
+					rp.BranchProtection = types.NewBranchProtection(rule.toProtection())
+				}
+			}
+			result[repo.Name] = rp
+		}
+	}
+	return result, nil
+}
+
+func queryBranchProtectionRules(ctx context.Context, client ghclient.GraphQLQuerier, owner string, repos []string) ([]gqlRepository, error) {
+	var q strings.Builder
+	q.WriteString("query {")
+	for i, name := range repos {
+		fmt.Fprintf(&q, "\n\tr%d: repository(owner: %s, name: %s) {\n\t\tname\n\t\tdefaultBranchRef { name }\n\t\tbranchProtectionRules(first: 100) { nodes {%s\n\t\t} }\n\t}",
+			i, graphQLString(owner), graphQLString(name), branchProtectionRuleFields)
+	}
+	q.WriteString("\n}")
+
+	var data map[string]*gqlRepository
+	if err := client.Query(ctx, q.String(), &data); err != nil {
+		return nil, err
+	}
+	repositories := make([]gqlRepository, 0, len(repos))
+	for i := range repos {
+		if repo := data[fmt.Sprintf("r%d", i)]; repo != nil {
+			repositories = append(repositories, *repo)
+		}
+	}
+	return repositories, nil
+}
+
+// matchingRule returns the rule protecting branch, preferring an exact
+// pattern over a wildcard one.
+func matchingRule(rules []gqlBranchProtectionRule, branch string) *gqlBranchProtectionRule {
+	var wildcard *gqlBranchProtectionRule
+	for i := range rules {
+		if rules[i].Pattern == branch {
+			return &rules[i]
+		}
+		if ok, _ := path.Match(rules[i].Pattern, branch); ok && wildcard == nil {
+			wildcard = &rules[i]
+		}
+	}
+	return wildcard
+}
+
+// toProtection converts a GraphQL branch protection rule into the REST
+// representation used throughout the tool.
+func (r *gqlBranchProtectionRule) toProtection() *github.Protection {
+	p := &github.Protection{
+		EnforceAdmins:                  &github.AdminEnforcement{Enabled: r.IsAdminEnforced},
+		RequireLinearHistory:           &github.RequireLinearHistory{Enabled: r.RequiresLinearHistory},
+		AllowForcePushes:               &github.AllowForcePushes{Enabled: r.AllowsForcePushes},
+		AllowDeletions:                 &github.AllowDeletions{Enabled: r.AllowsDeletions},
+		RequiredConversationResolution: &github.RequiredConversationResolution{Enabled: r.RequiresConversationResolution},
+		RequiredSignatures:             &github.SignaturesProtectedBranch{Enabled: github.Bool(r.RequiresCommitSignatures)},
+		BlockCreations:                 &github.BlockCreations{Enabled: github.Bool(r.BlocksCreations)},
+		LockBranch:                     &github.LockBranch{Enabled: github.Bool(r.LockBranch)},
+		AllowForkSyncing:               &github.AllowForkSyncing{Enabled: github.Bool(r.LockAllowsFetchAndMerge)},
+	}
+
+	if r.RequiresStatusChecks {
+		p.RequiredStatusChecks = &github.RequiredStatusChecks{
+			Strict:   r.RequiresStrictStatusChecks,
+			Contexts: r.RequiredStatusCheckContexts,
+		}
+	}
+
+	if r.RequiresApprovingReviews {
+		p.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcement{
+			DismissStaleReviews:          r.DismissesStaleReviews,
+			RequireCodeOwnerReviews:      r.RequiresCodeOwnerReviews,
+			RequiredApprovingReviewCount: r.RequiredApprovingReviewCount,
+			RequireLastPushApproval:      r.RequireLastPushApproval,
+		}
+		if r.RestrictsReviewDismissals {
+			dr := &github.DismissalRestrictions{}
+			for _, node := range r.ReviewDismissalAllowances.Nodes {
+				switch node.Actor.Typename {
+				case "User":
+					dr.Users = append(dr.Users, &github.User{Login: github.String(node.Actor.Login)})
+				case "Team":
+					dr.Teams = append(dr.Teams, &github.Team{Slug: github.String(node.Actor.Slug)})
+				case "App":
+					dr.Apps = append(dr.Apps, &github.App{Slug: github.String(node.Actor.Slug)})
+				}
+			}
+			p.RequiredPullRequestReviews.DismissalRestrictions = dr
+		}
+	}
+
+	if r.RestrictsPushes {
+		br := &github.BranchRestrictions{}
+		for _, node := range r.PushAllowances.Nodes {
+			switch node.Actor.Typename {
+			case "User":
+				br.Users = append(br.Users, &github.User{Login: github.String(node.Actor.Login)})
+			case "Team":
+				br.Teams = append(br.Teams, &github.Team{Slug: github.String(node.Actor.Slug)})
+			case "App":
+				br.Apps = append(br.Apps, &github.App{Slug: github.String(node.Actor.Slug)})
+			}
+		}
+		p.Restrictions = br
+	}
+
+	return p
+}
+
+// graphQLString renders s as a GraphQL string literal.
+func graphQLString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
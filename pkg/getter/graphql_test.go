@@ -0,0 +1,110 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestGetRepoProtectionsBulk(t *testing.T) {
+	var queries int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		queries++
+		var body struct{ Query string }
+		json.NewDecoder(r.Body).Decode(&body)
+		// The allowance actors are unions, so their members are selected
+		// inline rather than through a fragment on an interface
+		if strings.Contains(body.Query, "fragment") || !strings.Contains(body.Query, "actor { __typename ... on User { login } ... on Team { slug } ... on App { slug } }") {
+			t.Errorf("unexpected actor selection in query:\n%s", body.Query)
+		}
+
+		data := map[string]interface{}{}
+		if strings.Contains(body.Query, `name: "api"`) {
+			data["r0"] = map[string]interface{}{
+				"name":             "api",
+				"defaultBranchRef": map[string]string{"name": "main"},
+				"branchProtectionRules": map[string]interface{}{"nodes": []map[string]interface{}{
+					{"pattern": "release/*", "isAdminEnforced": false},
+					{
+						"pattern":                      "main",
+						"isAdminEnforced":              true,
+						"requiresApprovingReviews":     true,
+						"requiredApprovingReviewCount": 2,
+						"requiresStatusChecks":         true,
+						"requiredStatusCheckContexts":  []string{"build"},
+						"restrictsPushes":              true,
+						"pushAllowances": map[string]interface{}{"nodes": []map[string]interface{}{
+							{"actor": map[string]string{"__typename": "Team", "slug": "core"}},
+						}},
+					},
+				}},
+			}
+			data["r1"] = map[string]interface{}{
+				"name":                  "web",
+				"defaultBranchRef":      map[string]string{"name": "main"},
+				"branchProtectionRules": map[string]interface{}{"nodes": []interface{}{}},
+			}
+		} else {
+			data["r0"] = map[string]interface{}{
+				"name":             "docs",
+				"defaultBranchRef": map[string]string{"name": "trunk"},
+				"branchProtectionRules": map[string]interface{}{"nodes": []map[string]interface{}{
+					{"pattern": "*", "allowsForcePushes": true},
+				}},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer srv.Close()
+
+	gc, err := github.NewClient(nil).WithEnterpriseURLs(srv.URL+"/api/v3/", srv.URL+"/api/uploads/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetRepoProtectionsBulk(context.Background(), ghclient.New(gc).GraphQL, "octo", []string{"api", "web", "docs"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("got %d queries, want 2", queries)
+	}
+
+	api := got["api"].BranchProtection
+	if api == nil || !api.EnforceAdmins || api.Reviews.RequiredApprovals != 2 {
+		t.Fatalf("api protection not converted: %+v", api)
+	}
+	if api.StatusChecks.Contexts[0] != "build" || api.Restrictions.Teams[0] != "core" {
+		t.Errorf("api checks or restrictions not converted: %+v", api)
+	}
+	if got["web"].BranchProtection != nil {
+		t.Errorf("web should be unprotected")
+	}
+	if docs := got["docs"]; docs.Branch != "trunk" || !docs.BranchProtection.AllowForcePushes {
+		t.Errorf("docs should match the wildcard rule: %+v", docs)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package getter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// newHTTPClient returns a client whose requests are served by handler, to
+// exercise the decoding and pagination of go-github that the mocks skip.
+func newHTTPClient(t *testing.T, handler http.HandlerFunc) *ghclient.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	gc := github.NewClient(nil)
+	gc.BaseURL, _ = url.Parse(server.URL + "/")
+	return ghclient.New(gc)
+}
+
+func TestGetAllReposFromOrgFollowsLinks(t *testing.T) {
+	client := newHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/octo/repos" {
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/octo/repos?per_page=100&page=2>; rel="next"`, r.Host))
+			io.WriteString(w, `[{"name":"api"},{"name":"web"}]`)
+		case "2":
+			io.WriteString(w, `[{"name":"docs"}]`)
+		default:
+			t.Errorf("unexpected page %s", r.URL.Query().Get("page"))
+		}
+	})
+
+	repos, err := GetAllReposFromOrg(context.Background(), client.Repositories, "octo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 3 || repos[2].GetName() != "docs" {
+		t.Errorf("got %v, want the repositories of both pages", repos)
+	}
+}
+
+func TestGetAllReposFromOrgFetchesPagesConcurrently(t *testing.T) {
+	const pages = 20
+	var mu sync.Mutex
+	inFlight, most := 0, 0
+	client := newHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/octo/repos?page=2>; rel="next", <http://%s/orgs/octo/repos?page=%d>; rel="last"`, r.Host, r.Host, pages))
+			page = 1
+		} else {
+			mu.Lock()
+			inFlight++
+			if inFlight > most {
+				most = inFlight
+			}
+			mu.Unlock()
+			// Later pages answer first
+			time.Sleep(time.Duration(pages-page) * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}
+		fmt.Fprintf(w, `[{"name":"repo-%02d"}]`, page)
+	})
+
+	repos, err := GetAllReposFromOrg(context.Background(), client.Repositories, "octo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != pages {
+		t.Fatalf("got %d repositories, want %d", len(repos), pages)
+	}
+	for i, repo := range repos {
+		if want := fmt.Sprintf("repo-%02d", i+1); repo.GetName() != want {
+			t.Errorf("repository %d is %s, want %s in page order", i, repo.GetName(), want)
+		}
+	}
+	if most < 2 || most > pageWorkers {
+		t.Errorf("fetched up to %d pages at once, want between 2 and %d", most, pageWorkers)
+	}
+}
+
+func TestGetAllReposFromOrgPageError(t *testing.T) {
+	client := newHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/octo/repos?page=2>; rel="next", <http://%s/orgs/octo/repos?page=4>; rel="last"`, r.Host, r.Host))
+			io.WriteString(w, `[{"name":"api"}]`)
+		case "3":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			io.WriteString(w, `[]`)
+		}
+	})
+
+	if _, err := GetAllReposFromOrg(context.Background(), client.Repositories, "octo"); err == nil {
+		t.Fatal("a failing page was ignored")
+	}
+}
+
+const protectionPayload = `{
+  "required_status_checks": {"strict": true, "contexts": ["ci/build"], "checks": [{"context": "ci/build", "app_id": 15368}]},
+  "required_pull_request_reviews": {
+    "dismissal_restrictions": {"users": [{"login": "alice"}], "teams": [{"slug": "core"}], "apps": []},
+    "dismiss_stale_reviews": true,
+    "require_code_owner_reviews": true,
+    "required_approving_review_count": 2
+  },
+  "enforce_admins": {"url": "", "enabled": true},
+  "restrictions": {"users": [], "teams": [{"slug": "release"}], "apps": [{"slug": "deployer"}]},
+  "required_linear_history": {"enabled": true},
+  "allow_force_pushes": {"enabled": false},
+  "allow_deletions": {"enabled": false},
+  "required_conversation_resolution": {"enabled": true}
+}`
+
+func TestFetchRepoProtectionsDecodesPayloads(t *testing.T) {
+	client := newHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/octo/api":
+			io.WriteString(w, `{"name":"api","default_branch":"trunk","owner":{"login":"octo","type":"Organization"}}`)
+		case "/repos/octo/api/branches/trunk/protection":
+			io.WriteString(w, protectionPayload)
+		case "/repos/octo/api/rulesets":
+			io.WriteString(w, `[{"id":7,"name":"tags","enforcement":"active"}]`)
+		case "/repos/octo/api/rulesets/7":
+			io.WriteString(w, `{"id":7,"name":"tags","target":"tag","enforcement":"active","rules":[{"type":"deletion"}]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	rp, err := FetchRepoProtections(context.Background(), client, "octo", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.Branch != "trunk" || rp.OwnerType != "Organization" {
+		t.Errorf("got branch %q of a %q owner", rp.Branch, rp.OwnerType)
+	}
+	bp := rp.BranchProtection
+	reviews := bp.Reviews
+	if reviews.RequiredApprovals != 2 || !reviews.CodeOwners || reviews.DismissalRestrictions.Users[0] != "alice" {
+		t.Errorf("reviews decoded as %+v", reviews)
+	}
+	if !bp.EnforceAdmins || !bp.LinearHistory || bp.AllowForcePushes {
+		t.Errorf("toggles decoded as %+v", bp)
+	}
+	if checks := bp.StatusChecks.Checks; len(checks) != 1 || checks[0].AppID == nil || *checks[0].AppID != 15368 {
+		t.Errorf("checks decoded as %+v", checks)
+	}
+	if len(rp.Rulesets) != 1 || rp.Rulesets[0].GetTarget() != "tag" || len(rp.Rulesets[0].Rules) != 1 {
+		t.Errorf("rulesets decoded as %+v", rp.Rulesets)
+	}
+}
+
+func TestFetchBranchProtectionNotProtectedResponse(t *testing.T) {
+	client := newHTTPClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"message":"Branch not protected"}`)
+	})
+
+	protection, err := FetchBranchProtection(context.Background(), client.Repositories, "octo", "api", "main")
+	if protection != nil || err != nil {
+		t.Errorf("got %v, %v; want neither for an unprotected branch", protection, err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+import (
+	"context"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// actors implements ActorLookup on top of the users, teams and apps
+// services of a go-github client.
+type actors struct {
+	client *github.Client
+}
+
+func (a *actors) GetUser(ctx context.Context, login string) (*github.User, *github.Response, error) {
+	return a.client.Users.Get(ctx, login)
+}
+
+func (a *actors) GetTeamBySlug(ctx context.Context, org, slug string) (*github.Team, *github.Response, error) {
+	return a.client.Teams.GetTeamBySlug(ctx, org, slug)
+}
+
+func (a *actors) GetApp(ctx context.Context, slug string) (*github.App, *github.Response, error) {
+	return a.client.Apps.Get(ctx, slug)
+}
+
+// appInstallations implements AppInstallationLister on top of the
+// organizations and apps services of a go-github client.
+type appInstallations struct {
+	client *github.Client
+}
+
+func (a *appInstallations) ListInstallations(ctx context.Context, org string, opts *github.ListOptions) (*github.OrganizationInstallations, *github.Response, error) {
+	return a.client.Organizations.ListInstallations(ctx, org, opts)
+}
+
+func (a *appInstallations) ListUserRepos(ctx context.Context, id int64, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+	return a.client.Apps.ListUserRepos(ctx, id, opts)
+}
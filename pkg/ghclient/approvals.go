@@ -0,0 +1,62 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// workflowApprovals implements WorkflowApprovalManager on top of the raw
+// requests of a go-github client.
+type workflowApprovals struct {
+	client *github.Client
+}
+
+type forkPRApproval struct {
+	ApprovalPolicy string `json:"approval_policy"`
+}
+
+func forkPRApprovalURL(owner, repo string) string {
+	return fmt.Sprintf("repos/%v/%v/actions/permissions/fork-pr-contributor-approval", owner, repo)
+}
+
+// GetForkPRApprovalPolicy returns the approval policy of fork pull request
+// workflows, such as first_time_contributors.
+func (w *workflowApprovals) GetForkPRApprovalPolicy(ctx context.Context, owner, repo string) (string, *github.Response, error) {
+	req, err := w.client.NewRequest(http.MethodGet, forkPRApprovalURL(owner, repo), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	approval := new(forkPRApproval)
+	resp, err := w.client.Do(ctx, req, approval)
+	if err != nil {
+		return "", resp, err
+	}
+	return approval.ApprovalPolicy, resp, nil
+}
+
+// EditForkPRApprovalPolicy sets the approval policy of fork pull request workflows.
+func (w *workflowApprovals) EditForkPRApprovalPolicy(ctx context.Context, owner, repo, policy string) (*github.Response, error) {
+	req, err := w.client.NewRequest(http.MethodPut, forkPRApprovalURL(owner, repo), &forkPRApproval{ApprovalPolicy: policy})
+	if err != nil {
+		return nil, err
+	}
+	return w.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,300 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+//go:generate mockgen -source=ghclient.go -destination=mocks/mocks.go -package=mocks
+
+import (
+	"context"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// BranchProtectionReader reads the default branch and the branch protection
+// settings of a repository.
+type BranchProtectionReader interface {
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error)
+	GetSignaturesProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error)
+}
+
+// BranchProtectionWriter applies branch protection settings to a repository.
+type BranchProtectionWriter interface {
+	UpdateBranchProtection(ctx context.Context, owner, repo, branch string, preq *github.ProtectionRequest) (*github.Protection, *github.Response, error)
+	RequireSignaturesOnProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error)
+	OptionalSignaturesOnProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.Response, error)
+	RemoveBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Response, error)
+}
+
+// RepoEditor updates the settings of a repository.
+type RepoEditor interface {
+	Edit(ctx context.Context, owner, repo string, repository *github.Repository) (*github.Repository, *github.Response, error)
+}
+
+// SecurityManager reads and toggles the Dependabot alerts and security
+// updates of a repository.
+type SecurityManager interface {
+	GetVulnerabilityAlerts(ctx context.Context, owner, repository string) (bool, *github.Response, error)
+	EnableVulnerabilityAlerts(ctx context.Context, owner, repository string) (*github.Response, error)
+	DisableVulnerabilityAlerts(ctx context.Context, owner, repository string) (*github.Response, error)
+	GetAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.AutomatedSecurityFixes, *github.Response, error)
+	EnableAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.Response, error)
+	DisableAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.Response, error)
+}
+
+// ActionsPermissionsManager reads and writes the GitHub Actions settings of
+// a repository.
+type ActionsPermissionsManager interface {
+	GetActionsPermissions(ctx context.Context, owner, repo string) (*github.ActionsPermissionsRepository, *github.Response, error)
+	EditActionsPermissions(ctx context.Context, owner, repo string, actionsPermissionsRepository github.ActionsPermissionsRepository) (*github.ActionsPermissionsRepository, *github.Response, error)
+	GetActionsAllowed(ctx context.Context, org, repo string) (*github.ActionsAllowed, *github.Response, error)
+	EditActionsAllowed(ctx context.Context, org, repo string, actionsAllowed github.ActionsAllowed) (*github.ActionsAllowed, *github.Response, error)
+	GetDefaultWorkflowPermissions(ctx context.Context, owner, repo string) (*github.DefaultWorkflowPermissionRepository, *github.Response, error)
+	EditDefaultWorkflowPermissions(ctx context.Context, owner, repo string, permissions github.DefaultWorkflowPermissionRepository) (*github.DefaultWorkflowPermissionRepository, *github.Response, error)
+}
+
+// WorkflowApprovalManager reads and writes which outside contributors need
+// approval before workflows run on their fork pull requests.
+type WorkflowApprovalManager interface {
+	GetForkPRApprovalPolicy(ctx context.Context, owner, repo string) (string, *github.Response, error)
+	EditForkPRApprovalPolicy(ctx context.Context, owner, repo, policy string) (*github.Response, error)
+}
+
+// EnvironmentManager reads and writes the deployment environments of a
+// repository and their branch policies.
+type EnvironmentManager interface {
+	ListEnvironments(ctx context.Context, owner, repo string, opts *github.EnvironmentListOptions) (*github.EnvResponse, *github.Response, error)
+	CreateUpdateEnvironment(ctx context.Context, owner, repo, name string, environment *github.CreateUpdateEnvironment) (*github.Environment, *github.Response, error)
+	ListDeploymentBranchPolicies(ctx context.Context, owner, repo, environment string) (*github.DeploymentBranchPolicyResponse, *github.Response, error)
+	CreateDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, request *github.DeploymentBranchPolicyRequest) (*github.DeploymentBranchPolicy, *github.Response, error)
+	DeleteDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, branchPolicyID int64) (*github.Response, error)
+}
+
+// ContentManager validates and adds files in a repository.
+type ContentManager interface {
+	GetCodeownersErrors(ctx context.Context, owner, repo string, opts *github.GetCodeownersErrorsOptions) (*github.CodeownersErrors, *github.Response, error)
+	CreateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+	UpdateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error)
+}
+
+// RefManager reads and creates git references.
+type RefManager interface {
+	GetRef(ctx context.Context, owner string, repo string, ref string) (*github.Reference, *github.Response, error)
+	CreateRef(ctx context.Context, owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
+}
+
+// PullRequestCreator opens pull requests.
+type PullRequestCreator interface {
+	Create(ctx context.Context, owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+// HookManager reads and writes the webhooks of a repository.
+type HookManager interface {
+	ListHooks(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Hook, *github.Response, error)
+	CreateHook(ctx context.Context, owner, repo string, hook *github.Hook) (*github.Hook, *github.Response, error)
+	EditHook(ctx context.Context, owner, repo string, id int64, hook *github.Hook) (*github.Hook, *github.Response, error)
+}
+
+// KeyLister lists the deploy keys of a repository.
+type KeyLister interface {
+	ListKeys(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.Key, *github.Response, error)
+}
+
+// RunnerLister lists the self-hosted runners registered to a repository.
+type RunnerLister interface {
+	ListRunners(ctx context.Context, owner, repo string, opts *github.ListOptions) (*github.Runners, *github.Response, error)
+}
+
+// AutolinkManager reads and writes the autolink references of a repository.
+type AutolinkManager interface {
+	ListAutolinks(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Autolink, *github.Response, error)
+	AddAutolink(ctx context.Context, owner, repo string, opts *github.AutolinkOptions) (*github.Autolink, *github.Response, error)
+	DeleteAutolink(ctx context.Context, owner, repo string, id int64) (*github.Response, error)
+}
+
+// RepoLister lists the repositories of an organization.
+type RepoLister interface {
+	ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error)
+}
+
+// RulesetManager reads and writes repository rulesets.
+type RulesetManager interface {
+	GetAllRulesets(ctx context.Context, owner, repo string, includesParents bool) ([]*github.Ruleset, *github.Response, error)
+	GetRuleset(ctx context.Context, owner, repo string, rulesetID int64, includesParents bool) (*github.Ruleset, *github.Response, error)
+	CreateRuleset(ctx context.Context, owner, repo string, rs *github.Ruleset) (*github.Ruleset, *github.Response, error)
+	UpdateRuleset(ctx context.Context, owner, repo string, rulesetID int64, rs *github.Ruleset) (*github.Ruleset, *github.Response, error)
+	DeleteRuleset(ctx context.Context, owner, repo string, rulesetID int64) (*github.Response, error)
+}
+
+// AccessLister lists the users and teams with access to a repository.
+type AccessLister interface {
+	ListCollaborators(ctx context.Context, owner, repo string, opts *github.ListCollaboratorsOptions) ([]*github.User, *github.Response, error)
+	ListTeams(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.Team, *github.Response, error)
+}
+
+// StatusReader reads the commit statuses reported for a ref.
+type StatusReader interface {
+	GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) (*github.CombinedStatus, *github.Response, error)
+}
+
+// StatusWriter reports commit statuses.
+type StatusWriter interface {
+	CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
+}
+
+// CheckRunLister lists the check runs reported for a ref.
+type CheckRunLister interface {
+	ListCheckRunsForRef(ctx context.Context, owner, repo, ref string, opts *github.ListCheckRunsOptions) (*github.ListCheckRunsResults, *github.Response, error)
+}
+
+// InstallationRepoLister lists the repositories a GitHub App installation
+// has been granted access to.
+type InstallationRepoLister interface {
+	ListRepos(ctx context.Context, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error)
+}
+
+// PropertyLister lists the custom property values of the repositories of
+// an organization.
+type PropertyLister interface {
+	ListCustomPropertyValues(ctx context.Context, org string, opts *github.ListOptions) ([]*github.RepoCustomPropertyValue, *github.Response, error)
+}
+
+// TeamRepoLister lists the repositories a team of an organization has
+// access to.
+type TeamRepoLister interface {
+	ListTeamReposBySlug(ctx context.Context, org, slug string, opts *github.ListOptions) ([]*github.Repository, *github.Response, error)
+}
+
+// IssueManager finds, files and updates issues.
+type IssueManager interface {
+	ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+// LabelManager reads and writes the issue labels of a repository.
+type LabelManager interface {
+	ListLabels(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.Label, *github.Response, error)
+	CreateLabel(ctx context.Context, owner string, repo string, label *github.Label) (*github.Label, *github.Response, error)
+	EditLabel(ctx context.Context, owner string, repo string, name string, label *github.Label) (*github.Label, *github.Response, error)
+	DeleteLabel(ctx context.Context, owner string, repo string, name string) (*github.Response, error)
+}
+
+// RateLimitReader reads the current API rate limits.
+type RateLimitReader interface {
+	Get(ctx context.Context) (*github.RateLimits, *github.Response, error)
+}
+
+// MetaReader reads the meta information of the API, whose response tells
+// GitHub Enterprise Server instances apart from github.com.
+type MetaReader interface {
+	Get(ctx context.Context) (*github.APIMeta, *github.Response, error)
+}
+
+// ActorLookup finds the users, teams and apps branch restrictions refer to.
+type ActorLookup interface {
+	GetUser(ctx context.Context, login string) (*github.User, *github.Response, error)
+	GetTeamBySlug(ctx context.Context, org, slug string) (*github.Team, *github.Response, error)
+	GetApp(ctx context.Context, slug string) (*github.App, *github.Response, error)
+}
+
+// AppInstallationLister lists the GitHub Apps installed in an organization
+// and the repositories an installation was granted.
+type AppInstallationLister interface {
+	ListInstallations(ctx context.Context, org string, opts *github.ListOptions) (*github.OrganizationInstallations, *github.Response, error)
+	ListUserRepos(ctx context.Context, id int64, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error)
+}
+
+// GraphQLQuerier sends queries to the GraphQL API, decoding the data of the
+// response into data. Partial data is decoded without an error.
+type GraphQLQuerier interface {
+	Query(ctx context.Context, query string, data interface{}) error
+}
+
+// RateCache reports the core rate limit of the most recent API response.
+type RateCache interface {
+	Core() (github.Rate, bool)
+}
+
+// Repositories is the part of the repositories API used by the tool.
+// *github.RepositoriesService satisfies it.
+type Repositories interface {
+	BranchProtectionReader
+	BranchProtectionWriter
+	RepoEditor
+	SecurityManager
+	ActionsPermissionsManager
+	EnvironmentManager
+	ContentManager
+	HookManager
+	KeyLister
+	AutolinkManager
+	RepoLister
+	RulesetManager
+	AccessLister
+	StatusReader
+	StatusWriter
+}
+
+// Client bundles the API surfaces consumed by the getter and setter packages
+// so they can be exercised without talking to GitHub.
+type Client struct {
+	Repositories  Repositories
+	RateLimit     RateLimitReader
+	Meta          MetaReader
+	Apps          InstallationRepoLister
+	Checks        CheckRunLister
+	Issues        IssueManager
+	Organizations PropertyLister
+	Teams         TeamRepoLister
+	Git           RefManager
+	PullRequests  PullRequestCreator
+	Actions       RunnerLister
+	Labels        LabelManager
+	// WorkflowApprovals isn't covered by go-github and wraps the REST API directly.
+	WorkflowApprovals WorkflowApprovalManager
+	// Actors and AppInstallations span several go-github services.
+	Actors           ActorLookup
+	AppInstallations AppInstallationLister
+	// GraphQL sends queries with the transport of the REST client.
+	GraphQL GraphQLQuerier
+	// Rates is set by the caller when the transport of the client records
+	// the rate limit of every response.
+	Rates RateCache
+}
+
+// New wraps a go-github client.
+func New(client *github.Client) *Client {
+	return &Client{
+		Repositories:      &repositories{RepositoriesService: client.Repositories, client: client},
+		RateLimit:         client.RateLimit,
+		Meta:              client.Meta,
+		Apps:              client.Apps,
+		Checks:            client.Checks,
+		Issues:            client.Issues,
+		Organizations:     client.Organizations,
+		Teams:             client.Teams,
+		Git:               client.Git,
+		PullRequests:      client.PullRequests,
+		Actions:           client.Actions,
+		Labels:            client.Issues,
+		WorkflowApprovals: &workflowApprovals{client: client},
+		Actors:            &actors{client: client},
+		AppInstallations:  &appInstallations{client: client},
+		GraphQL:           &graphQL{client: client},
+	}
+}
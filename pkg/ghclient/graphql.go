@@ -0,0 +1,70 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// graphQL implements GraphQLQuerier with the authenticated transport of a
+// go-github client.
+type graphQL struct {
+	client *github.Client
+}
+
+func (g *graphQL) Query(ctx context.Context, query string, data interface{}) error {
+	req, err := g.client.NewRequest(http.MethodPost, graphQLURL(g.client.BaseURL), map[string]string{"query": query})
+	if err != nil {
+		return err
+	}
+	var payload struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := g.client.Do(ctx, req, &payload); err != nil {
+		return err
+	}
+	// Missing repositories and the like are reported alongside partial data
+	if len(payload.Data) == 0 || string(payload.Data) == "null" {
+		if len(payload.Errors) > 0 {
+			return fmt.Errorf("GraphQL query failed: %s", payload.Errors[0].Message)
+		}
+		return fmt.Errorf("GraphQL query returned no data")
+	}
+	return json.Unmarshal(payload.Data, data)
+}
+
+// graphQLURL derives the GraphQL endpoint from the REST base URL, handling
+// both github.com and GitHub Enterprise Server.
+func graphQLURL(baseURL *url.URL) string {
+	u := *baseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path = path.Join(u.Path, "graphql")
+	}
+	return u.String()
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com/":              "https://api.github.com/graphql",
+		"https://ghes.example.com/api/v3/":     "https://ghes.example.com/api/graphql",
+		"https://proxy.example.com/github/v3/": "https://proxy.example.com/github/v3/graphql",
+	}
+	for base, want := range tests {
+		u, _ := url.Parse(base)
+		if got := graphQLURL(u); got != want {
+			t.Errorf("graphQLURL(%s) = %s, want %s", base, got, want)
+		}
+	}
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	response := `{"data": {"r0": {"name": "api"}, "r1": null}, "errors": [{"message": "Could not resolve to a Repository"}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	defer srv.Close()
+	gc, _ := github.NewClient(nil).WithEnterpriseURLs(srv.URL+"/api/v3/", srv.URL+"/api/uploads/")
+	client := New(gc).GraphQL
+
+	var data map[string]*struct{ Name string }
+	if err := client.Query(context.Background(), "query {}", &data); err != nil {
+		t.Fatalf("partial data returned an error: %v", err)
+	}
+	if data["r0"] == nil || data["r0"].Name != "api" {
+		t.Errorf("data not decoded: %+v", data)
+	}
+
+	response = `{"data": null, "errors": [{"message": "Field 'lockBranch' doesn't exist"}]}`
+	if err := client.Query(context.Background(), "query {}", &data); err == nil {
+		t.Error("errors without data returned no error")
+	}
+}
@@ -0,0 +1,2621 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ghclient.go
+//
+// Generated by this command:
+//
+//	mockgen -source=ghclient.go -destination=mocks/mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	github "github.com/google/go-github/v59/github"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBranchProtectionReader is a mock of BranchProtectionReader interface.
+type MockBranchProtectionReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockBranchProtectionReaderMockRecorder
+}
+
+// MockBranchProtectionReaderMockRecorder is the mock recorder for MockBranchProtectionReader.
+type MockBranchProtectionReaderMockRecorder struct {
+	mock *MockBranchProtectionReader
+}
+
+// NewMockBranchProtectionReader creates a new mock instance.
+func NewMockBranchProtectionReader(ctrl *gomock.Controller) *MockBranchProtectionReader {
+	mock := &MockBranchProtectionReader{ctrl: ctrl}
+	mock.recorder = &MockBranchProtectionReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBranchProtectionReader) EXPECT() *MockBranchProtectionReaderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockBranchProtectionReader) Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBranchProtectionReaderMockRecorder) Get(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBranchProtectionReader)(nil).Get), ctx, owner, repo)
+}
+
+// GetBranchProtection mocks base method.
+func (m *MockBranchProtectionReader) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtection", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Protection)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBranchProtection indicates an expected call of GetBranchProtection.
+func (mr *MockBranchProtectionReaderMockRecorder) GetBranchProtection(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtection", reflect.TypeOf((*MockBranchProtectionReader)(nil).GetBranchProtection), ctx, owner, repo, branch)
+}
+
+// GetSignaturesProtectedBranch mocks base method.
+func (m *MockBranchProtectionReader) GetSignaturesProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSignaturesProtectedBranch", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.SignaturesProtectedBranch)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSignaturesProtectedBranch indicates an expected call of GetSignaturesProtectedBranch.
+func (mr *MockBranchProtectionReaderMockRecorder) GetSignaturesProtectedBranch(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignaturesProtectedBranch", reflect.TypeOf((*MockBranchProtectionReader)(nil).GetSignaturesProtectedBranch), ctx, owner, repo, branch)
+}
+
+// MockBranchProtectionWriter is a mock of BranchProtectionWriter interface.
+type MockBranchProtectionWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBranchProtectionWriterMockRecorder
+}
+
+// MockBranchProtectionWriterMockRecorder is the mock recorder for MockBranchProtectionWriter.
+type MockBranchProtectionWriterMockRecorder struct {
+	mock *MockBranchProtectionWriter
+}
+
+// NewMockBranchProtectionWriter creates a new mock instance.
+func NewMockBranchProtectionWriter(ctrl *gomock.Controller) *MockBranchProtectionWriter {
+	mock := &MockBranchProtectionWriter{ctrl: ctrl}
+	mock.recorder = &MockBranchProtectionWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBranchProtectionWriter) EXPECT() *MockBranchProtectionWriterMockRecorder {
+	return m.recorder
+}
+
+// OptionalSignaturesOnProtectedBranch mocks base method.
+func (m *MockBranchProtectionWriter) OptionalSignaturesOnProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OptionalSignaturesOnProtectedBranch", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OptionalSignaturesOnProtectedBranch indicates an expected call of OptionalSignaturesOnProtectedBranch.
+func (mr *MockBranchProtectionWriterMockRecorder) OptionalSignaturesOnProtectedBranch(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OptionalSignaturesOnProtectedBranch", reflect.TypeOf((*MockBranchProtectionWriter)(nil).OptionalSignaturesOnProtectedBranch), ctx, owner, repo, branch)
+}
+
+// RemoveBranchProtection mocks base method.
+func (m *MockBranchProtectionWriter) RemoveBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBranchProtection", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveBranchProtection indicates an expected call of RemoveBranchProtection.
+func (mr *MockBranchProtectionWriterMockRecorder) RemoveBranchProtection(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBranchProtection", reflect.TypeOf((*MockBranchProtectionWriter)(nil).RemoveBranchProtection), ctx, owner, repo, branch)
+}
+
+// RequireSignaturesOnProtectedBranch mocks base method.
+func (m *MockBranchProtectionWriter) RequireSignaturesOnProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireSignaturesOnProtectedBranch", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.SignaturesProtectedBranch)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RequireSignaturesOnProtectedBranch indicates an expected call of RequireSignaturesOnProtectedBranch.
+func (mr *MockBranchProtectionWriterMockRecorder) RequireSignaturesOnProtectedBranch(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireSignaturesOnProtectedBranch", reflect.TypeOf((*MockBranchProtectionWriter)(nil).RequireSignaturesOnProtectedBranch), ctx, owner, repo, branch)
+}
+
+// UpdateBranchProtection mocks base method.
+func (m *MockBranchProtectionWriter) UpdateBranchProtection(ctx context.Context, owner, repo, branch string, preq *github.ProtectionRequest) (*github.Protection, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBranchProtection", ctx, owner, repo, branch, preq)
+	ret0, _ := ret[0].(*github.Protection)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateBranchProtection indicates an expected call of UpdateBranchProtection.
+func (mr *MockBranchProtectionWriterMockRecorder) UpdateBranchProtection(ctx, owner, repo, branch, preq any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranchProtection", reflect.TypeOf((*MockBranchProtectionWriter)(nil).UpdateBranchProtection), ctx, owner, repo, branch, preq)
+}
+
+// MockRepoEditor is a mock of RepoEditor interface.
+type MockRepoEditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoEditorMockRecorder
+}
+
+// MockRepoEditorMockRecorder is the mock recorder for MockRepoEditor.
+type MockRepoEditorMockRecorder struct {
+	mock *MockRepoEditor
+}
+
+// NewMockRepoEditor creates a new mock instance.
+func NewMockRepoEditor(ctrl *gomock.Controller) *MockRepoEditor {
+	mock := &MockRepoEditor{ctrl: ctrl}
+	mock.recorder = &MockRepoEditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoEditor) EXPECT() *MockRepoEditorMockRecorder {
+	return m.recorder
+}
+
+// Edit mocks base method.
+func (m *MockRepoEditor) Edit(ctx context.Context, owner, repo string, repository *github.Repository) (*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, owner, repo, repository)
+	ret0, _ := ret[0].(*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockRepoEditorMockRecorder) Edit(ctx, owner, repo, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockRepoEditor)(nil).Edit), ctx, owner, repo, repository)
+}
+
+// MockSecurityManager is a mock of SecurityManager interface.
+type MockSecurityManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecurityManagerMockRecorder
+}
+
+// MockSecurityManagerMockRecorder is the mock recorder for MockSecurityManager.
+type MockSecurityManagerMockRecorder struct {
+	mock *MockSecurityManager
+}
+
+// NewMockSecurityManager creates a new mock instance.
+func NewMockSecurityManager(ctrl *gomock.Controller) *MockSecurityManager {
+	mock := &MockSecurityManager{ctrl: ctrl}
+	mock.recorder = &MockSecurityManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecurityManager) EXPECT() *MockSecurityManagerMockRecorder {
+	return m.recorder
+}
+
+// DisableAutomatedSecurityFixes mocks base method.
+func (m *MockSecurityManager) DisableAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableAutomatedSecurityFixes", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableAutomatedSecurityFixes indicates an expected call of DisableAutomatedSecurityFixes.
+func (mr *MockSecurityManagerMockRecorder) DisableAutomatedSecurityFixes(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableAutomatedSecurityFixes", reflect.TypeOf((*MockSecurityManager)(nil).DisableAutomatedSecurityFixes), ctx, owner, repository)
+}
+
+// DisableVulnerabilityAlerts mocks base method.
+func (m *MockSecurityManager) DisableVulnerabilityAlerts(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableVulnerabilityAlerts", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableVulnerabilityAlerts indicates an expected call of DisableVulnerabilityAlerts.
+func (mr *MockSecurityManagerMockRecorder) DisableVulnerabilityAlerts(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableVulnerabilityAlerts", reflect.TypeOf((*MockSecurityManager)(nil).DisableVulnerabilityAlerts), ctx, owner, repository)
+}
+
+// EnableAutomatedSecurityFixes mocks base method.
+func (m *MockSecurityManager) EnableAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableAutomatedSecurityFixes", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableAutomatedSecurityFixes indicates an expected call of EnableAutomatedSecurityFixes.
+func (mr *MockSecurityManagerMockRecorder) EnableAutomatedSecurityFixes(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAutomatedSecurityFixes", reflect.TypeOf((*MockSecurityManager)(nil).EnableAutomatedSecurityFixes), ctx, owner, repository)
+}
+
+// EnableVulnerabilityAlerts mocks base method.
+func (m *MockSecurityManager) EnableVulnerabilityAlerts(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableVulnerabilityAlerts", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableVulnerabilityAlerts indicates an expected call of EnableVulnerabilityAlerts.
+func (mr *MockSecurityManagerMockRecorder) EnableVulnerabilityAlerts(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableVulnerabilityAlerts", reflect.TypeOf((*MockSecurityManager)(nil).EnableVulnerabilityAlerts), ctx, owner, repository)
+}
+
+// GetAutomatedSecurityFixes mocks base method.
+func (m *MockSecurityManager) GetAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.AutomatedSecurityFixes, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomatedSecurityFixes", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.AutomatedSecurityFixes)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAutomatedSecurityFixes indicates an expected call of GetAutomatedSecurityFixes.
+func (mr *MockSecurityManagerMockRecorder) GetAutomatedSecurityFixes(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomatedSecurityFixes", reflect.TypeOf((*MockSecurityManager)(nil).GetAutomatedSecurityFixes), ctx, owner, repository)
+}
+
+// GetVulnerabilityAlerts mocks base method.
+func (m *MockSecurityManager) GetVulnerabilityAlerts(ctx context.Context, owner, repository string) (bool, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVulnerabilityAlerts", ctx, owner, repository)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVulnerabilityAlerts indicates an expected call of GetVulnerabilityAlerts.
+func (mr *MockSecurityManagerMockRecorder) GetVulnerabilityAlerts(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVulnerabilityAlerts", reflect.TypeOf((*MockSecurityManager)(nil).GetVulnerabilityAlerts), ctx, owner, repository)
+}
+
+// MockActionsPermissionsManager is a mock of ActionsPermissionsManager interface.
+type MockActionsPermissionsManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockActionsPermissionsManagerMockRecorder
+}
+
+// MockActionsPermissionsManagerMockRecorder is the mock recorder for MockActionsPermissionsManager.
+type MockActionsPermissionsManagerMockRecorder struct {
+	mock *MockActionsPermissionsManager
+}
+
+// NewMockActionsPermissionsManager creates a new mock instance.
+func NewMockActionsPermissionsManager(ctrl *gomock.Controller) *MockActionsPermissionsManager {
+	mock := &MockActionsPermissionsManager{ctrl: ctrl}
+	mock.recorder = &MockActionsPermissionsManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActionsPermissionsManager) EXPECT() *MockActionsPermissionsManagerMockRecorder {
+	return m.recorder
+}
+
+// EditActionsAllowed mocks base method.
+func (m *MockActionsPermissionsManager) EditActionsAllowed(ctx context.Context, org, repo string, actionsAllowed github.ActionsAllowed) (*github.ActionsAllowed, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditActionsAllowed", ctx, org, repo, actionsAllowed)
+	ret0, _ := ret[0].(*github.ActionsAllowed)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditActionsAllowed indicates an expected call of EditActionsAllowed.
+func (mr *MockActionsPermissionsManagerMockRecorder) EditActionsAllowed(ctx, org, repo, actionsAllowed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditActionsAllowed", reflect.TypeOf((*MockActionsPermissionsManager)(nil).EditActionsAllowed), ctx, org, repo, actionsAllowed)
+}
+
+// EditActionsPermissions mocks base method.
+func (m *MockActionsPermissionsManager) EditActionsPermissions(ctx context.Context, owner, repo string, actionsPermissionsRepository github.ActionsPermissionsRepository) (*github.ActionsPermissionsRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditActionsPermissions", ctx, owner, repo, actionsPermissionsRepository)
+	ret0, _ := ret[0].(*github.ActionsPermissionsRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditActionsPermissions indicates an expected call of EditActionsPermissions.
+func (mr *MockActionsPermissionsManagerMockRecorder) EditActionsPermissions(ctx, owner, repo, actionsPermissionsRepository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditActionsPermissions", reflect.TypeOf((*MockActionsPermissionsManager)(nil).EditActionsPermissions), ctx, owner, repo, actionsPermissionsRepository)
+}
+
+// EditDefaultWorkflowPermissions mocks base method.
+func (m *MockActionsPermissionsManager) EditDefaultWorkflowPermissions(ctx context.Context, owner, repo string, permissions github.DefaultWorkflowPermissionRepository) (*github.DefaultWorkflowPermissionRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditDefaultWorkflowPermissions", ctx, owner, repo, permissions)
+	ret0, _ := ret[0].(*github.DefaultWorkflowPermissionRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditDefaultWorkflowPermissions indicates an expected call of EditDefaultWorkflowPermissions.
+func (mr *MockActionsPermissionsManagerMockRecorder) EditDefaultWorkflowPermissions(ctx, owner, repo, permissions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditDefaultWorkflowPermissions", reflect.TypeOf((*MockActionsPermissionsManager)(nil).EditDefaultWorkflowPermissions), ctx, owner, repo, permissions)
+}
+
+// GetActionsAllowed mocks base method.
+func (m *MockActionsPermissionsManager) GetActionsAllowed(ctx context.Context, org, repo string) (*github.ActionsAllowed, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionsAllowed", ctx, org, repo)
+	ret0, _ := ret[0].(*github.ActionsAllowed)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActionsAllowed indicates an expected call of GetActionsAllowed.
+func (mr *MockActionsPermissionsManagerMockRecorder) GetActionsAllowed(ctx, org, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionsAllowed", reflect.TypeOf((*MockActionsPermissionsManager)(nil).GetActionsAllowed), ctx, org, repo)
+}
+
+// GetActionsPermissions mocks base method.
+func (m *MockActionsPermissionsManager) GetActionsPermissions(ctx context.Context, owner, repo string) (*github.ActionsPermissionsRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionsPermissions", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.ActionsPermissionsRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActionsPermissions indicates an expected call of GetActionsPermissions.
+func (mr *MockActionsPermissionsManagerMockRecorder) GetActionsPermissions(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionsPermissions", reflect.TypeOf((*MockActionsPermissionsManager)(nil).GetActionsPermissions), ctx, owner, repo)
+}
+
+// GetDefaultWorkflowPermissions mocks base method.
+func (m *MockActionsPermissionsManager) GetDefaultWorkflowPermissions(ctx context.Context, owner, repo string) (*github.DefaultWorkflowPermissionRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultWorkflowPermissions", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.DefaultWorkflowPermissionRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDefaultWorkflowPermissions indicates an expected call of GetDefaultWorkflowPermissions.
+func (mr *MockActionsPermissionsManagerMockRecorder) GetDefaultWorkflowPermissions(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultWorkflowPermissions", reflect.TypeOf((*MockActionsPermissionsManager)(nil).GetDefaultWorkflowPermissions), ctx, owner, repo)
+}
+
+// MockWorkflowApprovalManager is a mock of WorkflowApprovalManager interface.
+type MockWorkflowApprovalManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowApprovalManagerMockRecorder
+}
+
+// MockWorkflowApprovalManagerMockRecorder is the mock recorder for MockWorkflowApprovalManager.
+type MockWorkflowApprovalManagerMockRecorder struct {
+	mock *MockWorkflowApprovalManager
+}
+
+// NewMockWorkflowApprovalManager creates a new mock instance.
+func NewMockWorkflowApprovalManager(ctrl *gomock.Controller) *MockWorkflowApprovalManager {
+	mock := &MockWorkflowApprovalManager{ctrl: ctrl}
+	mock.recorder = &MockWorkflowApprovalManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowApprovalManager) EXPECT() *MockWorkflowApprovalManagerMockRecorder {
+	return m.recorder
+}
+
+// EditForkPRApprovalPolicy mocks base method.
+func (m *MockWorkflowApprovalManager) EditForkPRApprovalPolicy(ctx context.Context, owner, repo, policy string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditForkPRApprovalPolicy", ctx, owner, repo, policy)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditForkPRApprovalPolicy indicates an expected call of EditForkPRApprovalPolicy.
+func (mr *MockWorkflowApprovalManagerMockRecorder) EditForkPRApprovalPolicy(ctx, owner, repo, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditForkPRApprovalPolicy", reflect.TypeOf((*MockWorkflowApprovalManager)(nil).EditForkPRApprovalPolicy), ctx, owner, repo, policy)
+}
+
+// GetForkPRApprovalPolicy mocks base method.
+func (m *MockWorkflowApprovalManager) GetForkPRApprovalPolicy(ctx context.Context, owner, repo string) (string, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForkPRApprovalPolicy", ctx, owner, repo)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetForkPRApprovalPolicy indicates an expected call of GetForkPRApprovalPolicy.
+func (mr *MockWorkflowApprovalManagerMockRecorder) GetForkPRApprovalPolicy(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForkPRApprovalPolicy", reflect.TypeOf((*MockWorkflowApprovalManager)(nil).GetForkPRApprovalPolicy), ctx, owner, repo)
+}
+
+// MockEnvironmentManager is a mock of EnvironmentManager interface.
+type MockEnvironmentManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnvironmentManagerMockRecorder
+}
+
+// MockEnvironmentManagerMockRecorder is the mock recorder for MockEnvironmentManager.
+type MockEnvironmentManagerMockRecorder struct {
+	mock *MockEnvironmentManager
+}
+
+// NewMockEnvironmentManager creates a new mock instance.
+func NewMockEnvironmentManager(ctrl *gomock.Controller) *MockEnvironmentManager {
+	mock := &MockEnvironmentManager{ctrl: ctrl}
+	mock.recorder = &MockEnvironmentManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEnvironmentManager) EXPECT() *MockEnvironmentManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateDeploymentBranchPolicy mocks base method.
+func (m *MockEnvironmentManager) CreateDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, request *github.DeploymentBranchPolicyRequest) (*github.DeploymentBranchPolicy, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeploymentBranchPolicy", ctx, owner, repo, environment, request)
+	ret0, _ := ret[0].(*github.DeploymentBranchPolicy)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateDeploymentBranchPolicy indicates an expected call of CreateDeploymentBranchPolicy.
+func (mr *MockEnvironmentManagerMockRecorder) CreateDeploymentBranchPolicy(ctx, owner, repo, environment, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeploymentBranchPolicy", reflect.TypeOf((*MockEnvironmentManager)(nil).CreateDeploymentBranchPolicy), ctx, owner, repo, environment, request)
+}
+
+// CreateUpdateEnvironment mocks base method.
+func (m *MockEnvironmentManager) CreateUpdateEnvironment(ctx context.Context, owner, repo, name string, environment *github.CreateUpdateEnvironment) (*github.Environment, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpdateEnvironment", ctx, owner, repo, name, environment)
+	ret0, _ := ret[0].(*github.Environment)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateUpdateEnvironment indicates an expected call of CreateUpdateEnvironment.
+func (mr *MockEnvironmentManagerMockRecorder) CreateUpdateEnvironment(ctx, owner, repo, name, environment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpdateEnvironment", reflect.TypeOf((*MockEnvironmentManager)(nil).CreateUpdateEnvironment), ctx, owner, repo, name, environment)
+}
+
+// DeleteDeploymentBranchPolicy mocks base method.
+func (m *MockEnvironmentManager) DeleteDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, branchPolicyID int64) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeploymentBranchPolicy", ctx, owner, repo, environment, branchPolicyID)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeploymentBranchPolicy indicates an expected call of DeleteDeploymentBranchPolicy.
+func (mr *MockEnvironmentManagerMockRecorder) DeleteDeploymentBranchPolicy(ctx, owner, repo, environment, branchPolicyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeploymentBranchPolicy", reflect.TypeOf((*MockEnvironmentManager)(nil).DeleteDeploymentBranchPolicy), ctx, owner, repo, environment, branchPolicyID)
+}
+
+// ListDeploymentBranchPolicies mocks base method.
+func (m *MockEnvironmentManager) ListDeploymentBranchPolicies(ctx context.Context, owner, repo, environment string) (*github.DeploymentBranchPolicyResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeploymentBranchPolicies", ctx, owner, repo, environment)
+	ret0, _ := ret[0].(*github.DeploymentBranchPolicyResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeploymentBranchPolicies indicates an expected call of ListDeploymentBranchPolicies.
+func (mr *MockEnvironmentManagerMockRecorder) ListDeploymentBranchPolicies(ctx, owner, repo, environment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploymentBranchPolicies", reflect.TypeOf((*MockEnvironmentManager)(nil).ListDeploymentBranchPolicies), ctx, owner, repo, environment)
+}
+
+// ListEnvironments mocks base method.
+func (m *MockEnvironmentManager) ListEnvironments(ctx context.Context, owner, repo string, opts *github.EnvironmentListOptions) (*github.EnvResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnvironments", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.EnvResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListEnvironments indicates an expected call of ListEnvironments.
+func (mr *MockEnvironmentManagerMockRecorder) ListEnvironments(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnvironments", reflect.TypeOf((*MockEnvironmentManager)(nil).ListEnvironments), ctx, owner, repo, opts)
+}
+
+// MockContentManager is a mock of ContentManager interface.
+type MockContentManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockContentManagerMockRecorder
+}
+
+// MockContentManagerMockRecorder is the mock recorder for MockContentManager.
+type MockContentManagerMockRecorder struct {
+	mock *MockContentManager
+}
+
+// NewMockContentManager creates a new mock instance.
+func NewMockContentManager(ctrl *gomock.Controller) *MockContentManager {
+	mock := &MockContentManager{ctrl: ctrl}
+	mock.recorder = &MockContentManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContentManager) EXPECT() *MockContentManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateFile mocks base method.
+func (m *MockContentManager) CreateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFile", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContentResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateFile indicates an expected call of CreateFile.
+func (mr *MockContentManagerMockRecorder) CreateFile(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFile", reflect.TypeOf((*MockContentManager)(nil).CreateFile), ctx, owner, repo, path, opts)
+}
+
+// GetCodeownersErrors mocks base method.
+func (m *MockContentManager) GetCodeownersErrors(ctx context.Context, owner, repo string, opts *github.GetCodeownersErrorsOptions) (*github.CodeownersErrors, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeownersErrors", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.CodeownersErrors)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCodeownersErrors indicates an expected call of GetCodeownersErrors.
+func (mr *MockContentManagerMockRecorder) GetCodeownersErrors(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeownersErrors", reflect.TypeOf((*MockContentManager)(nil).GetCodeownersErrors), ctx, owner, repo, opts)
+}
+
+// GetContents mocks base method.
+func (m *MockContentManager) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContents", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContent)
+	ret1, _ := ret[1].([]*github.RepositoryContent)
+	ret2, _ := ret[2].(*github.Response)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetContents indicates an expected call of GetContents.
+func (mr *MockContentManagerMockRecorder) GetContents(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContents", reflect.TypeOf((*MockContentManager)(nil).GetContents), ctx, owner, repo, path, opts)
+}
+
+// UpdateFile mocks base method.
+func (m *MockContentManager) UpdateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFile", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContentResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateFile indicates an expected call of UpdateFile.
+func (mr *MockContentManagerMockRecorder) UpdateFile(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFile", reflect.TypeOf((*MockContentManager)(nil).UpdateFile), ctx, owner, repo, path, opts)
+}
+
+// MockRefManager is a mock of RefManager interface.
+type MockRefManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefManagerMockRecorder
+}
+
+// MockRefManagerMockRecorder is the mock recorder for MockRefManager.
+type MockRefManagerMockRecorder struct {
+	mock *MockRefManager
+}
+
+// NewMockRefManager creates a new mock instance.
+func NewMockRefManager(ctrl *gomock.Controller) *MockRefManager {
+	mock := &MockRefManager{ctrl: ctrl}
+	mock.recorder = &MockRefManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefManager) EXPECT() *MockRefManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateRef mocks base method.
+func (m *MockRefManager) CreateRef(ctx context.Context, owner, repo string, ref *github.Reference) (*github.Reference, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRef", ctx, owner, repo, ref)
+	ret0, _ := ret[0].(*github.Reference)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRef indicates an expected call of CreateRef.
+func (mr *MockRefManagerMockRecorder) CreateRef(ctx, owner, repo, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRef", reflect.TypeOf((*MockRefManager)(nil).CreateRef), ctx, owner, repo, ref)
+}
+
+// GetRef mocks base method.
+func (m *MockRefManager) GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRef", ctx, owner, repo, ref)
+	ret0, _ := ret[0].(*github.Reference)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRef indicates an expected call of GetRef.
+func (mr *MockRefManagerMockRecorder) GetRef(ctx, owner, repo, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRef", reflect.TypeOf((*MockRefManager)(nil).GetRef), ctx, owner, repo, ref)
+}
+
+// MockPullRequestCreator is a mock of PullRequestCreator interface.
+type MockPullRequestCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockPullRequestCreatorMockRecorder
+}
+
+// MockPullRequestCreatorMockRecorder is the mock recorder for MockPullRequestCreator.
+type MockPullRequestCreatorMockRecorder struct {
+	mock *MockPullRequestCreator
+}
+
+// NewMockPullRequestCreator creates a new mock instance.
+func NewMockPullRequestCreator(ctrl *gomock.Controller) *MockPullRequestCreator {
+	mock := &MockPullRequestCreator{ctrl: ctrl}
+	mock.recorder = &MockPullRequestCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPullRequestCreator) EXPECT() *MockPullRequestCreatorMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPullRequestCreator) Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, owner, repo, pull)
+	ret0, _ := ret[0].(*github.PullRequest)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPullRequestCreatorMockRecorder) Create(ctx, owner, repo, pull any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPullRequestCreator)(nil).Create), ctx, owner, repo, pull)
+}
+
+// MockHookManager is a mock of HookManager interface.
+type MockHookManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockHookManagerMockRecorder
+}
+
+// MockHookManagerMockRecorder is the mock recorder for MockHookManager.
+type MockHookManagerMockRecorder struct {
+	mock *MockHookManager
+}
+
+// NewMockHookManager creates a new mock instance.
+func NewMockHookManager(ctrl *gomock.Controller) *MockHookManager {
+	mock := &MockHookManager{ctrl: ctrl}
+	mock.recorder = &MockHookManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHookManager) EXPECT() *MockHookManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateHook mocks base method.
+func (m *MockHookManager) CreateHook(ctx context.Context, owner, repo string, hook *github.Hook) (*github.Hook, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHook", ctx, owner, repo, hook)
+	ret0, _ := ret[0].(*github.Hook)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateHook indicates an expected call of CreateHook.
+func (mr *MockHookManagerMockRecorder) CreateHook(ctx, owner, repo, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHook", reflect.TypeOf((*MockHookManager)(nil).CreateHook), ctx, owner, repo, hook)
+}
+
+// EditHook mocks base method.
+func (m *MockHookManager) EditHook(ctx context.Context, owner, repo string, id int64, hook *github.Hook) (*github.Hook, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditHook", ctx, owner, repo, id, hook)
+	ret0, _ := ret[0].(*github.Hook)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditHook indicates an expected call of EditHook.
+func (mr *MockHookManagerMockRecorder) EditHook(ctx, owner, repo, id, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditHook", reflect.TypeOf((*MockHookManager)(nil).EditHook), ctx, owner, repo, id, hook)
+}
+
+// ListHooks mocks base method.
+func (m *MockHookManager) ListHooks(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Hook, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHooks", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Hook)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListHooks indicates an expected call of ListHooks.
+func (mr *MockHookManagerMockRecorder) ListHooks(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHooks", reflect.TypeOf((*MockHookManager)(nil).ListHooks), ctx, owner, repo, opts)
+}
+
+// MockKeyLister is a mock of KeyLister interface.
+type MockKeyLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyListerMockRecorder
+}
+
+// MockKeyListerMockRecorder is the mock recorder for MockKeyLister.
+type MockKeyListerMockRecorder struct {
+	mock *MockKeyLister
+}
+
+// NewMockKeyLister creates a new mock instance.
+func NewMockKeyLister(ctrl *gomock.Controller) *MockKeyLister {
+	mock := &MockKeyLister{ctrl: ctrl}
+	mock.recorder = &MockKeyListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyLister) EXPECT() *MockKeyListerMockRecorder {
+	return m.recorder
+}
+
+// ListKeys mocks base method.
+func (m *MockKeyLister) ListKeys(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Key, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeys", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Key)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListKeys indicates an expected call of ListKeys.
+func (mr *MockKeyListerMockRecorder) ListKeys(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockKeyLister)(nil).ListKeys), ctx, owner, repo, opts)
+}
+
+// MockRunnerLister is a mock of RunnerLister interface.
+type MockRunnerLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunnerListerMockRecorder
+}
+
+// MockRunnerListerMockRecorder is the mock recorder for MockRunnerLister.
+type MockRunnerListerMockRecorder struct {
+	mock *MockRunnerLister
+}
+
+// NewMockRunnerLister creates a new mock instance.
+func NewMockRunnerLister(ctrl *gomock.Controller) *MockRunnerLister {
+	mock := &MockRunnerLister{ctrl: ctrl}
+	mock.recorder = &MockRunnerListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunnerLister) EXPECT() *MockRunnerListerMockRecorder {
+	return m.recorder
+}
+
+// ListRunners mocks base method.
+func (m *MockRunnerLister) ListRunners(ctx context.Context, owner, repo string, opts *github.ListOptions) (*github.Runners, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRunners", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.Runners)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRunners indicates an expected call of ListRunners.
+func (mr *MockRunnerListerMockRecorder) ListRunners(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunners", reflect.TypeOf((*MockRunnerLister)(nil).ListRunners), ctx, owner, repo, opts)
+}
+
+// MockAutolinkManager is a mock of AutolinkManager interface.
+type MockAutolinkManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockAutolinkManagerMockRecorder
+}
+
+// MockAutolinkManagerMockRecorder is the mock recorder for MockAutolinkManager.
+type MockAutolinkManagerMockRecorder struct {
+	mock *MockAutolinkManager
+}
+
+// NewMockAutolinkManager creates a new mock instance.
+func NewMockAutolinkManager(ctrl *gomock.Controller) *MockAutolinkManager {
+	mock := &MockAutolinkManager{ctrl: ctrl}
+	mock.recorder = &MockAutolinkManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAutolinkManager) EXPECT() *MockAutolinkManagerMockRecorder {
+	return m.recorder
+}
+
+// AddAutolink mocks base method.
+func (m *MockAutolinkManager) AddAutolink(ctx context.Context, owner, repo string, opts *github.AutolinkOptions) (*github.Autolink, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAutolink", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.Autolink)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddAutolink indicates an expected call of AddAutolink.
+func (mr *MockAutolinkManagerMockRecorder) AddAutolink(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAutolink", reflect.TypeOf((*MockAutolinkManager)(nil).AddAutolink), ctx, owner, repo, opts)
+}
+
+// DeleteAutolink mocks base method.
+func (m *MockAutolinkManager) DeleteAutolink(ctx context.Context, owner, repo string, id int64) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutolink", ctx, owner, repo, id)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAutolink indicates an expected call of DeleteAutolink.
+func (mr *MockAutolinkManagerMockRecorder) DeleteAutolink(ctx, owner, repo, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutolink", reflect.TypeOf((*MockAutolinkManager)(nil).DeleteAutolink), ctx, owner, repo, id)
+}
+
+// ListAutolinks mocks base method.
+func (m *MockAutolinkManager) ListAutolinks(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Autolink, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAutolinks", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Autolink)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAutolinks indicates an expected call of ListAutolinks.
+func (mr *MockAutolinkManagerMockRecorder) ListAutolinks(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAutolinks", reflect.TypeOf((*MockAutolinkManager)(nil).ListAutolinks), ctx, owner, repo, opts)
+}
+
+// MockRepoLister is a mock of RepoLister interface.
+type MockRepoLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoListerMockRecorder
+}
+
+// MockRepoListerMockRecorder is the mock recorder for MockRepoLister.
+type MockRepoListerMockRecorder struct {
+	mock *MockRepoLister
+}
+
+// NewMockRepoLister creates a new mock instance.
+func NewMockRepoLister(ctrl *gomock.Controller) *MockRepoLister {
+	mock := &MockRepoLister{ctrl: ctrl}
+	mock.recorder = &MockRepoListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoLister) EXPECT() *MockRepoListerMockRecorder {
+	return m.recorder
+}
+
+// ListByOrg mocks base method.
+func (m *MockRepoLister) ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOrg", ctx, org, opts)
+	ret0, _ := ret[0].([]*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByOrg indicates an expected call of ListByOrg.
+func (mr *MockRepoListerMockRecorder) ListByOrg(ctx, org, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOrg", reflect.TypeOf((*MockRepoLister)(nil).ListByOrg), ctx, org, opts)
+}
+
+// MockRulesetManager is a mock of RulesetManager interface.
+type MockRulesetManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRulesetManagerMockRecorder
+}
+
+// MockRulesetManagerMockRecorder is the mock recorder for MockRulesetManager.
+type MockRulesetManagerMockRecorder struct {
+	mock *MockRulesetManager
+}
+
+// NewMockRulesetManager creates a new mock instance.
+func NewMockRulesetManager(ctrl *gomock.Controller) *MockRulesetManager {
+	mock := &MockRulesetManager{ctrl: ctrl}
+	mock.recorder = &MockRulesetManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRulesetManager) EXPECT() *MockRulesetManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateRuleset mocks base method.
+func (m *MockRulesetManager) CreateRuleset(ctx context.Context, owner, repo string, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRuleset", ctx, owner, repo, rs)
+	ret0, _ := ret[0].(*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRuleset indicates an expected call of CreateRuleset.
+func (mr *MockRulesetManagerMockRecorder) CreateRuleset(ctx, owner, repo, rs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRuleset", reflect.TypeOf((*MockRulesetManager)(nil).CreateRuleset), ctx, owner, repo, rs)
+}
+
+// DeleteRuleset mocks base method.
+func (m *MockRulesetManager) DeleteRuleset(ctx context.Context, owner, repo string, rulesetID int64) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRuleset", ctx, owner, repo, rulesetID)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRuleset indicates an expected call of DeleteRuleset.
+func (mr *MockRulesetManagerMockRecorder) DeleteRuleset(ctx, owner, repo, rulesetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRuleset", reflect.TypeOf((*MockRulesetManager)(nil).DeleteRuleset), ctx, owner, repo, rulesetID)
+}
+
+// GetAllRulesets mocks base method.
+func (m *MockRulesetManager) GetAllRulesets(ctx context.Context, owner, repo string, includesParents bool) ([]*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllRulesets", ctx, owner, repo, includesParents)
+	ret0, _ := ret[0].([]*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllRulesets indicates an expected call of GetAllRulesets.
+func (mr *MockRulesetManagerMockRecorder) GetAllRulesets(ctx, owner, repo, includesParents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRulesets", reflect.TypeOf((*MockRulesetManager)(nil).GetAllRulesets), ctx, owner, repo, includesParents)
+}
+
+// GetRuleset mocks base method.
+func (m *MockRulesetManager) GetRuleset(ctx context.Context, owner, repo string, rulesetID int64, includesParents bool) (*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleset", ctx, owner, repo, rulesetID, includesParents)
+	ret0, _ := ret[0].(*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRuleset indicates an expected call of GetRuleset.
+func (mr *MockRulesetManagerMockRecorder) GetRuleset(ctx, owner, repo, rulesetID, includesParents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleset", reflect.TypeOf((*MockRulesetManager)(nil).GetRuleset), ctx, owner, repo, rulesetID, includesParents)
+}
+
+// UpdateRuleset mocks base method.
+func (m *MockRulesetManager) UpdateRuleset(ctx context.Context, owner, repo string, rulesetID int64, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRuleset", ctx, owner, repo, rulesetID, rs)
+	ret0, _ := ret[0].(*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateRuleset indicates an expected call of UpdateRuleset.
+func (mr *MockRulesetManagerMockRecorder) UpdateRuleset(ctx, owner, repo, rulesetID, rs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRuleset", reflect.TypeOf((*MockRulesetManager)(nil).UpdateRuleset), ctx, owner, repo, rulesetID, rs)
+}
+
+// MockAccessLister is a mock of AccessLister interface.
+type MockAccessLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessListerMockRecorder
+}
+
+// MockAccessListerMockRecorder is the mock recorder for MockAccessLister.
+type MockAccessListerMockRecorder struct {
+	mock *MockAccessLister
+}
+
+// NewMockAccessLister creates a new mock instance.
+func NewMockAccessLister(ctrl *gomock.Controller) *MockAccessLister {
+	mock := &MockAccessLister{ctrl: ctrl}
+	mock.recorder = &MockAccessListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessLister) EXPECT() *MockAccessListerMockRecorder {
+	return m.recorder
+}
+
+// ListCollaborators mocks base method.
+func (m *MockAccessLister) ListCollaborators(ctx context.Context, owner, repo string, opts *github.ListCollaboratorsOptions) ([]*github.User, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollaborators", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.User)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCollaborators indicates an expected call of ListCollaborators.
+func (mr *MockAccessListerMockRecorder) ListCollaborators(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollaborators", reflect.TypeOf((*MockAccessLister)(nil).ListCollaborators), ctx, owner, repo, opts)
+}
+
+// ListTeams mocks base method.
+func (m *MockAccessLister) ListTeams(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Team, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeams", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Team)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTeams indicates an expected call of ListTeams.
+func (mr *MockAccessListerMockRecorder) ListTeams(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeams", reflect.TypeOf((*MockAccessLister)(nil).ListTeams), ctx, owner, repo, opts)
+}
+
+// MockStatusReader is a mock of StatusReader interface.
+type MockStatusReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusReaderMockRecorder
+}
+
+// MockStatusReaderMockRecorder is the mock recorder for MockStatusReader.
+type MockStatusReaderMockRecorder struct {
+	mock *MockStatusReader
+}
+
+// NewMockStatusReader creates a new mock instance.
+func NewMockStatusReader(ctrl *gomock.Controller) *MockStatusReader {
+	mock := &MockStatusReader{ctrl: ctrl}
+	mock.recorder = &MockStatusReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusReader) EXPECT() *MockStatusReaderMockRecorder {
+	return m.recorder
+}
+
+// GetCombinedStatus mocks base method.
+func (m *MockStatusReader) GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) (*github.CombinedStatus, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCombinedStatus", ctx, owner, repo, ref, opts)
+	ret0, _ := ret[0].(*github.CombinedStatus)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCombinedStatus indicates an expected call of GetCombinedStatus.
+func (mr *MockStatusReaderMockRecorder) GetCombinedStatus(ctx, owner, repo, ref, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCombinedStatus", reflect.TypeOf((*MockStatusReader)(nil).GetCombinedStatus), ctx, owner, repo, ref, opts)
+}
+
+// MockStatusWriter is a mock of StatusWriter interface.
+type MockStatusWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusWriterMockRecorder
+}
+
+// MockStatusWriterMockRecorder is the mock recorder for MockStatusWriter.
+type MockStatusWriterMockRecorder struct {
+	mock *MockStatusWriter
+}
+
+// NewMockStatusWriter creates a new mock instance.
+func NewMockStatusWriter(ctrl *gomock.Controller) *MockStatusWriter {
+	mock := &MockStatusWriter{ctrl: ctrl}
+	mock.recorder = &MockStatusWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusWriter) EXPECT() *MockStatusWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateStatus mocks base method.
+func (m *MockStatusWriter) CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatus", ctx, owner, repo, ref, status)
+	ret0, _ := ret[0].(*github.RepoStatus)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateStatus indicates an expected call of CreateStatus.
+func (mr *MockStatusWriterMockRecorder) CreateStatus(ctx, owner, repo, ref, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatus", reflect.TypeOf((*MockStatusWriter)(nil).CreateStatus), ctx, owner, repo, ref, status)
+}
+
+// MockCheckRunLister is a mock of CheckRunLister interface.
+type MockCheckRunLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckRunListerMockRecorder
+}
+
+// MockCheckRunListerMockRecorder is the mock recorder for MockCheckRunLister.
+type MockCheckRunListerMockRecorder struct {
+	mock *MockCheckRunLister
+}
+
+// NewMockCheckRunLister creates a new mock instance.
+func NewMockCheckRunLister(ctrl *gomock.Controller) *MockCheckRunLister {
+	mock := &MockCheckRunLister{ctrl: ctrl}
+	mock.recorder = &MockCheckRunListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckRunLister) EXPECT() *MockCheckRunListerMockRecorder {
+	return m.recorder
+}
+
+// ListCheckRunsForRef mocks base method.
+func (m *MockCheckRunLister) ListCheckRunsForRef(ctx context.Context, owner, repo, ref string, opts *github.ListCheckRunsOptions) (*github.ListCheckRunsResults, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCheckRunsForRef", ctx, owner, repo, ref, opts)
+	ret0, _ := ret[0].(*github.ListCheckRunsResults)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCheckRunsForRef indicates an expected call of ListCheckRunsForRef.
+func (mr *MockCheckRunListerMockRecorder) ListCheckRunsForRef(ctx, owner, repo, ref, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCheckRunsForRef", reflect.TypeOf((*MockCheckRunLister)(nil).ListCheckRunsForRef), ctx, owner, repo, ref, opts)
+}
+
+// MockInstallationRepoLister is a mock of InstallationRepoLister interface.
+type MockInstallationRepoLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstallationRepoListerMockRecorder
+}
+
+// MockInstallationRepoListerMockRecorder is the mock recorder for MockInstallationRepoLister.
+type MockInstallationRepoListerMockRecorder struct {
+	mock *MockInstallationRepoLister
+}
+
+// NewMockInstallationRepoLister creates a new mock instance.
+func NewMockInstallationRepoLister(ctrl *gomock.Controller) *MockInstallationRepoLister {
+	mock := &MockInstallationRepoLister{ctrl: ctrl}
+	mock.recorder = &MockInstallationRepoListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstallationRepoLister) EXPECT() *MockInstallationRepoListerMockRecorder {
+	return m.recorder
+}
+
+// ListRepos mocks base method.
+func (m *MockInstallationRepoLister) ListRepos(ctx context.Context, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRepos", ctx, opts)
+	ret0, _ := ret[0].(*github.ListRepositories)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRepos indicates an expected call of ListRepos.
+func (mr *MockInstallationRepoListerMockRecorder) ListRepos(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepos", reflect.TypeOf((*MockInstallationRepoLister)(nil).ListRepos), ctx, opts)
+}
+
+// MockPropertyLister is a mock of PropertyLister interface.
+type MockPropertyLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockPropertyListerMockRecorder
+}
+
+// MockPropertyListerMockRecorder is the mock recorder for MockPropertyLister.
+type MockPropertyListerMockRecorder struct {
+	mock *MockPropertyLister
+}
+
+// NewMockPropertyLister creates a new mock instance.
+func NewMockPropertyLister(ctrl *gomock.Controller) *MockPropertyLister {
+	mock := &MockPropertyLister{ctrl: ctrl}
+	mock.recorder = &MockPropertyListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPropertyLister) EXPECT() *MockPropertyListerMockRecorder {
+	return m.recorder
+}
+
+// ListCustomPropertyValues mocks base method.
+func (m *MockPropertyLister) ListCustomPropertyValues(ctx context.Context, org string, opts *github.ListOptions) ([]*github.RepoCustomPropertyValue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomPropertyValues", ctx, org, opts)
+	ret0, _ := ret[0].([]*github.RepoCustomPropertyValue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCustomPropertyValues indicates an expected call of ListCustomPropertyValues.
+func (mr *MockPropertyListerMockRecorder) ListCustomPropertyValues(ctx, org, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomPropertyValues", reflect.TypeOf((*MockPropertyLister)(nil).ListCustomPropertyValues), ctx, org, opts)
+}
+
+// MockTeamRepoLister is a mock of TeamRepoLister interface.
+type MockTeamRepoLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTeamRepoListerMockRecorder
+}
+
+// MockTeamRepoListerMockRecorder is the mock recorder for MockTeamRepoLister.
+type MockTeamRepoListerMockRecorder struct {
+	mock *MockTeamRepoLister
+}
+
+// NewMockTeamRepoLister creates a new mock instance.
+func NewMockTeamRepoLister(ctrl *gomock.Controller) *MockTeamRepoLister {
+	mock := &MockTeamRepoLister{ctrl: ctrl}
+	mock.recorder = &MockTeamRepoListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTeamRepoLister) EXPECT() *MockTeamRepoListerMockRecorder {
+	return m.recorder
+}
+
+// ListTeamReposBySlug mocks base method.
+func (m *MockTeamRepoLister) ListTeamReposBySlug(ctx context.Context, org, slug string, opts *github.ListOptions) ([]*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamReposBySlug", ctx, org, slug, opts)
+	ret0, _ := ret[0].([]*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTeamReposBySlug indicates an expected call of ListTeamReposBySlug.
+func (mr *MockTeamRepoListerMockRecorder) ListTeamReposBySlug(ctx, org, slug, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamReposBySlug", reflect.TypeOf((*MockTeamRepoLister)(nil).ListTeamReposBySlug), ctx, org, slug, opts)
+}
+
+// MockIssueManager is a mock of IssueManager interface.
+type MockIssueManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockIssueManagerMockRecorder
+}
+
+// MockIssueManagerMockRecorder is the mock recorder for MockIssueManager.
+type MockIssueManagerMockRecorder struct {
+	mock *MockIssueManager
+}
+
+// NewMockIssueManager creates a new mock instance.
+func NewMockIssueManager(ctrl *gomock.Controller) *MockIssueManager {
+	mock := &MockIssueManager{ctrl: ctrl}
+	mock.recorder = &MockIssueManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIssueManager) EXPECT() *MockIssueManagerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIssueManager) Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, owner, repo, issue)
+	ret0, _ := ret[0].(*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIssueManagerMockRecorder) Create(ctx, owner, repo, issue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIssueManager)(nil).Create), ctx, owner, repo, issue)
+}
+
+// Edit mocks base method.
+func (m *MockIssueManager) Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, owner, repo, number, issue)
+	ret0, _ := ret[0].(*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockIssueManagerMockRecorder) Edit(ctx, owner, repo, number, issue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockIssueManager)(nil).Edit), ctx, owner, repo, number, issue)
+}
+
+// ListByRepo mocks base method.
+func (m *MockIssueManager) ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRepo", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Issue)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByRepo indicates an expected call of ListByRepo.
+func (mr *MockIssueManagerMockRecorder) ListByRepo(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRepo", reflect.TypeOf((*MockIssueManager)(nil).ListByRepo), ctx, owner, repo, opts)
+}
+
+// MockLabelManager is a mock of LabelManager interface.
+type MockLabelManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelManagerMockRecorder
+}
+
+// MockLabelManagerMockRecorder is the mock recorder for MockLabelManager.
+type MockLabelManagerMockRecorder struct {
+	mock *MockLabelManager
+}
+
+// NewMockLabelManager creates a new mock instance.
+func NewMockLabelManager(ctrl *gomock.Controller) *MockLabelManager {
+	mock := &MockLabelManager{ctrl: ctrl}
+	mock.recorder = &MockLabelManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelManager) EXPECT() *MockLabelManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateLabel mocks base method.
+func (m *MockLabelManager) CreateLabel(ctx context.Context, owner, repo string, label *github.Label) (*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", ctx, owner, repo, label)
+	ret0, _ := ret[0].(*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockLabelManagerMockRecorder) CreateLabel(ctx, owner, repo, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockLabelManager)(nil).CreateLabel), ctx, owner, repo, label)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockLabelManager) DeleteLabel(ctx context.Context, owner, repo, name string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", ctx, owner, repo, name)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockLabelManagerMockRecorder) DeleteLabel(ctx, owner, repo, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockLabelManager)(nil).DeleteLabel), ctx, owner, repo, name)
+}
+
+// EditLabel mocks base method.
+func (m *MockLabelManager) EditLabel(ctx context.Context, owner, repo, name string, label *github.Label) (*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditLabel", ctx, owner, repo, name, label)
+	ret0, _ := ret[0].(*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditLabel indicates an expected call of EditLabel.
+func (mr *MockLabelManagerMockRecorder) EditLabel(ctx, owner, repo, name, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditLabel", reflect.TypeOf((*MockLabelManager)(nil).EditLabel), ctx, owner, repo, name, label)
+}
+
+// ListLabels mocks base method.
+func (m *MockLabelManager) ListLabels(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLabels", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Label)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListLabels indicates an expected call of ListLabels.
+func (mr *MockLabelManagerMockRecorder) ListLabels(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLabels", reflect.TypeOf((*MockLabelManager)(nil).ListLabels), ctx, owner, repo, opts)
+}
+
+// MockRateLimitReader is a mock of RateLimitReader interface.
+type MockRateLimitReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitReaderMockRecorder
+}
+
+// MockRateLimitReaderMockRecorder is the mock recorder for MockRateLimitReader.
+type MockRateLimitReaderMockRecorder struct {
+	mock *MockRateLimitReader
+}
+
+// NewMockRateLimitReader creates a new mock instance.
+func NewMockRateLimitReader(ctrl *gomock.Controller) *MockRateLimitReader {
+	mock := &MockRateLimitReader{ctrl: ctrl}
+	mock.recorder = &MockRateLimitReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitReader) EXPECT() *MockRateLimitReaderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockRateLimitReader) Get(ctx context.Context) (*github.RateLimits, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(*github.RateLimits)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRateLimitReaderMockRecorder) Get(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRateLimitReader)(nil).Get), ctx)
+}
+
+// MockMetaReader is a mock of MetaReader interface.
+type MockMetaReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetaReaderMockRecorder
+}
+
+// MockMetaReaderMockRecorder is the mock recorder for MockMetaReader.
+type MockMetaReaderMockRecorder struct {
+	mock *MockMetaReader
+}
+
+// NewMockMetaReader creates a new mock instance.
+func NewMockMetaReader(ctrl *gomock.Controller) *MockMetaReader {
+	mock := &MockMetaReader{ctrl: ctrl}
+	mock.recorder = &MockMetaReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetaReader) EXPECT() *MockMetaReaderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockMetaReader) Get(ctx context.Context) (*github.APIMeta, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(*github.APIMeta)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMetaReaderMockRecorder) Get(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMetaReader)(nil).Get), ctx)
+}
+
+// MockActorLookup is a mock of ActorLookup interface.
+type MockActorLookup struct {
+	ctrl     *gomock.Controller
+	recorder *MockActorLookupMockRecorder
+}
+
+// MockActorLookupMockRecorder is the mock recorder for MockActorLookup.
+type MockActorLookupMockRecorder struct {
+	mock *MockActorLookup
+}
+
+// NewMockActorLookup creates a new mock instance.
+func NewMockActorLookup(ctrl *gomock.Controller) *MockActorLookup {
+	mock := &MockActorLookup{ctrl: ctrl}
+	mock.recorder = &MockActorLookupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActorLookup) EXPECT() *MockActorLookupMockRecorder {
+	return m.recorder
+}
+
+// GetApp mocks base method.
+func (m *MockActorLookup) GetApp(ctx context.Context, slug string) (*github.App, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApp", ctx, slug)
+	ret0, _ := ret[0].(*github.App)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetApp indicates an expected call of GetApp.
+func (mr *MockActorLookupMockRecorder) GetApp(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockActorLookup)(nil).GetApp), ctx, slug)
+}
+
+// GetTeamBySlug mocks base method.
+func (m *MockActorLookup) GetTeamBySlug(ctx context.Context, org, slug string) (*github.Team, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamBySlug", ctx, org, slug)
+	ret0, _ := ret[0].(*github.Team)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamBySlug indicates an expected call of GetTeamBySlug.
+func (mr *MockActorLookupMockRecorder) GetTeamBySlug(ctx, org, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamBySlug", reflect.TypeOf((*MockActorLookup)(nil).GetTeamBySlug), ctx, org, slug)
+}
+
+// GetUser mocks base method.
+func (m *MockActorLookup) GetUser(ctx context.Context, login string) (*github.User, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, login)
+	ret0, _ := ret[0].(*github.User)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockActorLookupMockRecorder) GetUser(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockActorLookup)(nil).GetUser), ctx, login)
+}
+
+// MockAppInstallationLister is a mock of AppInstallationLister interface.
+type MockAppInstallationLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppInstallationListerMockRecorder
+}
+
+// MockAppInstallationListerMockRecorder is the mock recorder for MockAppInstallationLister.
+type MockAppInstallationListerMockRecorder struct {
+	mock *MockAppInstallationLister
+}
+
+// NewMockAppInstallationLister creates a new mock instance.
+func NewMockAppInstallationLister(ctrl *gomock.Controller) *MockAppInstallationLister {
+	mock := &MockAppInstallationLister{ctrl: ctrl}
+	mock.recorder = &MockAppInstallationListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppInstallationLister) EXPECT() *MockAppInstallationListerMockRecorder {
+	return m.recorder
+}
+
+// ListInstallations mocks base method.
+func (m *MockAppInstallationLister) ListInstallations(ctx context.Context, org string, opts *github.ListOptions) (*github.OrganizationInstallations, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstallations", ctx, org, opts)
+	ret0, _ := ret[0].(*github.OrganizationInstallations)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListInstallations indicates an expected call of ListInstallations.
+func (mr *MockAppInstallationListerMockRecorder) ListInstallations(ctx, org, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstallations", reflect.TypeOf((*MockAppInstallationLister)(nil).ListInstallations), ctx, org, opts)
+}
+
+// ListUserRepos mocks base method.
+func (m *MockAppInstallationLister) ListUserRepos(ctx context.Context, id int64, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRepos", ctx, id, opts)
+	ret0, _ := ret[0].(*github.ListRepositories)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUserRepos indicates an expected call of ListUserRepos.
+func (mr *MockAppInstallationListerMockRecorder) ListUserRepos(ctx, id, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRepos", reflect.TypeOf((*MockAppInstallationLister)(nil).ListUserRepos), ctx, id, opts)
+}
+
+// MockGraphQLQuerier is a mock of GraphQLQuerier interface.
+type MockGraphQLQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLQuerierMockRecorder
+}
+
+// MockGraphQLQuerierMockRecorder is the mock recorder for MockGraphQLQuerier.
+type MockGraphQLQuerierMockRecorder struct {
+	mock *MockGraphQLQuerier
+}
+
+// NewMockGraphQLQuerier creates a new mock instance.
+func NewMockGraphQLQuerier(ctrl *gomock.Controller) *MockGraphQLQuerier {
+	mock := &MockGraphQLQuerier{ctrl: ctrl}
+	mock.recorder = &MockGraphQLQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQLQuerier) EXPECT() *MockGraphQLQuerierMockRecorder {
+	return m.recorder
+}
+
+// Query mocks base method.
+func (m *MockGraphQLQuerier) Query(ctx context.Context, query string, data any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", ctx, query, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockGraphQLQuerierMockRecorder) Query(ctx, query, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockGraphQLQuerier)(nil).Query), ctx, query, data)
+}
+
+// MockRateCache is a mock of RateCache interface.
+type MockRateCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateCacheMockRecorder
+}
+
+// MockRateCacheMockRecorder is the mock recorder for MockRateCache.
+type MockRateCacheMockRecorder struct {
+	mock *MockRateCache
+}
+
+// NewMockRateCache creates a new mock instance.
+func NewMockRateCache(ctrl *gomock.Controller) *MockRateCache {
+	mock := &MockRateCache{ctrl: ctrl}
+	mock.recorder = &MockRateCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateCache) EXPECT() *MockRateCacheMockRecorder {
+	return m.recorder
+}
+
+// Core mocks base method.
+func (m *MockRateCache) Core() (github.Rate, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Core")
+	ret0, _ := ret[0].(github.Rate)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Core indicates an expected call of Core.
+func (mr *MockRateCacheMockRecorder) Core() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Core", reflect.TypeOf((*MockRateCache)(nil).Core))
+}
+
+// MockRepositories is a mock of Repositories interface.
+type MockRepositories struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoriesMockRecorder
+}
+
+// MockRepositoriesMockRecorder is the mock recorder for MockRepositories.
+type MockRepositoriesMockRecorder struct {
+	mock *MockRepositories
+}
+
+// NewMockRepositories creates a new mock instance.
+func NewMockRepositories(ctrl *gomock.Controller) *MockRepositories {
+	mock := &MockRepositories{ctrl: ctrl}
+	mock.recorder = &MockRepositoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositories) EXPECT() *MockRepositoriesMockRecorder {
+	return m.recorder
+}
+
+// AddAutolink mocks base method.
+func (m *MockRepositories) AddAutolink(ctx context.Context, owner, repo string, opts *github.AutolinkOptions) (*github.Autolink, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAutolink", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.Autolink)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddAutolink indicates an expected call of AddAutolink.
+func (mr *MockRepositoriesMockRecorder) AddAutolink(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAutolink", reflect.TypeOf((*MockRepositories)(nil).AddAutolink), ctx, owner, repo, opts)
+}
+
+// CreateDeploymentBranchPolicy mocks base method.
+func (m *MockRepositories) CreateDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, request *github.DeploymentBranchPolicyRequest) (*github.DeploymentBranchPolicy, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeploymentBranchPolicy", ctx, owner, repo, environment, request)
+	ret0, _ := ret[0].(*github.DeploymentBranchPolicy)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateDeploymentBranchPolicy indicates an expected call of CreateDeploymentBranchPolicy.
+func (mr *MockRepositoriesMockRecorder) CreateDeploymentBranchPolicy(ctx, owner, repo, environment, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeploymentBranchPolicy", reflect.TypeOf((*MockRepositories)(nil).CreateDeploymentBranchPolicy), ctx, owner, repo, environment, request)
+}
+
+// CreateFile mocks base method.
+func (m *MockRepositories) CreateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFile", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContentResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateFile indicates an expected call of CreateFile.
+func (mr *MockRepositoriesMockRecorder) CreateFile(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFile", reflect.TypeOf((*MockRepositories)(nil).CreateFile), ctx, owner, repo, path, opts)
+}
+
+// CreateHook mocks base method.
+func (m *MockRepositories) CreateHook(ctx context.Context, owner, repo string, hook *github.Hook) (*github.Hook, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHook", ctx, owner, repo, hook)
+	ret0, _ := ret[0].(*github.Hook)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateHook indicates an expected call of CreateHook.
+func (mr *MockRepositoriesMockRecorder) CreateHook(ctx, owner, repo, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHook", reflect.TypeOf((*MockRepositories)(nil).CreateHook), ctx, owner, repo, hook)
+}
+
+// CreateRuleset mocks base method.
+func (m *MockRepositories) CreateRuleset(ctx context.Context, owner, repo string, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRuleset", ctx, owner, repo, rs)
+	ret0, _ := ret[0].(*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRuleset indicates an expected call of CreateRuleset.
+func (mr *MockRepositoriesMockRecorder) CreateRuleset(ctx, owner, repo, rs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRuleset", reflect.TypeOf((*MockRepositories)(nil).CreateRuleset), ctx, owner, repo, rs)
+}
+
+// CreateStatus mocks base method.
+func (m *MockRepositories) CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatus", ctx, owner, repo, ref, status)
+	ret0, _ := ret[0].(*github.RepoStatus)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateStatus indicates an expected call of CreateStatus.
+func (mr *MockRepositoriesMockRecorder) CreateStatus(ctx, owner, repo, ref, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatus", reflect.TypeOf((*MockRepositories)(nil).CreateStatus), ctx, owner, repo, ref, status)
+}
+
+// CreateUpdateEnvironment mocks base method.
+func (m *MockRepositories) CreateUpdateEnvironment(ctx context.Context, owner, repo, name string, environment *github.CreateUpdateEnvironment) (*github.Environment, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpdateEnvironment", ctx, owner, repo, name, environment)
+	ret0, _ := ret[0].(*github.Environment)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateUpdateEnvironment indicates an expected call of CreateUpdateEnvironment.
+func (mr *MockRepositoriesMockRecorder) CreateUpdateEnvironment(ctx, owner, repo, name, environment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpdateEnvironment", reflect.TypeOf((*MockRepositories)(nil).CreateUpdateEnvironment), ctx, owner, repo, name, environment)
+}
+
+// DeleteAutolink mocks base method.
+func (m *MockRepositories) DeleteAutolink(ctx context.Context, owner, repo string, id int64) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutolink", ctx, owner, repo, id)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAutolink indicates an expected call of DeleteAutolink.
+func (mr *MockRepositoriesMockRecorder) DeleteAutolink(ctx, owner, repo, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutolink", reflect.TypeOf((*MockRepositories)(nil).DeleteAutolink), ctx, owner, repo, id)
+}
+
+// DeleteDeploymentBranchPolicy mocks base method.
+func (m *MockRepositories) DeleteDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, branchPolicyID int64) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeploymentBranchPolicy", ctx, owner, repo, environment, branchPolicyID)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeploymentBranchPolicy indicates an expected call of DeleteDeploymentBranchPolicy.
+func (mr *MockRepositoriesMockRecorder) DeleteDeploymentBranchPolicy(ctx, owner, repo, environment, branchPolicyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeploymentBranchPolicy", reflect.TypeOf((*MockRepositories)(nil).DeleteDeploymentBranchPolicy), ctx, owner, repo, environment, branchPolicyID)
+}
+
+// DeleteRuleset mocks base method.
+func (m *MockRepositories) DeleteRuleset(ctx context.Context, owner, repo string, rulesetID int64) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRuleset", ctx, owner, repo, rulesetID)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRuleset indicates an expected call of DeleteRuleset.
+func (mr *MockRepositoriesMockRecorder) DeleteRuleset(ctx, owner, repo, rulesetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRuleset", reflect.TypeOf((*MockRepositories)(nil).DeleteRuleset), ctx, owner, repo, rulesetID)
+}
+
+// DisableAutomatedSecurityFixes mocks base method.
+func (m *MockRepositories) DisableAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableAutomatedSecurityFixes", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableAutomatedSecurityFixes indicates an expected call of DisableAutomatedSecurityFixes.
+func (mr *MockRepositoriesMockRecorder) DisableAutomatedSecurityFixes(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableAutomatedSecurityFixes", reflect.TypeOf((*MockRepositories)(nil).DisableAutomatedSecurityFixes), ctx, owner, repository)
+}
+
+// DisableVulnerabilityAlerts mocks base method.
+func (m *MockRepositories) DisableVulnerabilityAlerts(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableVulnerabilityAlerts", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableVulnerabilityAlerts indicates an expected call of DisableVulnerabilityAlerts.
+func (mr *MockRepositoriesMockRecorder) DisableVulnerabilityAlerts(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableVulnerabilityAlerts", reflect.TypeOf((*MockRepositories)(nil).DisableVulnerabilityAlerts), ctx, owner, repository)
+}
+
+// Edit mocks base method.
+func (m *MockRepositories) Edit(ctx context.Context, owner, repo string, repository *github.Repository) (*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, owner, repo, repository)
+	ret0, _ := ret[0].(*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockRepositoriesMockRecorder) Edit(ctx, owner, repo, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockRepositories)(nil).Edit), ctx, owner, repo, repository)
+}
+
+// EditActionsAllowed mocks base method.
+func (m *MockRepositories) EditActionsAllowed(ctx context.Context, org, repo string, actionsAllowed github.ActionsAllowed) (*github.ActionsAllowed, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditActionsAllowed", ctx, org, repo, actionsAllowed)
+	ret0, _ := ret[0].(*github.ActionsAllowed)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditActionsAllowed indicates an expected call of EditActionsAllowed.
+func (mr *MockRepositoriesMockRecorder) EditActionsAllowed(ctx, org, repo, actionsAllowed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditActionsAllowed", reflect.TypeOf((*MockRepositories)(nil).EditActionsAllowed), ctx, org, repo, actionsAllowed)
+}
+
+// EditActionsPermissions mocks base method.
+func (m *MockRepositories) EditActionsPermissions(ctx context.Context, owner, repo string, actionsPermissionsRepository github.ActionsPermissionsRepository) (*github.ActionsPermissionsRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditActionsPermissions", ctx, owner, repo, actionsPermissionsRepository)
+	ret0, _ := ret[0].(*github.ActionsPermissionsRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditActionsPermissions indicates an expected call of EditActionsPermissions.
+func (mr *MockRepositoriesMockRecorder) EditActionsPermissions(ctx, owner, repo, actionsPermissionsRepository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditActionsPermissions", reflect.TypeOf((*MockRepositories)(nil).EditActionsPermissions), ctx, owner, repo, actionsPermissionsRepository)
+}
+
+// EditDefaultWorkflowPermissions mocks base method.
+func (m *MockRepositories) EditDefaultWorkflowPermissions(ctx context.Context, owner, repo string, permissions github.DefaultWorkflowPermissionRepository) (*github.DefaultWorkflowPermissionRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditDefaultWorkflowPermissions", ctx, owner, repo, permissions)
+	ret0, _ := ret[0].(*github.DefaultWorkflowPermissionRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditDefaultWorkflowPermissions indicates an expected call of EditDefaultWorkflowPermissions.
+func (mr *MockRepositoriesMockRecorder) EditDefaultWorkflowPermissions(ctx, owner, repo, permissions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditDefaultWorkflowPermissions", reflect.TypeOf((*MockRepositories)(nil).EditDefaultWorkflowPermissions), ctx, owner, repo, permissions)
+}
+
+// EditHook mocks base method.
+func (m *MockRepositories) EditHook(ctx context.Context, owner, repo string, id int64, hook *github.Hook) (*github.Hook, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditHook", ctx, owner, repo, id, hook)
+	ret0, _ := ret[0].(*github.Hook)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditHook indicates an expected call of EditHook.
+func (mr *MockRepositoriesMockRecorder) EditHook(ctx, owner, repo, id, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditHook", reflect.TypeOf((*MockRepositories)(nil).EditHook), ctx, owner, repo, id, hook)
+}
+
+// EnableAutomatedSecurityFixes mocks base method.
+func (m *MockRepositories) EnableAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableAutomatedSecurityFixes", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableAutomatedSecurityFixes indicates an expected call of EnableAutomatedSecurityFixes.
+func (mr *MockRepositoriesMockRecorder) EnableAutomatedSecurityFixes(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAutomatedSecurityFixes", reflect.TypeOf((*MockRepositories)(nil).EnableAutomatedSecurityFixes), ctx, owner, repository)
+}
+
+// EnableVulnerabilityAlerts mocks base method.
+func (m *MockRepositories) EnableVulnerabilityAlerts(ctx context.Context, owner, repository string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableVulnerabilityAlerts", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableVulnerabilityAlerts indicates an expected call of EnableVulnerabilityAlerts.
+func (mr *MockRepositoriesMockRecorder) EnableVulnerabilityAlerts(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableVulnerabilityAlerts", reflect.TypeOf((*MockRepositories)(nil).EnableVulnerabilityAlerts), ctx, owner, repository)
+}
+
+// Get mocks base method.
+func (m *MockRepositories) Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepositoriesMockRecorder) Get(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepositories)(nil).Get), ctx, owner, repo)
+}
+
+// GetActionsAllowed mocks base method.
+func (m *MockRepositories) GetActionsAllowed(ctx context.Context, org, repo string) (*github.ActionsAllowed, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionsAllowed", ctx, org, repo)
+	ret0, _ := ret[0].(*github.ActionsAllowed)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActionsAllowed indicates an expected call of GetActionsAllowed.
+func (mr *MockRepositoriesMockRecorder) GetActionsAllowed(ctx, org, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionsAllowed", reflect.TypeOf((*MockRepositories)(nil).GetActionsAllowed), ctx, org, repo)
+}
+
+// GetActionsPermissions mocks base method.
+func (m *MockRepositories) GetActionsPermissions(ctx context.Context, owner, repo string) (*github.ActionsPermissionsRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionsPermissions", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.ActionsPermissionsRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActionsPermissions indicates an expected call of GetActionsPermissions.
+func (mr *MockRepositoriesMockRecorder) GetActionsPermissions(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionsPermissions", reflect.TypeOf((*MockRepositories)(nil).GetActionsPermissions), ctx, owner, repo)
+}
+
+// GetAllRulesets mocks base method.
+func (m *MockRepositories) GetAllRulesets(ctx context.Context, owner, repo string, includesParents bool) ([]*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllRulesets", ctx, owner, repo, includesParents)
+	ret0, _ := ret[0].([]*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllRulesets indicates an expected call of GetAllRulesets.
+func (mr *MockRepositoriesMockRecorder) GetAllRulesets(ctx, owner, repo, includesParents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRulesets", reflect.TypeOf((*MockRepositories)(nil).GetAllRulesets), ctx, owner, repo, includesParents)
+}
+
+// GetAutomatedSecurityFixes mocks base method.
+func (m *MockRepositories) GetAutomatedSecurityFixes(ctx context.Context, owner, repository string) (*github.AutomatedSecurityFixes, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomatedSecurityFixes", ctx, owner, repository)
+	ret0, _ := ret[0].(*github.AutomatedSecurityFixes)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAutomatedSecurityFixes indicates an expected call of GetAutomatedSecurityFixes.
+func (mr *MockRepositoriesMockRecorder) GetAutomatedSecurityFixes(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomatedSecurityFixes", reflect.TypeOf((*MockRepositories)(nil).GetAutomatedSecurityFixes), ctx, owner, repository)
+}
+
+// GetBranchProtection mocks base method.
+func (m *MockRepositories) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtection", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Protection)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBranchProtection indicates an expected call of GetBranchProtection.
+func (mr *MockRepositoriesMockRecorder) GetBranchProtection(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtection", reflect.TypeOf((*MockRepositories)(nil).GetBranchProtection), ctx, owner, repo, branch)
+}
+
+// GetCodeownersErrors mocks base method.
+func (m *MockRepositories) GetCodeownersErrors(ctx context.Context, owner, repo string, opts *github.GetCodeownersErrorsOptions) (*github.CodeownersErrors, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeownersErrors", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.CodeownersErrors)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCodeownersErrors indicates an expected call of GetCodeownersErrors.
+func (mr *MockRepositoriesMockRecorder) GetCodeownersErrors(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeownersErrors", reflect.TypeOf((*MockRepositories)(nil).GetCodeownersErrors), ctx, owner, repo, opts)
+}
+
+// GetCombinedStatus mocks base method.
+func (m *MockRepositories) GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) (*github.CombinedStatus, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCombinedStatus", ctx, owner, repo, ref, opts)
+	ret0, _ := ret[0].(*github.CombinedStatus)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCombinedStatus indicates an expected call of GetCombinedStatus.
+func (mr *MockRepositoriesMockRecorder) GetCombinedStatus(ctx, owner, repo, ref, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCombinedStatus", reflect.TypeOf((*MockRepositories)(nil).GetCombinedStatus), ctx, owner, repo, ref, opts)
+}
+
+// GetContents mocks base method.
+func (m *MockRepositories) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContents", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContent)
+	ret1, _ := ret[1].([]*github.RepositoryContent)
+	ret2, _ := ret[2].(*github.Response)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetContents indicates an expected call of GetContents.
+func (mr *MockRepositoriesMockRecorder) GetContents(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContents", reflect.TypeOf((*MockRepositories)(nil).GetContents), ctx, owner, repo, path, opts)
+}
+
+// GetDefaultWorkflowPermissions mocks base method.
+func (m *MockRepositories) GetDefaultWorkflowPermissions(ctx context.Context, owner, repo string) (*github.DefaultWorkflowPermissionRepository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultWorkflowPermissions", ctx, owner, repo)
+	ret0, _ := ret[0].(*github.DefaultWorkflowPermissionRepository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDefaultWorkflowPermissions indicates an expected call of GetDefaultWorkflowPermissions.
+func (mr *MockRepositoriesMockRecorder) GetDefaultWorkflowPermissions(ctx, owner, repo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultWorkflowPermissions", reflect.TypeOf((*MockRepositories)(nil).GetDefaultWorkflowPermissions), ctx, owner, repo)
+}
+
+// GetRuleset mocks base method.
+func (m *MockRepositories) GetRuleset(ctx context.Context, owner, repo string, rulesetID int64, includesParents bool) (*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleset", ctx, owner, repo, rulesetID, includesParents)
+	ret0, _ := ret[0].(*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRuleset indicates an expected call of GetRuleset.
+func (mr *MockRepositoriesMockRecorder) GetRuleset(ctx, owner, repo, rulesetID, includesParents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleset", reflect.TypeOf((*MockRepositories)(nil).GetRuleset), ctx, owner, repo, rulesetID, includesParents)
+}
+
+// GetSignaturesProtectedBranch mocks base method.
+func (m *MockRepositories) GetSignaturesProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSignaturesProtectedBranch", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.SignaturesProtectedBranch)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSignaturesProtectedBranch indicates an expected call of GetSignaturesProtectedBranch.
+func (mr *MockRepositoriesMockRecorder) GetSignaturesProtectedBranch(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignaturesProtectedBranch", reflect.TypeOf((*MockRepositories)(nil).GetSignaturesProtectedBranch), ctx, owner, repo, branch)
+}
+
+// GetVulnerabilityAlerts mocks base method.
+func (m *MockRepositories) GetVulnerabilityAlerts(ctx context.Context, owner, repository string) (bool, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVulnerabilityAlerts", ctx, owner, repository)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVulnerabilityAlerts indicates an expected call of GetVulnerabilityAlerts.
+func (mr *MockRepositoriesMockRecorder) GetVulnerabilityAlerts(ctx, owner, repository any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVulnerabilityAlerts", reflect.TypeOf((*MockRepositories)(nil).GetVulnerabilityAlerts), ctx, owner, repository)
+}
+
+// ListAutolinks mocks base method.
+func (m *MockRepositories) ListAutolinks(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Autolink, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAutolinks", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Autolink)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAutolinks indicates an expected call of ListAutolinks.
+func (mr *MockRepositoriesMockRecorder) ListAutolinks(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAutolinks", reflect.TypeOf((*MockRepositories)(nil).ListAutolinks), ctx, owner, repo, opts)
+}
+
+// ListByOrg mocks base method.
+func (m *MockRepositories) ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOrg", ctx, org, opts)
+	ret0, _ := ret[0].([]*github.Repository)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByOrg indicates an expected call of ListByOrg.
+func (mr *MockRepositoriesMockRecorder) ListByOrg(ctx, org, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOrg", reflect.TypeOf((*MockRepositories)(nil).ListByOrg), ctx, org, opts)
+}
+
+// ListCollaborators mocks base method.
+func (m *MockRepositories) ListCollaborators(ctx context.Context, owner, repo string, opts *github.ListCollaboratorsOptions) ([]*github.User, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollaborators", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.User)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCollaborators indicates an expected call of ListCollaborators.
+func (mr *MockRepositoriesMockRecorder) ListCollaborators(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollaborators", reflect.TypeOf((*MockRepositories)(nil).ListCollaborators), ctx, owner, repo, opts)
+}
+
+// ListDeploymentBranchPolicies mocks base method.
+func (m *MockRepositories) ListDeploymentBranchPolicies(ctx context.Context, owner, repo, environment string) (*github.DeploymentBranchPolicyResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeploymentBranchPolicies", ctx, owner, repo, environment)
+	ret0, _ := ret[0].(*github.DeploymentBranchPolicyResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeploymentBranchPolicies indicates an expected call of ListDeploymentBranchPolicies.
+func (mr *MockRepositoriesMockRecorder) ListDeploymentBranchPolicies(ctx, owner, repo, environment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploymentBranchPolicies", reflect.TypeOf((*MockRepositories)(nil).ListDeploymentBranchPolicies), ctx, owner, repo, environment)
+}
+
+// ListEnvironments mocks base method.
+func (m *MockRepositories) ListEnvironments(ctx context.Context, owner, repo string, opts *github.EnvironmentListOptions) (*github.EnvResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnvironments", ctx, owner, repo, opts)
+	ret0, _ := ret[0].(*github.EnvResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListEnvironments indicates an expected call of ListEnvironments.
+func (mr *MockRepositoriesMockRecorder) ListEnvironments(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnvironments", reflect.TypeOf((*MockRepositories)(nil).ListEnvironments), ctx, owner, repo, opts)
+}
+
+// ListHooks mocks base method.
+func (m *MockRepositories) ListHooks(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Hook, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHooks", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Hook)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListHooks indicates an expected call of ListHooks.
+func (mr *MockRepositoriesMockRecorder) ListHooks(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHooks", reflect.TypeOf((*MockRepositories)(nil).ListHooks), ctx, owner, repo, opts)
+}
+
+// ListKeys mocks base method.
+func (m *MockRepositories) ListKeys(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Key, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeys", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Key)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListKeys indicates an expected call of ListKeys.
+func (mr *MockRepositoriesMockRecorder) ListKeys(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockRepositories)(nil).ListKeys), ctx, owner, repo, opts)
+}
+
+// ListTeams mocks base method.
+func (m *MockRepositories) ListTeams(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Team, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeams", ctx, owner, repo, opts)
+	ret0, _ := ret[0].([]*github.Team)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTeams indicates an expected call of ListTeams.
+func (mr *MockRepositoriesMockRecorder) ListTeams(ctx, owner, repo, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeams", reflect.TypeOf((*MockRepositories)(nil).ListTeams), ctx, owner, repo, opts)
+}
+
+// OptionalSignaturesOnProtectedBranch mocks base method.
+func (m *MockRepositories) OptionalSignaturesOnProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OptionalSignaturesOnProtectedBranch", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OptionalSignaturesOnProtectedBranch indicates an expected call of OptionalSignaturesOnProtectedBranch.
+func (mr *MockRepositoriesMockRecorder) OptionalSignaturesOnProtectedBranch(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OptionalSignaturesOnProtectedBranch", reflect.TypeOf((*MockRepositories)(nil).OptionalSignaturesOnProtectedBranch), ctx, owner, repo, branch)
+}
+
+// RemoveBranchProtection mocks base method.
+func (m *MockRepositories) RemoveBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBranchProtection", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveBranchProtection indicates an expected call of RemoveBranchProtection.
+func (mr *MockRepositoriesMockRecorder) RemoveBranchProtection(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBranchProtection", reflect.TypeOf((*MockRepositories)(nil).RemoveBranchProtection), ctx, owner, repo, branch)
+}
+
+// RequireSignaturesOnProtectedBranch mocks base method.
+func (m *MockRepositories) RequireSignaturesOnProtectedBranch(ctx context.Context, owner, repo, branch string) (*github.SignaturesProtectedBranch, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireSignaturesOnProtectedBranch", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.SignaturesProtectedBranch)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RequireSignaturesOnProtectedBranch indicates an expected call of RequireSignaturesOnProtectedBranch.
+func (mr *MockRepositoriesMockRecorder) RequireSignaturesOnProtectedBranch(ctx, owner, repo, branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireSignaturesOnProtectedBranch", reflect.TypeOf((*MockRepositories)(nil).RequireSignaturesOnProtectedBranch), ctx, owner, repo, branch)
+}
+
+// UpdateBranchProtection mocks base method.
+func (m *MockRepositories) UpdateBranchProtection(ctx context.Context, owner, repo, branch string, preq *github.ProtectionRequest) (*github.Protection, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBranchProtection", ctx, owner, repo, branch, preq)
+	ret0, _ := ret[0].(*github.Protection)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateBranchProtection indicates an expected call of UpdateBranchProtection.
+func (mr *MockRepositoriesMockRecorder) UpdateBranchProtection(ctx, owner, repo, branch, preq any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranchProtection", reflect.TypeOf((*MockRepositories)(nil).UpdateBranchProtection), ctx, owner, repo, branch, preq)
+}
+
+// UpdateFile mocks base method.
+func (m *MockRepositories) UpdateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFile", ctx, owner, repo, path, opts)
+	ret0, _ := ret[0].(*github.RepositoryContentResponse)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateFile indicates an expected call of UpdateFile.
+func (mr *MockRepositoriesMockRecorder) UpdateFile(ctx, owner, repo, path, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFile", reflect.TypeOf((*MockRepositories)(nil).UpdateFile), ctx, owner, repo, path, opts)
+}
+
+// UpdateRuleset mocks base method.
+func (m *MockRepositories) UpdateRuleset(ctx context.Context, owner, repo string, rulesetID int64, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRuleset", ctx, owner, repo, rulesetID, rs)
+	ret0, _ := ret[0].(*github.Ruleset)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateRuleset indicates an expected call of UpdateRuleset.
+func (mr *MockRepositoriesMockRecorder) UpdateRuleset(ctx, owner, repo, rulesetID, rs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRuleset", reflect.TypeOf((*MockRepositories)(nil).UpdateRuleset), ctx, owner, repo, rulesetID, rs)
+}
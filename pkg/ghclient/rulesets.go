@@ -0,0 +1,138 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// repositories is the repositories API, with rulesets read and written
+// through DecodeRuleset so rule types unknown to go-github survive.
+type repositories struct {
+	*github.RepositoriesService
+	client *github.Client
+}
+
+// GetAllRulesets lists the rulesets of a repository, following every page
+// where go-github only returns the first. The response is the last page's.
+func (r *repositories) GetAllRulesets(ctx context.Context, owner, repo string, includesParents bool) ([]*github.Ruleset, *github.Response, error) {
+	var all []*github.Ruleset
+	page := 1
+	for {
+		u := fmt.Sprintf("repos/%v/%v/rulesets?includes_parents=%v&per_page=100&page=%d", owner, repo, includesParents, page)
+		req, err := r.client.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		var rulesets []*github.Ruleset
+		resp, err := r.client.Do(ctx, req, &rulesets)
+		if err != nil {
+			return nil, resp, err
+		}
+		all = append(all, rulesets...)
+		if resp.NextPage == 0 {
+			return all, resp, nil
+		}
+		page = resp.NextPage
+	}
+}
+
+// GetRuleset gets a ruleset of a repository.
+func (r *repositories) GetRuleset(ctx context.Context, owner, repo string, rulesetID int64, includesParents bool) (*github.Ruleset, *github.Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/rulesets/%v?includes_parents=%v", owner, repo, rulesetID, includesParents)
+	return r.ruleset(ctx, http.MethodGet, u, nil)
+}
+
+// CreateRuleset creates a ruleset in a repository.
+func (r *repositories) CreateRuleset(ctx context.Context, owner, repo string, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	return r.ruleset(ctx, http.MethodPost, fmt.Sprintf("repos/%v/%v/rulesets", owner, repo), rs)
+}
+
+// UpdateRuleset replaces a ruleset of a repository.
+func (r *repositories) UpdateRuleset(ctx context.Context, owner, repo string, rulesetID int64, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	return r.ruleset(ctx, http.MethodPut, fmt.Sprintf("repos/%v/%v/rulesets/%v", owner, repo, rulesetID), rs)
+}
+
+func (r *repositories) ruleset(ctx context.Context, method, u string, body *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+	// A nil *github.Ruleset would be sent as a null body
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = r.client.NewRequest(method, u, nil)
+	} else {
+		req, err = r.client.NewRequest(method, u, body)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raw json.RawMessage
+	resp, err := r.client.Do(ctx, req, &raw)
+	if err != nil {
+		return nil, resp, err
+	}
+	rs, err := DecodeRuleset(raw)
+	return rs, resp, err
+}
+
+// DecodeRuleset decodes a ruleset. go-github refuses rule types it doesn't
+// know yet, such as the file path, file size and file extension rules of
+// push rulesets, so every rule is kept with its parameters as returned.
+func DecodeRuleset(data []byte) (*github.Ruleset, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var rules []struct {
+		Type       string          `json:"type"`
+		Parameters json.RawMessage `json:"parameters"`
+	}
+	if raw, ok := fields["rules"]; ok {
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return nil, fmt.Errorf("decoding ruleset rules: %w", err)
+		}
+		delete(fields, "rules")
+	}
+
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	rs := new(github.Ruleset)
+	if err := json.Unmarshal(rest, rs); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		decoded := &github.RepositoryRule{Type: rule.Type}
+		if len(rule.Parameters) > 0 && string(rule.Parameters) != "null" {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, rule.Parameters); err != nil {
+				return nil, err
+			}
+			params := json.RawMessage(compact.Bytes())
+			decoded.Parameters = &params
+		}
+		rs.Rules = append(rs.Rules, decoded)
+	}
+	return rs, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ghclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+const pushRuleset = `{
+	"id": 42,
+	"name": "push guardrails",
+	"target": "push",
+	"enforcement": "active",
+	"rules": [
+		{"type": "file_path_restriction", "parameters": {"restricted_file_paths": [".github/workflows/**"]}},
+		{"type": "max_file_size", "parameters": {"max_file_size": 10}},
+		{"type": "workflows", "parameters": {"workflows": [{"path": ".github/workflows/ci.yml", "repository_id": 7}]}},
+		{"type": "non_fast_forward"}
+	]
+}`
+
+func TestDecodeRuleset(t *testing.T) {
+	rs, err := DecodeRuleset([]byte(pushRuleset))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs.GetID() != 42 || rs.Name != "push guardrails" || rs.GetTarget() != "push" || len(rs.Rules) != 4 {
+		t.Fatalf("unexpected ruleset %+v", rs)
+	}
+	if rs.Rules[0].Type != "file_path_restriction" || string(*rs.Rules[0].Parameters) != `{"restricted_file_paths":[".github/workflows/**"]}` {
+		t.Errorf("unexpected rule %s %s", rs.Rules[0].Type, *rs.Rules[0].Parameters)
+	}
+	if rs.Rules[3].Parameters != nil {
+		t.Errorf("rule without parameters got %s", *rs.Rules[3].Parameters)
+	}
+
+	// The rules are sent back as read
+	data, err := json.Marshal(rs.Rules[1])
+	if err != nil || string(data) != `{"type":"max_file_size","parameters":{"max_file_size":10}}` {
+		t.Errorf("got %s, %v", data, err)
+	}
+}
+
+func TestRepositoriesUpdateRuleset(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/repos/octo/api/rulesets/42" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		io.WriteString(w, pushRuleset)
+	}))
+	defer server.Close()
+
+	gc := github.NewClient(nil)
+	gc.BaseURL, _ = url.Parse(server.URL + "/")
+	client := New(gc)
+
+	rs, err := DecodeRuleset([]byte(pushRuleset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, _, err := client.Repositories.UpdateRuleset(context.Background(), "octo", "api", 42, rs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.Rules) != 4 {
+		t.Errorf("got %d rules back, want 4", len(updated.Rules))
+	}
+	if !strings.Contains(sent, `{"type":"file_path_restriction","parameters":{"restricted_file_paths":[".github/workflows/**"]}}`) {
+		t.Errorf("push rule not sent: %s", sent)
+	}
+}
+
+func TestRepositoriesGetAllRulesetsPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/api/rulesets" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", `<http://`+r.Host+`/repos/octo/api/rulesets?page=2>; rel="next"`)
+			io.WriteString(w, `[{"id":1,"name":"main","enforcement":"active"}]`)
+		case "2":
+			io.WriteString(w, `[{"id":2,"name":"tags","enforcement":"active"}]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	gc := github.NewClient(nil)
+	gc.BaseURL, _ = url.Parse(server.URL + "/")
+
+	rulesets, _, err := New(gc).Repositories.GetAllRulesets(context.Background(), "octo", "api", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rulesets) != 2 || rulesets[1].Name != "tags" {
+		t.Errorf("got %v, want the rulesets of both pages", rulesets)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+)
+
+// Access levels of GitLab protected branches.
+const (
+	NoAccess   = 0
+	Developer  = 30
+	Maintainer = 40
+)
+
+// Project is the protection of a GitLab project, in the shape of the GitLab
+// API resources that hold it: protected branches, approval rules and
+// settings, push rules and the merge settings of the project.
+type Project struct {
+	// Path names the project; exports of a whole organization hold one
+	// Project per repository.
+	Path              string            `json:"path,omitempty"`
+	ProtectedBranches []ProtectedBranch `json:"protected_branches"`
+	ApprovalRules     []ApprovalRule    `json:"approval_rules,omitempty"`
+	Approvals         *Approvals        `json:"approvals,omitempty"`
+	PushRule          *PushRule         `json:"push_rule,omitempty"`
+	Settings          *Settings         `json:"project_settings,omitempty"`
+}
+
+// ProtectedBranch is a protected branch as created with
+// POST /projects/:id/protected_branches.
+type ProtectedBranch struct {
+	Name                      string `json:"name"`
+	PushAccessLevel           int    `json:"push_access_level"`
+	MergeAccessLevel          int    `json:"merge_access_level"`
+	UnprotectAccessLevel      int    `json:"unprotect_access_level"`
+	AllowForcePush            bool   `json:"allow_force_push"`
+	CodeOwnerApprovalRequired bool   `json:"code_owner_approval_required"`
+}
+
+// ApprovalRule is a merge request approval rule. The protected branches are
+// named instead of referenced by ID, since IDs differ between projects; a
+// rule without branches applies to every branch.
+type ApprovalRule struct {
+	Name              string   `json:"name"`
+	ApprovalsRequired int      `json:"approvals_required"`
+	ProtectedBranches []string `json:"protected_branches,omitempty"`
+}
+
+// Approvals holds the merge request approval settings of the project.
+type Approvals struct {
+	ResetApprovalsOnPush                   bool `json:"reset_approvals_on_push"`
+	MergeRequestsDisableCommittersApproval bool `json:"merge_requests_disable_committers_approval"`
+}
+
+// PushRule holds the push rules of the project.
+type PushRule struct {
+	RejectUnsignedCommits bool `json:"reject_unsigned_commits"`
+}
+
+// Settings holds the merge settings of the project.
+type Settings struct {
+	// MergeMethod is merge, rebase_merge or ff.
+	MergeMethod                               string `json:"merge_method"`
+	OnlyAllowMergeIfPipelineSucceeds          bool   `json:"only_allow_merge_if_pipeline_succeeds"`
+	OnlyAllowMergeIfAllDiscussionsAreResolved bool   `json:"only_allow_merge_if_all_discussions_are_resolved"`
+}
+
+// New converts the branch protection of a repository to its closest GitLab
+// equivalent. Pull request reviews forbid direct pushes, and push
+// restrictions limit merges to maintainers, since the users and teams of the
+// restrictions have no GitLab counterpart. Rulesets aren't converted.
+func New(path string, rp *types.RepoProtection) (*Project, error) {
+	p := rp.BranchProtection
+	if p == nil {
+		return nil, fmt.Errorf("%s has no branch protection on %s", path, rp.Branch)
+	}
+
+	branch := ProtectedBranch{
+		Name:                 rp.Branch,
+		PushAccessLevel:      Developer,
+		MergeAccessLevel:     Developer,
+		UnprotectAccessLevel: Maintainer,
+		AllowForcePush:       p.AllowForcePushes,
+	}
+	project := &Project{Path: path}
+	if reviews := p.Reviews; reviews != nil {
+		branch.PushAccessLevel = NoAccess
+		branch.CodeOwnerApprovalRequired = reviews.CodeOwners
+		if reviews.RequiredApprovals > 0 {
+			project.ApprovalRules = []ApprovalRule{{
+				Name:              "Required reviews",
+				ApprovalsRequired: reviews.RequiredApprovals,
+				ProtectedBranches: []string{rp.Branch},
+			}}
+		}
+		project.Approvals = &Approvals{
+			ResetApprovalsOnPush:                   reviews.DismissStale,
+			MergeRequestsDisableCommittersApproval: reviews.LastPushApproval,
+		}
+	}
+	if p.Restrictions != nil {
+		branch.MergeAccessLevel = Maintainer
+		if branch.PushAccessLevel != NoAccess {
+			branch.PushAccessLevel = Maintainer
+		}
+	}
+	if p.LockBranch {
+		branch.PushAccessLevel, branch.MergeAccessLevel = NoAccess, NoAccess
+	}
+	project.ProtectedBranches = []ProtectedBranch{branch}
+
+	if p.SignedCommits {
+		project.PushRule = &PushRule{RejectUnsignedCommits: true}
+	}
+	project.Settings = &Settings{
+		MergeMethod:                               "merge",
+		OnlyAllowMergeIfPipelineSucceeds:          p.StatusChecks != nil,
+		OnlyAllowMergeIfAllDiscussionsAreResolved: p.ConversationResolution,
+	}
+	if p.LinearHistory {
+		project.Settings.MergeMethod = "ff"
+	}
+	return project, nil
+}
+
+// BranchProtection converts the protection of the first protected branch of
+// the project that isn't a wildcard. GitLab pipelines have no names to
+// require as status checks, so none are.
+func (project *Project) BranchProtection() (*types.BranchProtection, error) {
+	var branch *ProtectedBranch
+	for i := range project.ProtectedBranches {
+		if !strings.Contains(project.ProtectedBranches[i].Name, "*") {
+			branch = &project.ProtectedBranches[i]
+			break
+		}
+	}
+	if branch == nil {
+		return nil, errors.New("the GitLab project has no protected branch without a wildcard")
+	}
+
+	approvals := 0
+	for _, rule := range project.ApprovalRules {
+		if (len(rule.ProtectedBranches) == 0 || contains(rule.ProtectedBranches, branch.Name)) && rule.ApprovalsRequired > approvals {
+			approvals = rule.ApprovalsRequired
+		}
+	}
+
+	p := &types.BranchProtection{AllowForcePushes: branch.AllowForcePush}
+	if branch.PushAccessLevel == NoAccess && branch.MergeAccessLevel == NoAccess {
+		p.LockBranch = true
+	} else if branch.PushAccessLevel == NoAccess || approvals > 0 || branch.CodeOwnerApprovalRequired {
+		reviews := &types.Reviews{RequiredApprovals: approvals, CodeOwners: branch.CodeOwnerApprovalRequired}
+		// Code owner approval implies an approval
+		if reviews.CodeOwners && reviews.RequiredApprovals == 0 {
+			reviews.RequiredApprovals = 1
+		}
+		if a := project.Approvals; a != nil {
+			reviews.DismissStale = a.ResetApprovalsOnPush
+			reviews.LastPushApproval = a.MergeRequestsDisableCommittersApproval
+		}
+		p.Reviews = reviews
+	}
+	if branch.MergeAccessLevel >= Maintainer {
+		// Without users or teams, only admins can push
+		p.Restrictions = &types.Actors{Users: []string{}, Teams: []string{}, Apps: []string{}}
+	}
+	p.SignedCommits = project.PushRule != nil && project.PushRule.RejectUnsignedCommits
+	if s := project.Settings; s != nil {
+		p.LinearHistory = s.MergeMethod == "ff"
+		p.ConversationResolution = s.OnlyAllowMergeIfAllDiscussionsAreResolved
+	}
+	return p, nil
+}
+
+// Protection converts the project to an inline protection of the
+// configuration file.
+func Protection(project *Project) (map[string]interface{}, error) {
+	p, err := project.BranchProtection()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(p.GitHub())
+	if err != nil {
+		return nil, err
+	}
+	var protection map[string]interface{}
+	if err := json.Unmarshal(data, &protection); err != nil {
+		return nil, err
+	}
+	for key, value := range protection {
+		if value == nil {
+			delete(protection, key)
+		}
+	}
+	return protection, nil
+}
+
+// Read reads a GitLab protection file holding a single project.
+func Read(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// Parse decodes the protection file read from path.
+func Parse(path string, data []byte) (*Project, error) {
+	project := new(Project)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(project); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return project, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
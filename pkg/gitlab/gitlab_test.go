@@ -0,0 +1,131 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gitlab
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+)
+
+func TestNew(t *testing.T) {
+	rp := &types.RepoProtection{Branch: "main", BranchProtection: &types.BranchProtection{
+		StatusChecks:  &types.StatusChecks{Strict: true, Contexts: []string{"ci"}},
+		Reviews:       &types.Reviews{RequiredApprovals: 2, CodeOwners: true, DismissStale: true},
+		Restrictions:  &types.Actors{},
+		LinearHistory: true,
+		SignedCommits: true,
+	}}
+	project, err := New("api", rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Project{
+		Path: "api",
+		ProtectedBranches: []ProtectedBranch{{
+			Name:                      "main",
+			PushAccessLevel:           NoAccess,
+			MergeAccessLevel:          Maintainer,
+			UnprotectAccessLevel:      Maintainer,
+			CodeOwnerApprovalRequired: true,
+		}},
+		ApprovalRules: []ApprovalRule{{Name: "Required reviews", ApprovalsRequired: 2, ProtectedBranches: []string{"main"}}},
+		Approvals:     &Approvals{ResetApprovalsOnPush: true},
+		PushRule:      &PushRule{RejectUnsignedCommits: true},
+		Settings:      &Settings{MergeMethod: "ff", OnlyAllowMergeIfPipelineSucceeds: true},
+	}
+	if !reflect.DeepEqual(project, want) {
+		t.Errorf("New() = %+v, want %+v", project, want)
+	}
+
+	if _, err := New("api", &types.RepoProtection{Branch: "main"}); err == nil {
+		t.Error("New() accepted a repository without branch protection")
+	}
+}
+
+func TestBranchProtection(t *testing.T) {
+	project := &Project{
+		ProtectedBranches: []ProtectedBranch{
+			{Name: "release/*", PushAccessLevel: NoAccess, MergeAccessLevel: NoAccess},
+			{Name: "main", PushAccessLevel: Developer, MergeAccessLevel: Developer, CodeOwnerApprovalRequired: true},
+		},
+		ApprovalRules: []ApprovalRule{
+			{Name: "Releases", ApprovalsRequired: 3, ProtectedBranches: []string{"release/*"}},
+			{Name: "Everything", ApprovalsRequired: 0},
+		},
+		Settings: &Settings{MergeMethod: "ff", OnlyAllowMergeIfAllDiscussionsAreResolved: true},
+	}
+	p, err := project.BranchProtection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviews := p.Reviews
+	if reviews == nil || reviews.RequiredApprovals != 1 || !reviews.CodeOwners {
+		t.Errorf("reviews = %+v, want one code owner approval", reviews)
+	}
+	if p.Restrictions != nil || p.LockBranch {
+		t.Errorf("developers can merge, but got restrictions %+v and lock %v", p.Restrictions, p.LockBranch)
+	}
+	if !p.LinearHistory || !p.ConversationResolution {
+		t.Errorf("merge settings weren't converted: %+v", p)
+	}
+
+	if _, err := (&Project{ProtectedBranches: []ProtectedBranch{{Name: "*"}}}).BranchProtection(); err == nil {
+		t.Error("BranchProtection() accepted a project with only wildcard branches")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rp := &types.RepoProtection{Branch: "main", BranchProtection: &types.BranchProtection{
+		Reviews:                &types.Reviews{RequiredApprovals: 2, LastPushApproval: true},
+		ConversationResolution: true,
+	}}
+	project, err := New("api", rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "gitlab.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := read.BranchProtection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, rp.BranchProtection) {
+		t.Errorf("round trip = %+v, want %+v", p, rp.BranchProtection)
+	}
+
+	protection, err := Protection(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := protection["restrictions"]; ok {
+		t.Errorf("Protection() kept the unset restrictions: %v", protection)
+	}
+}
@@ -18,90 +18,139 @@ package helpers
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
 
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
 	"github.com/google/go-github/v59/github"
 )
 
+// Errors of HTTPStatusCodeCheck, one per class of failure, for callers to
+// branch on with errors.Is.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("resource not found")
+	ErrConflict     = errors.New("conflict")
+	ErrGone         = errors.New("gone")
+	ErrValidation   = errors.New("validation failed, or the endpoint has been spammed")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+	ErrUnexpected   = errors.New("unexpected status")
+)
+
+// StatusError is an unsuccessful HTTP status code; it unwraps to the error
+// of its class.
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v [%d]", e.Err, e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusCodeCheck returns nil for a successful status code and a
+// *StatusError for any other.
 func HTTPStatusCodeCheck(statuscode int) error {
-	// Check the HTTP status code
-	switch statuscode {
-	case 200, 201:
-		// Created
-		log.Printf("request successful[%d]", statuscode)
+	var err error
+	switch {
+	case statuscode >= 200 && statuscode < 300:
+		logging.Debugf("request successful[%d]", statuscode)
 		return nil
-	case 303:
-		log.Printf("same branch name pattern already exists[%d]", statuscode)
-		return errors.New("same branch name pattern already exists")
-	case 400:
-		// Bad Request
-		break
-	case 401:
-		// Unauthorized
-		break
-	case 403:
-		// Forbidden
-		log.Printf("Forbidden[%d]", statuscode)
-		return errors.New("Forbidden")
-	case 404:
-		// Not Found
-		log.Printf("resource not found[%d]", statuscode)
-		return errors.New("resource not found")
-	case 405:
-		// Method Not Allowed
-		break
-	case 406:
-		// Not Acceptable
-		break
-	case 409:
-		// Conflict
-		break
-	case 410:
-		// Gone
-		break
-	case 422:
-		// Unprocessable Entity
-		log.Printf("Validation failed, or the endpoint has been spammed.[%d]", statuscode)
-		return errors.New("Validation failed, or the endpoint has been spammed")
-	// case 429:
-	// 	// Too Many Requests
-	// 	break
-	// case 500:
-	// 	// Internal Server Error
-	// 	break
-	// case 501:
-	// 	// Not Implemented
-	// 	break
-	// case 502:
-	// 	// Bad Gateway
-	// 	break
-	// case 503:
-	// 	// Service Unavailable
-	// 	break
-	// case 504:
-	// 	// Gateway Timeout
-	// 	break
+	case statuscode == http.StatusSeeOther:
+		// returned when the same branch name pattern already exists
+		err = ErrConflict
+	case statuscode == http.StatusBadRequest:
+		err = ErrBadRequest
+	case statuscode == http.StatusUnauthorized:
+		err = ErrUnauthorized
+	case statuscode == http.StatusForbidden:
+		err = ErrForbidden
+	case statuscode == http.StatusNotFound:
+		err = ErrNotFound
+	case statuscode == http.StatusConflict:
+		err = ErrConflict
+	case statuscode == http.StatusGone:
+		err = ErrGone
+	case statuscode == http.StatusUnprocessableEntity:
+		err = ErrValidation
+	case statuscode == http.StatusTooManyRequests:
+		err = ErrRateLimited
+	case statuscode >= 500:
+		err = ErrServer
 	default:
-		// Handle unexpected response status code
-		log.Fatalf("ERROR: HTTP request failed with status code: %d\n", statuscode)
-		return errors.New("HTTP request failed with status code")
+		err = ErrUnexpected
+	}
+	return &StatusError{StatusCode: statuscode, Err: err}
+}
+
+// DetectRepository returns the owner and name of the GitHub repository the
+// tool is running for: the repository of the current GitHub Actions workflow,
+// or the origin remote of the git clone in dir.
+func DetectRepository(dir string) (string, string, error) {
+	if full := os.Getenv("GITHUB_REPOSITORY"); full != "" {
+		owner, repo, ok := strings.Cut(full, "/")
+		if !ok {
+			return "", "", fmt.Errorf("unexpected GITHUB_REPOSITORY %q", full)
+		}
+		return owner, repo, nil
 	}
 
-	return nil
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("reading the origin remote: %w", err)
+	}
+	return ParseRemoteURL(strings.TrimSpace(string(out)))
 }
 
-func DoesRulesetExist(ctx context.Context, client *github.Client, owner, repo, branch, sourceRuleset string) bool {
-	targetRulesets, response, err := client.Repositories.GetAllRulesets(ctx, owner, repo, false)
+// ParseRemoteURL extracts the owner and repository name from an HTTPS or SSH
+// git remote URL.
+func ParseRemoteURL(remote string) (string, string, error) {
+	path := remote
 	switch {
-	case err != nil:
-		log.Fatalf("Error fetching branch ruleset: %v\n", err)
-	case HTTPStatusCodeCheck(response.StatusCode) != nil:
-		log.Fatalf("Error fetching branch ruleset: %v\n", HTTPStatusCodeCheck(response.StatusCode))
-	}
-	for _, targetRuleset := range targetRulesets {
-		if sourceRuleset == targetRuleset.Name {
-			return true
+	case strings.Contains(remote, "://"):
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", err
 		}
+		path = u.Path
+	case strings.Contains(remote, ":"):
+		// scp-like syntax: git@github.com:owner/repo.git
+		_, path, _ = strings.Cut(remote, ":")
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", fmt.Errorf("cannot determine the repository from remote %q", remote)
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
+
+// TargetRulesets lists the rulesets of a repository keyed by name, so the
+// rulesets of a source are matched against a single listing.
+func TargetRulesets(ctx context.Context, client ghclient.RulesetManager, owner, repo string) (map[string]*github.Ruleset, error) {
+	rulesets, response, err := client.GetAllRulesets(ctx, owner, repo, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := HTTPStatusCodeCheck(response.StatusCode); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*github.Ruleset, len(rulesets))
+	for _, ruleset := range rulesets {
+		byName[ruleset.Name] = ruleset
 	}
-	return false
+	return byName, nil
 }
@@ -0,0 +1,88 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// Cache keeps the repository listings of owners on disk, so the invocations
+// of a rollout session don't list the whole organization every time.
+type Cache struct {
+	Dir string
+	// TTL is how long a listing is reused.
+	TTL time.Duration
+	// Refresh ignores the cached listings, which are replaced.
+	Refresh bool
+}
+
+type listing struct {
+	Listed time.Time            `json:"listed"`
+	Repos  []*github.Repository `json:"repos"`
+}
+
+// Load returns the cached listing named key, and false when there is none
+// younger than the TTL or Refresh is set.
+func (c *Cache) Load(key string) ([]*github.Repository, time.Time, bool, error) {
+	if c.Refresh {
+		return nil, time.Time{}, false, nil
+	}
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	var l listing
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("reading the cached repositories: %w", err)
+	}
+	if time.Since(l.Listed) > c.TTL {
+		return nil, time.Time{}, false, nil
+	}
+	return l.Repos, l.Listed, true, nil
+}
+
+// Save caches the listing named key.
+func (c *Cache) Save(key string, repos []*github.Repository) error {
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating the repository cache directory: %w", err)
+	}
+	data, err := json.Marshal(listing{Listed: time.Now(), Repos: repos})
+	if err != nil {
+		return err
+	}
+	// Written aside and renamed, so a concurrent run never reads half a file
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("caching the repositories: %w", err)
+	}
+	return os.Rename(tmp, c.path(key))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, strings.ToLower(key)+".json")
+}
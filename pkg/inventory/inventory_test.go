@@ -0,0 +1,59 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	cache := &Cache{Dir: t.TempDir(), TTL: time.Hour}
+	if _, _, ok, err := cache.Load("Org"); ok || err != nil {
+		t.Fatalf("Load() of an empty cache = %v, %v", ok, err)
+	}
+	if err := cache.Save("Org", []*github.Repository{{Name: github.String("api")}}); err != nil {
+		t.Fatal(err)
+	}
+	repos, _, ok, err := cache.Load("org")
+	if err != nil || !ok {
+		t.Fatalf("Load() = %v, %v; want the saved listing", ok, err)
+	}
+	if len(repos) != 1 || repos[0].GetName() != "api" {
+		t.Errorf("Load() = %v", repos)
+	}
+
+	cache.Refresh = true
+	if _, _, ok, _ := cache.Load("org"); ok {
+		t.Error("Load() with Refresh reused the listing")
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	dir := t.TempDir()
+	stale := `{"listed":"2020-01-01T00:00:00Z","repos":[{"name":"api"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "org.json"), []byte(stale), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cache := &Cache{Dir: dir, TTL: time.Hour}
+	if _, _, ok, err := cache.Load("org"); ok || err != nil {
+		t.Errorf("Load() of a stale listing = %v, %v; want a miss", ok, err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package keychain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Service is the service the secrets of the tool are stored under.
+const Service = "repo-protection-sync"
+
+// ErrNotFound is returned when no secret is stored for the account.
+var ErrNotFound = errors.New("no secret in the keychain")
+
+// command runs the keychain tools, and goos picks them. Tests replace them.
+var command = exec.CommandContext
+var goos = runtime.GOOS
+
+// Set stores the secret of account in the keychain of the operating system:
+// the login keychain on macOS, or the Secret Service of the desktop, through
+// secret-tool, on Linux. The secret never appears on a command line.
+func Set(ctx context.Context, account, secret string) error {
+	switch goos {
+	case "darwin":
+		// security -i reads its commands from stdin
+		script := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(Service), quote(account), quote(secret))
+		_, err := run(ctx, script, "security", "-i")
+		return err
+	case "linux":
+		_, err := run(ctx, secret, "secret-tool", "store", "--label", Service+" ("+account+")", "service", Service, "account", account)
+		return err
+	}
+	return unsupported()
+}
+
+// Get returns the secret of account, or ErrNotFound.
+func Get(ctx context.Context, account string) (string, error) {
+	var out string
+	var err error
+	switch goos {
+	case "darwin":
+		out, err = run(ctx, "", "security", "find-generic-password", "-s", Service, "-a", account, "-w")
+	case "linux":
+		out, err = run(ctx, "", "secret-tool", "lookup", "service", Service, "account", account)
+	default:
+		return "", unsupported()
+	}
+	secret := strings.TrimSpace(out)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) || (err == nil && secret == "") {
+		// Both tools exit with an error when nothing is stored
+		return "", ErrNotFound
+	}
+	return secret, err
+}
+
+// Delete removes the secret of account.
+func Delete(ctx context.Context, account string) error {
+	var err error
+	switch goos {
+	case "darwin":
+		_, err = run(ctx, "", "security", "delete-generic-password", "-s", Service, "-a", account)
+	case "linux":
+		_, err = run(ctx, "", "secret-tool", "clear", "service", Service, "account", account)
+	default:
+		return unsupported()
+	}
+	return err
+}
+
+func unsupported() error {
+	return fmt.Errorf("storing secrets isn't supported on %s; pass --token or set GITHUB_TOKEN", goos)
+}
+
+// quote quotes an argument of a security -i command.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func run(ctx context.Context, stdin, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := command(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running %s: %w: %s", name, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.String(), nil
+}
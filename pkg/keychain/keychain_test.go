@@ -0,0 +1,91 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package keychain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeTools replaces the keychain tools of os with the shell script, which
+// gets the stdin of the tool, recording the command lines.
+func fakeTools(t *testing.T, os, script string) *[]string {
+	t.Helper()
+	var calls []string
+	command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return exec.CommandContext(ctx, "sh", "-c", script)
+	}
+	previous := goos
+	goos = os
+	t.Cleanup(func() {
+		command = exec.CommandContext
+		goos = previous
+	})
+	return &calls
+}
+
+func TestSetLinux(t *testing.T) {
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	calls := fakeTools(t, "linux", "cat > "+stdin)
+	if err := Set(context.Background(), "github.com", "ghp_secret"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "secret-tool store --label repo-protection-sync (github.com) service repo-protection-sync account github.com"; (*calls)[0] != want {
+		t.Errorf("ran %q, want %q", (*calls)[0], want)
+	}
+	if data, _ := os.ReadFile(stdin); string(data) != "ghp_secret" {
+		t.Errorf("passed %q on stdin", data)
+	}
+}
+
+func TestSetDarwin(t *testing.T) {
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	calls := fakeTools(t, "darwin", "cat > "+stdin)
+	if err := Set(context.Background(), "github.com", `ghp_"x`); err != nil {
+		t.Fatal(err)
+	}
+	if (*calls)[0] != "security -i" {
+		t.Errorf("ran %q", (*calls)[0])
+	}
+	want := `add-generic-password -U -s "repo-protection-sync" -a "github.com" -w "ghp_\"x"` + "\n"
+	if data, _ := os.ReadFile(stdin); string(data) != want {
+		t.Errorf("passed %q on stdin, want %q", data, want)
+	}
+}
+
+func TestGet(t *testing.T) {
+	fakeTools(t, "darwin", "echo ghp_secret")
+	secret, err := Get(context.Background(), "github.com")
+	if err != nil || secret != "ghp_secret" {
+		t.Errorf("Get() = %q, %v", secret, err)
+	}
+
+	fakeTools(t, "linux", "exit 1")
+	if _, err := Get(context.Background(), "github.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a missing secret = %v, want ErrNotFound", err)
+	}
+
+	fakeTools(t, "windows", "true")
+	if _, err := Get(context.Background(), "github.com"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() on windows = %v, want unsupported", err)
+	}
+}
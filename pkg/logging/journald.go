@@ -0,0 +1,37 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package logging
+
+import (
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// journaldWriter sends every log line to the systemd journal as one entry.
+type journaldWriter struct{}
+
+func (journaldWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	if err := journal.Send(msg, journal.PriInfo, map[string]string{"SYSLOG_IDENTIFIER": "repo-protection-sync"}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func journaldEnabled() bool {
+	return journal.Enabled()
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package logging
+
+import "log"
+
+var quiet, verbose bool
+
+// Infof logs progress, such as a repository that was synced. It is dropped
+// with --quiet.
+func Infof(format string, v ...any) {
+	if !quiet {
+		log.Printf(format, v...)
+	}
+}
+
+// Quiet reports whether --quiet dropped progress output.
+func Quiet() bool {
+	return quiet
+}
+
+// Debugf logs detail that is only useful when troubleshooting, such as the
+// status of every request. It is only shown with --verbose.
+func Debugf(format string, v ...any) {
+	if verbose {
+		log.Printf(format, v...)
+	}
+}
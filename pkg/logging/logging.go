@@ -0,0 +1,103 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package logging
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Options configures where log output is written. When no sink is enabled
+// logs go to stderr; otherwise they go to the enabled sinks only, which is
+// what daemon deployments expect.
+type Options struct {
+	// File is the path of a log file, rotated according to the settings below.
+	File       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+
+	Syslog   bool
+	Journald bool
+
+	// Quiet drops progress messages, leaving warnings and errors; Verbose
+	// adds per-request detail.
+	Quiet   bool
+	Verbose bool
+}
+
+// Setup points the standard logger at the sinks configured in opts.
+func Setup(opts Options) error {
+	if opts.Quiet && opts.Verbose {
+		return errors.New("--quiet and --verbose can't be combined")
+	}
+	quiet, verbose = opts.Quiet, opts.Verbose
+
+	var sinks []io.Writer
+
+	if opts.File != "" {
+		sinks = append(sinks, &lumberjack.Logger{
+			Filename:   opts.File,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+			Compress:   opts.Compress,
+		})
+	}
+
+	if opts.Syslog {
+		w, err := newSyslogWriter()
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, w)
+	}
+
+	if opts.Journald {
+		if !journaldEnabled() {
+			return errors.New("journald is not available on this host")
+		}
+		sinks = append(sinks, journaldWriter{})
+	}
+
+	switch len(sinks) {
+	case 0:
+		log.SetOutput(os.Stderr)
+	case 1:
+		log.SetOutput(sinks[0])
+	default:
+		log.SetOutput(io.MultiWriter(sinks...))
+	}
+
+	// syslog and journald timestamp entries themselves
+	if opts.File == "" && (opts.Syslog || opts.Journald) {
+		log.SetFlags(0)
+	}
+	return nil
+}
+
+// SetRunID prefixes every log line with the ID of the run, after the
+// timestamp, so the logs of runs can be correlated with their reports and
+// audit records.
+func SetRunID(id string) {
+	log.SetPrefix("[" + id + "] ")
+	log.SetFlags(log.Flags() | log.Lmsgprefix)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package logging
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.log")
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	if err := Setup(Options{File: path, MaxSizeMB: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	log.Println("hello from the test")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading log file: %v", err)
+	}
+	if !strings.Contains(string(data), "hello from the test") {
+		t.Errorf("log file does not contain the message: %q", data)
+	}
+}
+
+func TestLevels(t *testing.T) {
+	var buf strings.Builder
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+		quiet, verbose = false, false
+	})
+
+	if err := Setup(Options{Quiet: true, Verbose: true}); err == nil {
+		t.Error("expected an error combining quiet and verbose")
+	}
+
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{}, "info\n"},
+		{Options{Quiet: true}, ""},
+		{Options{Verbose: true}, "info\ndebug\n"},
+	}
+	for _, tt := range tests {
+		if err := Setup(tt.opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		buf.Reset()
+		log.SetOutput(&buf)
+		log.SetFlags(0)
+		Infof("info")
+		Debugf("debug")
+		if buf.String() != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.opts, buf.String(), tt.want)
+		}
+	}
+	log.SetFlags(log.LstdFlags)
+}
+
+func TestSetRunID(t *testing.T) {
+	var buf strings.Builder
+	log.SetOutput(&buf)
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+		log.SetPrefix("")
+		log.SetFlags(log.LstdFlags)
+	})
+
+	SetRunID("20240501T120000Z-abcdef")
+	log.Println("synced")
+	if !strings.HasSuffix(buf.String(), " [20240501T120000Z-abcdef] synced\n") {
+		t.Errorf("got %q, want the run ID after the timestamp", buf.String())
+	}
+}
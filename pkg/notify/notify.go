@@ -0,0 +1,245 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+)
+
+// Summary describes the outcome of a sync run.
+type Summary struct {
+	// RunID identifies the run that applied the policy.
+	RunID  string `json:"run_id,omitempty"`
+	Owner  string `json:"owner"`
+	Source string `json:"source"`
+	// Policy names the policy of the configuration file that was applied.
+	Policy   string    `json:"policy,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Targets  int       `json:"targets"`
+	Failures []Failure `json:"failures"`
+	// Orphaned lists the repositories the preflight found without an active admin.
+	Orphaned []string `json:"orphaned,omitempty"`
+	// Empty lists the repositories without commits, which only received the
+	// rulesets and need their branch protection applied after the first push.
+	Empty []string `json:"empty,omitempty"`
+	// UnsupportedPlan lists the private repositories the plan of the
+	// organization doesn't offer branch protection for.
+	UnsupportedPlan []string `json:"unsupported_plan,omitempty"`
+	// OptedOut lists the repositories excluded by their owners.
+	OptedOut []string `json:"opted_out,omitempty"`
+	// Held lists the repositories left untouched because the canary cohort
+	// failed or the rollout wasn't confirmed.
+	Held []string `json:"held,omitempty"`
+	// RateLimitWaits counts the waits for the rate limit to reset, which
+	// took RateLimitWaitSeconds in total.
+	RateLimitWaits       int `json:"rate_limit_waits,omitempty"`
+	RateLimitWaitSeconds int `json:"rate_limit_wait_seconds,omitempty"`
+}
+
+// Failure is a repository that rejected the protection.
+type Failure struct {
+	Repo  string `json:"repo"`
+	Error string `json:"error"`
+}
+
+// RepoEvent is the outcome of syncing a single repository, posted to the
+// result webhooks as soon as it is known.
+type RepoEvent struct {
+	RunID  string `json:"run_id,omitempty"`
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Policy string `json:"policy,omitempty"`
+	// Action is created, updated, skipped or failed.
+	Action string `json:"action"`
+	// Diff lists the settings the sync changed.
+	Diff  []diff.Delta `json:"diff"`
+	Error string       `json:"error,omitempty"`
+}
+
+// NewSummary builds a Summary from the per-repository errors returned by the setter.
+func NewSummary(owner, source string, started time.Time, targets int, failures map[string]error) Summary {
+	s := Summary{
+		Owner:    owner,
+		Source:   source,
+		Started:  started,
+		Finished: time.Now(),
+		Targets:  targets,
+		Failures: make([]Failure, 0, len(failures)),
+	}
+	for repo, err := range failures {
+		s.Failures = append(s.Failures, Failure{Repo: repo, Error: err.Error()})
+	}
+	sort.Slice(s.Failures, func(i, j int) bool { return s.Failures[i].Repo < s.Failures[j].Repo })
+	return s
+}
+
+// Text renders the summary as a short human-readable message.
+func (s Summary) Text() string {
+	var b strings.Builder
+	from := s.Owner + "/" + s.Source
+	switch {
+	case s.Policy != "" && s.Source == "":
+		from = "policy " + s.Policy
+	case s.Policy != "":
+		from = fmt.Sprintf("policy %s (%s)", s.Policy, from)
+	}
+	fmt.Fprintf(&b, "repo-protection-sync: %s synced to %d repositories in %s, %d failed",
+		from, s.Targets, s.Finished.Sub(s.Started).Round(time.Second), len(s.Failures))
+	for _, f := range s.Failures {
+		fmt.Fprintf(&b, "\n• %s: %s", f.Repo, f.Error)
+	}
+	if len(s.Orphaned) > 0 {
+		fmt.Fprintf(&b, "\nRepositories without an active admin: %s", strings.Join(s.Orphaned, ", "))
+	}
+	if len(s.Empty) > 0 {
+		fmt.Fprintf(&b, "\nEmpty repositories awaiting a first push: %s", strings.Join(s.Empty, ", "))
+	}
+	if len(s.UnsupportedPlan) > 0 {
+		fmt.Fprintf(&b, "\nRepositories without branch protection on the current plan: %s", strings.Join(s.UnsupportedPlan, ", "))
+	}
+	if len(s.OptedOut) > 0 {
+		fmt.Fprintf(&b, "\nRepositories that opted out: %s", strings.Join(s.OptedOut, ", "))
+	}
+	if len(s.Held) > 0 {
+		fmt.Fprintf(&b, "\nRepositories held back after the canary: %s", strings.Join(s.Held, ", "))
+	}
+	if s.RateLimitWaits > 0 {
+		fmt.Fprintf(&b, "\nWaited %v for the rate limit to reset", time.Duration(s.RateLimitWaitSeconds)*time.Second)
+	}
+	if s.RunID != "" {
+		fmt.Fprintf(&b, "\nRun %s", s.RunID)
+	}
+	return b.String()
+}
+
+// Notifier delivers a run summary somewhere.
+type Notifier interface {
+	Notify(ctx context.Context, summary Summary) error
+}
+
+// Slack posts summaries to a Slack incoming webhook.
+type Slack struct {
+	WebhookURL string
+	Channel    string
+}
+
+func (n Slack) Notify(ctx context.Context, summary Summary) error {
+	payload := map[string]string{"text": summary.Text()}
+	if n.Channel != "" {
+		payload["channel"] = n.Channel
+	}
+	return postJSON(ctx, n.WebhookURL, nil, payload)
+}
+
+// Webhook posts summaries as JSON to a generic HTTP endpoint.
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+}
+
+func (n Webhook) Notify(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, n.URL, n.Headers, summary)
+}
+
+// FromConfig builds the notifiers enabled in the configuration file.
+func FromConfig(cfg config.Notifications) []Notifier {
+	var notifiers []Notifier
+	if cfg.Slack != nil && cfg.Slack.WebhookURL != "" {
+		notifiers = append(notifiers, Slack{WebhookURL: cfg.Slack.WebhookURL, Channel: cfg.Slack.Channel})
+	}
+	for _, wh := range cfg.Webhooks {
+		notifiers = append(notifiers, Webhook{URL: wh.URL, Headers: wh.Headers})
+	}
+	return notifiers
+}
+
+// Send delivers the summary to every notifier enabled in the configuration.
+// Delivery errors are logged and never fail the run.
+func Send(ctx context.Context, cfg config.Notifications, summary Summary) {
+	if cfg.OnlyOnFailure && len(summary.Failures) == 0 {
+		return
+	}
+	for _, n := range FromConfig(cfg) {
+		if err := n.Notify(ctx, summary); err != nil {
+			log.Printf("Failed to send notification: %v\n", err)
+		}
+	}
+}
+
+// RateLimited posts to Slack, when enabled in the configuration, that the
+// sync of owner waits until resume for the rate limit to reset. Delivery
+// errors are logged.
+func RateLimited(ctx context.Context, cfg config.Notifications, owner string, resume time.Time, remaining int) {
+	if cfg.Slack == nil || cfg.Slack.WebhookURL == "" || !cfg.Slack.RateLimit {
+		return
+	}
+	payload := map[string]string{"text": fmt.Sprintf("repo-protection-sync: the sync of %s ran out of rate limit and resumes at %s with %d repositories left",
+		owner, resume.UTC().Format(time.RFC3339), remaining)}
+	if cfg.Slack.Channel != "" {
+		payload["channel"] = cfg.Slack.Channel
+	}
+	if err := postJSON(ctx, cfg.Slack.WebhookURL, nil, payload); err != nil {
+		log.Printf("Failed to send notification: %v\n", err)
+	}
+}
+
+// SendRepoEvent posts the event to every result webhook of the
+// configuration. Delivery errors are logged and never fail the repository.
+func SendRepoEvent(ctx context.Context, cfg config.Notifications, event RepoEvent) {
+	for _, wh := range cfg.ResultWebhooks {
+		if err := postJSON(ctx, wh.URL, wh.Headers, event); err != nil {
+			log.Printf("Failed to send the result of %s: %v\n", event.Repo, err)
+		}
+	}
+}
+
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s returned %s", url, resp.Status)
+	}
+	return nil
+}
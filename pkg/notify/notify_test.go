@@ -0,0 +1,127 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+)
+
+func TestSendWebhook(t *testing.T) {
+	var received []Summary
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			t.Errorf("custom header not sent")
+		}
+		var s Summary
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			t.Errorf("decoding summary: %v", err)
+		}
+		received = append(received, s)
+	}))
+	defer srv.Close()
+
+	cfg := config.Notifications{
+		OnlyOnFailure: true,
+		Webhooks:      []config.Webhook{{URL: srv.URL, Headers: map[string]string{"X-Token": "secret"}}},
+	}
+
+	Send(context.Background(), cfg, NewSummary("octo", "template", time.Now(), 3, nil))
+	if len(received) != 0 {
+		t.Fatalf("expected no notification for a clean run with only_on_failure")
+	}
+
+	Send(context.Background(), cfg, NewSummary("octo", "template", time.Now(), 3, map[string]error{
+		"b": errors.New("422"),
+		"a": errors.New("403"),
+	}))
+	if len(received) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(received))
+	}
+	if f := received[0].Failures; len(f) != 2 || f[0].Repo != "a" || f[1].Repo != "b" {
+		t.Errorf("got failures %+v", f)
+	}
+}
+
+func TestSendRepoEvent(t *testing.T) {
+	var received []RepoEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e RepoEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("decoding event: %v", err)
+		}
+		received = append(received, e)
+	}))
+	defer srv.Close()
+
+	cfg := config.Notifications{
+		Webhooks:       []config.Webhook{{URL: srv.URL + "/summary"}},
+		ResultWebhooks: []config.Webhook{{URL: srv.URL}},
+	}
+	SendRepoEvent(context.Background(), cfg, RepoEvent{Owner: "octo", Repo: "api", Action: "updated",
+		Diff: []diff.Delta{{Field: "enforce_admins", From: "false", To: "true"}}})
+	if len(received) != 1 {
+		t.Fatalf("got %d events, want 1", len(received))
+	}
+	if e := received[0]; e.Repo != "api" || e.Action != "updated" || len(e.Diff) != 1 || e.Diff[0].To != "true" {
+		t.Errorf("got event %+v", e)
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	var payload map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer srv.Close()
+
+	n := Slack{WebhookURL: srv.URL, Channel: "#platform"}
+	if err := n.Notify(context.Background(), NewSummary("octo", "template", time.Now(), 1, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload["channel"] != "#platform" || payload["text"] == "" {
+		t.Errorf("unexpected payload %v", payload)
+	}
+}
+
+func TestSummaryTextPolicy(t *testing.T) {
+	s := NewSummary("octo", "", time.Now(), 2, nil)
+	s.Policy = "libraries"
+	if text := s.Text(); !strings.HasPrefix(text, "repo-protection-sync: policy libraries synced to 2 repositories") {
+		t.Errorf("unexpected text %q", text)
+	}
+	s.Source = "service-template"
+	if text := s.Text(); !strings.HasPrefix(text, "repo-protection-sync: policy libraries (octo/service-template) synced") {
+		t.Errorf("unexpected text %q", text)
+	}
+	s.RateLimitWaits, s.RateLimitWaitSeconds = 1, 90
+	if text := s.Text(); !strings.Contains(text, "\nWaited 1m30s for the rate limit to reset") {
+		t.Errorf("unexpected text %q", text)
+	}
+	s.RunID = "run-1"
+	if text := s.Text(); !strings.HasSuffix(text, "\nRun run-1") {
+		t.Errorf("unexpected text %q", text)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package operator
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// CRD is the CustomResourceDefinition of RepoProtectionPolicy, to apply
+// before running the controller.
+//
+//go:embed crd.yaml
+var CRD []byte
+
+// command runs kubectl. Tests replace it.
+var command = exec.CommandContext
+
+// Kubectl reads and writes the resources with kubectl, which finds the
+// cluster and the credentials as usual: the kubeconfig, or the service
+// account of the pod.
+type Kubectl struct {
+	// Binary is the kubectl executable, kubectl on the PATH by default.
+	Binary string
+	// Namespace limits the controller to a namespace; empty watches all.
+	Namespace string
+}
+
+func (k Kubectl) run(ctx context.Context, args ...string) ([]byte, error) {
+	binary := k.Binary
+	if binary == "" {
+		binary = "kubectl"
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := command(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", binary, args[0], err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// List returns the resources of the namespace, or of all namespaces.
+func (k Kubectl) List(ctx context.Context) ([]RepoProtectionPolicy, error) {
+	args := []string{"get", Resource + "." + Group, "--output=json"}
+	if k.Namespace == "" {
+		args = append(args, "--all-namespaces")
+	} else {
+		args = append(args, "--namespace="+k.Namespace)
+	}
+	out, err := k.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []RepoProtectionPolicy `json:"items"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parsing the %s: %w", Resource, err)
+	}
+	return list.Items, nil
+}
+
+// UpdateStatus writes the status of the resource through the status
+// subresource, leaving the spec alone.
+func (k Kubectl) UpdateStatus(ctx context.Context, p RepoProtectionPolicy) error {
+	patch, err := json.Marshal(map[string]Status{"status": p.Status})
+	if err != nil {
+		return err
+	}
+	_, err = k.run(ctx, "patch", Resource+"."+Group, p.Metadata.Name,
+		"--namespace="+p.Metadata.Namespace, "--subresource=status", "--type=merge", "--patch="+string(patch))
+	return err
+}
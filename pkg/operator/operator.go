@@ -0,0 +1,240 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package operator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+// Group, Version and Resource identify the RepoProtectionPolicy custom
+// resource of the CRD.
+const (
+	Group    = "repo-protection-sync.arush-sal.github.io"
+	Version  = "v1alpha1"
+	Resource = "repoprotectionpolicies"
+)
+
+// ConditionReady is the condition reporting whether the last reconciliation
+// applied the policy.
+const ConditionReady = "Ready"
+
+// RepoProtectionPolicy is a policy to keep applied to the repositories of an
+// organization, as stored in the cluster.
+type RepoProtectionPolicy struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     Spec     `json:"spec"`
+	Status   Status   `json:"status"`
+}
+
+// Metadata holds the object metadata the controller uses.
+type Metadata struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Generation int64  `json:"generation"`
+}
+
+// Spec is the desired state: a policy of the configuration file along with
+// the organization it applies to.
+type Spec struct {
+	Owner      string                 `json:"owner"`
+	Source     string                 `json:"source,omitempty"`
+	Protection map[string]interface{} `json:"protection,omitempty"`
+	Selector   Selector               `json:"selector,omitempty"`
+}
+
+// Selector picks the repositories of the owner the policy applies to, like
+// the selector of a policy of the configuration file.
+type Selector struct {
+	Topics     []string          `json:"topics,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Name       string            `json:"name,omitempty"`
+}
+
+// Status is the observed state the controller writes back. Targets and
+// Failures are always written, so the merge patch clears the failures of
+// the previous reconciliation.
+type Status struct {
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	LastSync           *time.Time  `json:"lastSync,omitempty"`
+	Targets            int         `json:"targets"`
+	Failures           []string    `json:"failures"`
+	Conditions         []Condition `json:"conditions,omitempty"`
+}
+
+// Condition follows the conventions of the status conditions of Kubernetes.
+type Condition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason"`
+	Message            string    `json:"message"`
+	ObservedGeneration int64     `json:"observedGeneration"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// Key returns the namespace/name of the resource.
+func (p RepoProtectionPolicy) Key() string {
+	return p.Metadata.Namespace + "/" + p.Metadata.Name
+}
+
+// Policy returns the spec as a policy of the configuration file, named
+// after the resource.
+func (p RepoProtectionPolicy) Policy() (config.Policy, error) {
+	if p.Spec.Owner == "" {
+		return config.Policy{}, fmt.Errorf("%s has no owner", p.Key())
+	}
+	if (p.Spec.Source == "") == (p.Spec.Protection == nil) {
+		return config.Policy{}, fmt.Errorf("%s must set exactly one of source and protection", p.Key())
+	}
+	return config.Policy{
+		Name:       p.Key(),
+		Source:     p.Spec.Source,
+		Protection: p.Spec.Protection,
+		Selector: config.Selector{
+			Topics:     p.Spec.Selector.Topics,
+			Properties: p.Spec.Selector.Properties,
+			Name:       p.Spec.Selector.Name,
+		},
+	}, nil
+}
+
+// SetCondition sets the condition of its type, keeping the transition time
+// when the status didn't change.
+func SetCondition(conditions []Condition, c Condition) []Condition {
+	for i, existing := range conditions {
+		if existing.Type != c.Type {
+			continue
+		}
+		if existing.Status == c.Status {
+			c.LastTransitionTime = existing.LastTransitionTime
+		}
+		conditions[i] = c
+		return conditions
+	}
+	return append(conditions, c)
+}
+
+// Kube reads the resources and writes their status.
+type Kube interface {
+	List(ctx context.Context) ([]RepoProtectionPolicy, error)
+	UpdateStatus(ctx context.Context, p RepoProtectionPolicy) error
+}
+
+// ReconcileFunc applies a policy to the repositories of its owner and
+// returns the summary of the sync.
+type ReconcileFunc func(ctx context.Context, owner string, p config.Policy) (notify.Summary, error)
+
+// Controller keeps the resources applied. It polls the resources every
+// Poll, and reconciles those whose spec changed since they were last
+// reconciled, or that weren't reconciled for Resync, to revert the changes
+// made on GitHub.
+type Controller struct {
+	Kube      Kube
+	Reconcile ReconcileFunc
+	Poll      time.Duration
+	Resync    time.Duration
+	// now is replaced by tests.
+	now func() time.Time
+}
+
+// Run reconciles until ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Poll)
+	defer ticker.Stop()
+	for {
+		if err := c.reconcileAll(ctx); err != nil {
+			log.Printf("Error listing the %s: %v\n", Resource, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcileAll reconciles the resources that are due.
+func (c *Controller) reconcileAll(ctx context.Context) error {
+	policies, err := c.Kube.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !c.due(p) {
+			continue
+		}
+		c.reconcile(ctx, &p)
+		if err := c.Kube.UpdateStatus(ctx, p); err != nil {
+			log.Printf("Error updating the status of %s: %v\n", p.Key(), err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// due tells whether the resource needs reconciling.
+func (c *Controller) due(p RepoProtectionPolicy) bool {
+	return p.Status.ObservedGeneration != p.Metadata.Generation ||
+		p.Status.LastSync == nil ||
+		c.clock().Sub(*p.Status.LastSync) >= c.Resync
+}
+
+// reconcile applies the resource and records the outcome in its status.
+func (c *Controller) reconcile(ctx context.Context, p *RepoProtectionPolicy) {
+	now := c.clock()
+	p.Status.ObservedGeneration = p.Metadata.Generation
+	p.Status.LastSync = &now
+	ready := Condition{Type: ConditionReady, ObservedGeneration: p.Metadata.Generation, LastTransitionTime: now}
+
+	pol, err := p.Policy()
+	if err != nil {
+		ready.Status, ready.Reason, ready.Message = "False", "InvalidSpec", err.Error()
+		p.Status.Conditions = SetCondition(p.Status.Conditions, ready)
+		return
+	}
+	summary, err := c.Reconcile(ctx, p.Spec.Owner, pol)
+	p.Status.Targets = summary.Targets
+	p.Status.Failures = nil
+	for _, f := range summary.Failures {
+		p.Status.Failures = append(p.Status.Failures, f.Repo)
+	}
+	switch {
+	case err != nil:
+		ready.Status, ready.Reason, ready.Message = "False", "SyncFailed", err.Error()
+	case len(summary.Failures) > 0:
+		ready.Status, ready.Reason = "False", "PartiallyApplied"
+		ready.Message = fmt.Sprintf("%d of %d repositories failed: %s", len(summary.Failures), summary.Targets, strings.Join(p.Status.Failures, ", "))
+	default:
+		ready.Status, ready.Reason = "True", "Synced"
+		ready.Message = fmt.Sprintf("Applied to %d repositories", summary.Targets)
+	}
+	p.Status.Conditions = SetCondition(p.Status.Conditions, ready)
+}
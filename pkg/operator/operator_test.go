@@ -0,0 +1,135 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package operator
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+)
+
+type fakeKube struct {
+	policies []RepoProtectionPolicy
+	updated  map[string]Status
+}
+
+func (f *fakeKube) List(ctx context.Context) ([]RepoProtectionPolicy, error) {
+	return f.policies, nil
+}
+
+func (f *fakeKube) UpdateStatus(ctx context.Context, p RepoProtectionPolicy) error {
+	f.updated[p.Key()] = p.Status
+	return nil
+}
+
+func resource(name string, generation int64, spec Spec, status Status) RepoProtectionPolicy {
+	return RepoProtectionPolicy{Metadata: Metadata{Name: name, Namespace: "github", Generation: generation}, Spec: spec, Status: status}
+}
+
+func TestReconcileAll(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Minute)
+	stale := now.Add(-2 * time.Hour)
+	spec := Spec{Owner: "octo", Source: "baseline"}
+	kube := &fakeKube{updated: map[string]Status{}, policies: []RepoProtectionPolicy{
+		resource("unchanged", 1, spec, Status{ObservedGeneration: 1, LastSync: &recent}),
+		resource("edited", 2, spec, Status{ObservedGeneration: 1, LastSync: &recent}),
+		resource("stale", 1, Spec{Owner: "broken", Source: "baseline"}, Status{ObservedGeneration: 1, LastSync: &stale}),
+		resource("invalid", 1, Spec{Owner: "octo"}, Status{}),
+	}}
+	var reconciled []string
+	c := &Controller{Kube: kube, Resync: time.Hour, now: func() time.Time { return now },
+		Reconcile: func(ctx context.Context, owner string, p config.Policy) (notify.Summary, error) {
+			reconciled = append(reconciled, p.Name)
+			if owner == "broken" {
+				return notify.Summary{}, errors.New("preflight: bad credentials")
+			}
+			return notify.Summary{Targets: 3, Failures: []notify.Failure{{Repo: "legacy"}}}, nil
+		}}
+	if err := c.reconcileAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(reconciled, " ") != "github/edited github/stale" {
+		t.Errorf("reconciled %v", reconciled)
+	}
+	if _, ok := kube.updated["github/unchanged"]; ok {
+		t.Error("updated the status of a resource that wasn't due")
+	}
+	want := map[string]string{"github/edited": "PartiallyApplied", "github/stale": "SyncFailed", "github/invalid": "InvalidSpec"}
+	for key, reason := range want {
+		status := kube.updated[key]
+		if len(status.Conditions) != 1 || status.Conditions[0].Status != "False" || status.Conditions[0].Reason != reason {
+			t.Errorf("conditions of %s = %+v, want the reason %s", key, status.Conditions, reason)
+		}
+	}
+	if edited := kube.updated["github/edited"]; edited.ObservedGeneration != 2 || edited.Targets != 3 || len(edited.Failures) != 1 || !edited.LastSync.Equal(now) {
+		t.Errorf("status of github/edited = %+v", edited)
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	before := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	after := before.Add(time.Hour)
+	conditions := []Condition{{Type: ConditionReady, Status: "True", LastTransitionTime: before}}
+
+	conditions = SetCondition(conditions, Condition{Type: ConditionReady, Status: "True", Reason: "Synced", LastTransitionTime: after})
+	if len(conditions) != 1 || !conditions[0].LastTransitionTime.Equal(before) || conditions[0].Reason != "Synced" {
+		t.Errorf("unchanged status moved the transition time: %+v", conditions)
+	}
+	conditions = SetCondition(conditions, Condition{Type: ConditionReady, Status: "False", LastTransitionTime: after})
+	if len(conditions) != 1 || !conditions[0].LastTransitionTime.Equal(after) {
+		t.Errorf("changed status kept the transition time: %+v", conditions)
+	}
+}
+
+func TestKubectl(t *testing.T) {
+	var calls []string
+	command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		if args[0] == "get" {
+			return exec.CommandContext(ctx, "echo", `{"items":[{"metadata":{"name":"baseline","namespace":"github","generation":3},"spec":{"owner":"octo","source":"baseline"}}]}`)
+		}
+		return exec.CommandContext(ctx, "true")
+	}
+	t.Cleanup(func() { command = exec.CommandContext })
+
+	k := Kubectl{Namespace: "github"}
+	policies, err := k.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 || policies[0].Key() != "github/baseline" || policies[0].Metadata.Generation != 3 || policies[0].Spec.Owner != "octo" {
+		t.Errorf("List() = %+v", policies)
+	}
+	policies[0].Status.Targets = 2
+	if err := k.UpdateStatus(context.Background(), policies[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"kubectl get repoprotectionpolicies.repo-protection-sync.arush-sal.github.io --output=json --namespace=github",
+		`kubectl patch repoprotectionpolicies.repo-protection-sync.arush-sal.github.io baseline --namespace=github --subresource=status --type=merge --patch={"status":{"targets":2,"failures":null}}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+)
+
+// ErrStale is returned by Apply when the live state of a target changed
+// after the plan was created.
+var ErrStale = errors.New("the live state changed since the plan was created")
+
+// Apply executes exactly the mutations of the plan. It refuses to change
+// anything when the live state of any target no longer matches the state the
+// plan was created against. A failing change skips the remaining changes of
+// its repository; the errors are returned keyed by repository name.
+func Apply(ctx context.Context, client *ghclient.Client, p *Plan) (map[string]error, error) {
+	var stale []string
+	for _, c := range p.Changes {
+		current, err := liveFingerprint(ctx, client, p.Owner, c)
+		if err != nil {
+			return nil, fmt.Errorf("checking %s of %s: %w", c.Action, c.Repo, err)
+		}
+		if current != c.Fingerprint {
+			stale = append(stale, fmt.Sprintf("%s (%s)", c.Repo, c.Action))
+		}
+	}
+	if len(stale) > 0 {
+		return nil, fmt.Errorf("%w: %s; create a new plan", ErrStale, strings.Join(stale, ", "))
+	}
+
+	failures := make(map[string]error)
+	for _, c := range p.Changes {
+		if failures[c.Repo] != nil {
+			continue
+		}
+		if err := execute(ctx, client, p.Owner, c); err != nil {
+			log.Printf("Error applying %s to repo %s: %v\n", c.Action, c.Repo, err)
+			failures[c.Repo] = fmt.Errorf("%s: %w", c.Action, err)
+			continue
+		}
+		logging.Infof("Applied %s %s\n", c.Method, c.Path)
+	}
+	return failures, nil
+}
+
+// liveFingerprint fingerprints the current state of the object a change
+// mutates, the same way Build did.
+func liveFingerprint(ctx context.Context, client *ghclient.Client, owner string, c Change) (string, error) {
+	switch c.Action {
+	case UpdateBranchProtection, RequireSignatures:
+		current, err := getter.FetchBranchProtection(ctx, client.Repositories, owner, c.Repo, c.Branch)
+		if err != nil {
+			return "", err
+		}
+		return protectionFingerprint(current), nil
+	case CreateRuleset:
+		existing, err := helpers.TargetRulesets(ctx, client.Repositories, owner, c.Repo)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := existing[c.Ruleset.Name]; ok {
+			return "present", nil
+		}
+		return fingerprint(nil), nil
+	case UpdateRuleset:
+		current, err := getter.FetchRuleset(ctx, client.Repositories, owner, c.Repo, c.Ruleset.GetID())
+		if err != nil {
+			return "", err
+		}
+		return fingerprint(rulesetBody(current)), nil
+	}
+	return "", fmt.Errorf("unknown action %q", c.Action)
+}
+
+func execute(ctx context.Context, client *ghclient.Client, owner string, c Change) error {
+	var status int
+	switch c.Action {
+	case UpdateBranchProtection:
+		_, resp, err := client.Repositories.UpdateBranchProtection(ctx, owner, c.Repo, c.Branch, c.BranchProtection)
+		if err != nil {
+			return err
+		}
+		status = resp.StatusCode
+	case RequireSignatures:
+		_, resp, err := client.Repositories.RequireSignaturesOnProtectedBranch(ctx, owner, c.Repo, c.Branch)
+		if err != nil {
+			return err
+		}
+		status = resp.StatusCode
+	case CreateRuleset:
+		_, resp, err := client.Repositories.CreateRuleset(ctx, owner, c.Repo, c.Ruleset)
+		if err != nil {
+			return err
+		}
+		status = resp.StatusCode
+	case UpdateRuleset:
+		_, resp, err := client.Repositories.UpdateRuleset(ctx, owner, c.Repo, c.Ruleset.GetID(), c.Ruleset)
+		if err != nil {
+			return err
+		}
+		status = resp.StatusCode
+	default:
+		return fmt.Errorf("unknown action %q", c.Action)
+	}
+	return helpers.HTTPStatusCodeCheck(status)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Build plans the mutations needed to bring the targets in line with the
+// protection of a policy, reading their live state without changing it.
+func Build(ctx context.Context, client *ghclient.Client, owner, policy string, protections *types.RepoProtection, targets []*github.Repository, opts setter.Options) ([]Change, error) {
+	var changes []Change
+	for _, repo := range targets {
+		repoChanges, err := buildRepo(ctx, client, owner, repo, protections, opts)
+		if err != nil {
+			return nil, fmt.Errorf("planning %s/%s: %w", owner, repo.GetName(), err)
+		}
+		for i := range repoChanges {
+			repoChanges[i].Policy = policy
+		}
+		changes = append(changes, repoChanges...)
+	}
+	return changes, nil
+}
+
+func buildRepo(ctx context.Context, client *ghclient.Client, owner string, repo *github.Repository, protections *types.RepoProtection, opts setter.Options) ([]Change, error) {
+	var changes []Change
+	name, branch := repo.GetName(), repo.GetDefaultBranch()
+
+	if branch != "" {
+		desired, err := setter.Desired(ctx, repo, protections, opts)
+		if err != nil {
+			return nil, err
+		}
+		current, err := getter.FetchBranchProtection(ctx, client.Repositories, owner, name, branch)
+		switch {
+		case setter.IsBranchNotFound(err):
+			// An empty repository, only its rulesets can be planned
+		case setter.IsUnsupportedPlan(err):
+			log.Printf("Skipping repo %s: branch protection isn't available for private repositories on the plan of %s\n", name, owner)
+			return nil, nil
+		case err != nil:
+			return nil, err
+		default:
+			path := fmt.Sprintf("/repos/%s/%s/branches/%s/protection", owner, name, branch)
+			fp := protectionFingerprint(current)
+			if diff := setter.Diff(desired, current); len(diff) > 0 {
+				c := Change{Repo: name, Action: UpdateBranchProtection, Method: "PUT", Path: path, Branch: branch, BranchProtection: desired, Fingerprint: fp}
+				for _, d := range diff {
+					c.Diff = append(c.Diff, FieldChange{Field: d.Field, From: d.Applied, To: d.Requested})
+				}
+				changes = append(changes, c)
+			}
+			if protections.BranchProtection.SignedCommits && setter.SignaturesSelected(opts.Fields) && !current.GetRequiredSignatures().GetEnabled() {
+				changes = append(changes, Change{Repo: name, Action: RequireSignatures, Method: "POST", Path: path + "/required_signatures", Branch: branch, Fingerprint: fp})
+			}
+		}
+	}
+
+	existing, err := helpers.TargetRulesets(ctx, client.Repositories, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range protections.Rulesets {
+		body := rulesetBody(source)
+		target, ok := existing[source.Name]
+		if !ok {
+			changes = append(changes, Change{
+				Repo: name, Action: CreateRuleset, Method: "POST",
+				Path:        fmt.Sprintf("/repos/%s/%s/rulesets", owner, name),
+				Ruleset:     body,
+				Diff:        diff.Ruleset(nil, body),
+				Fingerprint: fingerprint(nil),
+			})
+			continue
+		}
+		full, err := getter.FetchRuleset(ctx, client.Repositories, owner, name, target.GetID())
+		if err != nil {
+			return nil, err
+		}
+		if sameRuleset(body, rulesetBody(full)) {
+			continue
+		}
+		body.ID = full.ID
+		changes = append(changes, Change{
+			Repo: name, Action: UpdateRuleset, Method: "PUT",
+			Path:        fmt.Sprintf("/repos/%s/%s/rulesets/%d", owner, name, full.GetID()),
+			Ruleset:     body,
+			Diff:        diff.Ruleset(rulesetBody(full), body),
+			Fingerprint: fingerprint(rulesetBody(full)),
+		})
+	}
+	return changes, nil
+}
+
+// rulesetBody keeps the settings of a ruleset, dropping the identifiers and
+// links specific to the repository it was read from.
+func rulesetBody(rs *github.Ruleset) *github.Ruleset {
+	return &github.Ruleset{
+		Name:         rs.Name,
+		Target:       rs.Target,
+		Enforcement:  rs.Enforcement,
+		BypassActors: rs.BypassActors,
+		Conditions:   rs.Conditions,
+		Rules:        rs.Rules,
+	}
+}
+
+func sameRuleset(a, b *github.Ruleset) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
+// protectionFingerprint hashes the protection of a branch, nil when the
+// branch is unprotected.
+func protectionFingerprint(p *github.Protection) string {
+	if p == nil {
+		return fingerprint(nil)
+	}
+	return fingerprint(p)
+}
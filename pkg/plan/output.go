@@ -0,0 +1,73 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/color"
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+)
+
+// dryRunChange is the machine-readable shape of a planned change.
+type dryRunChange struct {
+	Repo    string        `json:"repo"`
+	Branch  string        `json:"branch,omitempty"`
+	Policy  string        `json:"policy,omitempty"`
+	Action  string        `json:"action"`
+	Ruleset string        `json:"ruleset,omitempty"`
+	Fields  []dryRunField `json:"fields,omitempty"`
+}
+
+type dryRunField struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// WriteJSON writes the changes as a JSON array with the before and after
+// value of every changed field, for pipelines to gate on.
+func WriteJSON(w io.Writer, changes []Change) error {
+	out := make([]dryRunChange, 0, len(changes))
+	for _, c := range changes {
+		change := dryRunChange{Repo: c.Repo, Branch: c.Branch, Policy: c.Policy, Action: c.Action}
+		if c.Ruleset != nil {
+			change.Ruleset = c.Ruleset.Name
+		}
+		for _, d := range c.Diff {
+			change.Fields = append(change.Fields, dryRunField{Field: d.Field, Before: d.From, After: d.To})
+		}
+		out = append(out, change)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteText writes the changes for a human to review, colored on a terminal.
+func WriteText(w io.Writer, owner string, changes []Change) error {
+	colored := color.Enabled(w)
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s/%s: %s %s\n", owner, c.Repo, c.Method, c.Path)
+		if err := diff.Write(w, "    ", c.Diff, colored); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "Plan: %d changes\n", len(changes))
+	return err
+}
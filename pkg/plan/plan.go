@@ -0,0 +1,149 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// Version is the version of the plan file format.
+const Version = 1
+
+// The API mutations a plan can contain.
+const (
+	UpdateBranchProtection = "update_branch_protection"
+	RequireSignatures      = "require_signatures"
+	CreateRuleset          = "create_ruleset"
+	UpdateRuleset          = "update_ruleset"
+)
+
+// Plan is the reviewable set of API mutations a sync would make.
+type Plan struct {
+	Version int    `json:"version"`
+	Owner   string `json:"owner"`
+	// RunID identifies the run that created the plan.
+	RunID   string    `json:"run_id,omitempty"`
+	Created time.Time `json:"created"`
+	Changes []Change  `json:"changes"`
+}
+
+// Change is a single API mutation on a target repository.
+type Change struct {
+	Repo   string `json:"repo"`
+	Policy string `json:"policy,omitempty"`
+	Action string `json:"action"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Branch string `json:"branch,omitempty"`
+	// Diff lists the settings changed by a branch protection update.
+	Diff             []FieldChange             `json:"diff,omitempty"`
+	BranchProtection *github.ProtectionRequest `json:"branch_protection,omitempty"`
+	Ruleset          *github.Ruleset           `json:"ruleset,omitempty"`
+	// Fingerprint identifies the live state the change was planned against.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// UnmarshalJSON decodes a change, keeping the rules of its ruleset that
+// go-github can't decode.
+func (c *Change) UnmarshalJSON(data []byte) error {
+	type change Change
+	var raw struct {
+		change
+		Ruleset json.RawMessage `json:"ruleset,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Change(raw.change)
+	if len(raw.Ruleset) > 0 && string(raw.Ruleset) != "null" {
+		rs, err := ghclient.DecodeRuleset(raw.Ruleset)
+		if err != nil {
+			return err
+		}
+		c.Ruleset = rs
+	}
+	return nil
+}
+
+// FieldChange is a setting changed from its current value.
+type FieldChange = diff.Delta
+
+// Load reads a plan file.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// Parse decodes the plan read from path.
+func Parse(path string, data []byte) (*Plan, error) {
+	p := new(Plan)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("%s: unsupported plan version %d", path, p.Version)
+	}
+	return p, nil
+}
+
+// Save writes the plan to path.
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Repos returns the names of the repositories the plan changes, in the
+// order of their first change.
+func (p *Plan) Repos() []string {
+	var repos []string
+	seen := make(map[string]bool)
+	for _, c := range p.Changes {
+		if !seen[c.Repo] {
+			seen[c.Repo] = true
+			repos = append(repos, c.Repo)
+		}
+	}
+	return repos
+}
+
+// fingerprint hashes a piece of live state. A missing object has a fixed
+// fingerprint so its later creation is detected too.
+func fingerprint(v interface{}) string {
+	if v == nil {
+		return "absent"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "unhashable"
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
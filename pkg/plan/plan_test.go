@@ -0,0 +1,160 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+func source() *types.RepoProtection {
+	return &types.RepoProtection{
+		BranchProtection: &types.BranchProtection{EnforceAdmins: true},
+		Rulesets:         []*github.Ruleset{{ID: github.Int64(1), Name: "tags", Enforcement: "active"}},
+	}
+}
+
+func TestBuildAndApply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+	target := &github.Repository{Name: github.String("api"), DefaultBranch: github.String("main")}
+	current := &github.Protection{EnforceAdmins: &github.AdminEnforcement{Enabled: false}}
+
+	repos.EXPECT().GetBranchProtection(gomock.Any(), "octo", "api", "main").Return(current, okResponse(), nil).Times(2)
+	repos.EXPECT().GetAllRulesets(gomock.Any(), "octo", "api", false).Return(nil, okResponse(), nil).Times(2)
+
+	changes, err := Build(context.Background(), client, "octo", "services", source(), []*github.Repository{target}, setter.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Action != UpdateBranchProtection || changes[1].Action != CreateRuleset {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if changes[0].Policy != "services" || changes[0].Path != "/repos/octo/api/branches/main/protection" {
+		t.Errorf("unexpected change %+v", changes[0])
+	}
+
+	// Round trip through the plan file
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := (&Plan{Version: Version, Owner: "octo", Changes: changes}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+	repos.EXPECT().CreateRuleset(gomock.Any(), "octo", "api", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, rs *github.Ruleset) (*github.Ruleset, *github.Response, error) {
+			if rs.Name != "tags" || rs.ID != nil {
+				t.Errorf("unexpected ruleset %+v", rs)
+			}
+			return rs, okResponse(), nil
+		})
+
+	failures, err := Apply(context.Background(), client, p)
+	if err != nil || len(failures) != 0 {
+		t.Errorf("got %v, %v", failures, err)
+	}
+}
+
+func TestApplyRefusesStalePlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	repos.EXPECT().GetBranchProtection(gomock.Any(), "octo", "api", "main").
+		Return(&github.Protection{EnforceAdmins: &github.AdminEnforcement{Enabled: true}}, okResponse(), nil)
+
+	p := &Plan{Version: Version, Owner: "octo", Changes: []Change{{
+		Repo: "api", Branch: "main", Action: UpdateBranchProtection,
+		BranchProtection: &github.ProtectionRequest{},
+		Fingerprint:      protectionFingerprint(&github.Protection{}),
+	}}}
+	if _, err := Apply(context.Background(), client, p); !errors.Is(err, ErrStale) {
+		t.Errorf("got %v, want ErrStale", err)
+	}
+}
+
+func TestLoadPushRuleset(t *testing.T) {
+	params := json.RawMessage(`{"max_file_size":10}`)
+	p := &Plan{Version: Version, Owner: "octo", Changes: []Change{{
+		Repo: "api", Action: CreateRuleset,
+		Ruleset: &github.Ruleset{Name: "push", Target: github.String("push"), Rules: []*github.RepositoryRule{{Type: "max_file_size", Parameters: &params}}},
+	}}}
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rule := loaded.Changes[0].Ruleset.Rules[0]
+	if rule.Type != "max_file_size" || string(*rule.Parameters) != string(params) {
+		t.Errorf("got rule %s %s", rule.Type, *rule.Parameters)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	changes := []Change{
+		{Repo: "api", Branch: "main", Action: UpdateBranchProtection, Diff: []FieldChange{{Field: "enforce_admins", From: "false", To: "true"}}},
+		{Repo: "api", Action: CreateRuleset, Ruleset: &github.Ruleset{Name: "tags"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+	}
+	if len(out) != 2 || out[1]["ruleset"] != "tags" {
+		t.Fatalf("unexpected output %s", buf.String())
+	}
+	fields := out[0]["fields"].([]interface{})
+	if field := fields[0].(map[string]interface{}); field["before"] != "false" || field["after"] != "true" {
+		t.Errorf("unexpected field %v", field)
+	}
+
+	// No changes is an empty array rather than null
+	buf.Reset()
+	WriteJSON(&buf, nil)
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("got %q", buf.String())
+	}
+}
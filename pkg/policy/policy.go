@@ -0,0 +1,156 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Resolve returns the protection applied by a policy: the protection and
+// rulesets of its source repository, or its inline protection. A policy
+// with both, as inherited with extends, overrides the fields of the source.
+func Resolve(ctx context.Context, client *ghclient.Client, owner string, p config.Policy) (*types.RepoProtection, error) {
+	if p.Source == "" {
+		return Inline(p.Protection)
+	}
+	sourceOwner, source := SourceRepo(owner, p)
+	rp, err := getter.FetchRepoProtections(ctx, client, sourceOwner, source)
+	if err != nil || p.Protection == nil {
+		return rp, err
+	}
+	return Override(rp, p.Protection)
+}
+
+// Override returns a copy of rp whose branch protection has the fields of
+// overrides replacing its own.
+func Override(rp *types.RepoProtection, overrides map[string]interface{}) (*types.RepoProtection, error) {
+	var current map[string]interface{}
+	if rp.BranchProtection != nil {
+		data, err := json.Marshal(rp.BranchProtection.GitHub())
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &current); err != nil {
+			return nil, err
+		}
+	}
+	inline, err := Inline(config.MergeProtection(current, overrides))
+	if err != nil {
+		return nil, err
+	}
+	overridden := *rp
+	overridden.BranchProtection = inline.BranchProtection
+	return &overridden, nil
+}
+
+// MaterializeInherited adds a copy of every ruleset the source inherits from
+// its organization to the rulesets applied to the targets, as rulesets of
+// their own. A ruleset named like one of the source is left out.
+func MaterializeInherited(rp *types.RepoProtection) {
+	names := make(map[string]bool, len(rp.Rulesets))
+	for _, ruleset := range rp.Rulesets {
+		names[ruleset.Name] = true
+	}
+	for _, inherited := range rp.InheritedRulesets {
+		if names[inherited.Name] {
+			log.Printf("Warning: not materializing ruleset %q of %s, the source has a ruleset of the same name\n", inherited.Name, inherited.Source)
+			continue
+		}
+		ruleset := *inherited
+		ruleset.ID, ruleset.SourceType, ruleset.Source, ruleset.NodeID, ruleset.Links = nil, nil, "", nil, nil
+		rp.Rulesets = append(rp.Rulesets, &ruleset)
+		logging.Infof("Materializing ruleset %q inherited from %s\n", inherited.Name, inherited.Source)
+	}
+}
+
+// SourceRepo returns the owner and name of the source of p, whose owner
+// defaults to the owner of the targets.
+func SourceRepo(owner string, p config.Policy) (string, string) {
+	if sourceOwner, source, ok := strings.Cut(p.Source, "/"); ok {
+		return sourceOwner, source
+	}
+	return owner, p.Source
+}
+
+// Inline converts a protection defined in the configuration file, in the
+// shape the GitHub API returns branch protection, to a RepoProtection.
+func Inline(definition map[string]interface{}) (*types.RepoProtection, error) {
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	protection := new(github.Protection)
+	if err := json.Unmarshal(data, protection); err != nil {
+		return nil, fmt.Errorf("invalid inline protection: %w", err)
+	}
+	return &types.RepoProtection{BranchProtection: types.NewBranchProtection(protection)}, nil
+}
+
+// NeedsProperties reports whether any of the policies selects repositories
+// by custom property.
+func NeedsProperties(policies []config.Policy) bool {
+	for _, p := range policies {
+		if len(p.Selector.Properties) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the repositories matched by the selector, given the custom
+// property values of the repositories keyed by repository name.
+func Select(repos []*github.Repository, sel config.Selector, properties map[string]map[string]string) []*github.Repository {
+	// The name selector is validated when the configuration is loaded
+	name := regexp.MustCompile(sel.Name)
+
+	selected := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if !name.MatchString(repo.GetName()) || !hasAnyTopic(repo, sel.Topics) {
+			continue
+		}
+		selected = append(selected, repo)
+	}
+	if len(sel.Properties) > 0 {
+		selected = getter.FilterByProperties(selected, properties, sel.Properties)
+	}
+	return selected
+}
+
+func hasAnyTopic(repo *github.Repository, topics []string) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, want := range topics {
+		for _, topic := range repo.Topics {
+			if topic == want {
+				return true
+			}
+		}
+	}
+	return false
+}
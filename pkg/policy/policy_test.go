@@ -0,0 +1,138 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package policy
+
+import (
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestInline(t *testing.T) {
+	rp, err := Inline(map[string]interface{}{
+		"enforce_admins": map[string]interface{}{"enabled": true},
+		"required_pull_request_reviews": map[string]interface{}{
+			"required_approving_review_count": 2,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := rp.BranchProtection
+	if !p.EnforceAdmins || p.Reviews.RequiredApprovals != 2 {
+		t.Errorf("inline protection not converted: %+v", p)
+	}
+
+	if _, err := Inline(map[string]interface{}{"enforce_admins": "yes"}); err == nil {
+		t.Error("expected an error for a malformed definition")
+	}
+}
+
+func TestOverride(t *testing.T) {
+	rp := &types.RepoProtection{
+		BranchProtection: &types.BranchProtection{
+			EnforceAdmins: true,
+			Reviews:       &types.Reviews{CodeOwners: true, RequiredApprovals: 2},
+		},
+		Rulesets: []*github.Ruleset{{Name: "main"}},
+	}
+	overridden, err := Override(rp, map[string]interface{}{
+		"required_pull_request_reviews": map[string]interface{}{"required_approving_review_count": 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reviews := overridden.BranchProtection.Reviews
+	if reviews.RequiredApprovals != 1 || !reviews.CodeOwners || !overridden.BranchProtection.EnforceAdmins {
+		t.Errorf("protection not overridden field by field: %+v", overridden.BranchProtection)
+	}
+	if len(overridden.Rulesets) != 1 || rp.BranchProtection.Reviews.RequiredApprovals != 2 {
+		t.Errorf("Override() dropped the rulesets or modified the source: %+v", overridden)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	repos := []*github.Repository{
+		{Name: github.String("svc-payments"), Topics: []string{"service"}},
+		{Name: github.String("svc-docs"), Topics: []string{"docs"}},
+		{Name: github.String("lib-http"), Topics: []string{"service"}},
+	}
+	properties := map[string]map[string]string{"svc-payments": {"tier": "1"}}
+
+	tests := []struct {
+		name string
+		sel  config.Selector
+		want []string
+	}{
+		{name: "empty", want: []string{"svc-payments", "svc-docs", "lib-http"}},
+		{name: "regex", sel: config.Selector{Name: "^svc-"}, want: []string{"svc-payments", "svc-docs"}},
+		{name: "topics", sel: config.Selector{Topics: []string{"docs", "service"}}, want: []string{"svc-payments", "svc-docs", "lib-http"}},
+		{name: "all criteria", sel: config.Selector{Name: "^svc-", Topics: []string{"service"}, Properties: map[string]string{"tier": "1"}}, want: []string{"svc-payments"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Select(repos, tt.sel, properties)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d repos, want %v", len(got), tt.want)
+			}
+			for i, repo := range got {
+				if repo.GetName() != tt.want[i] {
+					t.Errorf("repo %d: got %s, want %s", i, repo.GetName(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSourceRepo(t *testing.T) {
+	tests := []struct {
+		source, owner, repo string
+	}{
+		{"template", "octo", "template"},
+		{"upstream/template", "upstream", "template"},
+	}
+	for _, tt := range tests {
+		owner, repo := SourceRepo("octo", config.Policy{Source: tt.source})
+		if owner != tt.owner || repo != tt.repo {
+			t.Errorf("%s: got %s/%s, want %s/%s", tt.source, owner, repo, tt.owner, tt.repo)
+		}
+	}
+}
+
+func TestMaterializeInherited(t *testing.T) {
+	org := github.String("Organization")
+	rp := &types.RepoProtection{
+		Rulesets: []*github.Ruleset{{ID: github.Int64(1), Name: "main"}},
+		InheritedRulesets: []*github.Ruleset{
+			{ID: github.Int64(2), Name: "tags", SourceType: org, Source: "octo"},
+			{ID: github.Int64(3), Name: "main", SourceType: org, Source: "octo"},
+		},
+	}
+
+	MaterializeInherited(rp)
+	if len(rp.Rulesets) != 2 {
+		t.Fatalf("got %d rulesets, want the source's and the inherited tags", len(rp.Rulesets))
+	}
+	tags := rp.Rulesets[1]
+	if tags.Name != "tags" || tags.ID != nil || tags.SourceType != nil || tags.Source != "" {
+		t.Errorf("got %+v, want a copy without the identity of the organization ruleset", tags)
+	}
+	if rp.InheritedRulesets[0].GetID() != 2 {
+		t.Error("the inherited ruleset was modified")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// Coverage sorts the targets by what a fine-grained token may do to them.
+type Coverage struct {
+	// Covered lists the repositories the token can administer.
+	Covered []string
+	// NoAdmin lists the repositories granted to the token without the
+	// Administration permission branch protection requires.
+	NoAdmin []string
+	// NotGranted lists the repositories the token wasn't granted at all.
+	NotGranted []string
+}
+
+// ProbeCoverage checks which of the targets a fine-grained token has the
+// Administration permission on and logs a summary. Unlike a classic token, such a token is
+// granted per repository, so it may cover only part of an organization.
+func ProbeCoverage(ctx context.Context, client ghclient.AccessLister, owner string, repos []*github.Repository) Coverage {
+	var c Coverage
+	for _, repo := range repos {
+		name := repo.GetName()
+		// Listing the teams of a repository requires admin access to it
+		_, _, err := client.ListTeams(ctx, owner, name, &github.ListOptions{PerPage: 1})
+		var ghErr *github.ErrorResponse
+		switch {
+		case err == nil:
+			c.Covered = append(c.Covered, name)
+		case errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusForbidden:
+			c.NoAdmin = append(c.NoAdmin, name)
+		case errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound:
+			c.NotGranted = append(c.NotGranted, name)
+		default:
+			log.Printf("Preflight: could not probe the access to %s/%s: %v\n", owner, name, err)
+		}
+	}
+
+	log.Printf("Preflight: the fine-grained token has the Administration permission on %d of %d target repositories\n", len(c.Covered), len(repos))
+	if len(c.NoAdmin) > 0 {
+		log.Printf("Preflight: %d repositories lack the Administration permission, branch protection and rulesets can't be changed:\n", len(c.NoAdmin))
+		for _, name := range c.NoAdmin {
+			log.Printf("  - %s/%s\n", owner, name)
+		}
+	}
+	if len(c.NotGranted) > 0 {
+		log.Printf("Preflight: %d repositories aren't granted to the token:\n", len(c.NotGranted))
+		for _, name := range c.NotGranted {
+			log.Printf("  - %s/%s\n", owner, name)
+		}
+	}
+	return c
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProbeCoverage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	access := mocks.NewMockAccessLister(ctrl)
+
+	status := func(code int) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: code}}
+	}
+	access.EXPECT().ListTeams(gomock.Any(), "octo", "api", gomock.Any()).Return(nil, okResponse(), nil)
+	access.EXPECT().ListTeams(gomock.Any(), "octo", "web", gomock.Any()).Return(nil, nil, status(http.StatusForbidden))
+	access.EXPECT().ListTeams(gomock.Any(), "octo", "infra", gomock.Any()).Return(nil, nil, status(http.StatusNotFound))
+
+	repos := []*github.Repository{{Name: github.String("api")}, {Name: github.String("web")}, {Name: github.String("infra")}}
+	got := ProbeCoverage(context.Background(), access, "octo", repos)
+	want := Coverage{Covered: []string{"api"}, NoAdmin: []string{"web"}, NotGranted: []string{"infra"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight
+
+import (
+	"context"
+	"log"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// Result collects the findings of the permission preflight.
+type Result struct {
+	// Orphaned lists the repositories without an active admin. They usually
+	// need an ownership fix before their protection can be managed.
+	Orphaned []string
+}
+
+// Run performs the permission preflight checks against the target
+// repositories and logs a report section for every finding.
+func Run(ctx context.Context, client ghclient.AccessLister, owner string, repos []*github.Repository) Result {
+	var result Result
+	for _, repo := range repos {
+		ok, err := hasActiveAdmin(ctx, client, owner, repo.GetName())
+		if err != nil {
+			log.Printf("Preflight: could not list the admins of %s/%s: %v\n", owner, repo.GetName(), err)
+			continue
+		}
+		if !ok {
+			result.Orphaned = append(result.Orphaned, repo.GetName())
+		}
+	}
+
+	if len(result.Orphaned) > 0 {
+		log.Printf("Preflight: %d repositories have no active admin:\n", len(result.Orphaned))
+		for _, name := range result.Orphaned {
+			log.Printf("  - %s/%s\n", owner, name)
+		}
+	}
+	return result
+}
+
+// hasActiveAdmin reports whether a team or a non-suspended user is a direct
+// admin of the repository. Organization owners are implicit admins of every
+// repository and deliberately not counted.
+func hasActiveAdmin(ctx context.Context, client ghclient.AccessLister, owner, repo string) (bool, error) {
+	teamOpts := &github.ListOptions{PerPage: 100}
+	for {
+		teams, resp, err := client.ListTeams(ctx, owner, repo, teamOpts)
+		if err != nil {
+			return false, err
+		}
+		for _, team := range teams {
+			if team.GetPermission() == "admin" {
+				return true, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		teamOpts.Page = resp.NextPage
+	}
+
+	userOpts := &github.ListCollaboratorsOptions{
+		Affiliation: "direct",
+		Permission:  "admin",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		users, resp, err := client.ListCollaborators(ctx, owner, repo, userOpts)
+		if err != nil {
+			return false, err
+		}
+		for _, user := range users {
+			if user.SuspendedAt == nil {
+				return true, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		userOpts.Page = resp.NextPage
+	}
+
+	return false, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+func TestRunFindsOrphanedRepos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	access := mocks.NewMockAccessLister(ctrl)
+
+	repos := []*github.Repository{
+		{Name: github.String("team-admin")},
+		{Name: github.String("user-admin")},
+		{Name: github.String("suspended-admin")},
+		{Name: github.String("nobody")},
+	}
+
+	access.EXPECT().ListTeams(gomock.Any(), "octo", "team-admin", gomock.Any()).
+		Return([]*github.Team{{Slug: github.String("core"), Permission: github.String("admin")}}, okResponse(), nil)
+
+	for _, name := range []string{"user-admin", "suspended-admin", "nobody"} {
+		access.EXPECT().ListTeams(gomock.Any(), "octo", name, gomock.Any()).
+			Return([]*github.Team{{Slug: github.String("readers"), Permission: github.String("pull")}}, okResponse(), nil)
+	}
+	access.EXPECT().ListCollaborators(gomock.Any(), "octo", "user-admin", gomock.Any()).
+		Return([]*github.User{{Login: github.String("alice")}}, okResponse(), nil)
+	access.EXPECT().ListCollaborators(gomock.Any(), "octo", "suspended-admin", gomock.Any()).
+		Return([]*github.User{{Login: github.String("bob"), SuspendedAt: &github.Timestamp{}}}, okResponse(), nil)
+	access.EXPECT().ListCollaborators(gomock.Any(), "octo", "nobody", gomock.Any()).
+		Return(nil, okResponse(), nil)
+
+	result := Run(context.Background(), access, "octo", repos)
+	if want := []string{"suspended-admin", "nobody"}; !reflect.DeepEqual(result.Orphaned, want) {
+		t.Errorf("got orphaned %v, want %v", result.Orphaned, want)
+	}
+}
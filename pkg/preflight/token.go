@@ -0,0 +1,117 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// scopesHeader lists the OAuth scopes of a classic token. Responses to App
+// and fine-grained tokens don't have it.
+const scopesHeader = "X-OAuth-Scopes"
+
+// Token checks before a run that the credentials can administer the
+// repositories of owner, so a missing scope fails once with a clear message
+// instead of once per repository. The scopes of a classic token are read
+// from the response headers; every kind of credentials is then probed with
+// an admin-only read of each of repos.
+func Token(ctx context.Context, rl ghclient.RateLimitReader, access ghclient.AccessLister, owner string, repos ...string) error {
+	_, response, err := rl.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("checking the credentials: %w", err)
+	}
+	if header, ok := response.Header[http.CanonicalHeaderKey(scopesHeader)]; ok {
+		if err := checkScopes(header); err != nil {
+			return err
+		}
+	}
+	for _, repo := range repos {
+		if err := probeAdmin(ctx, access, owner, repo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// probeAdmin fails unless the credentials can administer repo.
+func probeAdmin(ctx context.Context, access ghclient.AccessLister, owner, repo string) error {
+	// Listing the teams of a repository requires admin access to it
+	_, _, err := access.ListTeams(ctx, owner, repo, &github.ListOptions{PerPage: 1})
+	switch {
+	case err == nil:
+		return nil
+	case hasStatus(err, http.StatusForbidden):
+		return fmt.Errorf("the credentials can't administer %s/%s: a classic token needs admin rights on the repositories, a fine-grained token or GitHub App the Administration repository permission", owner, repo)
+	case hasStatus(err, http.StatusNotFound):
+		return fmt.Errorf("%s/%s doesn't exist or isn't visible to the credentials", owner, repo)
+	default:
+		return fmt.Errorf("checking the access to %s/%s: %w", owner, repo, err)
+	}
+}
+
+// Readable checks before a run that the credentials can read repo, the
+// source of a run whose targets are the only repositories they administer.
+func Readable(ctx context.Context, reader ghclient.BranchProtectionReader, owner, repo string) error {
+	_, _, err := reader.Get(ctx, owner, repo)
+	switch {
+	case err == nil:
+		return nil
+	case hasStatus(err, http.StatusNotFound), hasStatus(err, http.StatusForbidden):
+		return fmt.Errorf("the policy source %s/%s doesn't exist or isn't readable with the credentials", owner, repo)
+	default:
+		return fmt.Errorf("checking the access to %s/%s: %w", owner, repo, err)
+	}
+}
+
+// hasStatus tells whether err is an API error with the status code.
+func hasStatus(err error, code int) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == code
+}
+
+// checkScopes fails unless the scopes of a classic token include repo, which
+// branch protection of private repositories requires.
+func checkScopes(header []string) error {
+	var scopes []string
+	for _, value := range header {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	switch {
+	case slices.Contains(scopes, "repo"):
+		return nil
+	case slices.Contains(scopes, "public_repo"):
+		log.Println("Warning: the token only has the public_repo scope, private repositories can't be synced")
+		return nil
+	case len(scopes) == 0:
+		return errors.New("the token has no scopes: it needs the repo scope")
+	default:
+		return fmt.Errorf("the token is missing the repo scope (it has %s)", strings.Join(scopes, ", "))
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func scopesResponse(scopes ...string) *github.Response {
+	header := http.Header{}
+	for _, scope := range scopes {
+		header.Add(scopesHeader, scope)
+	}
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK, Header: header}}
+}
+
+func TestTokenScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *github.Response
+		wantErr  string
+	}{
+		{"repo scope", scopesResponse("read:org, repo"), ""},
+		{"public_repo scope", scopesResponse("public_repo"), ""},
+		{"missing repo scope", scopesResponse("read:org, gist"), "missing the repo scope (it has read:org, gist)"},
+		{"no scopes", scopesResponse(""), "has no scopes"},
+		{"app or fine-grained token", scopesResponse(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			rl := mocks.NewMockRateLimitReader(ctrl)
+			rl.EXPECT().Get(gomock.Any()).Return(&github.RateLimits{}, tt.response, nil)
+
+			err := Token(context.Background(), rl, mocks.NewMockAccessLister(ctrl), "octo")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenAdminProbe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rl := mocks.NewMockRateLimitReader(ctrl)
+	access := mocks.NewMockAccessLister(ctrl)
+
+	rl.EXPECT().Get(gomock.Any()).Return(&github.RateLimits{}, scopesResponse(), nil).Times(2)
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}, Message: "Must have admin rights to Repository."}
+	access.EXPECT().ListTeams(gomock.Any(), "octo", "template", gomock.Any()).Return(nil, nil, forbidden)
+	access.EXPECT().ListTeams(gomock.Any(), "octo", "template", gomock.Any()).Return(nil, okResponse(), nil)
+
+	err := Token(context.Background(), rl, access, "octo", "template")
+	if err == nil || !strings.Contains(err.Error(), "can't administer octo/template") {
+		t.Errorf("got %v, want the missing admin access to be reported", err)
+	}
+	if err := Token(context.Background(), rl, access, "octo", "template"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReadable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reader := mocks.NewMockBranchProtectionReader(ctrl)
+
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Not Found"}
+	reader.EXPECT().Get(gomock.Any(), "octo", "template").Return(nil, nil, notFound)
+	reader.EXPECT().Get(gomock.Any(), "octo", "template").Return(&github.Repository{}, okResponse(), nil)
+
+	if err := Readable(context.Background(), reader, "octo", "template"); err == nil || !strings.Contains(err.Error(), "isn't readable") {
+		t.Errorf("got %v, want the unreadable source to be reported", err)
+	}
+	if err := Readable(context.Background(), reader, "octo", "template"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
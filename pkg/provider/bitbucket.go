@@ -0,0 +1,376 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// BitbucketURL is the API of Bitbucket Cloud.
+const BitbucketURL = "https://api.bitbucket.org"
+
+// Kinds of Bitbucket branch restrictions; the require_ kinds are the merge
+// checks of pull requests.
+const (
+	restrictPush              = "push"
+	restrictForce             = "force"
+	restrictDelete            = "delete"
+	requireApprovals          = "require_approvals_to_merge"
+	requireDefaultReviewers   = "require_default_reviewer_approvals_to_merge"
+	requireNoChangesRequested = "require_no_changes_requested"
+	requirePassingBuilds      = "require_passing_builds_to_merge"
+	requireTasksCompleted     = "require_tasks_to_be_completed"
+	resetApprovalsOnChange    = "reset_pullrequest_approvals_on_change"
+)
+
+// bitbucketKinds are the restrictions the provider manages. Restrictions of
+// other kinds are left alone.
+var bitbucketKinds = []string{
+	restrictPush, restrictForce, restrictDelete, requireApprovals, requireDefaultReviewers,
+	requireNoChangesRequested, requirePassingBuilds, requireTasksCompleted, resetApprovalsOnChange,
+}
+
+// Bitbucket is the provider of Bitbucket Cloud. The owner is the workspace.
+type Bitbucket struct {
+	// BaseURL defaults to BitbucketURL.
+	BaseURL string
+	// Token is an access token, or username:app-password.
+	Token string
+	HTTP  *http.Client
+}
+
+// BranchRestriction is a branch restriction of Bitbucket, matching a branch
+// by glob pattern.
+type BranchRestriction struct {
+	ID              int64            `json:"id,omitempty"`
+	Kind            string           `json:"kind"`
+	BranchMatchKind string           `json:"branch_match_kind"`
+	Pattern         string           `json:"pattern"`
+	Value           *int             `json:"value,omitempty"`
+	Users           []bitbucketUser  `json:"users"`
+	Groups          []bitbucketGroup `json:"groups"`
+}
+
+type bitbucketUser struct {
+	Username string `json:"username,omitempty"`
+}
+
+type bitbucketGroup struct {
+	Slug string `json:"slug"`
+}
+
+// bitbucketRepo holds the fields of a Bitbucket repository the sync uses.
+type bitbucketRepo struct {
+	Slug       string `json:"slug"`
+	IsPrivate  bool   `json:"is_private"`
+	MainBranch *struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+// page is a page of a paginated Bitbucket response.
+type page struct {
+	Values []json.RawMessage `json:"values"`
+	Next   string            `json:"next"`
+}
+
+// do sends a request to the API, given a path or the absolute URL of a next
+// page, and decodes the response into out, when it isn't nil.
+func (b Bitbucket) do(ctx context.Context, method, path string, body, out interface{}) error {
+	base := b.BaseURL
+	if base == "" {
+		base = BitbucketURL
+	}
+	target := path
+	if !strings.HasPrefix(path, "http") {
+		target = strings.TrimSuffix(base, "/") + "/2.0" + path
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if user, password, ok := strings.Cut(b.Token, ":"); ok {
+		req.SetBasicAuth(user, password)
+	} else if b.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.Token)
+	}
+	client := b.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, apiErr.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// list fetches every page of a paginated collection, decoding every value
+// with decode.
+func (b Bitbucket) list(ctx context.Context, path string, decode func(json.RawMessage) error) error {
+	for path != "" {
+		var p page
+		if err := b.do(ctx, http.MethodGet, path, nil, &p); err != nil {
+			return err
+		}
+		for _, value := range p.Values {
+			if err := decode(value); err != nil {
+				return err
+			}
+		}
+		path = p.Next
+	}
+	return nil
+}
+
+// Repositories lists the repositories of the workspace.
+func (b Bitbucket) Repositories(ctx context.Context, owner string) ([]*github.Repository, error) {
+	var repos []*github.Repository
+	err := b.list(ctx, "/repositories/"+url.PathEscape(owner)+"?pagelen=100", func(value json.RawMessage) error {
+		var r bitbucketRepo
+		if err := json.Unmarshal(value, &r); err != nil {
+			return err
+		}
+		repos = append(repos, r.repository())
+		return nil
+	})
+	return repos, err
+}
+
+// repository converts the repository; an empty one has no main branch.
+func (r bitbucketRepo) repository() *github.Repository {
+	repo := &github.Repository{Name: github.String(r.Slug), Private: github.Bool(r.IsPrivate)}
+	if r.MainBranch != nil {
+		repo.DefaultBranch = github.String(r.MainBranch.Name)
+	}
+	return repo
+}
+
+// restrictions returns the managed restrictions of the branch.
+func (b Bitbucket) restrictions(ctx context.Context, owner, repo, branch string) ([]BranchRestriction, error) {
+	var managed []BranchRestriction
+	err := b.list(ctx, restrictionsPath(owner, repo)+"?pagelen=100&pattern="+url.QueryEscape(branch), func(value json.RawMessage) error {
+		var r BranchRestriction
+		if err := json.Unmarshal(value, &r); err != nil {
+			return err
+		}
+		if r.Pattern == branch && contains(bitbucketKinds, r.Kind) {
+			managed = append(managed, r)
+		}
+		return nil
+	})
+	return managed, err
+}
+
+func restrictionsPath(owner, repo string) string {
+	return "/repositories/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/branch-restrictions"
+}
+
+// Protection returns the restrictions of the main branch as branch
+// protection, nil when there are none.
+func (b Bitbucket) Protection(ctx context.Context, owner, repo string) (*types.RepoProtection, error) {
+	var r bitbucketRepo
+	if err := b.do(ctx, http.MethodGet, "/repositories/"+url.PathEscape(owner)+"/"+url.PathEscape(repo), nil, &r); err != nil {
+		return nil, err
+	}
+	if r.MainBranch == nil {
+		return &types.RepoProtection{}, nil
+	}
+	rp := &types.RepoProtection{Branch: r.MainBranch.Name}
+	restrictions, err := b.restrictions(ctx, owner, repo, rp.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(restrictions) > 0 {
+		rp.BranchProtection = bitbucketProtection(restrictions)
+	}
+	return rp, nil
+}
+
+// SetProtection makes the managed restrictions of the main branch match the
+// branch protection: missing ones are created, existing ones updated and
+// the others deleted.
+func (b Bitbucket) SetProtection(ctx context.Context, owner string, repo *github.Repository, rp *types.RepoProtection) error {
+	branch := repo.GetDefaultBranch()
+	existing, err := b.restrictions(ctx, owner, repo.GetName(), branch)
+	if err != nil {
+		return err
+	}
+	byKind := make(map[string]BranchRestriction, len(existing))
+	for _, r := range existing {
+		byKind[r.Kind] = r
+	}
+
+	path := restrictionsPath(owner, repo.GetName())
+	for _, r := range NewBranchRestrictions(branch, rp.BranchProtection) {
+		current, ok := byKind[r.Kind]
+		delete(byKind, r.Kind)
+		if !ok {
+			if err := b.do(ctx, http.MethodPost, path, r, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		r.ID = current.ID
+		if err := b.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", path, current.ID), r, nil); err != nil {
+			return err
+		}
+	}
+	for _, r := range byKind {
+		if err := b.do(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", path, r.ID), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewBranchRestrictions converts branch protection to the restrictions of
+// the branch. Required reviews forbid direct pushes, and push restrictions
+// keep the pushes to their teams, as Bitbucket groups of the same slug;
+// their users aren't carried over, since Bitbucket no longer identifies
+// users by name. Code owner reviews become default reviewer approvals.
+func NewBranchRestrictions(branch string, p *types.BranchProtection) []BranchRestriction {
+	restriction := func(kind string, value int) BranchRestriction {
+		r := BranchRestriction{Kind: kind, BranchMatchKind: "glob", Pattern: branch, Users: []bitbucketUser{}, Groups: []bitbucketGroup{}}
+		if value > 0 {
+			r.Value = github.Int(value)
+		}
+		return r
+	}
+
+	var restrictions []BranchRestriction
+	reviews := p.Reviews
+	if r := p.Restrictions; reviews != nil || r != nil {
+		push := restriction(restrictPush, 0)
+		if reviews == nil {
+			for _, slug := range r.Teams {
+				push.Groups = append(push.Groups, bitbucketGroup{Slug: slug})
+			}
+		}
+		restrictions = append(restrictions, push)
+	}
+	if !p.AllowForcePushes {
+		restrictions = append(restrictions, restriction(restrictForce, 0))
+	}
+	if !p.AllowDeletions {
+		restrictions = append(restrictions, restriction(restrictDelete, 0))
+	}
+	if reviews != nil {
+		if reviews.RequiredApprovals > 0 {
+			restrictions = append(restrictions, restriction(requireApprovals, reviews.RequiredApprovals))
+		}
+		if reviews.CodeOwners {
+			restrictions = append(restrictions, restriction(requireDefaultReviewers, 1))
+		}
+		if reviews.DismissStale {
+			restrictions = append(restrictions, restriction(resetApprovalsOnChange, 0))
+		}
+		restrictions = append(restrictions, restriction(requireNoChangesRequested, 0))
+	}
+	if checks := p.StatusChecks; checks != nil {
+		builds := len(checks.Names())
+		if builds == 0 {
+			builds = 1
+		}
+		restrictions = append(restrictions, restriction(requirePassingBuilds, builds))
+	}
+	if p.ConversationResolution {
+		restrictions = append(restrictions, restriction(requireTasksCompleted, 0))
+	}
+	return restrictions
+}
+
+// bitbucketProtection converts the restrictions of a branch back to branch
+// protection. Bitbucket builds have no names, so the status checks required
+// have no contexts.
+func bitbucketProtection(restrictions []BranchRestriction) *types.BranchProtection {
+	p := &types.BranchProtection{AllowForcePushes: true, AllowDeletions: true}
+	reviews := func() *types.Reviews {
+		if p.Reviews == nil {
+			p.Reviews = &types.Reviews{}
+		}
+		return p.Reviews
+	}
+	for _, r := range restrictions {
+		switch r.Kind {
+		case restrictPush:
+			if len(r.Users) == 0 && len(r.Groups) == 0 {
+				reviews()
+				continue
+			}
+			actors := &types.Actors{Users: []string{}, Teams: []string{}, Apps: []string{}}
+			for _, g := range r.Groups {
+				actors.Teams = append(actors.Teams, g.Slug)
+			}
+			p.Restrictions = actors
+		case restrictForce:
+			p.AllowForcePushes = false
+		case restrictDelete:
+			p.AllowDeletions = false
+		case requireApprovals:
+			if r.Value != nil {
+				reviews().RequiredApprovals = *r.Value
+			}
+		case requireDefaultReviewers:
+			reviews().CodeOwners = true
+		case resetApprovalsOnChange:
+			reviews().DismissStale = true
+		case requireNoChangesRequested:
+			reviews()
+		case requirePassingBuilds:
+			p.StatusChecks = &types.StatusChecks{Contexts: []string{}}
+		case requireTasksCompleted:
+			p.ConversationResolution = true
+		}
+	}
+	return p
+}
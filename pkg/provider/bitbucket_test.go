@@ -0,0 +1,141 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+)
+
+func TestBitbucket(t *testing.T) {
+	var mu sync.Mutex
+	var writes []string
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2.0/repositories/octo", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "bot" || password != "secret" {
+			t.Errorf("not authenticated with the app password")
+		}
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"values": [{"slug": "api", "mainbranch": {"name": "main"}}], "next": %q}`, server.URL+"/2.0/repositories/octo?page=2")
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"slug": "empty"}]}`)
+	})
+	mux.HandleFunc("/2.0/repositories/octo/api", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"slug": "api", "mainbranch": {"name": "main"}}`)
+	})
+	mux.HandleFunc("/2.0/repositories/octo/api/branch-restrictions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var created BranchRestriction
+			json.NewDecoder(r.Body).Decode(&created)
+			mu.Lock()
+			writes = append(writes, "POST "+created.Kind)
+			mu.Unlock()
+			return
+		}
+		if r.URL.Query().Get("pattern") != "main" {
+			t.Errorf("restrictions listed for the pattern %q", r.URL.Query().Get("pattern"))
+		}
+		fmt.Fprint(w, `{"values": [
+			{"id": 1, "kind": "require_approvals_to_merge", "pattern": "main", "value": 2},
+			{"id": 2, "kind": "push", "pattern": "main", "users": [], "groups": []},
+			{"id": 3, "kind": "require_passing_builds_to_merge", "pattern": "main", "value": 1},
+			{"id": 4, "kind": "delete", "pattern": "release/*"},
+			{"id": 5, "kind": "enforce_merge_checks", "pattern": "main"}
+		]}`)
+	})
+	mux.HandleFunc("/2.0/repositories/octo/api/branch-restrictions/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		writes = append(writes, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/2.0/repositories/octo/api/branch-restrictions/"))
+		mu.Unlock()
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	b := Bitbucket{BaseURL: server.URL, Token: "bot:secret"}
+	ctx := context.Background()
+	repos, err := b.Repositories(ctx, "octo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 2 || repos[0].GetDefaultBranch() != "main" || repos[1].DefaultBranch != nil {
+		t.Errorf("Repositories() = %v", repos)
+	}
+
+	rp, err := b.Protection(ctx, "octo", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &types.BranchProtection{
+		Reviews:          &types.Reviews{RequiredApprovals: 2},
+		StatusChecks:     &types.StatusChecks{Contexts: []string{}},
+		AllowForcePushes: true,
+		AllowDeletions:   true,
+	}
+	if !reflect.DeepEqual(rp.BranchProtection, want) {
+		t.Errorf("Protection() = %+v, want %+v", rp.BranchProtection, want)
+	}
+
+	desired := &types.RepoProtection{BranchProtection: &types.BranchProtection{
+		Reviews:        &types.Reviews{RequiredApprovals: 1},
+		AllowDeletions: true,
+	}}
+	if err := b.SetProtection(ctx, "octo", repos[0], desired); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(writes)
+	wantWrites := []string{"DELETE 3", "POST force", "POST require_no_changes_requested", "PUT 1", "PUT 2"}
+	if !reflect.DeepEqual(writes, wantWrites) {
+		t.Errorf("wrote %v, want %v", writes, wantWrites)
+	}
+}
+
+func TestNewBranchRestrictions(t *testing.T) {
+	p := &types.BranchProtection{
+		StatusChecks:           &types.StatusChecks{Contexts: []string{"ci", "lint"}},
+		Restrictions:           &types.Actors{Teams: []string{"maintainers"}},
+		AllowForcePushes:       true,
+		ConversationResolution: true,
+	}
+	restrictions := NewBranchRestrictions("main", p)
+	var kinds []string
+	for _, r := range restrictions {
+		kinds = append(kinds, r.Kind)
+		if r.Pattern != "main" || r.BranchMatchKind != "glob" {
+			t.Errorf("restriction %s matches %s %q", r.Kind, r.BranchMatchKind, r.Pattern)
+		}
+	}
+	if strings.Join(kinds, " ") != "push delete require_passing_builds_to_merge require_tasks_to_be_completed" {
+		t.Errorf("kinds = %v", kinds)
+	}
+	if len(restrictions[0].Groups) != 1 || restrictions[0].Groups[0].Slug != "maintainers" {
+		t.Errorf("push restriction groups = %v", restrictions[0].Groups)
+	}
+	if *restrictions[2].Value != 2 {
+		t.Errorf("passing builds required = %d, want 2", *restrictions[2].Value)
+	}
+}
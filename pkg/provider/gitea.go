@@ -0,0 +1,258 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// errNotFound is returned for the 404 responses of Gitea.
+var errNotFound = errors.New("not found")
+
+// Gitea is the provider of Gitea and Forgejo, which share their API.
+type Gitea struct {
+	// BaseURL is the URL of the instance, such as https://gitea.example.com.
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// giteaRepo holds the fields of a Gitea repository the sync uses.
+type giteaRepo struct {
+	Name          string `json:"name"`
+	DefaultBranch string `json:"default_branch"`
+	Archived      bool   `json:"archived"`
+	Private       bool   `json:"private"`
+	Empty         bool   `json:"empty"`
+}
+
+// BranchProtection is a branch protection rule of Gitea.
+type BranchProtection struct {
+	RuleName                string   `json:"rule_name"`
+	EnablePush              bool     `json:"enable_push"`
+	EnablePushWhitelist     bool     `json:"enable_push_whitelist"`
+	PushWhitelistUsernames  []string `json:"push_whitelist_usernames"`
+	PushWhitelistTeams      []string `json:"push_whitelist_teams"`
+	EnableMergeWhitelist    bool     `json:"enable_merge_whitelist"`
+	MergeWhitelistUsernames []string `json:"merge_whitelist_usernames"`
+	MergeWhitelistTeams     []string `json:"merge_whitelist_teams"`
+	EnableStatusCheck       bool     `json:"enable_status_check"`
+	StatusCheckContexts     []string `json:"status_check_contexts"`
+	RequiredApprovals       int      `json:"required_approvals"`
+	BlockOnRejectedReviews  bool     `json:"block_on_rejected_reviews"`
+	BlockOnOutdatedBranch   bool     `json:"block_on_outdated_branch"`
+	DismissStaleApprovals   bool     `json:"dismiss_stale_approvals"`
+	RequireSignedCommits    bool     `json:"require_signed_commits"`
+}
+
+// do sends a request to the API of the instance and decodes the response
+// into out, when it isn't nil.
+func (g Gitea) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(g.BaseURL, "/")+"/api/v1"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if g.Token != "" {
+		req.Header.Set("Authorization", "token "+g.Token)
+	}
+	client := g.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, path, errNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, apiErr.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Repositories lists the repositories of the organization, or of the user
+// when there is no organization of that name.
+func (g Gitea) Repositories(ctx context.Context, owner string) ([]*github.Repository, error) {
+	const limit = 50
+	kind := "orgs"
+	var repos []*github.Repository
+	for page := 1; ; page++ {
+		var batch []giteaRepo
+		err := g.do(ctx, http.MethodGet, fmt.Sprintf("/%s/%s/repos?page=%d&limit=%d", kind, url.PathEscape(owner), page, limit), nil, &batch)
+		if errors.Is(err, errNotFound) && kind == "orgs" {
+			kind, page = "users", 0
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			repos = append(repos, r.repository())
+		}
+		if len(batch) < limit {
+			return repos, nil
+		}
+	}
+}
+
+// repository converts the repository; an empty one has no default branch.
+func (r giteaRepo) repository() *github.Repository {
+	repo := &github.Repository{Name: github.String(r.Name), Archived: github.Bool(r.Archived), Private: github.Bool(r.Private)}
+	if !r.Empty {
+		repo.DefaultBranch = github.String(r.DefaultBranch)
+	}
+	return repo
+}
+
+// Protection returns the protection rule of the default branch.
+func (g Gitea) Protection(ctx context.Context, owner, repo string) (*types.RepoProtection, error) {
+	var r giteaRepo
+	if err := g.do(ctx, http.MethodGet, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo), nil, &r); err != nil {
+		return nil, err
+	}
+	rp := &types.RepoProtection{Branch: r.DefaultBranch}
+	bp, err := g.branchProtection(ctx, owner, repo, r.DefaultBranch)
+	if errors.Is(err, errNotFound) {
+		return rp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rp.BranchProtection = bp.protection()
+	return rp, nil
+}
+
+func (g Gitea) branchProtection(ctx context.Context, owner, repo, rule string) (*BranchProtection, error) {
+	bp := new(BranchProtection)
+	err := g.do(ctx, http.MethodGet, protectionPath(owner, repo)+"/"+url.PathEscape(rule), nil, bp)
+	return bp, err
+}
+
+func protectionPath(owner, repo string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/branch_protections"
+}
+
+// SetProtection creates the protection rule of the default branch, or
+// replaces the settings of the existing one.
+func (g Gitea) SetProtection(ctx context.Context, owner string, repo *github.Repository, rp *types.RepoProtection) error {
+	branch := repo.GetDefaultBranch()
+	bp := NewBranchProtection(branch, rp.BranchProtection)
+	_, err := g.branchProtection(ctx, owner, repo.GetName(), branch)
+	switch {
+	case errors.Is(err, errNotFound):
+		return g.do(ctx, http.MethodPost, protectionPath(owner, repo.GetName()), bp, nil)
+	case err != nil:
+		return err
+	}
+	return g.do(ctx, http.MethodPatch, protectionPath(owner, repo.GetName())+"/"+url.PathEscape(branch), bp, nil)
+}
+
+// NewBranchProtection converts branch protection to a Gitea rule for the
+// branch. Required reviews disable direct pushes, and push restrictions
+// become the push and merge allowlists. Settings Gitea doesn't have, such as
+// linear history or conversation resolution, are left out.
+func NewBranchProtection(branch string, p *types.BranchProtection) *BranchProtection {
+	bp := &BranchProtection{RuleName: branch, EnablePush: true}
+	if reviews := p.Reviews; reviews != nil {
+		bp.EnablePush = false
+		bp.RequiredApprovals = reviews.RequiredApprovals
+		bp.DismissStaleApprovals = reviews.DismissStale
+		bp.BlockOnRejectedReviews = true
+	}
+	if r := p.Restrictions; r != nil {
+		bp.EnablePushWhitelist = bp.EnablePush
+		bp.PushWhitelistUsernames, bp.PushWhitelistTeams = r.Users, r.Teams
+		bp.EnableMergeWhitelist = true
+		bp.MergeWhitelistUsernames, bp.MergeWhitelistTeams = r.Users, r.Teams
+	}
+	if checks := p.StatusChecks; checks != nil {
+		bp.EnableStatusCheck = true
+		bp.BlockOnOutdatedBranch = checks.Strict
+		bp.StatusCheckContexts = checks.Names()
+	}
+	bp.RequireSignedCommits = p.SignedCommits
+	return bp
+}
+
+// protection converts the rule back to branch protection.
+func (bp *BranchProtection) protection() *types.BranchProtection {
+	p := &types.BranchProtection{SignedCommits: bp.RequireSignedCommits}
+	if !bp.EnablePush {
+		p.Reviews = &types.Reviews{RequiredApprovals: bp.RequiredApprovals, DismissStale: bp.DismissStaleApprovals}
+	}
+	if bp.EnablePushWhitelist || bp.EnableMergeWhitelist {
+		users, teams := bp.PushWhitelistUsernames, bp.PushWhitelistTeams
+		if !bp.EnablePushWhitelist {
+			users, teams = bp.MergeWhitelistUsernames, bp.MergeWhitelistTeams
+		}
+		p.Restrictions = &types.Actors{Users: nonNil(users), Teams: nonNil(teams), Apps: []string{}}
+	}
+	if bp.EnableStatusCheck {
+		p.StatusChecks = &types.StatusChecks{Strict: bp.BlockOnOutdatedBranch, Contexts: bp.StatusCheckContexts}
+	}
+	return p
+}
+
+// nonNil returns values, or an empty list for nil.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// fakeGitea serves the endpoints of the Gitea API the provider uses, for a
+// user owning the repositories api, with a protected main branch, and web.
+func fakeGitea(t *testing.T, written map[string]BranchProtection) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/orgs/octo/repos", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/api/v1/users/octo/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		json.NewEncoder(w).Encode([]giteaRepo{
+			{Name: "api", DefaultBranch: "main"},
+			{Name: "web", DefaultBranch: "main", Empty: true},
+		})
+	})
+	mux.HandleFunc("/api/v1/repos/octo/api", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(giteaRepo{Name: "api", DefaultBranch: "main"})
+	})
+	mux.HandleFunc("/api/v1/repos/octo/api/branch_protections/main", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			var bp BranchProtection
+			json.NewDecoder(r.Body).Decode(&bp)
+			written["PATCH api"] = bp
+		}
+		json.NewEncoder(w).Encode(BranchProtection{RuleName: "main", RequiredApprovals: 2, EnableStatusCheck: true, StatusCheckContexts: []string{"ci"}})
+	})
+	mux.HandleFunc("/api/v1/repos/octo/web/branch_protections/main", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/api/v1/repos/octo/web/branch_protections", func(w http.ResponseWriter, r *http.Request) {
+		var bp BranchProtection
+		json.NewDecoder(r.Body).Decode(&bp)
+		written[r.Method+" web"] = bp
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitea(t *testing.T) {
+	written := make(map[string]BranchProtection)
+	server := fakeGitea(t, written)
+	g := Gitea{BaseURL: server.URL + "/", Token: "secret"}
+	ctx := context.Background()
+
+	repos, err := g.Repositories(ctx, "octo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 2 || repos[0].GetDefaultBranch() != "main" || repos[1].DefaultBranch != nil {
+		t.Errorf("Repositories() = %v", repos)
+	}
+
+	rp, err := g.Protection(ctx, "octo", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &types.BranchProtection{
+		Reviews:      &types.Reviews{RequiredApprovals: 2},
+		StatusChecks: &types.StatusChecks{Contexts: []string{"ci"}},
+	}
+	if rp.Branch != "main" || !reflect.DeepEqual(rp.BranchProtection, want) {
+		t.Errorf("Protection() = %+v, want %+v", rp.BranchProtection, want)
+	}
+
+	for _, name := range []string{"api", "web"} {
+		target := &github.Repository{Name: github.String(name), DefaultBranch: github.String("main")}
+		if err := g.SetProtection(ctx, "octo", target, rp); err != nil {
+			t.Fatalf("SetProtection(%s): %v", name, err)
+		}
+	}
+	created, ok := written["POST web"]
+	if !ok || created.RuleName != "main" || created.EnablePush || created.RequiredApprovals != 2 {
+		t.Errorf("created %+v on web", created)
+	}
+	if _, ok := written["PATCH api"]; !ok {
+		t.Errorf("the existing rule of api wasn't updated: %v", written)
+	}
+}
+
+func TestNewBranchProtection(t *testing.T) {
+	p := &types.BranchProtection{
+		StatusChecks: &types.StatusChecks{
+			Strict:   true,
+			Contexts: []string{"ci"},
+			Checks:   []types.StatusCheck{{Context: "ci"}, {Context: "lint"}},
+		},
+		Restrictions:  &types.Actors{Users: []string{"release-bot"}, Teams: []string{"maintainers"}},
+		SignedCommits: true,
+	}
+	bp := NewBranchProtection("main", p)
+	want := &BranchProtection{
+		RuleName:                "main",
+		EnablePush:              true,
+		EnablePushWhitelist:     true,
+		PushWhitelistUsernames:  []string{"release-bot"},
+		PushWhitelistTeams:      []string{"maintainers"},
+		EnableMergeWhitelist:    true,
+		MergeWhitelistUsernames: []string{"release-bot"},
+		MergeWhitelistTeams:     []string{"maintainers"},
+		EnableStatusCheck:       true,
+		StatusCheckContexts:     []string{"ci", "lint"},
+		BlockOnOutdatedBranch:   true,
+		RequireSignedCommits:    true,
+	}
+	if !reflect.DeepEqual(bp, want) {
+		t.Errorf("NewBranchProtection() = %+v, want %+v", bp, want)
+	}
+
+	back := bp.protection()
+	if len(back.Restrictions.Users) != 1 || back.Restrictions.Teams[0] != "maintainers" || !back.StatusChecks.Strict {
+		t.Errorf("protection() = %+v", back)
+	}
+}
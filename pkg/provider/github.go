@@ -0,0 +1,59 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// GitHub is the provider of GitHub and GitHub Enterprise Server. The sync
+// of the executor covers far more of GitHub than Sync does, and is what the
+// commands use; GitHub is there for the tools written against Provider.
+type GitHub struct {
+	Client *ghclient.Client
+	// Installation lists the repositories of the App installation instead
+	// of those of the owner.
+	Installation bool
+}
+
+// Repositories lists the repositories of the owner or of the installation.
+func (g GitHub) Repositories(ctx context.Context, owner string) ([]*github.Repository, error) {
+	if g.Installation {
+		return getter.GetAllReposFromInstallation(ctx, g.Client.Apps, owner)
+	}
+	return getter.GetAllReposFromOrg(ctx, g.Client.Repositories, owner)
+}
+
+// Protection returns the branch protection and rulesets of the repository.
+func (g GitHub) Protection(ctx context.Context, owner, repo string) (*types.RepoProtection, error) {
+	return getter.FetchRepoProtections(ctx, g.Client, owner, repo)
+}
+
+// SetProtection applies the branch protection; rulesets aren't applied.
+func (g GitHub) SetProtection(ctx context.Context, owner string, repo *github.Repository, rp *types.RepoProtection) error {
+	request, err := setter.Desired(ctx, repo, rp, setter.Options{})
+	if err != nil {
+		return err
+	}
+	_, _, err = g.Client.Repositories.UpdateBranchProtection(ctx, owner, repo.GetName(), repo.GetDefaultBranch(), request)
+	return err
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/notify"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Provider reads and writes the branch protection of the repositories of a
+// forge. Repositories and protection are described with the GitHub types,
+// which every provider converts from and to.
+type Provider interface {
+	// Repositories lists the repositories of the owner.
+	Repositories(ctx context.Context, owner string) ([]*github.Repository, error)
+	// Protection returns the protection of the default branch of a
+	// repository, with a nil BranchProtection when it isn't protected.
+	Protection(ctx context.Context, owner, repo string) (*types.RepoProtection, error)
+	// SetProtection applies the branch protection of rp to the default
+	// branch of the repository.
+	SetProtection(ctx context.Context, owner string, repo *github.Repository, rp *types.RepoProtection) error
+}
+
+// Sync applies the branch protection of the source repository to the
+// targets, or to every other repository of the owner when there are none.
+// Archived repositories are skipped. A failing repository doesn't stop the
+// others; the failures are returned in the summary.
+func Sync(ctx context.Context, p Provider, owner, source string, targets []string) (notify.Summary, error) {
+	started := time.Now()
+	rp, err := p.Protection(ctx, owner, source)
+	if err != nil {
+		return notify.Summary{}, fmt.Errorf("fetching the protection of %s: %w", source, err)
+	}
+	if rp.BranchProtection == nil {
+		return notify.Summary{}, fmt.Errorf("%s has no branch protection on its default branch; protect it before syncing", source)
+	}
+
+	repos, err := p.Repositories(ctx, owner)
+	if err != nil {
+		return notify.Summary{}, fmt.Errorf("fetching repositories: %w", err)
+	}
+	selected := make(map[string]bool, len(targets))
+	for _, name := range targets {
+		selected[strings.ToLower(name)] = true
+	}
+
+	count := 0
+	failures := make(map[string]error)
+	for _, repo := range repos {
+		name := repo.GetName()
+		if strings.EqualFold(name, source) || repo.GetArchived() || (len(targets) > 0 && !selected[strings.ToLower(name)]) {
+			continue
+		}
+		count++
+		if repo.GetDefaultBranch() == "" {
+			logging.Infof("Skipping empty repo %s\n", name)
+			continue
+		}
+		if err := p.SetProtection(ctx, owner, repo, rp); err != nil {
+			log.Printf("Error applying branch protection to repo %s: %v\n", name, err)
+			failures[name] = err
+			continue
+		}
+		logging.Infof("Branch protection applied to repo %s successfully\n", name)
+	}
+	return notify.NewSummary(owner, source, started, count, failures), nil
+}
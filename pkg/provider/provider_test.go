@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+type fakeProvider struct {
+	repos []*github.Repository
+	set   []string
+}
+
+func (f *fakeProvider) Repositories(ctx context.Context, owner string) ([]*github.Repository, error) {
+	return f.repos, nil
+}
+
+func (f *fakeProvider) Protection(ctx context.Context, owner, repo string) (*types.RepoProtection, error) {
+	return &types.RepoProtection{Branch: "main", BranchProtection: &types.BranchProtection{}}, nil
+}
+
+func (f *fakeProvider) SetProtection(ctx context.Context, owner string, repo *github.Repository, rp *types.RepoProtection) error {
+	if repo.GetName() == "locked" {
+		return errors.New("403 Forbidden")
+	}
+	f.set = append(f.set, repo.GetName())
+	return nil
+}
+
+func repo(name, branch string, archived bool) *github.Repository {
+	r := &github.Repository{Name: github.String(name), Archived: github.Bool(archived)}
+	if branch != "" {
+		r.DefaultBranch = github.String(branch)
+	}
+	return r
+}
+
+func TestSync(t *testing.T) {
+	p := &fakeProvider{repos: []*github.Repository{
+		repo("baseline", "main", false),
+		repo("api", "main", false),
+		repo("web", "trunk", false),
+		repo("old", "main", true),
+		repo("empty", "", false),
+		repo("locked", "main", false),
+	}}
+	summary, err := Sync(context.Background(), p, "octo", "baseline", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(p.set)
+	if strings.Join(p.set, " ") != "api web" {
+		t.Errorf("protected %v", p.set)
+	}
+	if summary.Targets != 4 || len(summary.Failures) != 1 || summary.Failures[0].Repo != "locked" {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	p.set = nil
+	if _, err := Sync(context.Background(), p, "octo", "baseline", []string{"WEB"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(p.set, " ") != "web" {
+		t.Errorf("protected %v with the target web", p.set)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/diff"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/gitlab"
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Branch is the branch proposing an updated policy file is pushed to.
+const Branch = "repo-protection-sync/policy"
+
+// Drift compares a policy file exported with --format gitlab to the live
+// protection of its source. The live protection goes through the same
+// conversion, so the settings the file can't hold don't count as drift. It
+// returns the changes made to the source since the export, along with the
+// policy file matching the source.
+func Drift(file *gitlab.Project, live *types.RepoProtection) ([]diff.Delta, *gitlab.Project, error) {
+	want, err := file.BranchProtection()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the policy file: %w", err)
+	}
+	current, err := gitlab.New(file.Path, live)
+	if err != nil {
+		return nil, nil, err
+	}
+	got, err := current.BranchProtection()
+	if err != nil {
+		return nil, nil, err
+	}
+	return diff.Protection(want, got), current, nil
+}
+
+// Propose opens a pull request updating the policy file at path in the
+// repository owner/repo to project. It returns nil without an error when the
+// branch of a previous proposal still exists.
+func Propose(ctx context.Context, client *ghclient.Client, owner, repo, path, source string, project *gitlab.Project) (*github.PullRequest, error) {
+	_, _, err := client.Git.GetRef(ctx, owner, repo, "heads/"+Branch)
+	if err == nil {
+		return nil, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+
+	content, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	content = append(content, '\n')
+
+	r, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	base := r.GetDefaultBranch()
+	head, _, err := client.Git.GetRef(ctx, owner, repo, "heads/"+base)
+	if err != nil {
+		return nil, fmt.Errorf("reading branch %s: %w", base, err)
+	}
+	// The file is updated when it exists, or else added
+	var sha *string
+	existing, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: base})
+	switch {
+	case err == nil:
+		sha = existing.SHA
+	case !isNotFound(err):
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	ref := &github.Reference{Ref: github.String("refs/heads/" + Branch), Object: &github.GitObject{SHA: head.GetObject().SHA}}
+	if _, _, err := client.Git.CreateRef(ctx, owner, repo, ref); err != nil {
+		return nil, fmt.Errorf("creating branch %s: %w", Branch, err)
+	}
+	title := "Update the policy to the protection of " + source
+	file := &github.RepositoryContentFileOptions{
+		Message: github.String(title),
+		Content: content,
+		Branch:  github.String(Branch),
+		SHA:     sha,
+	}
+	if sha != nil {
+		_, _, err = client.Repositories.UpdateFile(ctx, owner, repo, path, file)
+	} else {
+		_, _, err = client.Repositories.CreateFile(ctx, owner, repo, path, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("updating %s: %w", path, err)
+	}
+
+	body := fmt.Sprintf("The branch protection of %s was changed outside of %s. "+
+		"Merge this pull request to keep the change, or close it and sync the policy to revert the source.", source, path)
+	if id := runid.From(ctx); id != "" {
+		body += fmt.Sprintf("\n\nOpened by run `%s`.", id)
+	}
+	pr, _, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(Branch),
+		Base:  github.String(base),
+		Body:  &body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening the pull request: %w", err)
+	}
+	return pr, nil
+}
+
+func isNotFound(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package reconcile
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/gitlab"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func notFound() error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Not Found"}
+}
+
+func exported(t *testing.T, bp *types.BranchProtection) *gitlab.Project {
+	t.Helper()
+	project, err := gitlab.New("template", &types.RepoProtection{Branch: "main", BranchProtection: bp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func TestDrift(t *testing.T) {
+	file := exported(t, &types.BranchProtection{Reviews: &types.Reviews{RequiredApprovals: 1}})
+
+	// The protection the file was exported from doesn't drift
+	same := &types.RepoProtection{Branch: "main", BranchProtection: &types.BranchProtection{Reviews: &types.Reviews{RequiredApprovals: 1}}}
+	deltas, _, err := Drift(file, same)
+	if err != nil || len(deltas) != 0 {
+		t.Fatalf("Drift() of the exported protection = %v, %v; want none", deltas, err)
+	}
+
+	changed := &types.RepoProtection{Branch: "main", BranchProtection: &types.BranchProtection{Reviews: &types.Reviews{RequiredApprovals: 2}, LinearHistory: true}}
+	deltas, current, err := Drift(file, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deltas) == 0 {
+		t.Fatal("Drift() found no change to the source")
+	}
+	if current.Path != "template" || current.Settings.MergeMethod != "ff" {
+		t.Errorf("Drift() returned the policy file %+v", current)
+	}
+}
+
+func TestProposeUpdatesFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	git := mocks.NewMockRefManager(ctrl)
+	pulls := mocks.NewMockPullRequestCreator(ctrl)
+	client := &ghclient.Client{Repositories: repos, Git: git, PullRequests: pulls}
+	project := exported(t, &types.BranchProtection{LinearHistory: true})
+
+	git.EXPECT().GetRef(gomock.Any(), "octo", "policies", "heads/"+Branch).Return(nil, nil, notFound())
+	repos.EXPECT().Get(gomock.Any(), "octo", "policies").Return(&github.Repository{DefaultBranch: github.String("main")}, nil, nil)
+	git.EXPECT().GetRef(gomock.Any(), "octo", "policies", "heads/main").
+		Return(&github.Reference{Object: &github.GitObject{SHA: github.String("abc")}}, nil, nil)
+	repos.EXPECT().GetContents(gomock.Any(), "octo", "policies", "template.json", &github.RepositoryContentGetOptions{Ref: "main"}).
+		Return(&github.RepositoryContent{SHA: github.String("old")}, nil, nil, nil)
+	git.EXPECT().CreateRef(gomock.Any(), "octo", "policies", gomock.Any()).Return(&github.Reference{}, nil, nil)
+	repos.EXPECT().UpdateFile(gomock.Any(), "octo", "policies", "template.json", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _ string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+			if opts.GetSHA() != "old" || opts.GetBranch() != Branch || !strings.Contains(string(opts.Content), `"merge_method": "ff"`) {
+				t.Errorf("unexpected file options %+v", opts)
+			}
+			return &github.RepositoryContentResponse{}, nil, nil
+		})
+	pulls.EXPECT().Create(gomock.Any(), "octo", "policies", gomock.Any()).Return(&github.PullRequest{Number: github.Int(7)}, nil, nil)
+
+	pr, err := Propose(context.Background(), client, "octo", "policies", "template.json", "octo/template", project)
+	if err != nil || pr.GetNumber() != 7 {
+		t.Errorf("got %v, %v", pr, err)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rego
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// DefaultQuery is the rule evaluated when the configuration names none.
+const DefaultQuery = "data.repo_protection_sync.decisions"
+
+// Input describes a repository to the Rego policy.
+type Input struct {
+	Name          string            `json:"name"`
+	Visibility    string            `json:"visibility"`
+	DefaultBranch string            `json:"default_branch"`
+	Language      string            `json:"language"`
+	Topics        []string          `json:"topics"`
+	Properties    map[string]string `json:"properties"`
+	Archived      bool              `json:"archived"`
+	Fork          bool              `json:"fork"`
+	// Policy is the policy whose selector matched the repository, empty
+	// when none did or the configuration has no policies.
+	Policy string `json:"policy"`
+}
+
+// NewInput describes repo, with its custom properties and the policy its
+// selectors assigned.
+func NewInput(repo *github.Repository, properties map[string]string, policy string) Input {
+	return Input{
+		Name:          repo.GetName(),
+		Visibility:    repo.GetVisibility(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		Language:      repo.GetLanguage(),
+		Topics:        repo.Topics,
+		Properties:    properties,
+		Archived:      repo.GetArchived(),
+		Fork:          repo.GetFork(),
+		Policy:        policy,
+	}
+}
+
+// Decision is what the Rego policy decided for a repository.
+type Decision struct {
+	// Sync is false for repositories that must be left alone.
+	Sync bool `json:"sync"`
+	// Policy names the policy to apply instead of the one the selectors
+	// assigned, when set.
+	Policy string `json:"policy"`
+}
+
+// Evaluator evaluates a Rego policy with the opa command line tool.
+type Evaluator struct {
+	// Binary is the opa executable, looked up on the PATH when empty.
+	Binary string
+	// Policy is the path of the Rego files, a file, directory or bundle.
+	Policy string
+	// Query defaults to DefaultQuery.
+	Query string
+}
+
+// Evaluate decides for every repository in one evaluation. The policy gets
+// the repositories as input.repositories and its query evaluates to an
+// object of decisions keyed by repository name; a repository without a
+// decision isn't synced.
+func (e Evaluator) Evaluate(ctx context.Context, inputs []Input) (map[string]Decision, error) {
+	binary, query := e.Binary, e.Query
+	if binary == "" {
+		binary = "opa"
+	}
+	if query == "" {
+		query = DefaultQuery
+	}
+	stdin, err := json.Marshal(map[string][]Input{"repositories": inputs})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "eval", "--format=json", "--stdin-input", "--data", e.Policy, query)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("evaluating %s: %w: %s", e.Policy, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return parse(stdout.Bytes(), query)
+}
+
+// evalOutput is the part of the JSON output of opa eval holding the value
+// of the query.
+type evalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+func parse(data []byte, query string) (map[string]Decision, error) {
+	var out evalOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing the output of opa: %w", err)
+	}
+	if len(out.Result) == 0 || len(out.Result[0].Expressions) == 0 {
+		return nil, errors.New(query + " is undefined")
+	}
+	var decisions map[string]Decision
+	if err := json.Unmarshal(out.Result[0].Expressions[0].Value, &decisions); err != nil {
+		return nil, fmt.Errorf("%s must be an object of decisions keyed by repository name: %w", query, err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rego
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// fakeOPA writes a script standing in for opa, which saves its arguments
+// and input next to it and prints output.
+func fakeOPA(t *testing.T, output string) (binary, dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "output.json"), []byte(output), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"$@\" > " + dir + "/args\ncat > " + dir + "/input.json\ncat " + dir + "/output.json\n"
+	binary = filepath.Join(dir, "opa")
+	if err := os.WriteFile(binary, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return binary, dir
+}
+
+func TestEvaluate(t *testing.T) {
+	binary, dir := fakeOPA(t, `{"result": [{"expressions": [{"value": {
+		"api": {"sync": true},
+		"docs": {"sync": true, "policy": "relaxed"},
+		"legacy": {"sync": false}
+	}}]}]}`)
+
+	repo := &github.Repository{Name: github.String("api"), Visibility: github.String("private"), Topics: []string{"service"}}
+	inputs := []Input{NewInput(repo, map[string]string{"tier": "1"}, "strict")}
+	e := Evaluator{Binary: binary, Policy: "policy.rego"}
+	decisions, err := e.Evaluate(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if !decisions["api"].Sync || decisions["docs"].Policy != "relaxed" || decisions["legacy"].Sync {
+		t.Errorf("Evaluate() = %+v", decisions)
+	}
+
+	args, _ := os.ReadFile(filepath.Join(dir, "args"))
+	if want := "eval --format=json --stdin-input --data policy.rego " + DefaultQuery; strings.TrimSpace(string(args)) != want {
+		t.Errorf("opa called with %q, want %q", args, want)
+	}
+	input, _ := os.ReadFile(filepath.Join(dir, "input.json"))
+	for _, want := range []string{`"repositories":[`, `"name":"api"`, `"visibility":"private"`, `"properties":{"tier":"1"}`, `"policy":"strict"`} {
+		if !strings.Contains(string(input), want) {
+			t.Errorf("input %s doesn't contain %s", input, want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	for _, output := range []string{`{"result": []}`, `{"result": [{"expressions": [{"value": true}]}]}`, `not json`} {
+		binary, _ := fakeOPA(t, output)
+		if _, err := (Evaluator{Binary: binary, Policy: "policy.rego"}).Evaluate(context.Background(), nil); err == nil {
+			t.Errorf("Evaluate() succeeded on the output %s", output)
+		}
+	}
+
+	missing := Evaluator{Binary: filepath.Join(t.TempDir(), "opa"), Policy: "policy.rego"}
+	if _, err := missing.Evaluate(context.Background(), nil); err == nil {
+		t.Error("Evaluate() succeeded without opa")
+	}
+}
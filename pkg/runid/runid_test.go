@@ -0,0 +1,42 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package runid
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	id := New()
+	if !regexp.MustCompile(`^\d{8}T\d{6}Z-[0-9a-f]{6}$`).MatchString(id) {
+		t.Errorf("New() = %q", id)
+	}
+	if New() == id {
+		t.Error("New() returned the same ID twice")
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if id := From(ctx); id != "" {
+		t.Errorf("From() = %q without a run ID", id)
+	}
+	if id := From(With(ctx, "run")); id != "run" {
+		t.Errorf("From() = %q, want run", id)
+	}
+}
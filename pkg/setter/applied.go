@@ -0,0 +1,127 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// FieldWarning describes a protection setting that GitHub accepted in the
+// request but did not persist as requested.
+type FieldWarning struct {
+	Field     string
+	Requested string
+	Applied   string
+}
+
+func (w FieldWarning) String() string {
+	return fmt.Sprintf("%s: requested %s, applied %s", w.Field, w.Requested, w.Applied)
+}
+
+// Diff compares the desired request against the protection a target
+// currently has, nil when its branch is unprotected. Requested holds the
+// desired value and Applied the current one.
+func Diff(desired *github.ProtectionRequest, current *github.Protection) []FieldWarning {
+	if current == nil {
+		current = &github.Protection{}
+	}
+	return compareAppliedProtection(desired, current)
+}
+
+// compareAppliedProtection compares the Protection object returned by the
+// API after an update against the request that was sent, returning a warning
+// for every field the API normalized or dropped.
+func compareAppliedProtection(req *github.ProtectionRequest, applied *github.Protection) []FieldWarning {
+	if req == nil || applied == nil {
+		return nil
+	}
+
+	var warnings []FieldWarning
+	check := func(field string, requested, got interface{}) {
+		r, a := fmt.Sprint(requested), fmt.Sprint(got)
+		if r != a {
+			warnings = append(warnings, FieldWarning{Field: field, Requested: r, Applied: a})
+		}
+	}
+	checkBool := func(field string, requested *bool, got bool) {
+		if requested != nil {
+			check(field, *requested, got)
+		}
+	}
+
+	check("enforce_admins", req.EnforceAdmins, applied.EnforceAdmins != nil && applied.EnforceAdmins.Enabled)
+	checkBool("required_linear_history", req.RequireLinearHistory, applied.RequireLinearHistory != nil && applied.RequireLinearHistory.Enabled)
+	checkBool("allow_force_pushes", req.AllowForcePushes, applied.AllowForcePushes != nil && applied.AllowForcePushes.Enabled)
+	checkBool("allow_deletions", req.AllowDeletions, applied.AllowDeletions != nil && applied.AllowDeletions.Enabled)
+	checkBool("required_conversation_resolution", req.RequiredConversationResolution, applied.RequiredConversationResolution != nil && applied.RequiredConversationResolution.Enabled)
+	checkBool("block_creations", req.BlockCreations, applied.GetBlockCreations().GetEnabled())
+	checkBool("lock_branch", req.LockBranch, applied.GetLockBranch().GetEnabled())
+	checkBool("allow_fork_syncing", req.AllowForkSyncing, applied.GetAllowForkSyncing().GetEnabled())
+
+	if rsc := req.RequiredStatusChecks; rsc != nil {
+		got := applied.GetRequiredStatusChecks()
+		if got == nil {
+			got = &github.RequiredStatusChecks{}
+		}
+		check("required_status_checks.strict", rsc.Strict, got.Strict)
+		check("required_status_checks.contexts", sortedList(rsc.Contexts), sortedList(got.Contexts))
+	}
+
+	if prr := req.RequiredPullRequestReviews; prr != nil {
+		got := applied.GetRequiredPullRequestReviews()
+		if got == nil {
+			got = &github.PullRequestReviewsEnforcement{}
+		}
+		check("required_pull_request_reviews.dismiss_stale_reviews", prr.DismissStaleReviews, got.DismissStaleReviews)
+		check("required_pull_request_reviews.require_code_owner_reviews", prr.RequireCodeOwnerReviews, got.RequireCodeOwnerReviews)
+		check("required_pull_request_reviews.required_approving_review_count", prr.RequiredApprovingReviewCount, got.RequiredApprovingReviewCount)
+		if prr.RequireLastPushApproval != nil {
+			check("required_pull_request_reviews.require_last_push_approval", *prr.RequireLastPushApproval, got.RequireLastPushApproval)
+		}
+	}
+
+	if br := req.Restrictions; br != nil {
+		got := applied.GetRestrictions()
+		var users, teams, apps []string
+		if got != nil {
+			for _, u := range got.Users {
+				users = append(users, u.GetLogin())
+			}
+			for _, t := range got.Teams {
+				teams = append(teams, t.GetSlug())
+			}
+			for _, a := range got.Apps {
+				apps = append(apps, a.GetSlug())
+			}
+		}
+		check("restrictions.users", sortedList(br.Users), sortedList(users))
+		check("restrictions.teams", sortedList(br.Teams), sortedList(teams))
+		check("restrictions.apps", sortedList(br.Apps), sortedList(apps))
+	}
+
+	return warnings
+}
+
+// sortedList renders a list of names independent of their order.
+func sortedList(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return "[" + strings.Join(sorted, ",") + "]"
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+const (
+	defaultRepoTimeout = 5 * time.Minute
+	defaultAbortAfter  = 10
+)
+
+// ErrAborted is returned for the repositories that weren't synced because
+// the run was aborted after too many identical failures.
+var ErrAborted = errors.New("run aborted")
+
+// circuitBreaker trips once threshold repositories in a row failed with the
+// same error. Such a streak almost always has a cause outside the repos,
+// like a token missing a scope, which syncing the rest won't fix. It also
+// trips as soon as the failure policy says to stop.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	policy    FailurePolicy
+	repos     int
+	failures  int
+	last      string
+	streak    int
+	tripped   error
+}
+
+// newCircuitBreaker returns a breaker for a sync of repos repositories,
+// tripping after threshold identical failures, defaulting to 10; a negative
+// threshold never trips on a streak.
+func newCircuitBreaker(threshold int, policy FailurePolicy, repos int) *circuitBreaker {
+	if threshold == 0 {
+		threshold = defaultAbortAfter
+	}
+	return &circuitBreaker{threshold: threshold, policy: policy, repos: repos}
+}
+
+// Record counts the outcome of a repository. A success ends the streak.
+func (b *circuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tripped != nil {
+		return
+	}
+	if err == nil {
+		b.last, b.streak = "", 0
+		return
+	}
+
+	b.failures++
+	cause := failureCause(err)
+	switch {
+	case b.policy.OnError == FailFast:
+		b.tripped = fmt.Errorf("%w after the first failure: %s", ErrAborted, cause)
+		return
+	case b.policy.OnError == Threshold && float64(b.failures)*100 > b.policy.Percent*float64(b.repos):
+		b.tripped = fmt.Errorf("%w after %d of %d repositories failed, over the threshold of %s%%: last failure: %s",
+			ErrAborted, b.failures, b.repos, strconv.FormatFloat(b.policy.Percent, 'f', -1, 64), cause)
+		return
+	case b.threshold < 0:
+		return
+	}
+	if cause == b.last {
+		b.streak++
+	} else {
+		b.last, b.streak = cause, 1
+	}
+	if b.streak >= b.threshold {
+		b.tripped = fmt.Errorf("%w after %d repositories in a row failed with: %s", ErrAborted, b.streak, cause)
+	}
+}
+
+// Tripped returns the diagnosis once the breaker tripped, nil before.
+func (b *circuitBreaker) Tripped() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tripped
+}
+
+// failureCause reduces err to what identical failures have in common: the
+// status and message of an API error, which don't name the repository.
+func failureCause(err error) string {
+	var ghErr *github.ErrorResponse
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out"
+	case errors.As(err, &ghErr) && ghErr.Response != nil:
+		return fmt.Sprintf("%d %s", ghErr.Response.StatusCode, ghErr.Message)
+	default:
+		return err.Error()
+	}
+}
+
+// repoTimeout returns the configured per-repository deadline or the default.
+func repoTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return defaultRepoTimeout
+	}
+	return timeout
+}
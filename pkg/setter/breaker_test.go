@@ -0,0 +1,170 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func forbidden(repo string) error {
+	return fmt.Errorf("updating ruleset %q: %w", repo, &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusForbidden},
+		Message:  "Resource not accessible by integration",
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	b := newCircuitBreaker(3, FailurePolicy{}, 10)
+	b.Record(forbidden("a"))
+	b.Record(forbidden("b"))
+	b.Record(nil)
+	b.Record(forbidden("c"))
+	b.Record(errors.New("something else"))
+	b.Record(forbidden("d"))
+	b.Record(forbidden("e"))
+	if err := b.Tripped(); err != nil {
+		t.Fatalf("tripped early: %v", err)
+	}
+
+	b.Record(forbidden("f"))
+	err := b.Tripped()
+	if !errors.Is(err, ErrAborted) {
+		t.Fatalf("got %v, want the breaker to trip", err)
+	}
+	if want := "run aborted after 3 repositories in a row failed with: 403 Resource not accessible by integration"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+
+	never := newCircuitBreaker(-1, FailurePolicy{}, 10)
+	for i := 0; i < 20; i++ {
+		never.Record(forbidden("a"))
+	}
+	if err := never.Tripped(); err != nil {
+		t.Errorf("a negative threshold should never trip: %v", err)
+	}
+}
+
+func TestCircuitBreakerFailurePolicy(t *testing.T) {
+	fail := newCircuitBreaker(-1, FailurePolicy{OnError: FailFast}, 10)
+	fail.Record(nil)
+	fail.Record(forbidden("a"))
+	if err := fail.Tripped(); !errors.Is(err, ErrAborted) {
+		t.Errorf("fail: got %v, want the breaker to trip on the first failure", err)
+	}
+
+	threshold := newCircuitBreaker(-1, FailurePolicy{OnError: Threshold, Percent: 20}, 10)
+	threshold.Record(forbidden("a"))
+	threshold.Record(errors.New("something else"))
+	if err := threshold.Tripped(); err != nil {
+		t.Fatalf("threshold: tripped at 20%%: %v", err)
+	}
+	threshold.Record(forbidden("b"))
+	err := threshold.Tripped()
+	if !errors.Is(err, ErrAborted) {
+		t.Fatalf("threshold: got %v, want the breaker to trip over 20%%", err)
+	}
+	if want := "run aborted after 3 of 10 repositories failed, over the threshold of 20%: last failure: 403 Resource not accessible by integration"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+}
+
+func TestSetRulesetAborts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	var targets []*github.Repository
+	for _, name := range []string{"a", "b", "c", "d"} {
+		targets = append(targets, &github.Repository{Name: github.String(name), DefaultBranch: github.String("main")})
+	}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", gomock.Any(), "main", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, repo, _ string, _ *github.ProtectionRequest) (*github.Protection, *github.Response, error) {
+			return nil, nil, forbidden(repo)
+		}).Times(2)
+	repos.EXPECT().Get(gomock.Any(), "octo", gomock.Any()).Return(&github.Repository{}, okResponse(), nil).Times(2)
+
+	opts := Options{Concurrency: config.Concurrency{MaxWorkers: 1, AbortAfter: 2}}
+	results, err := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	if !errors.Is(err, ErrAborted) {
+		t.Errorf("got %v, want the run to be aborted", err)
+	}
+	failures := Failures(results)
+	if len(failures) != 4 {
+		t.Fatalf("got failures %v, want all four repos", failures)
+	}
+	for _, name := range []string{"c", "d"} {
+		if !errors.Is(failures[name], ErrAborted) {
+			t.Errorf("%s: got %v, want the run to be aborted", name, failures[name])
+		}
+	}
+}
+
+func TestSetRulesetTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{{Name: github.String("slow"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "slow", "main", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _, _, _ string, _ *github.ProtectionRequest) (*github.Protection, *github.Response, error) {
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		})
+
+	opts := Options{Concurrency: config.Concurrency{RepoTimeout: 10 * time.Millisecond}}
+	results, _ := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	failures := Failures(results)
+	if !errors.Is(failures["slow"], context.DeadlineExceeded) {
+		t.Errorf("got %v, want a timeout", failures["slow"])
+	}
+}
+
+func TestSetRulesetTimeoutSparesHooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{{Name: github.String("api"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+
+	// A person taking longer than the timeout to confirm doesn't fail the sync
+	confirm := func(ctx context.Context, _ *github.Repository, _ *github.ProtectionRequest) (bool, error) {
+		time.Sleep(50 * time.Millisecond)
+		return false, ctx.Err()
+	}
+	opts := Options{Concurrency: config.Concurrency{RepoTimeout: 10 * time.Millisecond}, BeforeApply: []BeforeApplyHook{confirm}}
+	results, _ := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	if err := Failures(results)["api"]; err != nil {
+		t.Errorf("got %v, want the confirmed repository synced", err)
+	}
+}
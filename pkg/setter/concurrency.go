@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"sync"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+)
+
+const defaultScaleDownBelow = 1000
+
+// adaptiveSemaphore bounds the number of concurrent workers, lowering the
+// bound as the remaining rate limit approaches zero and raising it again once
+// the limit has been reset.
+type adaptiveSemaphore struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	active int
+	limit  int
+
+	min, max       int
+	scaleDownBelow int
+}
+
+// newAdaptiveSemaphore sizes the semaphore from cfg, defaulting the maximum to
+// one tenth of the number of repos with a minimum of 1.
+func newAdaptiveSemaphore(cfg config.Concurrency, repos int) *adaptiveSemaphore {
+	s := &adaptiveSemaphore{
+		min:            cfg.MinWorkers,
+		max:            cfg.MaxWorkers,
+		scaleDownBelow: cfg.ScaleDownBelow,
+	}
+	if s.max < 1 {
+		s.max = repos / 10
+	}
+	if s.max < 1 {
+		s.max = 1
+	}
+	if s.min < 1 {
+		s.min = 1
+	}
+	if s.min > s.max {
+		s.min = s.max
+	}
+	if s.scaleDownBelow < 1 {
+		s.scaleDownBelow = defaultScaleDownBelow
+	}
+	s.limit = s.max
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Acquire blocks until a worker slot is free.
+func (s *adaptiveSemaphore) Acquire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.active >= s.limit {
+		s.cond.Wait()
+	}
+	s.active++
+}
+
+// Release frees a worker slot.
+func (s *adaptiveSemaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+	s.cond.Broadcast()
+}
+
+// Update recomputes the worker limit from the remaining rate limit.
+func (s *adaptiveSemaphore) Update(remaining int) {
+	limit := s.max
+	if remaining < s.scaleDownBelow {
+		limit = s.min + (s.max-s.min)*remaining/s.scaleDownBelow
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit != s.limit {
+		logging.Debugf("Adjusting concurrency from %d to %d workers (%d requests remaining)\n", s.limit, limit, remaining)
+		s.limit = limit
+		s.cond.Broadcast()
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+)
+
+func TestAdaptiveSemaphoreDefaults(t *testing.T) {
+	s := newAdaptiveSemaphore(config.Concurrency{}, 45)
+	if s.max != 4 || s.min != 1 || s.scaleDownBelow != defaultScaleDownBelow {
+		t.Fatalf("unexpected defaults: max=%d min=%d below=%d", s.max, s.min, s.scaleDownBelow)
+	}
+
+	s = newAdaptiveSemaphore(config.Concurrency{MinWorkers: 8}, 3)
+	if s.max != 1 || s.min != 1 {
+		t.Fatalf("min should be capped by max: max=%d min=%d", s.max, s.min)
+	}
+}
+
+func TestAdaptiveSemaphoreUpdate(t *testing.T) {
+	s := newAdaptiveSemaphore(config.Concurrency{MaxWorkers: 10, MinWorkers: 2, ScaleDownBelow: 100}, 0)
+
+	tests := []struct {
+		remaining int
+		want      int
+	}{
+		{5000, 10},
+		{100, 10},
+		{50, 6},
+		{0, 2},
+		{4999, 10},
+	}
+	for _, tt := range tests {
+		s.Update(tt.remaining)
+		if s.limit != tt.want {
+			t.Errorf("Update(%d): limit = %d, want %d", tt.remaining, s.limit, tt.want)
+		}
+	}
+}
+
+func TestAdaptiveSemaphoreAcquireWaitsForRelease(t *testing.T) {
+	s := newAdaptiveSemaphore(config.Concurrency{MaxWorkers: 1}, 0)
+	s.Acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		s.Acquire()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire should block while the only slot is held")
+	default:
+	}
+
+	s.Release()
+	<-acquired
+	s.Release()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// SetEnvironments creates or updates the environments of a repository so
+// their protection rules and custom branch policies match the given ones.
+// Environments of the repository that aren't given are left untouched.
+func SetEnvironments(ctx context.Context, client ghclient.EnvironmentManager, owner, repo string, environments []types.Environment) error {
+	for _, env := range environments {
+		_, response, err := client.CreateUpdateEnvironment(ctx, owner, repo, env.Name, env.Protection)
+		if err != nil {
+			return fmt.Errorf("updating environment %q: %w", env.Name, err)
+		}
+		if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+			return fmt.Errorf("updating environment %q: %w", env.Name, err)
+		}
+
+		if !env.Protection.GetDeploymentBranchPolicy().GetCustomBranchPolicies() {
+			continue
+		}
+		if err := setBranchPolicies(ctx, client, owner, repo, env); err != nil {
+			return fmt.Errorf("updating branch policies of environment %q: %w", env.Name, err)
+		}
+	}
+	return nil
+}
+
+// setBranchPolicies creates the missing branch policies of an environment
+// and deletes those the source doesn't have.
+func setBranchPolicies(ctx context.Context, client ghclient.EnvironmentManager, owner, repo string, env types.Environment) error {
+	key := func(name, kind string) string {
+		if kind == "" {
+			kind = "branch"
+		}
+		return kind + ":" + name
+	}
+
+	existing, _, err := client.ListDeploymentBranchPolicies(ctx, owner, repo, env.Name)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*github.DeploymentBranchPolicy, len(existing.BranchPolicies))
+	for _, policy := range existing.BranchPolicies {
+		current[key(policy.GetName(), policy.GetType())] = policy
+	}
+
+	for _, policy := range env.BranchPolicies {
+		k := key(policy.GetName(), policy.GetType())
+		if _, ok := current[k]; ok {
+			delete(current, k)
+			continue
+		}
+		if _, _, err := client.CreateDeploymentBranchPolicy(ctx, owner, repo, env.Name, policy); err != nil {
+			return fmt.Errorf("creating %q: %w", policy.GetName(), err)
+		}
+	}
+	for _, policy := range current {
+		if _, err := client.DeleteDeploymentBranchPolicy(ctx, owner, repo, env.Name, policy.GetID()); err != nil {
+			return fmt.Errorf("deleting %q: %w", policy.GetName(), err)
+		}
+	}
+	return nil
+}
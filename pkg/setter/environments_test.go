@@ -0,0 +1,59 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetEnvironments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	envs := mocks.NewMockEnvironmentManager(ctrl)
+
+	production := types.Environment{
+		Name: "production",
+		Protection: &github.CreateUpdateEnvironment{
+			WaitTimer:              github.Int(30),
+			DeploymentBranchPolicy: &github.BranchPolicy{ProtectedBranches: github.Bool(false), CustomBranchPolicies: github.Bool(true)},
+		},
+		BranchPolicies: []*github.DeploymentBranchPolicyRequest{
+			{Name: github.String("main"), Type: github.String("branch")},
+			{Name: github.String("v*"), Type: github.String("tag")},
+		},
+	}
+	preview := types.Environment{Name: "preview", Protection: &github.CreateUpdateEnvironment{}}
+
+	envs.EXPECT().CreateUpdateEnvironment(gomock.Any(), "octo", "api", "production", production.Protection).Return(&github.Environment{}, okResponse(), nil)
+	envs.EXPECT().ListDeploymentBranchPolicies(gomock.Any(), "octo", "api", "production").Return(&github.DeploymentBranchPolicyResponse{
+		BranchPolicies: []*github.DeploymentBranchPolicy{
+			{ID: github.Int64(1), Name: github.String("main"), Type: github.String("branch")},
+			{ID: github.Int64(2), Name: github.String("develop"), Type: github.String("branch")},
+		},
+	}, okResponse(), nil)
+	envs.EXPECT().CreateDeploymentBranchPolicy(gomock.Any(), "octo", "api", "production", production.BranchPolicies[1]).Return(&github.DeploymentBranchPolicy{}, okResponse(), nil)
+	envs.EXPECT().DeleteDeploymentBranchPolicy(gomock.Any(), "octo", "api", "production", int64(2)).Return(okResponse(), nil)
+	envs.EXPECT().CreateUpdateEnvironment(gomock.Any(), "octo", "api", "preview", preview.Protection).Return(&github.Environment{}, okResponse(), nil)
+
+	if err := SetEnvironments(context.Background(), envs, "octo", "api", []types.Environment{production, preview}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// fieldAliases maps the short names accepted by --only and --skip to the
+// protection fields they stand for.
+var fieldAliases = map[string]string{
+	"reviews":       "required_pull_request_reviews",
+	"status_checks": "required_status_checks",
+}
+
+// keepFields copies a protection field from the current protection of the
+// target into the request.
+var keepFields = map[string]func(req, current *github.ProtectionRequest){
+	"required_status_checks": func(req, current *github.ProtectionRequest) {
+		req.RequiredStatusChecks = current.RequiredStatusChecks
+	},
+	"required_pull_request_reviews": func(req, current *github.ProtectionRequest) {
+		req.RequiredPullRequestReviews = current.RequiredPullRequestReviews
+	},
+	"enforce_admins": func(req, current *github.ProtectionRequest) {
+		req.EnforceAdmins = current.EnforceAdmins
+	},
+	"restrictions": func(req, current *github.ProtectionRequest) {
+		req.Restrictions = current.Restrictions
+	},
+	"required_linear_history": func(req, current *github.ProtectionRequest) {
+		req.RequireLinearHistory = current.RequireLinearHistory
+	},
+	"allow_force_pushes": func(req, current *github.ProtectionRequest) {
+		req.AllowForcePushes = current.AllowForcePushes
+	},
+	"allow_deletions": func(req, current *github.ProtectionRequest) {
+		req.AllowDeletions = current.AllowDeletions
+	},
+	"required_conversation_resolution": func(req, current *github.ProtectionRequest) {
+		req.RequiredConversationResolution = current.RequiredConversationResolution
+	},
+	"block_creations": func(req, current *github.ProtectionRequest) {
+		req.BlockCreations = current.BlockCreations
+	},
+	"lock_branch": func(req, current *github.ProtectionRequest) {
+		req.LockBranch = current.LockBranch
+	},
+	"allow_fork_syncing": func(req, current *github.ProtectionRequest) {
+		req.AllowForkSyncing = current.AllowForkSyncing
+	},
+	// Signed commits aren't part of the request, SignaturesSelected tells
+	// whether they are synced
+	"required_signatures": func(req, current *github.ProtectionRequest) {},
+}
+
+// SignaturesSelected tells whether the signed commits requirement is among
+// the fields to sync, all of which are when fields is nil.
+func SignaturesSelected(fields map[string]bool) bool {
+	return fields == nil || fields["required_signatures"]
+}
+
+// Fields returns the protection fields --only and --skip select from.
+func Fields() []string {
+	fields := make([]string, 0, len(keepFields))
+	for field := range keepFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// SelectFields returns the fields to sync: those of only, or every field
+// but those of skip. Both take field names or their short aliases.
+func SelectFields(only, skip []string) (map[string]bool, error) {
+	if len(only) > 0 && len(skip) > 0 {
+		return nil, fmt.Errorf("--only and --skip can't be combined")
+	}
+
+	parse := func(names []string) (map[string]bool, error) {
+		fields := make(map[string]bool, len(names))
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if alias, ok := fieldAliases[name]; ok {
+				name = alias
+			}
+			if _, ok := keepFields[name]; !ok {
+				return nil, fmt.Errorf("unknown protection field %q, expected one of %s", name, strings.Join(Fields(), ", "))
+			}
+			fields[name] = true
+		}
+		return fields, nil
+	}
+
+	if len(only) > 0 {
+		return parse(only)
+	}
+	skipped, err := parse(skip)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool, len(keepFields))
+	for field := range keepFields {
+		if !skipped[field] {
+			selected[field] = true
+		}
+	}
+	return selected, nil
+}
+
+// KeepUnselected returns a transform that resets every field not in
+// selected to the current value of the target, so fields teams manage
+// locally aren't overwritten. It has to run after the other transforms.
+func KeepUnselected(client ghclient.BranchProtectionReader, owner string, selected map[string]bool) RequestTransform {
+	return func(ctx context.Context, repo *github.Repository, req *github.ProtectionRequest) error {
+		branch := repo.GetDefaultBranch()
+		if branch == "" {
+			return nil
+		}
+		protection, err := getter.FetchBranchProtection(ctx, client, owner, repo.GetName(), branch)
+		switch {
+		case IsBranchNotFound(err):
+			return nil
+		case err != nil:
+			return fmt.Errorf("fetching the current protection: %w", err)
+		case protection == nil:
+			// The fields of an unprotected branch are all disabled
+			protection = &github.Protection{}
+		}
+
+		current := currentRequest(protection)
+		for field, keep := range keepFields {
+			if !selected[field] {
+				keep(req, current)
+			}
+		}
+		return nil
+	}
+}
+
+// currentRequest converts the current protection of a target to a request
+// keeping it as is, leaving out the sections it doesn't have.
+func currentRequest(protection *github.Protection) *github.ProtectionRequest {
+	request := protectionRequest(protection)
+	if protection.RequiredStatusChecks == nil {
+		request.RequiredStatusChecks = nil
+	}
+	if protection.RequiredPullRequestReviews == nil {
+		request.RequiredPullRequestReviews = nil
+	}
+	if protection.Restrictions == nil {
+		request.Restrictions = nil
+	}
+	return request
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSelectFields(t *testing.T) {
+	got, err := SelectFields([]string{"required_status_checks", "reviews"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]bool{"required_status_checks": true, "required_pull_request_reviews": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, err = SelectFields(nil, []string{"enforce_admins"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["enforce_admins"] || !got["restrictions"] || len(got) != len(Fields())-1 {
+		t.Errorf("got %v, want every field but enforce_admins", got)
+	}
+
+	if _, err := SelectFields([]string{"reviewers"}, nil); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if _, err := SelectFields([]string{"reviews"}, []string{"restrictions"}); err == nil {
+		t.Error("expected an error combining only and skip")
+	}
+}
+
+func TestKeepUnselected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reader := mocks.NewMockBranchProtectionReader(ctrl)
+	current := &github.Protection{
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 3, RequireLastPushApproval: true},
+		EnforceAdmins:              &github.AdminEnforcement{Enabled: false},
+		LockBranch:                 &github.LockBranch{Enabled: github.Bool(true)},
+	}
+	reader.EXPECT().GetBranchProtection(gomock.Any(), "octo", "api", "main").Return(current, okResponse(), nil)
+
+	req := protectionRequest(sourceProtection(false))
+	transform := KeepUnselected(reader, "octo", map[string]bool{"required_status_checks": true})
+	repo := &github.Repository{Name: github.String("api"), DefaultBranch: github.String("main")}
+	if err := transform(context.Background(), repo, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if req.RequiredStatusChecks == nil || len(req.RequiredStatusChecks.Contexts) == 0 {
+		t.Error("the selected status checks should come from the source")
+	}
+	prr := req.RequiredPullRequestReviews
+	if prr.RequiredApprovingReviewCount != 3 || !prr.GetRequireLastPushApproval() {
+		t.Errorf("the reviews of the target should be kept, got %+v", prr)
+	}
+	if req.EnforceAdmins || !req.GetLockBranch() || req.Restrictions != nil {
+		t.Errorf("the other fields of the target should be kept, got %+v", req)
+	}
+}
+
+func TestUnselectedSignatures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+	targets := []*github.Repository{{Name: github.String("api"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(true))}
+
+	// Only the status checks are synced, the signatures of the target stay
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+	if _, err := SetRuleset(context.Background(), client, "octo", targets, protections, Options{Fields: map[string]bool{"required_status_checks": true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+	repos.EXPECT().RequireSignaturesOnProtectedBranch(gomock.Any(), "octo", "api", "main").Return(&github.SignaturesProtectedBranch{}, okResponse(), nil)
+	if _, err := SetRuleset(context.Background(), client, "octo", targets, protections, Options{Fields: map[string]bool{"required_signatures": true}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncLockBranch(t *testing.T) {
+	source := sourceProtection(false)
+	source.LockBranch = &github.LockBranch{Enabled: github.Bool(true)}
+	targets := []*github.Repository{{Name: github.String("api"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(source)}
+
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	reader := mocks.NewMockBranchProtectionReader(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+	only := map[string]bool{"lock_branch": true}
+	reader.EXPECT().GetBranchProtection(gomock.Any(), "octo", "api", "main").Return(&github.Protection{}, okResponse(), nil)
+
+	for name, opts := range map[string]Options{
+		"every field": {},
+		"only":        {Fields: only, Transforms: []RequestTransform{KeepUnselected(reader, "octo", only)}},
+	} {
+		var body map[string]any
+		repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _, _ string, req *github.ProtectionRequest) (*github.Protection, *github.Response, error) {
+				data, _ := json.Marshal(req)
+				return &github.Protection{}, okResponse(), json.Unmarshal(data, &body)
+			})
+		if _, err := SetRuleset(context.Background(), client, "octo", targets, protections, opts); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if body["lock_branch"] != true {
+			t.Errorf("%s: sent lock_branch %v, want true", name, body["lock_branch"])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// The fixtures of testdata/protections are protection payloads as the API
+// returns them. Each has a golden file with the request converted from it
+// and one with its diff against testdata/current.json; run the tests with
+// -update after a deliberate change to regenerate them.
+func TestGoldenProtections(t *testing.T) {
+	current := readProtection(t, filepath.Join("testdata", "current.json"))
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "protections", "*.json"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures: %v", err)
+	}
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			request := convertProtectionToRequest(readProtection(t, fixture))
+
+			data, err := json.MarshalIndent(request, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, filepath.Join("testdata", "protections", name+".request.golden"), append(data, '\n'))
+
+			var diff bytes.Buffer
+			for _, d := range Diff(request, current) {
+				diff.WriteString(d.String() + "\n")
+			}
+			golden(t, filepath.Join("testdata", "protections", name+".diff.golden"), diff.Bytes())
+		})
+	}
+}
+
+func readProtection(t *testing.T, path string) *github.Protection {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var protection github.Protection
+	if err := json.Unmarshal(data, &protection); err != nil {
+		t.Fatalf("decoding %s: %v", path, err)
+	}
+	return &protection
+}
+
+// golden compares got with the content of path, or writes it with -update.
+func golden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run the tests with -update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the output:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// BeforeApplyHook is called with the final protection request of a target
+// repository right before it is applied. Returning skip leaves the repository
+// untouched; returning an error records it as a failure.
+type BeforeApplyHook func(ctx context.Context, repo *github.Repository, desired *github.ProtectionRequest) (skip bool, err error)
+
+// AfterApplyHook is called once per target repository after the sync has
+// been attempted, whether it succeeded, failed or was skipped.
+type AfterApplyHook func(ctx context.Context, repo *github.Repository, result ApplyResult)
+
+// Step syncs further settings of a target repository once its protection
+// and rulesets have been applied. A failing step fails the repository.
+type Step func(ctx context.Context, repo *github.Repository) error
+
+// ApplyResult describes the outcome of syncing a single repository.
+type ApplyResult struct {
+	// Request is the protection request that was, or would have been, applied.
+	Request *github.ProtectionRequest
+	// Skipped is set when a BeforeApply hook skipped the repository.
+	Skipped bool
+	// Empty is set when the repository has no commits and thus no branch to
+	// protect yet; only the rulesets were applied to it.
+	Empty bool
+	// UnsupportedPlan is set when the plan of the organization doesn't offer
+	// branch protection for the repository; nothing was applied to it.
+	UnsupportedPlan bool
+	// CreatedRulesets names the rulesets that were missing and created.
+	CreatedRulesets []string
+	Err             error
+}
+
+// Action classifies the outcome the way the results of SetRuleset do.
+func (r ApplyResult) Action() Action {
+	return action(r)
+}
+
+// beforeApply runs the hooks in order, stopping at the first one that skips
+// the repository or fails.
+func beforeApply(ctx context.Context, hooks []BeforeApplyHook, repo *github.Repository, desired *github.ProtectionRequest) (bool, error) {
+	for _, hook := range hooks {
+		skip, err := hook(ctx, repo, desired)
+		if skip || err != nil {
+			return skip, err
+		}
+	}
+	return false, nil
+}
+
+// afterApply runs every hook in order.
+func afterApply(ctx context.Context, hooks []AfterApplyHook, repo *github.Repository, result ApplyResult) {
+	for _, hook := range hooks {
+		hook(ctx, repo, result)
+	}
+}
+
+// runSteps runs the steps in order, stopping at the first failure.
+func runSteps(ctx context.Context, steps []Step, repo *github.Repository) error {
+	for _, step := range steps {
+		if err := step(ctx, repo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
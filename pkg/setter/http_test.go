@@ -0,0 +1,195 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/transport"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// The request GitHub receives for sourceProtection, as documented for
+// PUT /repos/{owner}/{repo}/branches/{branch}/protection.
+const wantProtectionBody = `{
+  "required_status_checks": {"strict": true, "contexts": ["ci"]},
+  "required_pull_request_reviews": {
+    "dismissal_restrictions": {"users": ["alice"], "teams": ["core"]},
+    "dismiss_stale_reviews": true,
+    "require_code_owner_reviews": true,
+    "required_approving_review_count": 2,
+    "require_last_push_approval": false
+  },
+  "enforce_admins": true,
+  "restrictions": {"users": ["bob"], "teams": ["release"], "apps": ["bot"]},
+  "required_linear_history": true,
+  "allow_force_pushes": false,
+  "allow_deletions": false,
+  "required_conversation_resolution": true,
+  "block_creations": false,
+  "lock_branch": false,
+  "allow_fork_syncing": false
+}`
+
+func TestSetRulesetOverHTTP(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	var mu sync.Mutex
+	requests := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path] = string(body)
+		mu.Unlock()
+
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /repos/octo/api/branches/main/protection":
+			io.WriteString(w, `{"required_linear_history":{"enabled":true}}`)
+		case "POST /repos/octo/api/branches/main/protection/required_signatures":
+			io.WriteString(w, `{"enabled":true}`)
+		case "GET /repos/octo/api/rulesets":
+			io.WriteString(w, `[]`)
+		case "POST /repos/octo/api/rulesets":
+			io.WriteString(w, `{"id":9,"name":"tags","enforcement":"active"}`)
+		case "PUT /repos/octo/web/branches/main/protection":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			io.WriteString(w, `{"message":"Validation Failed","errors":["Only organization repositories can have users and team restrictions"]}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	rates := &transport.RateCache{Base: http.DefaultTransport}
+	gc := github.NewClient(&http.Client{Transport: rates})
+	gc.BaseURL, _ = url.Parse(server.URL + "/")
+	client := ghclient.New(gc)
+	client.Rates = rates
+
+	targets := []*github.Repository{
+		{Name: github.String("api"), DefaultBranch: github.String("main")},
+		{Name: github.String("web"), DefaultBranch: github.String("main")},
+	}
+	protections := &types.RepoProtection{
+		BranchProtection: types.NewBranchProtection(sourceProtection(true)),
+		Rulesets:         []*github.Ruleset{{ID: github.Int64(3), Name: "tags", Enforcement: "active"}},
+	}
+
+	results, _ := SetRuleset(context.Background(), client, "octo", targets, protections, Options{})
+	if results[0].Action != ActionCreated || results[1].Action != ActionFailed {
+		t.Errorf("got actions %s and %s, want created and failed", results[0].Action, results[1].Action)
+	}
+	failures := Failures(results)
+	if len(failures) != 1 || !strings.Contains(failures["web"].Error(), "422 Validation Failed") {
+		t.Errorf("got failures %v, want the 422 of web", failures)
+	}
+
+	var got, want map[string]any
+	if err := json.Unmarshal([]byte(requests["PUT /repos/octo/api/branches/main/protection"]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(wantProtectionBody), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v\nwant %v", got, want)
+	}
+	if _, ok := requests["POST /repos/octo/api/rulesets"]; !ok {
+		t.Error("the missing ruleset wasn't created")
+	}
+
+	rate, ok := rates.Core()
+	if !ok || rate.Remaining != 4321 {
+		t.Errorf("got rate %+v, %v; want the remaining count of the responses", rate, ok)
+	}
+}
+
+func TestSetRulesetSkipsUnchanged(t *testing.T) {
+	const current = `{
+		"required_status_checks": {"strict": true, "contexts": ["ci"]},
+		"required_pull_request_reviews": {"required_approving_review_count": 1},
+		"enforce_admins": {"enabled": true},
+		"restrictions": {"users": [], "teams": [], "apps": []}
+	}`
+	var mu sync.Mutex
+	puts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/octo/api/branches/main/protection":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			io.WriteString(w, current)
+		case "PUT /repos/octo/api/branches/main/protection":
+			mu.Lock()
+			puts++
+			mu.Unlock()
+			io.WriteString(w, current)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	gc := github.NewClient(&http.Client{Transport: &transport.ETagCache{Dir: t.TempDir(), Base: http.DefaultTransport}})
+	gc.BaseURL, _ = url.Parse(server.URL + "/")
+	client := ghclient.New(gc)
+	targets := []*github.Repository{{Name: github.String("api"), DefaultBranch: github.String("main")}}
+	source := new(github.Protection)
+	if err := json.Unmarshal([]byte(current), source); err != nil {
+		t.Fatal(err)
+	}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(source)}
+	opts := Options{SkipUnchanged: true}
+
+	// The first read isn't known to be unchanged, the second is and matches
+	for run := 1; run <= 2; run++ {
+		if _, err := SetRuleset(context.Background(), client, "octo", targets, protections, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if puts != 1 {
+		t.Errorf("got %d PUTs over two runs, want the unchanged protection written once", puts)
+	}
+
+	// An unchanged protection differing from the request is still written
+	opts.Transforms = []RequestTransform{EnforceAdmins(false)}
+	if _, err := SetRuleset(context.Background(), client, "octo", targets, protections, opts); err != nil {
+		t.Fatal(err)
+	}
+	if puts != 2 {
+		t.Errorf("got %d PUTs, want the outdated protection written", puts)
+	}
+}
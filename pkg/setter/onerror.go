@@ -0,0 +1,74 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OnError is what a sync does when a repository fails.
+type OnError int
+
+const (
+	// Continue syncs the remaining repositories, unless the same failure
+	// repeats as configured by the abort_after setting.
+	Continue OnError = iota
+	// FailFast stops at the first failure.
+	FailFast
+	// Threshold stops once the failures exceed a share of the repositories.
+	Threshold
+)
+
+// FailurePolicy decides when failing repositories stop a sync.
+type FailurePolicy struct {
+	OnError OnError
+	// Percent is the share of the repositories, from 0 to 100, that may
+	// fail before a Threshold policy stops the sync.
+	Percent float64
+}
+
+// ParseFailurePolicy parses fail, continue or threshold=N%.
+func ParseFailurePolicy(s string) (FailurePolicy, error) {
+	switch s {
+	case "", "continue":
+		return FailurePolicy{OnError: Continue}, nil
+	case "fail":
+		return FailurePolicy{OnError: FailFast}, nil
+	}
+	value, ok := strings.CutPrefix(s, "threshold=")
+	if !ok {
+		return FailurePolicy{}, fmt.Errorf("unknown failure policy %q: use fail, continue or threshold=N%%", s)
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return FailurePolicy{}, fmt.Errorf("invalid failure threshold %q: use a percentage between 0 and 100", value)
+	}
+	return FailurePolicy{OnError: Threshold, Percent: percent}, nil
+}
+
+// String formats the policy as ParseFailurePolicy reads it.
+func (p FailurePolicy) String() string {
+	switch p.OnError {
+	case FailFast:
+		return "fail"
+	case Threshold:
+		return "threshold=" + strconv.FormatFloat(p.Percent, 'f', -1, 64) + "%"
+	default:
+		return "continue"
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import "testing"
+
+func TestParseFailurePolicy(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FailurePolicy
+	}{
+		{"", FailurePolicy{OnError: Continue}},
+		{"continue", FailurePolicy{OnError: Continue}},
+		{"fail", FailurePolicy{OnError: FailFast}},
+		{"threshold=10%", FailurePolicy{OnError: Threshold, Percent: 10}},
+		{"threshold=2.5", FailurePolicy{OnError: Threshold, Percent: 2.5}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFailurePolicy(tt.in)
+		if err != nil {
+			t.Errorf("ParseFailurePolicy(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFailurePolicy(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if tt.in != "" && tt.in != "threshold=2.5" && got.String() != tt.in {
+			t.Errorf("%+v.String() = %q, want %q", got, got.String(), tt.in)
+		}
+	}
+
+	for _, in := range []string{"stop", "threshold=", "threshold=abc%", "threshold=150%", "threshold=-1%"} {
+		if _, err := ParseFailurePolicy(in); err == nil {
+			t.Errorf("ParseFailurePolicy(%q) succeeded", in)
+		}
+	}
+}
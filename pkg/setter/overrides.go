@@ -0,0 +1,49 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// EnforceAdmins returns a transform enforcing the protection for admins, or
+// lifting it, on every target whatever the source has.
+func EnforceAdmins(enabled bool) RequestTransform {
+	return func(_ context.Context, _ *github.Repository, req *github.ProtectionRequest) error {
+		req.EnforceAdmins = enabled
+		return nil
+	}
+}
+
+// MinReviews returns a transform raising the number of approving reviews
+// required on every target to at least n.
+func MinReviews(n int) RequestTransform {
+	return func(_ context.Context, _ *github.Repository, req *github.ProtectionRequest) error {
+		if req.RequiredPullRequestReviews == nil {
+			req.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{}
+		} else {
+			// The request may share its reviews with the source
+			reviews := *req.RequiredPullRequestReviews
+			req.RequiredPullRequestReviews = &reviews
+		}
+		if req.RequiredPullRequestReviews.RequiredApprovingReviewCount < n {
+			req.RequiredPullRequestReviews.RequiredApprovingReviewCount = n
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestEnforceAdmins(t *testing.T) {
+	repo := &github.Repository{Name: github.String("api")}
+	for _, enabled := range []bool{true, false} {
+		req := &github.ProtectionRequest{EnforceAdmins: !enabled}
+		if err := EnforceAdmins(enabled)(context.Background(), repo, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if req.EnforceAdmins != enabled {
+			t.Errorf("got %v, want %v", req.EnforceAdmins, enabled)
+		}
+	}
+}
+
+func TestMinReviews(t *testing.T) {
+	repo := &github.Repository{Name: github.String("api")}
+	tests := []struct {
+		reviews *github.PullRequestReviewsEnforcementRequest
+		want    int
+	}{
+		{nil, 2},
+		{&github.PullRequestReviewsEnforcementRequest{RequiredApprovingReviewCount: 1}, 2},
+		{&github.PullRequestReviewsEnforcementRequest{RequiredApprovingReviewCount: 4}, 4},
+	}
+	for _, tt := range tests {
+		req := &github.ProtectionRequest{RequiredPullRequestReviews: tt.reviews}
+		if err := MinReviews(2)(context.Background(), repo, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := req.RequiredPullRequestReviews.RequiredApprovingReviewCount; got != tt.want {
+			t.Errorf("got %d reviews, want %d", got, tt.want)
+		}
+		if tt.reviews != nil && tt.reviews.RequiredApprovingReviewCount == 1 && req.RequiredPullRequestReviews == tt.reviews {
+			t.Error("the reviews of the source should not be modified")
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/google/go-github/v59/github"
+)
+
+// PermissionError reports a repository the credentials can see but not
+// administer, such as one where they only have the security manager role.
+type PermissionError struct {
+	// Permission is the access level of the credentials to the repository,
+	// or empty when the API doesn't report it, as for a GitHub App.
+	Permission string
+	Err        error
+}
+
+func (e *PermissionError) Error() string {
+	if e.Permission == "" {
+		return "insufficient permission: branch protection requires admin access"
+	}
+	return fmt.Sprintf("insufficient permission: the credentials have %s access, branch protection requires admin", e.Permission)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// classifyForbidden turns the 403 of a repository the credentials can't
+// administer into a PermissionError naming their access level, fetched from
+// the API. Other errors are returned unchanged.
+func classifyForbidden(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo string, err error) error {
+	var ghErr *github.ErrorResponse
+	if !errors.As(err, &ghErr) || ghErr.Response == nil || ghErr.Response.StatusCode != http.StatusForbidden {
+		return err
+	}
+
+	permErr := &PermissionError{Err: err}
+	if r, _, getErr := client.Get(ctx, owner, repo); getErr == nil {
+		permErr.Permission = permissionLevel(r.GetPermissions())
+	}
+	// An admin can still be refused, as for a private repository on a plan
+	// without branch protection
+	if permErr.Permission == "admin" {
+		return err
+	}
+	return permErr
+}
+
+// permissionLevel returns the highest access level of a permissions map.
+func permissionLevel(permissions map[string]bool) string {
+	for _, level := range []struct{ key, name string }{
+		{"admin", "admin"},
+		{"maintain", "maintain"},
+		{"push", "write"},
+		{"triage", "triage"},
+		{"pull", "read"},
+	} {
+		if permissions[level.key] {
+			return level.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClassifyForbidden(t *testing.T) {
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}, Message: "Resource not accessible by integration"}
+
+	tests := []struct {
+		name        string
+		permissions map[string]bool
+		want        string
+	}{
+		{"security manager", map[string]bool{"push": true, "triage": true, "pull": true}, "insufficient permission: the credentials have write access, branch protection requires admin"},
+		{"app", nil, "insufficient permission: branch protection requires admin access"},
+		{"admin", map[string]bool{"admin": true, "pull": true}, forbidden.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repos := mocks.NewMockRepositories(ctrl)
+			repos.EXPECT().Get(gomock.Any(), "octo", "svc").Return(&github.Repository{Permissions: tt.permissions}, okResponse(), nil)
+
+			err := classifyForbidden(context.Background(), repos, "octo", "svc", forbidden)
+			if err.Error() != tt.want {
+				t.Errorf("got %q, want %q", err, tt.want)
+			}
+			if !errors.Is(err, forbidden) {
+				t.Error("the classified error should wrap the API error")
+			}
+		})
+	}
+
+	other := errors.New("boom")
+	if err := classifyForbidden(context.Background(), nil, "octo", "svc", other); err != other {
+		t.Errorf("got %v, want other errors unchanged", err)
+	}
+}
+
+func TestSetRulesetReportsInsufficientPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{{Name: github.String("svc"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}, Message: "Must have admin rights to Repository."}
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "svc", "main", gomock.Any()).Return(nil, nil, forbidden)
+	repos.EXPECT().Get(gomock.Any(), "octo", "svc").Return(&github.Repository{Permissions: map[string]bool{"maintain": true}}, okResponse(), nil)
+
+	results, _ := SetRuleset(context.Background(), client, "octo", targets, protections, Options{})
+	failures := Failures(results)
+	var permErr *PermissionError
+	if !errors.As(failures["svc"], &permErr) || permErr.Permission != "maintain" {
+		t.Errorf("got %v, want an insufficient permission error", failures["svc"])
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+)
+
+// ErrMaxWait is returned instead of waiting for a rate limit reset further
+// away than the configured maximum wait.
+var ErrMaxWait = errors.New("the rate limit resets after the maximum wait")
+
+// RateLimitWait describes a wait for the rate limit to reset.
+type RateLimitWait struct {
+	// Resume is when requests are sent again.
+	Resume time.Time
+	Wait   time.Duration
+	// Remaining is the number of repositories not synced yet.
+	Remaining int
+}
+
+// RateLimitHook is notified once per wait for the rate limit to reset.
+type RateLimitHook func(ctx context.Context, wait RateLimitWait)
+
+// rateLimiter is shared by the workers of a sync. It adjusts the concurrency
+// to the remaining rate limit, as last reported by an API response, and once
+// the limit ran out holds every worker until the same reset, reporting it
+// once. A reset further away than maxWait fails the workers instead.
+type rateLimiter struct {
+	rates     ghclient.RateCache
+	semaphore *adaptiveSemaphore
+	maxWait   time.Duration
+	onWait    RateLimitHook
+	// remaining counts the repositories not synced yet
+	remaining int64
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+func newRateLimiter(rates ghclient.RateCache, semaphore *adaptiveSemaphore) *rateLimiter {
+	return &rateLimiter{rates: rates, semaphore: semaphore}
+}
+
+// done records that a repository was synced.
+func (l *rateLimiter) done() {
+	atomic.AddInt64(&l.remaining, -1)
+}
+
+// Wait returns once requests may be sent again, or with the error of ctx.
+// Nothing is known before the first response, or without a rate cache.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l.rates == nil {
+		return nil
+	}
+
+	var started *RateLimitWait
+	l.mu.Lock()
+	if rate, ok := l.rates.Core(); ok {
+		l.semaphore.Update(rate.Remaining)
+		// A buffer ensures the limit has reset
+		reset := rate.Reset.Add(time.Second)
+		if rate.Remaining < 1 && reset.After(l.until) && time.Now().Before(reset) {
+			wait := time.Until(reset).Round(time.Second)
+			if l.maxWait > 0 && wait > l.maxWait {
+				l.mu.Unlock()
+				return fmt.Errorf("%w of %v: it resets at %v, in %v", ErrMaxWait, l.maxWait, rate.Reset.Time, wait)
+			}
+			l.until = reset
+			started = &RateLimitWait{Resume: reset, Wait: wait, Remaining: int(atomic.LoadInt64(&l.remaining))}
+		}
+	}
+	until := l.until
+	l.mu.Unlock()
+
+	if started != nil {
+		log.Printf("Rate limit exceeded. Waiting until %v (%v) with %d repositories left\n", started.Resume, started.Wait, started.Remaining)
+		if l.onWait != nil {
+			l.onWait(ctx, *started)
+		}
+	}
+
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimiterScales(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockRateCache(ctrl)
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 50}, true)
+
+	semaphore := newAdaptiveSemaphore(config.Concurrency{MaxWorkers: 10, ScaleDownBelow: 100}, 0)
+	if err := newRateLimiter(rates, semaphore).Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if semaphore.limit != 5 {
+		t.Errorf("got limit %d, want the concurrency scaled to the cached rate", semaphore.limit)
+	}
+
+	// Nothing is known before the first response
+	rates.EXPECT().Core().Return(github.Rate{}, false)
+	newRateLimiter(rates, semaphore).Wait(context.Background())
+	newRateLimiter(nil, semaphore).Wait(context.Background())
+	if semaphore.limit != 5 {
+		t.Errorf("got limit %d, want it unchanged", semaphore.limit)
+	}
+}
+
+func TestRateLimiterWaitsForTheReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockRateCache(ctrl)
+	// The limiter adds a second to the reset, leaving half a second to wait
+	reset := github.Timestamp{Time: time.Now().Add(-500 * time.Millisecond)}
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 0, Reset: reset}, true).Times(3)
+
+	limiter := newRateLimiter(rates, newAdaptiveSemaphore(config.Concurrency{}, 0))
+	started := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(started); elapsed < 400*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("the workers waited %v, want them held until the reset", elapsed)
+	}
+
+	// Once the reset passed, the stale count doesn't hold the workers
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 0, Reset: reset}, true)
+	started = time.Now()
+	limiter.Wait(context.Background())
+	if elapsed := time.Since(started); elapsed > 100*time.Millisecond {
+		t.Errorf("waited %v after the reset", elapsed)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockRateCache(ctrl)
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 0, Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}}, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newRateLimiter(rates, newAdaptiveSemaphore(config.Concurrency{}, 0)).Wait(ctx); err != context.Canceled {
+		t.Errorf("got %v, want the wait to be canceled", err)
+	}
+}
+
+func TestRateLimiterReportsTheWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockRateCache(ctrl)
+	reset := github.Timestamp{Time: time.Now().Add(-500 * time.Millisecond)}
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 0, Reset: reset}, true).Times(2)
+
+	limiter := newRateLimiter(rates, newAdaptiveSemaphore(config.Concurrency{}, 0))
+	limiter.remaining = 7
+	var waits []RateLimitWait
+	limiter.onWait = func(_ context.Context, wait RateLimitWait) { waits = append(waits, wait) }
+	limiter.Wait(context.Background())
+	limiter.Wait(context.Background())
+	if len(waits) != 1 || waits[0].Remaining != 7 || !waits[0].Resume.Equal(reset.Add(time.Second)) {
+		t.Errorf("got waits %+v, want a single one with the remaining repositories", waits)
+	}
+}
+
+func TestRateLimiterMaxWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockRateCache(ctrl)
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 0, Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}}, true)
+
+	limiter := newRateLimiter(rates, newAdaptiveSemaphore(config.Concurrency{}, 0))
+	limiter.maxWait = time.Minute
+	limiter.onWait = func(context.Context, RateLimitWait) { t.Error("reported a wait beyond the maximum") }
+	started := time.Now()
+	if err := limiter.Wait(context.Background()); !errors.Is(err, ErrMaxWait) {
+		t.Errorf("got %v, want ErrMaxWait", err)
+	}
+	if elapsed := time.Since(started); elapsed > 100*time.Millisecond {
+		t.Errorf("waited %v before giving up", elapsed)
+	}
+}
+
+func TestSetRulesetMaxWaitNotifiesHooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rates := mocks.NewMockRateCache(ctrl)
+	rates.EXPECT().Core().Return(github.Rate{Remaining: 0, Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}}, true).AnyTimes()
+	client := &ghclient.Client{Repositories: mocks.NewMockRepositories(ctrl), Rates: rates}
+	targets := []*github.Repository{{Name: github.String("api"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(&github.Protection{})}
+
+	var notified []ApplyResult
+	opts := Options{
+		Concurrency: config.Concurrency{MaxWait: time.Minute},
+		OnError:     FailurePolicy{OnError: Continue},
+		AfterApply: []AfterApplyHook{func(_ context.Context, _ *github.Repository, result ApplyResult) {
+			notified = append(notified, result)
+		}},
+	}
+	results, err := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrMaxWait) {
+		t.Errorf("got %+v, want api failed with ErrMaxWait", results)
+	}
+	if len(notified) != 1 || !errors.Is(notified[0].Err, ErrMaxWait) {
+		t.Errorf("the after apply hooks were notified of %+v, want the failure", notified)
+	}
+}
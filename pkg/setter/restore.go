@@ -0,0 +1,106 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Restore sets the branch protection and rulesets of a repository back to a
+// previous state. A nil protection removes the protection of the branch, and
+// the rulesets of the repository missing from rulesets are deleted.
+func Restore(ctx context.Context, client ghclient.Repositories, owner, repo, branch string, protection *types.BranchProtection, rulesets []*github.Ruleset) error {
+	if branch != "" {
+		if err := restoreProtection(ctx, client, owner, repo, branch, protection); err != nil {
+			return err
+		}
+	}
+
+	existing, err := helpers.TargetRulesets(ctx, client, owner, repo)
+	if err != nil {
+		return fmt.Errorf("listing rulesets: %w", err)
+	}
+	keep := make(map[string]bool, len(rulesets))
+	for _, ruleset := range rulesets {
+		keep[ruleset.Name] = true
+	}
+	for name, ruleset := range existing {
+		if keep[name] {
+			continue
+		}
+		response, err := client.DeleteRuleset(ctx, owner, repo, ruleset.GetID())
+		if err == nil {
+			err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("deleting ruleset %q: %w", name, err)
+		}
+	}
+	_, err = setRulesSets(ctx, client, owner, repo, branch, rulesets)
+	return err
+}
+
+// restoreProtection applies the protection to the branch, or removes the
+// protection of the branch when nil.
+func restoreProtection(ctx context.Context, client ghclient.BranchProtectionWriter, owner, repo, branch string, protection *types.BranchProtection) error {
+	if protection == nil {
+		response, err := client.RemoveBranchProtection(ctx, owner, repo, branch)
+		var ghErr *github.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound {
+			// Already unprotected
+			return nil
+		}
+		if err == nil {
+			err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("removing branch protection: %w", err)
+		}
+		return nil
+	}
+
+	applied, response, err := client.UpdateBranchProtection(ctx, owner, repo, branch, protectionRequest(protection.GitHub()))
+	if err == nil {
+		err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+	}
+	if err != nil {
+		return fmt.Errorf("updating branch protection: %w", err)
+	}
+	// Signatures aren't part of the protection request
+	switch signed := applied.GetRequiredSignatures().GetEnabled(); {
+	case protection.SignedCommits && !signed:
+		_, response, err = client.RequireSignaturesOnProtectedBranch(ctx, owner, repo, branch)
+	case !protection.SignedCommits && signed:
+		response, err = client.OptionalSignaturesOnProtectedBranch(ctx, owner, repo, branch)
+	default:
+		return nil
+	}
+	if err == nil {
+		err = helpers.HTTPStatusCodeCheck(response.StatusCode)
+	}
+	if err != nil {
+		return fmt.Errorf("restoring required signatures: %w", err)
+	}
+	return nil
+}
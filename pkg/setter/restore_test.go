@@ -0,0 +1,69 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	ctx := context.Background()
+
+	protection := &types.BranchProtection{EnforceAdmins: true}
+	applied := &github.Protection{RequiredSignatures: &github.SignaturesProtectedBranch{Enabled: github.Bool(true)}}
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _ string, req *github.ProtectionRequest) (*github.Protection, *github.Response, error) {
+			if !req.EnforceAdmins {
+				t.Errorf("restored request %+v", req)
+			}
+			return applied, okResponse(), nil
+		})
+	repos.EXPECT().OptionalSignaturesOnProtectedBranch(gomock.Any(), "octo", "api", "main").Return(okResponse(), nil)
+
+	kept := &github.Ruleset{ID: github.Int64(1), Name: "main"}
+	created := &github.Ruleset{ID: github.Int64(2), Name: "tags"}
+	summaries := []*github.Ruleset{kept, created}
+	repos.EXPECT().GetAllRulesets(gomock.Any(), "octo", "api", false).Return(summaries, okResponse(), nil).Times(2)
+	repos.EXPECT().DeleteRuleset(gomock.Any(), "octo", "api", int64(2)).Return(okResponse(), nil)
+	repos.EXPECT().UpdateRuleset(gomock.Any(), "octo", "api", int64(1), kept).Return(kept, okResponse(), nil)
+
+	if err := Restore(ctx, repos, "octo", "api", "main", protection, []*github.Ruleset{kept}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRestoreUnprotected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	repos.EXPECT().RemoveBranchProtection(gomock.Any(), "octo", "api", "main").Return(nil, notFound)
+	repos.EXPECT().GetAllRulesets(gomock.Any(), "octo", "api", false).Return(nil, okResponse(), nil)
+
+	// A branch that is already unprotected stays so
+	if err := Restore(context.Background(), repos, "octo", "api", "main", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
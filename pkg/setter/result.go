@@ -0,0 +1,72 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"time"
+)
+
+// Action is what a sync did to a repository.
+type Action string
+
+const (
+	// ActionCreated means the protection was applied and rulesets missing
+	// from the repository were created.
+	ActionCreated Action = "created"
+	// ActionUpdated means the protection and the existing rulesets were
+	// applied. The protection is replaced as a whole, so a sync can't tell
+	// whether the branch was protected before.
+	ActionUpdated Action = "updated"
+	// ActionSkipped means nothing was applied, as asked by a hook or because
+	// the plan of the organization doesn't offer branch protection.
+	ActionSkipped Action = "skipped"
+	ActionFailed  Action = "failed"
+)
+
+// RepoResult is the outcome of syncing a single repository.
+type RepoResult struct {
+	Repo     string
+	Action   Action
+	Duration time.Duration
+	// Err is the error of a failed repository, the API error when it was
+	// rejected by GitHub.
+	Err error
+}
+
+// Failures returns the errors of the failed results keyed by repository.
+func Failures(results []RepoResult) map[string]error {
+	failures := make(map[string]error)
+	for _, r := range results {
+		if r.Err != nil {
+			failures[r.Repo] = r.Err
+		}
+	}
+	return failures
+}
+
+// action classifies the outcome of syncRepo.
+func action(result ApplyResult) Action {
+	switch {
+	case result.Err != nil:
+		return ActionFailed
+	case result.Skipped, result.UnsupportedPlan:
+		return ActionSkipped
+	case len(result.CreatedRulesets) > 0:
+		return ActionCreated
+	default:
+		return ActionUpdated
+	}
+}
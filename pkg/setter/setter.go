@@ -16,125 +16,341 @@ limitations under the License.
 package setter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
 	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
 	"github.com/arush-sal/repo-protection-sync/pkg/types"
 	"github.com/google/go-github/v59/github"
 )
 
-var signedCommits bool
+// RequestTransform adjusts the protection request of a single target
+// repository before it is applied. The request is a fresh copy per target,
+// but nested values may be shared with the source and must be replaced
+// rather than modified in place.
+type RequestTransform func(ctx context.Context, repo *github.Repository, req *github.ProtectionRequest) error
+
+// Options tunes how SetRuleset applies the protection.
+type Options struct {
+	// Transforms are applied in order to the request of every target.
+	Transforms  []RequestTransform
+	Concurrency config.Concurrency
+	// BeforeApply hooks run after the transforms and may veto a target.
+	BeforeApply []BeforeApplyHook
+	// AfterApply hooks are notified of the outcome of every target.
+	AfterApply []AfterApplyHook
+	// Steps sync further repository settings after the protection.
+	Steps []Step
+	// OnError decides when failing repositories stop the sync.
+	OnError FailurePolicy
+	// OnRateLimit is notified when the sync waits for the rate limit to
+	// reset.
+	OnRateLimit RateLimitHook
+	// Fields are the protection fields to sync, every one when nil. The
+	// KeepUnselected transform keeps the others in the request; Fields
+	// covers the signed commits, which aren't part of it.
+	Fields map[string]bool
+	// SkipUnchanged reads the protection of every target first, and doesn't
+	// write it when the ETag cache answered it unchanged and it already is
+	// the requested one. The read costs no rate limit when unchanged.
+	SkipUnchanged bool
+}
 
 // SetRuleset sets the branch protection rules for the list of repositories provided
-// under a particular GitHub user or organization.
-func SetRuleset(ctx context.Context, client *github.Client, owner string, repos []*github.Repository, protections *types.RepoProtection) {
-
-	// Calculate the number of semaphores as one tenth of the total number of repos
-	// with a minimum of 1
-	semaphoreCount := len(repos) / 10
-	if semaphoreCount < 1 {
-		semaphoreCount = 1
-	}
-	semaphore := make(chan struct{}, semaphoreCount)
+// under a particular GitHub user or organization. A repository that rejects the
+// protection doesn't stop the others from being synced; a result is returned
+// for every repository, in order. The error is set when the run was aborted
+// after too many identical failures or as the failure policy of opts says,
+// which the remaining repositories fail with.
+func SetRuleset(ctx context.Context, client *ghclient.Client, owner string, repos []*github.Repository, protections *types.RepoProtection, opts Options) ([]RepoResult, error) {
+
+	// The number of workers scales with the remaining rate limit
+	semaphore := newAdaptiveSemaphore(opts.Concurrency, len(repos))
+	breaker := newCircuitBreaker(opts.Concurrency.AbortAfter, opts.OnError, len(repos))
+	timeout := repoTimeout(opts.Concurrency.RepoTimeout)
+	limiter := newRateLimiter(client.Rates, semaphore)
+	limiter.maxWait = opts.Concurrency.MaxWait
+	limiter.onWait = opts.OnRateLimit
+	limiter.remaining = int64(len(repos))
 
 	var wg sync.WaitGroup
-
-	for _, repo := range repos {
+	// Every goroutine writes its own element
+	results := make([]RepoResult, len(repos))
+
+	aborted := 0
+	for i, repo := range repos {
+		semaphore.Acquire()
+		if err := breaker.Tripped(); err != nil {
+			semaphore.Release()
+			limiter.done()
+			if repo != nil && repo.Name != nil {
+				results[i] = RepoResult{Repo: *repo.Name, Action: ActionFailed, Err: err}
+				aborted++
+			}
+			continue
+		}
 		wg.Add(1)
-		semaphore <- struct{}{} // Acquire a semaphore
 
-		go func(repo *github.Repository) {
+		go func(i int, repo *github.Repository) {
 			defer wg.Done()
-			defer func() { <-semaphore }() // Release the semaphore
+			defer semaphore.Release()
+			defer limiter.done()
 
-			if repo == nil || repo.Name == nil || repo.DefaultBranch == nil {
+			if repo == nil || repo.Name == nil {
 				log.Printf("Skipping repository due to missing information: %+v\n", repo)
 				return
 			}
 
-			// Check and handle rate limit before attempting to set branch protection
-			if !checkAndHandleRateLimit(ctx, client) {
-				log.Printf("Failed to handle rate limit, skipping repo: %s\n", *repo.Name)
-				return
+			// Check and handle rate limit before attempting to set branch protection.
+			// A repository given up on while waiting is a failure like any other
+			var result ApplyResult
+			started := time.Now()
+			if err := limiter.Wait(ctx); err != nil {
+				result.Err = err
+			} else {
+				started = time.Now()
+				result = syncRepo(ctx, client, owner, repo, protections, opts, timeout)
 			}
+			breaker.Record(result.Err)
+			results[i] = RepoResult{Repo: *repo.Name, Action: action(result), Duration: time.Since(started), Err: result.Err}
+			afterApply(ctx, opts.AfterApply, repo, result)
+		}(i, repo)
+	}
 
-			log.Printf("Starting branch protection sync for repo %s...", *repo.Name)
-
-			err := setBranchProtectionRules(ctx, client, owner, *repo.Name, *repo.DefaultBranch, convertProtectionToRequest(protections.BranchProtection))
-			if err != nil {
-				log.Fatalf("Error applying branch protection to repo %s: %v\n", *repo.Name, err)
-			}
+	wg.Wait() // Wait for all goroutines to complete
 
-			err = setRulesSets(ctx, client, owner, *repo.Name, *repo.DefaultBranch, protections.Rulesets)
-			if err != nil {
-				log.Fatalf("Error applying ruleset to repo %s: %v\n", *repo.Name, err)
-			}
+	// Repositories missing a name have no result
+	named := results[:0]
+	for _, r := range results {
+		if r.Repo != "" {
+			named = append(named, r)
+		}
+	}
 
-			log.Printf("Branch protection and Rulesets applied to repo %s successfully\n", *repo.Name)
-		}(repo)
+	err := breaker.Tripped()
+	if err != nil {
+		log.Printf("%d repositories were not synced; fix the cause and run the sync again\n", aborted)
 	}
+	return named, err
+}
 
-	wg.Wait() // Wait for all goroutines to complete
+// Desired returns the protection request for a target: the protection of
+// the source with the transforms of opts applied.
+func Desired(ctx context.Context, repo *github.Repository, protections *types.RepoProtection, opts Options) (*github.ProtectionRequest, error) {
+	request := convertProtectionToRequest(protections.BranchProtection.GitHub())
+	for _, transform := range opts.Transforms {
+		if err := transform(ctx, repo, request); err != nil {
+			return request, err
+		}
+	}
+	return request, nil
 }
 
-// checkAndHandleRateLimit checks the rate limit for the GitHub API and
-// in case if the rate limiting exceeds it handles the particular scenario by
-// adding a wait time before the next request is made.
-func checkAndHandleRateLimit(ctx context.Context, client *github.Client) bool {
-	rateLimits, _, err := client.RateLimit.Get(ctx)
+// syncRepo applies the protection and rulesets to a single repository. The
+// timeout bounds preparing the request and applying it, but not the hooks in
+// between, which may wait for a person to confirm.
+func syncRepo(ctx context.Context, client *ghclient.Client, owner string, repo *github.Repository, protections *types.RepoProtection, opts Options, timeout time.Duration) ApplyResult {
+	logging.Infof("Starting branch protection sync for repo %s...", *repo.Name)
+
+	prepareCtx, cancel := context.WithTimeout(ctx, timeout)
+	request, err := Desired(prepareCtx, repo, protections, opts)
+	err = timedOut(prepareCtx, err, timeout)
+	cancel()
+	result := ApplyResult{Request: request}
 	if err != nil {
-		log.Fatalf("Failed to fetch rate limit: %v\n", err)
-		return false
+		log.Printf("Error preparing branch protection for repo %s: %v\n", *repo.Name, err)
+		result.Err = err
+		return result
 	}
 
-	if rateLimits.Core.Remaining < 1 {
-		resetTime := rateLimits.Core.Reset.Time
-		waitDuration := time.Until(resetTime)
-		log.Printf("Rate limit exceeded. Waiting until %v (%v)\n", resetTime, waitDuration)
-		time.Sleep(waitDuration + time.Second) // Add a buffer to ensure limit has reset
+	skip, err := beforeApply(ctx, opts.BeforeApply, repo, request)
+	if err != nil {
+		log.Printf("Branch protection for repo %s rejected: %v\n", *repo.Name, err)
+		result.Err = err
+		return result
+	}
+	if skip {
+		log.Printf("Skipping repo %s as requested by a hook\n", *repo.Name)
+		result.Skipped = true
+		return result
 	}
 
-	return true
+	applyCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result = applyRepo(applyCtx, client, owner, repo, protections, opts, result)
+	result.Err = timedOut(applyCtx, result.Err, timeout)
+	return result
 }
 
-func setRulesSets(ctx context.Context, client *github.Client, owner, repo, branch string, rulesets []*github.Ruleset) error {
+// timedOut explains err when ctx, bounding a part of the sync of a
+// repository, ran out of time.
+func timedOut(ctx context.Context, err error, timeout time.Duration) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("sync timed out after %s: %w", timeout, ctx.Err())
+	}
+	return err
+}
+
+// applyRepo applies the request of result, then the rulesets and settings,
+// to a single repository.
+func applyRepo(ctx context.Context, client *ghclient.Client, owner string, repo *github.Repository, protections *types.RepoProtection, opts Options, result ApplyResult) ApplyResult {
+	request := result.Request
 	var err error
+
+	// An empty repository has no branch to protect until its first push, but
+	// its rulesets still apply to the default branch once it exists
+	branch := repo.GetDefaultBranch()
+	signed := protections.BranchProtection.SignedCommits && SignaturesSelected(opts.Fields)
+	switch {
+	case branch == "":
+	case opts.SkipUnchanged && upToDate(ctx, client.Repositories, owner, *repo.Name, branch, request, signed):
+		logging.Infof("Branch protection of repo %s is unchanged and up to date, not writing it\n", *repo.Name)
+	default:
+		err = setBranchProtectionRules(ctx, client.Repositories, owner, *repo.Name, branch, request, signed)
+	}
+	if IsUnsupportedPlan(err) {
+		// Rulesets are unavailable on the plan as well
+		log.Printf("Skipping repo %s: branch protection isn't available for private repositories on the plan of %s\n", *repo.Name, owner)
+		result.UnsupportedPlan = true
+		return result
+	}
+	if branch == "" || IsBranchNotFound(err) {
+		logging.Infof("Repo %s is empty, applying rulesets only\n", *repo.Name)
+		result.Empty = true
+	} else if err != nil {
+		err = classifyForbidden(ctx, client.Repositories, owner, *repo.Name, err)
+		log.Printf("Error applying branch protection to repo %s: %v\n", *repo.Name, err)
+		result.Err = err
+		return result
+	}
+
+	result.CreatedRulesets, err = setRulesSets(ctx, client.Repositories, owner, *repo.Name, branch, protections.Rulesets)
+	if err != nil {
+		log.Printf("Error applying ruleset to repo %s: %v\n", *repo.Name, err)
+		result.Err = err
+		return result
+	}
+
+	if err := runSteps(ctx, opts.Steps, repo); err != nil {
+		log.Printf("Error syncing the settings of repo %s: %v\n", *repo.Name, err)
+		result.Err = err
+		return result
+	}
+
+	if result.Empty {
+		logging.Infof("Rulesets applied to empty repo %s successfully\n", *repo.Name)
+		return result
+	}
+	logging.Infof("Branch protection and Rulesets applied to repo %s successfully\n", *repo.Name)
+	return result
+}
+
+// upToDate tells whether the protection of a branch is unchanged since it
+// was last read and already matches the request. Any doubt, such as a failed
+// read, leaves it to be written.
+func upToDate(ctx context.Context, client ghclient.BranchProtectionReader, owner, repo, branch string, request *github.ProtectionRequest, signed bool) bool {
+	current, unchanged, err := getter.FetchBranchProtectionUnchanged(ctx, client, owner, repo, branch)
+	if err != nil || !unchanged || current == nil {
+		return false
+	}
+	if signed && !current.GetRequiredSignatures().GetEnabled() {
+		return false
+	}
+	// Every field counts, not only the ones Diff reports
+	want, err := json.Marshal(request)
+	if err != nil {
+		return false
+	}
+	got, err := json.Marshal(currentRequest(current))
+	return err == nil && bytes.Equal(want, got)
+}
+
+// IsBranchNotFound reports whether err is the 404 GitHub returns when
+// protecting a branch that doesn't exist, as in an empty repository.
+func IsBranchNotFound(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) &&
+		ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound &&
+		ghErr.Message == "Branch not found"
+}
+
+// IsUnsupportedPlan reports whether err is the 403 GitHub returns for the
+// protection of a private repository in an organization on the free plan.
+func IsUnsupportedPlan(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) &&
+		ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusForbidden &&
+		strings.Contains(ghErr.Message, "Upgrade to GitHub Pro")
+}
+
+// setRulesSets applies the rulesets to a repository, returning the names of
+// the ones it created. The rulesets of the repository are listed once and
+// matched by name; an existing one is updated under its own ID.
+func setRulesSets(ctx context.Context, client ghclient.RulesetManager, owner, repo, branch string, rulesets []*github.Ruleset) ([]string, error) {
+	if len(rulesets) == 0 {
+		return nil, nil
+	}
+	existing, err := helpers.TargetRulesets(ctx, client, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("listing rulesets: %w", err)
+	}
+
+	var created []string
 	for _, ruleset := range rulesets {
-		if helpers.DoesRulesetExist(ctx, client, owner, repo, branch, ruleset.Name) {
-			_, response, err := client.Repositories.UpdateRuleset(ctx, owner, repo, ruleset.GetID(), ruleset)
-			switch {
-			case err != nil:
-				log.Fatalf("Error updating branch ruleset: %v\n", err)
-			case helpers.HTTPStatusCodeCheck(response.StatusCode) != nil:
-				log.Fatalf("Error updating branch ruleset: %v\n", helpers.HTTPStatusCodeCheck(response.StatusCode))
+		if target, ok := existing[ruleset.Name]; ok {
+			_, response, err := client.UpdateRuleset(ctx, owner, repo, target.GetID(), ruleset)
+			if err != nil {
+				return created, fmt.Errorf("updating ruleset %q: %w", ruleset.Name, err)
+			}
+			if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+				return created, fmt.Errorf("updating ruleset %q: %w", ruleset.Name, err)
 			}
 		} else {
-			_, response, err := client.Repositories.CreateRuleset(ctx, owner, repo, ruleset)
-			switch {
-			case err != nil:
-				log.Fatalf("Error creating branch ruleset: %v\n", err)
-			case helpers.HTTPStatusCodeCheck(response.StatusCode) != nil:
-				log.Fatalf("Error creating branch ruleset: %v\n", helpers.HTTPStatusCodeCheck(response.StatusCode))
+			_, response, err := client.CreateRuleset(ctx, owner, repo, ruleset)
+			if err != nil {
+				return created, fmt.Errorf("creating ruleset %q: %w", ruleset.Name, err)
+			}
+			if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+				return created, fmt.Errorf("creating ruleset %q: %w", ruleset.Name, err)
 			}
+			created = append(created, ruleset.Name)
 		}
 	}
-	return err
+	return created, nil
 }
 
-// setBranchProtectionRules applies branch protection rules to a specified branch in a GitHub repository.
+// setBranchProtectionRules applies branch protection rules to a specified branch in a GitHub repository,
+// requiring signed commits when signed is set, as the request has no field for them.
 // can't find an API for "Require deployments to succeed before merging" check
-func setBranchProtectionRules(ctx context.Context, client *github.Client, owner, repo, branch string, protection *github.ProtectionRequest) error {
-	_, response, err := client.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, protection)
+func setBranchProtectionRules(ctx context.Context, client ghclient.BranchProtectionWriter, owner, repo, branch string, protection *github.ProtectionRequest, signed bool) error {
+	applied, response, err := client.UpdateBranchProtection(ctx, owner, repo, branch, protection)
 	if err != nil {
-		log.Fatalf("Error applying branch protection: %v\n", err)
 		return err
 	}
-	// RequireSignaturesOnProtectedBranch
-	if signedCommits {
-		client.Repositories.RequireSignaturesOnProtectedBranch(ctx, owner, repo, branch)
+	// GitHub may accept the payload but normalize or drop some of its fields
+	for _, warning := range compareAppliedProtection(protection, applied) {
+		log.Printf("Warning: setting not applied on %s/%s@%s: %s\n", owner, repo, branch, warning)
+	}
+	if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+		return err
+	}
+	if signed {
+		_, response, err = client.RequireSignaturesOnProtectedBranch(ctx, owner, repo, branch)
+		if err != nil {
+			return fmt.Errorf("requiring signed commits: %w", err)
+		}
 	}
 	// log.Printf("Branch protection details: %v\n", protectionDetails)
 
@@ -151,6 +367,11 @@ func convertProtectionToRequest(protection *github.Protection) *github.Protectio
 	if protection == nil {
 		log.Fatal("Protection object is nil")
 	}
+	return protectionRequest(protection)
+}
+
+// protectionRequest converts a protection to the request applying it.
+func protectionRequest(protection *github.Protection) *github.ProtectionRequest {
 
 	// Initialize the ProtectionRequest with zero values.
 	request := &github.ProtectionRequest{}
@@ -166,6 +387,7 @@ func convertProtectionToRequest(protection *github.Protection) *github.Protectio
 			DismissStaleReviews:          protection.RequiredPullRequestReviews.DismissStaleReviews,
 			RequireCodeOwnerReviews:      protection.RequiredPullRequestReviews.RequireCodeOwnerReviews,
 			RequiredApprovingReviewCount: protection.RequiredPullRequestReviews.RequiredApprovingReviewCount,
+			RequireLastPushApproval:      github.Bool(protection.RequiredPullRequestReviews.RequireLastPushApproval),
 		}
 
 		// Add Dismissal restrictions
@@ -193,14 +415,14 @@ func convertProtectionToRequest(protection *github.Protection) *github.Protectio
 		request.Restrictions = &github.BranchRestrictionsRequest{}
 	}
 
-	request.RequireLinearHistory = &protection.RequireLinearHistory.Enabled
-	request.AllowForcePushes = &protection.AllowForcePushes.Enabled
-	request.AllowDeletions = &protection.AllowDeletions.Enabled
-	request.RequiredConversationResolution = &protection.RequiredConversationResolution.Enabled
-	// RequireSignaturesOnProtectedBranch
-	if *protection.RequiredSignatures.Enabled {
-		signedCommits = true
-	}
+	// Settings missing from the protection, as in an inline policy, are disabled
+	request.RequireLinearHistory = github.Bool(protection.RequireLinearHistory != nil && protection.RequireLinearHistory.Enabled)
+	request.AllowForcePushes = github.Bool(protection.AllowForcePushes != nil && protection.AllowForcePushes.Enabled)
+	request.AllowDeletions = github.Bool(protection.AllowDeletions != nil && protection.AllowDeletions.Enabled)
+	request.RequiredConversationResolution = github.Bool(protection.RequiredConversationResolution != nil && protection.RequiredConversationResolution.Enabled)
+	request.BlockCreations = github.Bool(protection.GetBlockCreations().GetEnabled())
+	request.LockBranch = github.Bool(protection.GetLockBranch().GetEnabled())
+	request.AllowForkSyncing = github.Bool(protection.GetAllowForkSyncing().GetEnabled())
 
 	return request
 }
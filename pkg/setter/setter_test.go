@@ -0,0 +1,401 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+// sourceProtection returns a protection with every field populated the way
+// the API returns it.
+func sourceProtection(signed bool) *github.Protection {
+	return &github.Protection{
+		RequiredStatusChecks: &github.RequiredStatusChecks{Strict: true, Contexts: []string{"ci"}},
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{
+			DismissStaleReviews:          true,
+			RequireCodeOwnerReviews:      true,
+			RequiredApprovingReviewCount: 2,
+			DismissalRestrictions: &github.DismissalRestrictions{
+				Users: []*github.User{{Login: github.String("alice")}},
+				Teams: []*github.Team{{Slug: github.String("core")}},
+			},
+		},
+		EnforceAdmins: &github.AdminEnforcement{Enabled: true},
+		Restrictions: &github.BranchRestrictions{
+			Users: []*github.User{{Login: github.String("bob")}},
+			Teams: []*github.Team{{Slug: github.String("release")}},
+			Apps:  []*github.App{{Slug: github.String("bot")}},
+		},
+		RequireLinearHistory:           &github.RequireLinearHistory{Enabled: true},
+		AllowForcePushes:               &github.AllowForcePushes{Enabled: false},
+		AllowDeletions:                 &github.AllowDeletions{Enabled: false},
+		RequiredConversationResolution: &github.RequiredConversationResolution{Enabled: true},
+		RequiredSignatures:             &github.SignaturesProtectedBranch{Enabled: github.Bool(signed)},
+	}
+}
+
+func TestConvertProtectionToRequest(t *testing.T) {
+	req := convertProtectionToRequest(sourceProtection(true))
+
+	if !req.EnforceAdmins {
+		t.Error("EnforceAdmins not copied")
+	}
+	if !req.RequiredStatusChecks.Strict || !reflect.DeepEqual(req.RequiredStatusChecks.Contexts, []string{"ci"}) {
+		t.Errorf("RequiredStatusChecks not copied: %+v", req.RequiredStatusChecks)
+	}
+	reviews := req.RequiredPullRequestReviews
+	if !reviews.DismissStaleReviews || !reviews.RequireCodeOwnerReviews || reviews.RequiredApprovingReviewCount != 2 {
+		t.Errorf("RequiredPullRequestReviews not copied: %+v", reviews)
+	}
+	if !reflect.DeepEqual(*reviews.DismissalRestrictionsRequest.Users, []string{"alice"}) ||
+		!reflect.DeepEqual(*reviews.DismissalRestrictionsRequest.Teams, []string{"core"}) {
+		t.Errorf("DismissalRestrictions not copied: %+v", reviews.DismissalRestrictionsRequest)
+	}
+	if !reflect.DeepEqual(req.Restrictions.Users, []string{"bob"}) ||
+		!reflect.DeepEqual(req.Restrictions.Teams, []string{"release"}) ||
+		!reflect.DeepEqual(req.Restrictions.Apps, []string{"bot"}) {
+		t.Errorf("Restrictions not copied: %+v", req.Restrictions)
+	}
+	if !*req.RequireLinearHistory || *req.AllowForcePushes || *req.AllowDeletions || !*req.RequiredConversationResolution {
+		t.Error("boolean toggles not copied")
+	}
+}
+
+func TestConvertProtectionToRequestDefaults(t *testing.T) {
+	p := sourceProtection(false)
+	p.RequiredPullRequestReviews = nil
+	p.EnforceAdmins = nil
+	p.Restrictions = nil
+
+	req := convertProtectionToRequest(p)
+
+	if req.RequiredPullRequestReviews == nil || req.RequiredPullRequestReviews.RequiredApprovingReviewCount != 0 {
+		t.Errorf("expected empty review enforcement, got %+v", req.RequiredPullRequestReviews)
+	}
+	if req.EnforceAdmins {
+		t.Error("expected EnforceAdmins to default to false")
+	}
+	if req.Restrictions == nil || len(req.Restrictions.Users) != 0 {
+		t.Errorf("expected empty restrictions, got %+v", req.Restrictions)
+	}
+}
+
+func TestConvertRestrictionsEmpty(t *testing.T) {
+	drr := convertPRDismissalRestrictionsToRequest(&github.DismissalRestrictions{})
+	if drr.Users == nil || len(*drr.Users) != 0 || drr.Teams == nil || len(*drr.Teams) != 0 {
+		t.Errorf("expected empty dismissal restrictions, got %+v", drr)
+	}
+
+	brr := convertProtectionRestrictionToRequest(&github.BranchRestrictions{})
+	if brr.Users == nil || brr.Teams == nil || brr.Apps == nil {
+		t.Errorf("expected non-nil empty restrictions, got %+v", brr)
+	}
+}
+
+func TestSetBranchProtectionRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		signed bool
+	}{
+		{name: "unsigned"},
+		{name: "signed", signed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			writer := mocks.NewMockBranchProtectionWriter(ctrl)
+			req := &github.ProtectionRequest{EnforceAdmins: true}
+			writer.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "target", "main", req).Return(&github.Protection{}, okResponse(), nil)
+			if tt.signed {
+				writer.EXPECT().RequireSignaturesOnProtectedBranch(gomock.Any(), "octo", "target", "main").
+					Return(&github.SignaturesProtectedBranch{Enabled: github.Bool(true)}, okResponse(), nil)
+			}
+
+			if err := setBranchProtectionRules(context.Background(), writer, "octo", "target", "main", req, tt.signed); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetBranchProtectionRulesSignaturesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	writer := mocks.NewMockBranchProtectionWriter(ctrl)
+	req := &github.ProtectionRequest{}
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}, Message: "Resource not accessible by integration"}
+	writer.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "target", "main", req).Return(&github.Protection{}, okResponse(), nil)
+	writer.EXPECT().RequireSignaturesOnProtectedBranch(gomock.Any(), "octo", "target", "main").Return(nil, nil, forbidden)
+
+	err := setBranchProtectionRules(context.Background(), writer, "octo", "target", "main", req, true)
+	if err == nil || !strings.Contains(err.Error(), "requiring signed commits") {
+		t.Errorf("got %v, want the failure to require signed commits", err)
+	}
+}
+
+func TestSetRulesSets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rm := mocks.NewMockRulesetManager(ctrl)
+	existing := &github.Ruleset{ID: github.Int64(7), Name: "main"}
+	fresh := &github.Ruleset{Name: "release"}
+
+	// Listed once, and updated under the ID of the target
+	rm.EXPECT().GetAllRulesets(gomock.Any(), "octo", "target", false).
+		Return([]*github.Ruleset{{ID: github.Int64(12), Name: "main"}}, okResponse(), nil)
+	rm.EXPECT().UpdateRuleset(gomock.Any(), "octo", "target", int64(12), existing).Return(existing, okResponse(), nil)
+	rm.EXPECT().CreateRuleset(gomock.Any(), "octo", "target", fresh).Return(fresh, okResponse(), nil)
+
+	created, err := setRulesSets(context.Background(), rm, "octo", "target", "main", []*github.Ruleset{existing, fresh})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(created, []string{"release"}) {
+		t.Errorf("created %v, want only the missing ruleset", created)
+	}
+}
+
+func TestSetRuleset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{
+		{Name: github.String("one"), DefaultBranch: github.String("main")},
+		{Name: github.String("two"), DefaultBranch: github.String("trunk")},
+		{Name: github.String("empty")},
+		nil,
+	}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "one", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "two", "trunk", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+
+	results, err := SetRuleset(context.Background(), client, "octo", targets, protections, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the unnamed repository has no result
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, name := range []string{"one", "two", "empty"} {
+		if r := results[i]; r.Repo != name || r.Action != ActionUpdated || r.Err != nil {
+			t.Errorf("results[%d] = %+v, want %s updated", i, r, name)
+		}
+	}
+}
+
+func TestCompareAppliedProtection(t *testing.T) {
+	req := convertProtectionToRequest(sourceProtection(false))
+
+	if warnings := compareAppliedProtection(req, sourceProtection(false)); len(warnings) != 0 {
+		t.Errorf("expected no warnings for a faithful apply, got %v", warnings)
+	}
+
+	applied := sourceProtection(false)
+	applied.RequiredPullRequestReviews.RequiredApprovingReviewCount = 1
+	applied.Restrictions.Apps = nil
+	applied.RequireLinearHistory.Enabled = false
+
+	warnings := compareAppliedProtection(req, applied)
+	fields := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		fields = append(fields, w.Field)
+	}
+	want := []string{
+		"required_linear_history",
+		"required_pull_request_reviews.required_approving_review_count",
+		"restrictions.apps",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got warnings for %v, want %v", fields, want)
+	}
+}
+
+func TestSetRulesetCollectsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{
+		{Name: github.String("rejects"), DefaultBranch: github.String("main")},
+		{Name: github.String("accepts"), DefaultBranch: github.String("main")},
+	}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "rejects", "main", gomock.Any()).Return(nil, nil, errors.New("422 Validation Failed"))
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "accepts", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+
+	results, _ := SetRuleset(context.Background(), client, "octo", targets, protections, Options{})
+	failures := Failures(results)
+	if len(failures) != 1 || failures["rejects"] == nil {
+		t.Errorf("got failures %v, want only rejects", failures)
+	}
+}
+
+func TestSetRulesetHooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{
+		{Name: github.String("vetoed"), DefaultBranch: github.String("main")},
+		{Name: github.String("applied"), DefaultBranch: github.String("main")},
+	}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "applied", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+
+	var mu sync.Mutex
+	results := make(map[string]ApplyResult)
+	opts := Options{
+		BeforeApply: []BeforeApplyHook{func(_ context.Context, repo *github.Repository, _ *github.ProtectionRequest) (bool, error) {
+			return repo.GetName() == "vetoed", nil
+		}},
+		AfterApply: []AfterApplyHook{func(_ context.Context, repo *github.Repository, result ApplyResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			results[repo.GetName()] = result
+		}},
+	}
+
+	synced, _ := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	if failures := Failures(synced); len(failures) != 0 {
+		t.Errorf("unexpected failures %v", failures)
+	}
+	if synced[0].Action != ActionSkipped {
+		t.Errorf("got action %s for the vetoed repo, want skipped", synced[0].Action)
+	}
+	if !results["vetoed"].Skipped {
+		t.Errorf("vetoed repo was not reported as skipped: %+v", results["vetoed"])
+	}
+	if r := results["applied"]; r.Skipped || r.Err != nil || r.Request == nil {
+		t.Errorf("unexpected result for applied repo: %+v", r)
+	}
+}
+
+func TestSetRulesetEmptyRepos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{
+		{Name: github.String("no-branch")},
+		{Name: github.String("no-commits"), DefaultBranch: github.String("main")},
+	}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+	notFound := &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+		Message:  "Branch not found",
+	}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "no-commits", "main", gomock.Any()).Return(nil, nil, notFound)
+
+	var mu sync.Mutex
+	var empty []string
+	opts := Options{AfterApply: []AfterApplyHook{func(_ context.Context, repo *github.Repository, result ApplyResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Empty {
+			empty = append(empty, repo.GetName())
+		}
+	}}}
+
+	synced, _ := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	if failures := Failures(synced); len(failures) != 0 {
+		t.Errorf("empty repos should not fail, got %v", failures)
+	}
+	if len(empty) != 2 {
+		t.Errorf("got empty repos %v, want both", empty)
+	}
+}
+
+func TestSetRulesetUnsupportedPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{{Name: github.String("private"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+	upgrade := &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusForbidden},
+		Message:  "Upgrade to GitHub Pro or make this repository public to enable this feature.",
+	}
+
+	// Neither the rulesets nor the permission of the token are looked at
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "private", "main", gomock.Any()).Return(nil, nil, upgrade)
+
+	var unsupported bool
+	opts := Options{AfterApply: []AfterApplyHook{func(_ context.Context, _ *github.Repository, result ApplyResult) {
+		unsupported = result.UnsupportedPlan
+	}}}
+
+	synced, _ := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	if failures := Failures(synced); len(failures) != 0 {
+		t.Errorf("unsupported repos should not fail, got %v", failures)
+	}
+	if !unsupported {
+		t.Error("the repo wasn't reported as unsupported by the plan")
+	}
+}
+
+func TestSetRulesetSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	targets := []*github.Repository{{Name: github.String("api"), DefaultBranch: github.String("main")}}
+	protections := &types.RepoProtection{BranchProtection: types.NewBranchProtection(sourceProtection(false))}
+
+	repos.EXPECT().UpdateBranchProtection(gomock.Any(), "octo", "api", "main", gomock.Any()).Return(&github.Protection{}, okResponse(), nil)
+
+	var ran []string
+	opts := Options{Steps: []Step{
+		func(context.Context, *github.Repository) error {
+			ran = append(ran, "first")
+			return errors.New("edit rejected")
+		},
+		func(context.Context, *github.Repository) error {
+			ran = append(ran, "second")
+			return nil
+		},
+	}}
+
+	results, _ := SetRuleset(context.Background(), client, "octo", targets, protections, opts)
+	failures := Failures(results)
+	if failures["api"] == nil {
+		t.Error("a failing step should fail the repository")
+	}
+	if len(ran) != 1 {
+		t.Errorf("steps after a failure should not run, ran %v", ran)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// Template returns a transform expanding the variables ${repo}, ${owner} and
+// ${default_branch} in the required status checks and the actors of the
+// request to the values of every target, so checks and teams named after the
+// repository can be required by a single policy. Other text is left as is.
+func Template(owner string) RequestTransform {
+	return func(_ context.Context, repo *github.Repository, req *github.ProtectionRequest) error {
+		r := strings.NewReplacer(
+			"${repo}", repo.GetName(),
+			"${owner}", owner,
+			"${default_branch}", repo.GetDefaultBranch(),
+		)
+
+		// Nested values may be shared with the source and are copied
+		if sc := req.RequiredStatusChecks; sc != nil {
+			checks := *sc
+			checks.Contexts = expandAll(r, sc.Contexts)
+			if sc.Checks != nil {
+				checks.Checks = make([]*github.RequiredStatusCheck, len(sc.Checks))
+				for i, c := range sc.Checks {
+					check := *c
+					check.Context = r.Replace(c.Context)
+					checks.Checks[i] = &check
+				}
+			}
+			req.RequiredStatusChecks = &checks
+		}
+		if reviews := req.RequiredPullRequestReviews; reviews != nil {
+			expanded := *reviews
+			if d := reviews.DismissalRestrictionsRequest; d != nil {
+				expanded.DismissalRestrictionsRequest = &github.DismissalRestrictionsRequest{
+					Users: expandPtr(r, d.Users),
+					Teams: expandPtr(r, d.Teams),
+					Apps:  expandPtr(r, d.Apps),
+				}
+			}
+			if b := reviews.BypassPullRequestAllowancesRequest; b != nil {
+				expanded.BypassPullRequestAllowancesRequest = &github.BypassPullRequestAllowancesRequest{
+					Users: expandAll(r, b.Users),
+					Teams: expandAll(r, b.Teams),
+					Apps:  expandAll(r, b.Apps),
+				}
+			}
+			req.RequiredPullRequestReviews = &expanded
+		}
+		if br := req.Restrictions; br != nil {
+			req.Restrictions = &github.BranchRestrictionsRequest{
+				Users: expandAll(r, br.Users),
+				Teams: expandAll(r, br.Teams),
+				Apps:  expandAll(r, br.Apps),
+			}
+		}
+		return nil
+	}
+}
+
+// IsTemplate reports whether a name contains a variable expanded per target.
+func IsTemplate(name string) bool {
+	return strings.Contains(name, "${")
+}
+
+// expandAll returns a copy of values with the variables expanded, keeping
+// nil and empty slices apart, as the API tells them apart.
+func expandAll(r *strings.Replacer, values []string) []string {
+	if values == nil {
+		return nil
+	}
+	expanded := make([]string, len(values))
+	for i, v := range values {
+		expanded[i] = r.Replace(v)
+	}
+	return expanded
+}
+
+func expandPtr(r *strings.Replacer, values *[]string) *[]string {
+	if values == nil {
+		return nil
+	}
+	expanded := expandAll(r, *values)
+	return &expanded
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package setter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestTemplate(t *testing.T) {
+	repo := &github.Repository{Name: github.String("api"), DefaultBranch: github.String("trunk")}
+	teams := []string{"${repo}-maintainers"}
+	source := &github.ProtectionRequest{
+		RequiredStatusChecks: &github.RequiredStatusChecks{
+			Contexts: []string{"build-${repo}", "lint"},
+			Checks:   []*github.RequiredStatusCheck{{Context: "deploy-${owner}/${repo}@${default_branch}"}},
+		},
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcementRequest{
+			DismissalRestrictionsRequest: &github.DismissalRestrictionsRequest{Teams: &teams},
+		},
+		Restrictions: &github.BranchRestrictionsRequest{Users: []string{}, Teams: []string{"${repo}-admins", "$HOME"}},
+	}
+	req := *source
+	if err := Template("octo")(context.Background(), repo, &req); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := req.RequiredStatusChecks.Contexts; !reflect.DeepEqual(got, []string{"build-api", "lint"}) {
+		t.Errorf("got contexts %v", got)
+	}
+	if got := req.RequiredStatusChecks.Checks[0].Context; got != "deploy-octo/api@trunk" {
+		t.Errorf("got check %q", got)
+	}
+	if got := *req.RequiredPullRequestReviews.DismissalRestrictionsRequest.Teams; !reflect.DeepEqual(got, []string{"api-maintainers"}) {
+		t.Errorf("got dismissal teams %v", got)
+	}
+	if got := req.Restrictions; got.Users == nil || !reflect.DeepEqual(got.Teams, []string{"api-admins", "$HOME"}) {
+		t.Errorf("got restrictions %+v", got)
+	}
+
+	// The source is shared by every target
+	if source.RequiredStatusChecks.Contexts[0] != "build-${repo}" || teams[0] != "${repo}-maintainers" || source.Restrictions.Teams[0] != "${repo}-admins" {
+		t.Error("the request of the source was modified")
+	}
+}
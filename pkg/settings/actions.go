@@ -0,0 +1,119 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// ActionsSettings are the GitHub Actions settings of a repository.
+type ActionsSettings struct {
+	// Permissions enables Actions and sets which actions may run.
+	Permissions github.ActionsPermissionsRepository
+	// Allowed lists the allowed actions when only selected ones may run.
+	Allowed *github.ActionsAllowed
+	// Workflow holds the default permissions of the workflow token.
+	Workflow github.DefaultWorkflowPermissionRepository
+	// ForkPRApproval is the approval policy of fork pull request workflows.
+	ForkPRApproval string
+}
+
+// FetchActionsSettings reads the Actions settings of a repository.
+func FetchActionsSettings(ctx context.Context, client *ghclient.Client, owner, name string) (*ActionsSettings, error) {
+	permissions, _, err := client.Repositories.GetActionsPermissions(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading Actions permissions: %w", err)
+	}
+	settings := &ActionsSettings{
+		// The selected actions URL is read-only
+		Permissions: github.ActionsPermissionsRepository{
+			Enabled:        permissions.Enabled,
+			AllowedActions: permissions.AllowedActions,
+		},
+	}
+
+	if permissions.GetAllowedActions() == "selected" {
+		settings.Allowed, _, err = client.Repositories.GetActionsAllowed(ctx, owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading allowed actions: %w", err)
+		}
+	}
+
+	workflow, _, err := client.Repositories.GetDefaultWorkflowPermissions(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading default workflow permissions: %w", err)
+	}
+	settings.Workflow = *workflow
+
+	settings.ForkPRApproval, _, err = client.WorkflowApprovals.GetForkPRApprovalPolicy(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading fork pull request workflow approval: %w", err)
+	}
+	return settings, nil
+}
+
+// Actions returns a step copying the Actions settings of the source
+// repository to every target. The settings of the source are read once.
+func Actions(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+	settings, err := FetchActionsSettings(ctx, client, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the Actions settings of %s/%s: %w", owner, source, err)
+	}
+
+	return func(ctx context.Context, target *github.Repository) error {
+		name := target.GetName()
+
+		_, response, err := client.Repositories.EditActionsPermissions(ctx, owner, name, settings.Permissions)
+		if err := checkResponse(response, err); err != nil {
+			return fmt.Errorf("updating Actions permissions: %w", err)
+		}
+		if settings.Allowed != nil {
+			_, response, err := client.Repositories.EditActionsAllowed(ctx, owner, name, *settings.Allowed)
+			if err := checkResponse(response, err); err != nil {
+				return fmt.Errorf("updating allowed actions: %w", err)
+			}
+		}
+		_, response, err = client.Repositories.EditDefaultWorkflowPermissions(ctx, owner, name, settings.Workflow)
+		if err := checkResponse(response, err); err != nil {
+			return fmt.Errorf("updating default workflow permissions: %w", err)
+		}
+		if settings.ForkPRApproval != "" {
+			response, err := client.WorkflowApprovals.EditForkPRApprovalPolicy(ctx, owner, name, settings.ForkPRApproval)
+			if err := checkResponse(response, err); err != nil {
+				return fmt.Errorf("updating fork pull request workflow approval: %w", err)
+			}
+		}
+
+		logging.Infof("Actions settings applied to repo %s\n", name)
+		return nil
+	}, nil
+}
+
+// checkResponse returns the error of an API call, or the error matching the
+// status code of its response.
+func checkResponse(response *github.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	return helpers.HTTPStatusCodeCheck(response.StatusCode)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestActions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	approvals := mocks.NewMockWorkflowApprovalManager(ctrl)
+	client := &ghclient.Client{Repositories: repos, WorkflowApprovals: approvals}
+
+	allowed := &github.ActionsAllowed{GithubOwnedAllowed: github.Bool(true), PatternsAllowed: []string{"octo/*"}}
+	workflow := &github.DefaultWorkflowPermissionRepository{
+		DefaultWorkflowPermissions:   github.String("read"),
+		CanApprovePullRequestReviews: github.Bool(false),
+	}
+	repos.EXPECT().GetActionsPermissions(gomock.Any(), "octo", "template").Return(&github.ActionsPermissionsRepository{
+		Enabled:            github.Bool(true),
+		AllowedActions:     github.String("selected"),
+		SelectedActionsURL: github.String("https://api.github.com/repos/octo/template/actions/permissions/selected-actions"),
+	}, okResponse(), nil)
+	repos.EXPECT().GetActionsAllowed(gomock.Any(), "octo", "template").Return(allowed, okResponse(), nil)
+	repos.EXPECT().GetDefaultWorkflowPermissions(gomock.Any(), "octo", "template").Return(workflow, okResponse(), nil)
+	approvals.EXPECT().GetForkPRApprovalPolicy(gomock.Any(), "octo", "template").Return("all_external_contributors", okResponse(), nil)
+
+	repos.EXPECT().EditActionsPermissions(gomock.Any(), "octo", "api", github.ActionsPermissionsRepository{
+		Enabled:        github.Bool(true),
+		AllowedActions: github.String("selected"),
+	}).Return(nil, okResponse(), nil)
+	repos.EXPECT().EditActionsAllowed(gomock.Any(), "octo", "api", *allowed).Return(allowed, okResponse(), nil)
+	repos.EXPECT().EditDefaultWorkflowPermissions(gomock.Any(), "octo", "api", *workflow).Return(workflow, okResponse(), nil)
+	approvals.EXPECT().EditForkPRApprovalPolicy(gomock.Any(), "octo", "api", "all_external_contributors").Return(okResponse(), nil)
+
+	step, err := Actions(context.Background(), client, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestActionsAllActionsAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	approvals := mocks.NewMockWorkflowApprovalManager(ctrl)
+	client := &ghclient.Client{Repositories: repos, WorkflowApprovals: approvals}
+
+	repos.EXPECT().GetActionsPermissions(gomock.Any(), "octo", "template").Return(&github.ActionsPermissionsRepository{
+		Enabled:        github.Bool(true),
+		AllowedActions: github.String("all"),
+	}, okResponse(), nil)
+	repos.EXPECT().GetDefaultWorkflowPermissions(gomock.Any(), "octo", "template").Return(&github.DefaultWorkflowPermissionRepository{}, okResponse(), nil)
+	approvals.EXPECT().GetForkPRApprovalPolicy(gomock.Any(), "octo", "template").Return("", okResponse(), nil)
+
+	// Neither the allowed actions nor the approval policy are written
+	repos.EXPECT().EditActionsPermissions(gomock.Any(), "octo", "api", gomock.Any()).Return(nil, okResponse(), nil)
+	repos.EXPECT().EditDefaultWorkflowPermissions(gomock.Any(), "octo", "api", gomock.Any()).Return(nil, okResponse(), nil)
+
+	step, err := Actions(context.Background(), client, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// Autolinks returns a step copying the autolink references of the source
+// repository to every target. Autolinks are matched by key prefix; as they
+// can't be edited, one with a different URL template is deleted and added
+// again. Autolinks only the target has are left untouched. The autolinks of
+// the source are read once.
+func Autolinks(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+	autolinks, err := listAutolinks(ctx, client.Repositories, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the autolinks of %s/%s: %w", owner, source, err)
+	}
+
+	return func(ctx context.Context, target *github.Repository) error {
+		name := target.GetName()
+		existing, err := listAutolinks(ctx, client.Repositories, owner, name)
+		if err != nil {
+			return fmt.Errorf("listing autolinks: %w", err)
+		}
+		current := make(map[string]*github.Autolink, len(existing))
+		for _, autolink := range existing {
+			current[strings.ToLower(autolink.GetKeyPrefix())] = autolink
+		}
+
+		var added int
+		for _, autolink := range autolinks {
+			have, ok := current[strings.ToLower(autolink.GetKeyPrefix())]
+			if ok && have.GetURLTemplate() == autolink.GetURLTemplate() && have.GetIsAlphanumeric() == autolink.GetIsAlphanumeric() {
+				continue
+			}
+			if ok {
+				if _, err := client.Repositories.DeleteAutolink(ctx, owner, name, have.GetID()); err != nil {
+					return fmt.Errorf("replacing autolink %s: %w", autolink.GetKeyPrefix(), err)
+				}
+			}
+			_, _, err := client.Repositories.AddAutolink(ctx, owner, name, &github.AutolinkOptions{
+				KeyPrefix:      autolink.KeyPrefix,
+				URLTemplate:    autolink.URLTemplate,
+				IsAlphanumeric: autolink.IsAlphanumeric,
+			})
+			if err != nil {
+				return fmt.Errorf("adding autolink %s: %w", autolink.GetKeyPrefix(), err)
+			}
+			added++
+		}
+
+		logging.Infof("Autolinks of repo %s synced: %d added or replaced\n", name, added)
+		return nil
+	}, nil
+}
+
+func listAutolinks(ctx context.Context, client ghclient.AutolinkManager, owner, repo string) ([]*github.Autolink, error) {
+	var autolinks []*github.Autolink
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.ListAutolinks(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		autolinks = append(autolinks, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return autolinks, nil
+}
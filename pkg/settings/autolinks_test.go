@@ -0,0 +1,65 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func autolink(id int64, prefix, template string) *github.Autolink {
+	return &github.Autolink{ID: github.Int64(id), KeyPrefix: github.String(prefix), URLTemplate: github.String(template), IsAlphanumeric: github.Bool(true)}
+}
+
+func TestAutolinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	repos.EXPECT().ListAutolinks(gomock.Any(), "octo", "template", gomock.Any()).Return([]*github.Autolink{
+		autolink(1, "JIRA-", "https://jira.example.com/browse/JIRA-<num>"),
+		autolink(2, "OPS-", "https://ops.example.com/<num>"),
+		autolink(3, "ZD-", "https://zendesk.example.com/tickets/<num>"),
+	}, okResponse(), nil)
+	repos.EXPECT().ListAutolinks(gomock.Any(), "octo", "api", gomock.Any()).Return([]*github.Autolink{
+		autolink(10, "JIRA-", "https://jira.example.com/browse/JIRA-<num>"),
+		autolink(11, "OPS-", "https://old-ops.example.com/<num>"),
+		autolink(12, "LOCAL-", "https://local.example.com/<num>"),
+	}, okResponse(), nil)
+
+	gomock.InOrder(
+		repos.EXPECT().DeleteAutolink(gomock.Any(), "octo", "api", int64(11)).Return(okResponse(), nil),
+		repos.EXPECT().AddAutolink(gomock.Any(), "octo", "api", &github.AutolinkOptions{
+			KeyPrefix: github.String("OPS-"), URLTemplate: github.String("https://ops.example.com/<num>"), IsAlphanumeric: github.Bool(true),
+		}).Return(nil, okResponse(), nil),
+	)
+	repos.EXPECT().AddAutolink(gomock.Any(), "octo", "api", &github.AutolinkOptions{
+		KeyPrefix: github.String("ZD-"), URLTemplate: github.String("https://zendesk.example.com/tickets/<num>"), IsAlphanumeric: github.Bool(true),
+	}).Return(nil, okResponse(), nil)
+
+	step, err := Autolinks(context.Background(), client, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
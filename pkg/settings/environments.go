@@ -0,0 +1,45 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/getter"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// Environments returns a step replicating the deployment environments of the
+// source repository, with their protection rules, to every target. Missing
+// environments are created. The environments of the source are read once.
+func Environments(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+	environments, err := getter.FetchEnvironments(ctx, client.Repositories, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the environments of %s/%s: %w", owner, source, err)
+	}
+
+	return func(ctx context.Context, target *github.Repository) error {
+		if err := setter.SetEnvironments(ctx, client.Repositories, owner, target.GetName(), environments); err != nil {
+			return err
+		}
+		logging.Infof("%d environments applied to repo %s\n", len(environments), target.GetName())
+		return nil
+	}, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// Labels returns a step copying the issue labels of the source repository,
+// with their colors and descriptions, to every target. Labels are matched by
+// name, ignoring case as GitHub does. With prune, the labels of a target the
+// source doesn't have are deleted, which also removes them from its issues
+// and pull requests. The labels of the source are read once.
+func Labels(ctx context.Context, client *ghclient.Client, owner, source string, prune bool) (setter.Step, error) {
+	labels, err := listLabels(ctx, client.Labels, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the labels of %s/%s: %w", owner, source, err)
+	}
+
+	return func(ctx context.Context, target *github.Repository) error {
+		name := target.GetName()
+		existing, err := listLabels(ctx, client.Labels, owner, name)
+		if err != nil {
+			return fmt.Errorf("listing labels: %w", err)
+		}
+		current := make(map[string]*github.Label, len(existing))
+		for _, label := range existing {
+			current[strings.ToLower(label.GetName())] = label
+		}
+
+		var created, updated, deleted int
+		for _, label := range labels {
+			desired := &github.Label{Name: label.Name, Color: label.Color, Description: label.Description}
+			key := strings.ToLower(label.GetName())
+			have, ok := current[key]
+			delete(current, key)
+			switch {
+			case !ok:
+				if _, _, err := client.Labels.CreateLabel(ctx, owner, name, desired); err != nil {
+					return fmt.Errorf("creating label %q: %w", label.GetName(), err)
+				}
+				created++
+			case !sameLabel(have, desired):
+				// Editing by the current name also fixes its case
+				if _, _, err := client.Labels.EditLabel(ctx, owner, name, have.GetName(), desired); err != nil {
+					return fmt.Errorf("updating label %q: %w", label.GetName(), err)
+				}
+				updated++
+			}
+		}
+
+		if prune {
+			for _, label := range current {
+				if _, err := client.Labels.DeleteLabel(ctx, owner, name, label.GetName()); err != nil {
+					return fmt.Errorf("deleting label %q: %w", label.GetName(), err)
+				}
+				deleted++
+			}
+		}
+
+		logging.Infof("Labels of repo %s synced: %d created, %d updated, %d deleted\n", name, created, updated, deleted)
+		return nil
+	}, nil
+}
+
+func listLabels(ctx context.Context, client ghclient.LabelManager, owner, repo string) ([]*github.Label, error) {
+	var labels []*github.Label
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return labels, nil
+}
+
+func sameLabel(have, want *github.Label) bool {
+	return have.GetName() == want.GetName() &&
+		strings.EqualFold(have.GetColor(), want.GetColor()) &&
+		have.GetDescription() == want.GetDescription()
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func label(name, color, description string) *github.Label {
+	return &github.Label{Name: github.String(name), Color: github.String(color), Description: github.String(description)}
+}
+
+func TestLabels(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+		labels := mocks.NewMockLabelManager(ctrl)
+		client := &ghclient.Client{Labels: labels}
+
+		labels.EXPECT().ListLabels(gomock.Any(), "octo", "template", gomock.Any()).Return([]*github.Label{
+			label("bug", "d73a4a", "Something isn't working"),
+			label("security", "b60205", "Security issue"),
+			label("Needs Triage", "ededed", ""),
+		}, okResponse(), nil)
+		labels.EXPECT().ListLabels(gomock.Any(), "octo", "api", gomock.Any()).Return([]*github.Label{
+			label("bug", "D73A4A", "Something isn't working"),
+			label("needs triage", "ededed", ""),
+			label("wontfix", "ffffff", ""),
+		}, okResponse(), nil)
+
+		labels.EXPECT().CreateLabel(gomock.Any(), "octo", "api", label("security", "b60205", "Security issue")).Return(nil, okResponse(), nil)
+		labels.EXPECT().EditLabel(gomock.Any(), "octo", "api", "needs triage", label("Needs Triage", "ededed", "")).Return(nil, okResponse(), nil)
+		if prune {
+			labels.EXPECT().DeleteLabel(gomock.Any(), "octo", "api", "wontfix").Return(okResponse(), nil)
+		}
+
+		step, err := Labels(context.Background(), client, "octo", "template", prune)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+			t.Errorf("prune=%v: unexpected error: %v", prune, err)
+		}
+		ctrl.Finish()
+	}
+}
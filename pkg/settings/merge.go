@@ -0,0 +1,67 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// MergeSettings returns the pull request merge settings of a repository: the
+// allowed merge methods, auto-merge, branch deletion and the default commit
+// titles and messages. Every other field is left unset so editing a target
+// with it changes nothing else.
+func MergeSettings(repo *github.Repository) *github.Repository {
+	return &github.Repository{
+		AllowSquashMerge:         repo.AllowSquashMerge,
+		AllowMergeCommit:         repo.AllowMergeCommit,
+		AllowRebaseMerge:         repo.AllowRebaseMerge,
+		AllowAutoMerge:           repo.AllowAutoMerge,
+		DeleteBranchOnMerge:      repo.DeleteBranchOnMerge,
+		SquashMergeCommitTitle:   repo.SquashMergeCommitTitle,
+		SquashMergeCommitMessage: repo.SquashMergeCommitMessage,
+		MergeCommitTitle:         repo.MergeCommitTitle,
+		MergeCommitMessage:       repo.MergeCommitMessage,
+	}
+}
+
+// Merge returns a step copying the merge settings of the source repository
+// to every target. The settings of the source are read once.
+func Merge(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+	repo, _, err := client.Repositories.Get(ctx, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the merge settings of %s/%s: %w", owner, source, err)
+	}
+	settings := MergeSettings(repo)
+
+	return func(ctx context.Context, target *github.Repository) error {
+		_, response, err := client.Repositories.Edit(ctx, owner, target.GetName(), settings)
+		if err != nil {
+			return fmt.Errorf("updating merge settings: %w", err)
+		}
+		if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+			return fmt.Errorf("updating merge settings: %w", err)
+		}
+		logging.Infof("Merge settings applied to repo %s\n", target.GetName())
+		return nil
+	}, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+func TestMerge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+
+	source := &github.Repository{
+		Name:                   github.String("template"),
+		Description:            github.String("not copied"),
+		AllowSquashMerge:       github.Bool(true),
+		AllowMergeCommit:       github.Bool(false),
+		AllowRebaseMerge:       github.Bool(false),
+		DeleteBranchOnMerge:    github.Bool(true),
+		SquashMergeCommitTitle: github.String("PR_TITLE"),
+	}
+	repos.EXPECT().Get(gomock.Any(), "octo", "template").Return(source, okResponse(), nil)
+	repos.EXPECT().Edit(gomock.Any(), "octo", "api", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, repo *github.Repository) (*github.Repository, *github.Response, error) {
+			if repo.Name != nil || repo.Description != nil {
+				t.Errorf("unrelated settings sent: %+v", repo)
+			}
+			if !repo.GetAllowSquashMerge() || repo.GetAllowMergeCommit() || !repo.GetDeleteBranchOnMerge() || repo.GetSquashMergeCommitTitle() != "PR_TITLE" {
+				t.Errorf("unexpected merge settings: %+v", repo)
+			}
+			return repo, okResponse(), nil
+		})
+
+	step, err := Merge(context.Background(), &ghclient.Client{Repositories: repos}, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMergeEditFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+
+	repos.EXPECT().Get(gomock.Any(), "octo", "template").Return(&github.Repository{}, okResponse(), nil)
+	repos.EXPECT().Edit(gomock.Any(), "octo", "api", gomock.Any()).Return(nil, nil, errors.New("forbidden"))
+
+	step, err := Merge(context.Background(), &ghclient.Client{Repositories: repos}, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err == nil {
+		t.Error("expected the edit error")
+	}
+}
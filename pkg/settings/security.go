@@ -0,0 +1,131 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/helpers"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// SecuritySettings are the code security settings of a repository.
+type SecuritySettings struct {
+	// Analysis holds advanced security, secret scanning and push protection.
+	Analysis *github.SecurityAndAnalysis
+	// VulnerabilityAlerts enables Dependabot alerts.
+	VulnerabilityAlerts bool
+	// AutomatedSecurityFixes enables Dependabot security updates.
+	AutomatedSecurityFixes bool
+}
+
+// FetchSecuritySettings reads the security settings of a repository.
+func FetchSecuritySettings(ctx context.Context, client ghclient.Repositories, owner, name string) (*SecuritySettings, error) {
+	repo, _, err := client.Get(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	alerts, _, err := client.GetVulnerabilityAlerts(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability alerts: %w", err)
+	}
+	fixes, _, err := client.GetAutomatedSecurityFixes(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading automated security fixes: %w", err)
+	}
+
+	settings := &SecuritySettings{
+		VulnerabilityAlerts:    alerts,
+		AutomatedSecurityFixes: fixes.GetEnabled(),
+	}
+	if analysis := repo.GetSecurityAndAnalysis(); analysis != nil {
+		// Dependabot security updates are toggled through their own endpoint
+		settings.Analysis = &github.SecurityAndAnalysis{
+			AdvancedSecurity:             analysis.AdvancedSecurity,
+			SecretScanning:               analysis.SecretScanning,
+			SecretScanningPushProtection: analysis.SecretScanningPushProtection,
+		}
+	}
+	return settings, nil
+}
+
+// Security returns a step making the security settings of every target match
+// those of the source repository. The settings of the source are read once.
+func Security(ctx context.Context, client *ghclient.Client, owner, source string) (setter.Step, error) {
+	settings, err := FetchSecuritySettings(ctx, client.Repositories, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the security settings of %s/%s: %w", owner, source, err)
+	}
+
+	return func(ctx context.Context, target *github.Repository) error {
+		name := target.GetName()
+		if err := editAnalysis(ctx, client.Repositories, owner, target, settings.Analysis); err != nil {
+			return fmt.Errorf("updating security and analysis settings: %w", err)
+		}
+
+		// Security updates depend on alerts: enable alerts first, disable them last
+		var calls []func(context.Context, string, string) (*github.Response, error)
+		if settings.VulnerabilityAlerts {
+			calls = append(calls, client.Repositories.EnableVulnerabilityAlerts)
+		}
+		if settings.AutomatedSecurityFixes {
+			calls = append(calls, client.Repositories.EnableAutomatedSecurityFixes)
+		} else {
+			calls = append(calls, client.Repositories.DisableAutomatedSecurityFixes)
+		}
+		if !settings.VulnerabilityAlerts {
+			calls = append(calls, client.Repositories.DisableVulnerabilityAlerts)
+		}
+		for _, call := range calls {
+			response, err := call(ctx, owner, name)
+			if err != nil {
+				return fmt.Errorf("updating Dependabot settings: %w", err)
+			}
+			if err := helpers.HTTPStatusCodeCheck(response.StatusCode); err != nil {
+				return fmt.Errorf("updating Dependabot settings: %w", err)
+			}
+		}
+
+		logging.Infof("Security settings applied to repo %s\n", name)
+		return nil
+	}, nil
+}
+
+// editAnalysis applies the security and analysis settings to a target.
+// Advanced security is always enabled on public repositories and GitHub
+// rejects changing it there, so it is left out for them.
+func editAnalysis(ctx context.Context, client ghclient.RepoEditor, owner string, target *github.Repository, analysis *github.SecurityAndAnalysis) error {
+	if analysis == nil {
+		return nil
+	}
+	edit := *analysis
+	if !target.GetPrivate() {
+		edit.AdvancedSecurity = nil
+	}
+	if edit == (github.SecurityAndAnalysis{}) {
+		return nil
+	}
+
+	_, response, err := client.Edit(ctx, owner, target.GetName(), &github.Repository{SecurityAndAnalysis: &edit})
+	if err != nil {
+		return err
+	}
+	return helpers.HTTPStatusCodeCheck(response.StatusCode)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSecurity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+
+	source := &github.Repository{SecurityAndAnalysis: &github.SecurityAndAnalysis{
+		AdvancedSecurity:          &github.AdvancedSecurity{Status: github.String("enabled")},
+		SecretScanning:            &github.SecretScanning{Status: github.String("enabled")},
+		DependabotSecurityUpdates: &github.DependabotSecurityUpdates{Status: github.String("enabled")},
+	}}
+	repos.EXPECT().Get(gomock.Any(), "octo", "template").Return(source, okResponse(), nil)
+	repos.EXPECT().GetVulnerabilityAlerts(gomock.Any(), "octo", "template").Return(true, okResponse(), nil)
+	repos.EXPECT().GetAutomatedSecurityFixes(gomock.Any(), "octo", "template").Return(&github.AutomatedSecurityFixes{Enabled: github.Bool(false)}, okResponse(), nil)
+
+	gomock.InOrder(
+		repos.EXPECT().Edit(gomock.Any(), "octo", "public", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ string, repo *github.Repository) (*github.Repository, *github.Response, error) {
+				analysis := repo.GetSecurityAndAnalysis()
+				if analysis.AdvancedSecurity != nil || analysis.DependabotSecurityUpdates != nil {
+					t.Errorf("unexpected settings sent to a public repo: %+v", analysis)
+				}
+				if analysis.GetSecretScanning().GetStatus() != "enabled" {
+					t.Errorf("secret scanning not enabled: %+v", analysis)
+				}
+				return repo, okResponse(), nil
+			}),
+		repos.EXPECT().EnableVulnerabilityAlerts(gomock.Any(), "octo", "public").Return(okResponse(), nil),
+		repos.EXPECT().DisableAutomatedSecurityFixes(gomock.Any(), "octo", "public").Return(okResponse(), nil),
+	)
+
+	step, err := Security(context.Background(), &ghclient.Client{Repositories: repos}, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("public")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSecurityDisablesFixesBeforeAlerts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+
+	repos.EXPECT().Get(gomock.Any(), "octo", "template").Return(&github.Repository{}, okResponse(), nil)
+	repos.EXPECT().GetVulnerabilityAlerts(gomock.Any(), "octo", "template").Return(false, okResponse(), nil)
+	repos.EXPECT().GetAutomatedSecurityFixes(gomock.Any(), "octo", "template").Return(&github.AutomatedSecurityFixes{}, okResponse(), nil)
+
+	gomock.InOrder(
+		repos.EXPECT().DisableAutomatedSecurityFixes(gomock.Any(), "octo", "api").Return(okResponse(), nil),
+		repos.EXPECT().DisableVulnerabilityAlerts(gomock.Any(), "octo", "api").Return(okResponse(), nil),
+	)
+
+	step, err := Security(context.Background(), &ghclient.Client{Repositories: repos}, "octo", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
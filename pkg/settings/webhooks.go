@@ -0,0 +1,158 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/arush-sal/repo-protection-sync/pkg/setter"
+	"github.com/google/go-github/v59/github"
+)
+
+// hookConfigKeys are the webhook settings copied from the source. The secret
+// is never returned by GitHub and comes from the configuration file instead.
+var hookConfigKeys = []string{"url", "content_type", "insecure_ssl"}
+
+// webhook is a webhook of the source along with whether a secret is set on
+// the targets.
+type webhook struct {
+	hook      *github.Hook
+	hasSecret bool
+}
+
+// Webhooks returns a step copying the webhooks of the source repository to
+// every target. Webhooks are matched by URL, so a target's existing webhook
+// is updated rather than duplicated. The webhooks of the source are read
+// once; one with a secret can only be copied when the secret is configured.
+func Webhooks(ctx context.Context, client *ghclient.Client, owner, source string, cfg config.RepoWebhooks) (setter.Step, error) {
+	hooks, err := listHooks(ctx, client.Repositories, owner, source)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the webhooks of %s/%s: %w", owner, source, err)
+	}
+
+	webhooks := make([]webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		url := hookURL(hook)
+		desired := &github.Hook{Name: github.String("web"), Events: hook.Events, Active: hook.Active, Config: make(map[string]interface{})}
+		for _, key := range hookConfigKeys {
+			if value, ok := hook.Config[key]; ok {
+				desired.Config[key] = value
+			}
+		}
+
+		secret, ok := cfg.Secret(url)
+		if ok {
+			desired.Config["secret"] = secret
+		} else if _, signed := hook.Config["secret"]; signed {
+			return nil, fmt.Errorf("webhook %s of %s/%s has a secret, which must be set under repo_webhooks.secrets", url, owner, source)
+		}
+		webhooks = append(webhooks, webhook{hook: desired, hasSecret: ok})
+	}
+
+	return func(ctx context.Context, target *github.Repository) error {
+		name := target.GetName()
+		existing, err := listHooks(ctx, client.Repositories, owner, name)
+		if err != nil {
+			return fmt.Errorf("listing webhooks: %w", err)
+		}
+		byURL := make(map[string]*github.Hook, len(existing))
+		for _, hook := range existing {
+			if _, ok := byURL[hookURL(hook)]; !ok {
+				byURL[hookURL(hook)] = hook
+			}
+		}
+
+		for _, wh := range webhooks {
+			url := hookURL(wh.hook)
+			current, ok := byURL[url]
+			if !ok {
+				if _, _, err := client.Repositories.CreateHook(ctx, owner, name, wh.hook); err != nil {
+					return fmt.Errorf("creating webhook %s: %w", url, err)
+				}
+				continue
+			}
+			if !wh.hasSecret && sameHook(current, wh.hook) {
+				continue
+			}
+
+			edit := &github.Hook{Events: wh.hook.Events, Active: wh.hook.Active}
+			// Replacing the config of a webhook without a secret removes its secret
+			if _, signed := current.Config["secret"]; wh.hasSecret || !signed {
+				edit.Config = wh.hook.Config
+			} else if !sameConfig(current, wh.hook) {
+				log.Printf("Warning: not updating the config of webhook %s on repo %s, it has a secret that isn't configured\n", url, name)
+			}
+			if _, _, err := client.Repositories.EditHook(ctx, owner, name, current.GetID(), edit); err != nil {
+				return fmt.Errorf("updating webhook %s: %w", url, err)
+			}
+		}
+
+		logging.Infof("%d webhooks applied to repo %s\n", len(webhooks), name)
+		return nil
+	}, nil
+}
+
+func listHooks(ctx context.Context, client ghclient.HookManager, owner, repo string) ([]*github.Hook, error) {
+	var hooks []*github.Hook
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.ListHooks(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return hooks, nil
+}
+
+func hookURL(hook *github.Hook) string {
+	url, _ := hook.Config["url"].(string)
+	return url
+}
+
+// sameHook reports whether a webhook already delivers the same events, with
+// the same settings, as the desired one.
+func sameHook(current, desired *github.Hook) bool {
+	return current.GetActive() == desired.GetActive() &&
+		reflect.DeepEqual(sortedEvents(current.Events), sortedEvents(desired.Events)) &&
+		sameConfig(current, desired)
+}
+
+func sameConfig(current, desired *github.Hook) bool {
+	for _, key := range hookConfigKeys {
+		if current.Config[key] != desired.Config[key] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedEvents(events []string) []string {
+	sorted := append([]string{}, events...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient/mocks"
+	"github.com/google/go-github/v59/github"
+	"go.uber.org/mock/gomock"
+)
+
+func hook(id int64, url string, events []string, secret bool) *github.Hook {
+	h := &github.Hook{
+		ID:     github.Int64(id),
+		Events: events,
+		Active: github.Bool(true),
+		Config: map[string]interface{}{"url": url, "content_type": "json", "insecure_ssl": "0"},
+	}
+	if secret {
+		h.Config["secret"] = "********"
+	}
+	return h
+}
+
+func TestWebhooks(t *testing.T) {
+	t.Setenv("AUDIT_SECRET", "s3cret")
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+	cfg := config.RepoWebhooks{Secrets: map[string]string{"https://audit.example.com": "${AUDIT_SECRET}"}}
+
+	repos.EXPECT().ListHooks(gomock.Any(), "octo", "template", gomock.Any()).Return([]*github.Hook{
+		hook(1, "https://ci.example.com", []string{"push", "pull_request"}, false),
+		hook(2, "https://audit.example.com", []string{"*"}, true),
+		hook(3, "https://chat.example.com", []string{"issues"}, false),
+	}, okResponse(), nil)
+	repos.EXPECT().ListHooks(gomock.Any(), "octo", "api", gomock.Any()).Return([]*github.Hook{
+		hook(10, "https://ci.example.com", []string{"push"}, false),
+		hook(11, "https://chat.example.com", []string{"issues"}, false),
+	}, okResponse(), nil)
+
+	repos.EXPECT().EditHook(gomock.Any(), "octo", "api", int64(10), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, _ int64, h *github.Hook) (*github.Hook, *github.Response, error) {
+			if len(h.Events) != 2 || h.Config["url"] != "https://ci.example.com" {
+				t.Errorf("unexpected edit %+v", h)
+			}
+			return h, okResponse(), nil
+		})
+	repos.EXPECT().CreateHook(gomock.Any(), "octo", "api", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, h *github.Hook) (*github.Hook, *github.Response, error) {
+			if h.GetName() != "web" || h.Config["url"] != "https://audit.example.com" || h.Config["secret"] != "s3cret" {
+				t.Errorf("unexpected hook %+v", h)
+			}
+			return h, okResponse(), nil
+		})
+
+	step, err := Webhooks(context.Background(), client, "octo", "template", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := step(context.Background(), &github.Repository{Name: github.String("api")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWebhooksSecretRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repos := mocks.NewMockRepositories(ctrl)
+	client := &ghclient.Client{Repositories: repos}
+
+	repos.EXPECT().ListHooks(gomock.Any(), "octo", "template", gomock.Any()).Return([]*github.Hook{
+		hook(2, "https://audit.example.com", []string{"*"}, true),
+	}, okResponse(), nil)
+
+	if _, err := Webhooks(context.Background(), client, "octo", "template", config.RepoWebhooks{}); err == nil {
+		t.Error("expected an error for a webhook with an unconfigured secret")
+	}
+}
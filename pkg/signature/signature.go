@@ -0,0 +1,119 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package signature
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Tools are the signing tools supported.
+var Tools = []string{"cosign", "minisign", "gpg"}
+
+// command runs the signing tools. Tests replace it.
+var command = exec.CommandContext
+
+// Path returns where the signature of file is written by default.
+func Path(file string) string {
+	return file + ".sig"
+}
+
+// Sign writes a detached signature of file to signature with the private
+// key key, using tool. gpg signs with its default key when key is empty.
+func Sign(ctx context.Context, tool, file, signature, key string) error {
+	var args []string
+	switch tool {
+	case "cosign":
+		args = []string{"sign-blob", "--yes", "--key", key, "--output-signature", signature, file}
+	case "minisign":
+		args = []string{"-S", "-s", key, "-m", file, "-x", signature}
+	case "gpg":
+		args = []string{"--batch", "--yes", "--detach-sign", "--output", signature}
+		if key != "" {
+			args = append(args, "--local-user", key)
+		}
+		args = append(args, file)
+	default:
+		return fmt.Errorf("unsupported signing tool %q", tool)
+	}
+	if (tool == "cosign" || tool == "minisign") && key == "" {
+		return fmt.Errorf("%s needs the private key to sign with", tool)
+	}
+	return run(ctx, "signing "+file, tool, args...)
+}
+
+// Verify checks the detached signature of file against the public key key
+// with tool. gpg checks it against the default keyring when key is empty,
+// and against the keyring or exported public key key with gpgv otherwise.
+func Verify(ctx context.Context, tool, file, signature, key string) error {
+	name, args := tool, []string(nil)
+	switch tool {
+	case "cosign":
+		args = []string{"verify-blob", "--key", key, "--signature", signature, file}
+	case "minisign":
+		args = []string{"-V", "-p", key, "-m", file, "-x", signature}
+	case "gpg":
+		args = []string{"--batch", "--verify", signature, file}
+		if key != "" {
+			name, args = "gpgv", []string{"--keyring", key, signature, file}
+		}
+	default:
+		return fmt.Errorf("unsupported signing tool %q", tool)
+	}
+	if (tool == "cosign" || tool == "minisign") && key == "" {
+		return fmt.Errorf("%s needs the public key to verify with", tool)
+	}
+	return run(ctx, "verifying the signature of "+file, name, args...)
+}
+
+// ReadVerified reads file and verifies its signature like Verify. The bytes
+// verified are a private copy of the file, so they are the ones returned
+// even when the file changes in the meantime.
+func ReadVerified(ctx context.Context, tool, file, signature, key string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "verify-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(ctx, tool, tmp.Name(), signature, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return data, nil
+}
+
+func run(ctx context.Context, what, name string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := command(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w: %s", what, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
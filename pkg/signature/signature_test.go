@@ -0,0 +1,113 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package signature
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordCommands replaces the signing tools with the given command,
+// recording the command lines.
+func recordCommands(t *testing.T, result string) *[]string {
+	t.Helper()
+	var calls []string
+	command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return exec.CommandContext(ctx, result)
+	}
+	t.Cleanup(func() { command = exec.CommandContext })
+	return &calls
+}
+
+func TestSign(t *testing.T) {
+	calls := recordCommands(t, "true")
+	ctx := context.Background()
+	for _, tool := range Tools {
+		if err := Sign(ctx, tool, "plan.json", "plan.json.sig", "key"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"cosign sign-blob --yes --key key --output-signature plan.json.sig plan.json",
+		"minisign -S -s key -m plan.json -x plan.json.sig",
+		"gpg --batch --yes --detach-sign --output plan.json.sig --local-user key plan.json",
+	}
+	if strings.Join(*calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran %q, want %q", *calls, want)
+	}
+	if err := Sign(ctx, "minisign", "plan.json", "plan.json.sig", ""); err == nil {
+		t.Error("Sign() with minisign accepted no key")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	calls := recordCommands(t, "true")
+	ctx := context.Background()
+	for _, key := range []string{"", "release.gpg"} {
+		if err := Verify(ctx, "gpg", "plan.json", "plan.json.sig", key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Verify(ctx, "cosign", "plan.json", "plan.json.sig", "cosign.pub"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"gpg --batch --verify plan.json.sig plan.json",
+		"gpgv --keyring release.gpg plan.json.sig plan.json",
+		"cosign verify-blob --key cosign.pub --signature plan.json.sig plan.json",
+	}
+	if strings.Join(*calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran %q, want %q", *calls, want)
+	}
+	if err := Verify(ctx, "openssl", "plan.json", "plan.json.sig", "key"); err == nil {
+		t.Error("Verify() accepted an unsupported tool")
+	}
+}
+
+func TestVerifyFails(t *testing.T) {
+	recordCommands(t, "false")
+	if err := Verify(context.Background(), "minisign", "plan.json", "plan.json.sig", "minisign.pub"); err == nil {
+		t.Error("Verify() accepted a rejected signature")
+	}
+}
+
+func TestReadVerified(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(file, []byte("baseline_repo: policy\n"), 0o600)
+
+	calls := recordCommands(t, "true")
+	data, err := ReadVerified(context.Background(), "minisign", file, file+".sig", "minisign.pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "baseline_repo: policy\n" {
+		t.Errorf("read %q", data)
+	}
+	// The copy is verified, not the file itself
+	if len(*calls) != 1 || strings.Contains((*calls)[0], " -m "+file+" ") {
+		t.Errorf("ran %q, want the copy verified", *calls)
+	}
+
+	recordCommands(t, "false")
+	if _, err := ReadVerified(context.Background(), "minisign", file, file+".sig", "minisign.pub"); err == nil {
+		t.Error("ReadVerified() accepted a rejected signature")
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/ghclient"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Snapshot is the state of a repository right before a run changed it.
+type Snapshot struct {
+	RunID  string    `json:"run_id"`
+	Taken  time.Time `json:"taken"`
+	Owner  string    `json:"owner"`
+	Repo   string    `json:"repo"`
+	Branch string    `json:"branch,omitempty"`
+	// BranchProtection is nil when the branch wasn't protected.
+	BranchProtection *types.BranchProtection `json:"branch_protection"`
+	// Rulesets are the rulesets defined on the repository, without those it
+	// inherits.
+	Rulesets []*github.Ruleset `json:"rulesets"`
+}
+
+// UnmarshalJSON decodes a snapshot, keeping the rules of its rulesets that
+// go-github can't decode.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	type snapshot Snapshot
+	var raw struct {
+		snapshot
+		Rulesets []json.RawMessage `json:"rulesets"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = Snapshot(raw.snapshot)
+	for _, data := range raw.Rulesets {
+		rs, err := ghclient.DecodeRuleset(data)
+		if err != nil {
+			return err
+		}
+		s.Rulesets = append(s.Rulesets, rs)
+	}
+	return nil
+}
+
+// Run is a run that took snapshots.
+type Run struct {
+	ID      string
+	Started time.Time
+	Repos   []string
+}
+
+// Store keeps snapshots as JSON files below Dir, in a directory per owner
+// and repository.
+type Store struct {
+	Dir string
+}
+
+// Save writes the snapshot to the store.
+func (s *Store) Save(snap Snapshot) error {
+	dir := s.repoDir(snap.Owner, snap.Repo)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating the snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := snap.Taken.UTC().Format("20060102T150405.000000000Z") + "_" + snap.RunID + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("writing the snapshot of %s/%s: %w", snap.Owner, snap.Repo, err)
+	}
+	return nil
+}
+
+// List returns the snapshots of the repositories of owner, or of repos when
+// given, oldest first.
+func (s *Store) List(owner string, repos []string) ([]Snapshot, error) {
+	if len(repos) == 0 {
+		entries, err := os.ReadDir(s.ownerDir(owner))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				repos = append(repos, entry.Name())
+			}
+		}
+	}
+
+	var snapshots []Snapshot
+	for _, repo := range repos {
+		paths, err := filepath.Glob(filepath.Join(s.repoDir(owner, repo), "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			var snap Snapshot
+			if err := json.Unmarshal(data, &snap); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", path, err)
+			}
+			snapshots = append(snapshots, snap)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Taken.Before(snapshots[j].Taken) })
+	return snapshots, nil
+}
+
+// Runs returns the runs that took snapshots of the repositories of owner,
+// oldest first.
+func (s *Store) Runs(owner string) ([]Run, error) {
+	snapshots, err := s.List(owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	index := make(map[string]int)
+	for _, snap := range snapshots {
+		i, ok := index[snap.RunID]
+		if !ok {
+			i = len(runs)
+			index[snap.RunID] = i
+			runs = append(runs, Run{ID: snap.RunID, Started: snap.Taken})
+		}
+		runs[i].Repos = append(runs[i].Repos, snap.Repo)
+	}
+	for _, run := range runs {
+		sort.Strings(run.Repos)
+	}
+	return runs, nil
+}
+
+// Select returns the state of the repositories of owner, or of repos when
+// given, at to: a timestamp, or the ID of a run, which stands for the moment
+// it started. A repository's state at that moment is its first snapshot
+// taken after it; repositories no run changed since are left out, as they
+// are still in that state.
+func (s *Store) Select(owner, to string, repos []string) ([]Snapshot, error) {
+	snapshots, err := s.List(owner, repos)
+	if err != nil {
+		return nil, err
+	}
+	since, ok := ParseTime(to)
+	if !ok {
+		for _, snap := range snapshots {
+			if snap.RunID == to {
+				since, ok = snap.Taken, true
+				break
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("%q is neither a timestamp nor a run with snapshots of %s", to, owner)
+		}
+	}
+
+	var selected []Snapshot
+	seen := make(map[string]bool)
+	for _, snap := range snapshots {
+		if snap.Taken.Before(since) || seen[snap.Repo] {
+			continue
+		}
+		seen[snap.Repo] = true
+		selected = append(selected, snap)
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Repo < selected[j].Repo })
+	return selected, nil
+}
+
+// timeLayouts are the timestamps Select accepts, in local time unless they
+// have a zone.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// ParseTime parses a timestamp in one of the layouts Select accepts.
+func ParseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s *Store) ownerDir(owner string) string {
+	return filepath.Join(s.Dir, strings.ToLower(owner))
+}
+
+func (s *Store) repoDir(owner, repo string) string {
+	return filepath.Join(s.ownerDir(owner), strings.ToLower(repo))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestStore(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	saved := []Snapshot{
+		{RunID: "first", Taken: start, Owner: "Octo", Repo: "api", Branch: "main"},
+		{RunID: "first", Taken: start.Add(time.Second), Owner: "octo", Repo: "web", Branch: "main", BranchProtection: &types.BranchProtection{EnforceAdmins: true}},
+		{RunID: "second", Taken: start.Add(time.Hour), Owner: "octo", Repo: "api", Branch: "main", BranchProtection: &types.BranchProtection{LinearHistory: true},
+			Rulesets: []*github.Ruleset{{Name: "tags", Enforcement: "active", Rules: []*github.RepositoryRule{{Type: "deletion"}}}}},
+	}
+	for _, snap := range saved {
+		if err := store.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := store.Runs("octo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != "first" || !reflect.DeepEqual(runs[0].Repos, []string{"api", "web"}) {
+		t.Errorf("Runs() = %+v", runs)
+	}
+
+	tests := []struct {
+		to    string
+		repos []string
+		want  []string
+	}{
+		// The state before the first run
+		{to: "first", want: []string{"first api", "first web"}},
+		// web wasn't changed since the second run
+		{to: "second", want: []string{"second api"}},
+		{to: "2024-05-01T12:30:00Z", want: []string{"second api"}},
+		{to: "first", repos: []string{"web"}, want: []string{"first web"}},
+		{to: "2024-05-02", want: nil},
+	}
+	for _, tt := range tests {
+		selected, err := store.Select("octo", tt.to, tt.repos)
+		if err != nil {
+			t.Fatalf("Select(%s): %v", tt.to, err)
+		}
+		var got []string
+		for _, snap := range selected {
+			got = append(got, snap.RunID+" "+snap.Repo)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Select(%s, %v) = %v, want %v", tt.to, tt.repos, got, tt.want)
+		}
+	}
+
+	selected, _ := store.Select("octo", "second", nil)
+	if got := selected[0]; !got.BranchProtection.LinearHistory || len(got.Rulesets) != 1 || got.Rulesets[0].Rules[0].Type != "deletion" {
+		t.Errorf("snapshot read back as %+v", got)
+	}
+
+	if _, err := store.Select("octo", "unknown", nil); err == nil {
+		t.Error("Select() accepted an unknown run")
+	}
+}
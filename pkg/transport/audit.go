@@ -0,0 +1,163 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+)
+
+// repoPath captures the repository an API path belongs to.
+var repoPath = regexp.MustCompile(`^(?:/api/v3)?/repos/([^/]+)/([^/]+)`)
+
+// AuditLog appends a JSON line for every write sent to the API to a local
+// file, so the changes made by the tool can be traced independently of the
+// retention of the audit log of GitHub.
+type AuditLog struct {
+	Path string
+	// Actor is recorded with every write. It is set once the credentials
+	// are known, before the first write.
+	Actor string
+
+	mu sync.Mutex
+}
+
+// AuditRecord is a line of the audit log.
+type AuditRecord struct {
+	Time  time.Time `json:"time"`
+	Actor string    `json:"actor"`
+	// RunID identifies the run that sent the write.
+	RunID  string `json:"run_id,omitempty"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Repo   string `json:"repo,omitempty"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// BeforeSHA256 hashes the resource read before updating or deleting
+	// it, and AfterSHA256 the response to the write.
+	BeforeSHA256 string          `json:"before_sha256,omitempty"`
+	AfterSHA256  string          `json:"after_sha256,omitempty"`
+	Request      json.RawMessage `json:"request,omitempty"`
+}
+
+// Wrap returns a round tripper recording the writes sent to base.
+func (a *AuditLog) Wrap(base http.RoundTripper) http.RoundTripper {
+	return &auditTransport{log: a, base: base}
+}
+
+// Append writes the record as a line at the end of the log.
+func (a *AuditLog) Append(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, err := os.OpenFile(a.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type auditTransport struct {
+	log  *AuditLog
+	base http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	record := AuditRecord{Time: time.Now().UTC(), Actor: t.log.Actor, RunID: runid.From(req.Context()), Method: req.Method, Path: req.URL.Path}
+	if m := repoPath.FindStringSubmatch(req.URL.Path); m != nil {
+		record.Repo = m[1] + "/" + m[2]
+	}
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			if json.Valid(data) {
+				record.Request = data
+			}
+		}
+	}
+	if req.Method == http.MethodPut || req.Method == http.MethodPatch || req.Method == http.MethodDelete {
+		record.BeforeSHA256 = t.current(req)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Status = resp.StatusCode
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return nil, readErr
+		}
+		record.AfterSHA256 = digest(body)
+	}
+	if appendErr := t.log.Append(record); appendErr != nil {
+		log.Printf("Warning: writing the audit log %s: %v\n", t.log.Path, appendErr)
+	}
+	return resp, err
+}
+
+// current hashes the resource a write replaces, read from the same URL.
+// Nothing is recorded when it can't be read, as before its creation.
+func (t *auditTransport) current(req *http.Request) string {
+	get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return ""
+	}
+	get.Header.Set("Accept", req.Header.Get("Accept"))
+	resp, err := t.base.RoundTrip(get)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	return digest(body)
+}
+
+func digest(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+)
+
+func TestAuditLog(t *testing.T) {
+	current := `{"enforce_admins":{"enabled":false}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			io.WriteString(w, current)
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			current = string(body)
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"id":1}`)
+		}
+	}))
+	defer server.Close()
+
+	audit := &AuditLog{Path: filepath.Join(t.TempDir(), "audit.jsonl"), Actor: "octocat"}
+	client := &http.Client{Transport: audit.Wrap(http.DefaultTransport)}
+
+	before := current
+	if _, err := client.Get(server.URL + "/repos/octo/api/branches/main/protection"); err != nil {
+		t.Fatal(err)
+	}
+	put, _ := http.NewRequestWithContext(runid.With(context.Background(), "run-1"), http.MethodPut, server.URL+"/repos/octo/api/branches/main/protection", bytes.NewBufferString(`{"enforce_admins":true}`))
+	resp, err := client.Do(put)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != `{"enforce_admins":true}` {
+		t.Errorf("the response body was consumed: %q", body)
+	}
+	if _, err := client.Post(server.URL+"/repos/octo/api/rulesets", "application/json", bytes.NewBufferString(`{"name":"main"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(audit.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var records []AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid line %s: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want one per write", len(records))
+	}
+
+	update := records[0]
+	if update.Method != http.MethodPut || update.Repo != "octo/api" || update.Actor != "octocat" || update.RunID != "run-1" || update.Status != http.StatusOK {
+		t.Errorf("update recorded as %+v", update)
+	}
+	if update.BeforeSHA256 != digest([]byte(before)) || update.AfterSHA256 != digest([]byte(`{"enforce_admins":true}`)) {
+		t.Errorf("update hashes = %s, %s", update.BeforeSHA256, update.AfterSHA256)
+	}
+	if string(update.Request) != `{"enforce_admins":true}` {
+		t.Errorf("update request = %s", update.Request)
+	}
+
+	create := records[1]
+	if create.Method != http.MethodPost || create.Status != http.StatusCreated || create.BeforeSHA256 != "" || string(create.Request) != `{"name":"main"}` {
+		t.Errorf("creation recorded as %+v", create)
+	}
+}
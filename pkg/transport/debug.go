@@ -0,0 +1,156 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DebugLog is a round tripper logging every request and response in full,
+// headers and bodies included, for troubleshooting requests GitHub rejects.
+// Credentials are redacted: the Authorization header, the secrets of JSON
+// bodies such as webhook secrets, and anything shaped like a GitHub token.
+type DebugLog struct {
+	Base http.RoundTripper
+}
+
+// redacted replaces the secrets of the log.
+const redacted = "[REDACTED]"
+
+// secretKeys are the JSON fields whose values are redacted.
+var secretKeys = map[string]bool{
+	"secret":        true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"password":      true,
+	"client_secret": true,
+	"private_key":   true,
+}
+
+// tokenPattern matches GitHub tokens, and queryToken the tokens of URLs.
+var tokenPattern = regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{16,}|github_pat_[A-Za-z0-9_]{16,})`)
+var queryToken = regexp.MustCompile(`\b((?:access_)?token=)[^&\s"]+`)
+
+func (d *DebugLog) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("--> %s %s\n%s%s", req.Method, redact(req.URL.String()), headers(req.Header), redactBody(body))
+
+	started := time.Now()
+	resp, err := d.Base.RoundTrip(req)
+	if err != nil {
+		log.Printf("<-- %s %s failed after %v: %v\n", req.Method, redact(req.URL.String()), time.Since(started).Round(time.Millisecond), err)
+		return nil, err
+	}
+	body, err = readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("<-- %s %s %s (%v)\n%s%s", req.Method, redact(req.URL.String()), resp.Status, time.Since(started).Round(time.Millisecond), headers(resp.Header), redactBody(body))
+	return resp, nil
+}
+
+// readBody reads a body, replacing it with a copy to be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// headers formats headers one per line, sorted, with the credentials
+// redacted.
+func headers(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		for _, value := range h[name] {
+			switch http.CanonicalHeaderKey(name) {
+			case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+				scheme, _, _ := strings.Cut(value, " ")
+				value = scheme + " " + redacted
+			default:
+				value = redact(value)
+			}
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+	return b.String()
+}
+
+// redactBody redacts the secrets of a JSON body, or the tokens of any
+// other body.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	return "\n" + redactPayload(body) + "\n"
+}
+
+// redactPayload returns body with the secrets of JSON and the tokens of any
+// other content redacted.
+func redactPayload(body []byte) string {
+	var v interface{}
+	if json.Unmarshal(body, &v) == nil {
+		if data, err := json.Marshal(redactJSON(v)); err == nil {
+			body = data
+		}
+	}
+	return redact(string(body))
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, isString := value.(string); isString && secretKeys[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactJSON(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return v
+}
+
+// redact masks the GitHub tokens of s.
+func redact(s string) string {
+	s = tokenPattern.ReplaceAllString(s, redacted)
+	return queryToken.ReplaceAllString(s, "${1}"+redacted)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestDebugLogRedacts(t *testing.T) {
+	var out bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&out)
+	t.Cleanup(func() { log.SetOutput(previous) })
+
+	token := "ghp_" + strings.Repeat("a", 36)
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), "s3cr3t") {
+			t.Errorf("the request body sent was altered: %s", body)
+		}
+		return &http.Response{
+			StatusCode: http.StatusUnprocessableEntity,
+			Status:     "422 Unprocessable Entity",
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"message":"Validation Failed","errors":[{"field":"contexts"}]}`)),
+		}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/repos/octo/api/hooks?access_token=abc", strings.NewReader(`{"config":{"url":"https://ci.example.com","secret":"s3cr3t"},"note":"`+token+`"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := (&DebugLog{Base: base}).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "Validation Failed") {
+		t.Errorf("the response body was consumed: %s", body)
+	}
+
+	logged := out.String()
+	for _, secret := range []string{token, "s3cr3t", "access_token=abc"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("logged %q:\n%s", secret, logged)
+		}
+	}
+	for _, want := range []string{"--> POST", "Authorization: Bearer [REDACTED]", `"secret":"[REDACTED]"`, "<-- POST", "422 Unprocessable Entity", `"field":"contexts"`} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("logged no %q:\n%s", want, logged)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// CacheStatusHeader is set to CacheStatusUnchanged on responses served from
+// the cache after GitHub answered 304 Not Modified, so callers can skip
+// repositories whose protection didn't change since it was last read.
+const (
+	CacheStatusHeader    = "X-Repo-Protection-Sync-Cache"
+	CacheStatusUnchanged = "unchanged"
+)
+
+// cacheablePath matches the protection and ruleset reads worth caching and
+// captures the repository they belong to.
+var cacheablePath = regexp.MustCompile(`^(?:/api/v3)?/repos/([^/]+)/([^/]+)/(?:branches/[^/]+/protection|rulesets)(?:/|$)`)
+
+// ETagCache is a round tripper that remembers the ETag and body of
+// protection and ruleset GETs on disk, keyed by repository, and revalidates
+// them with If-None-Match. Conditional requests answered with 304 don't count
+// against the rate limit.
+type ETagCache struct {
+	Dir  string
+	Base http.RoundTripper
+}
+
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// Unchanged reports whether resp was served from the cache because the
+// resource didn't change.
+func Unchanged(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(CacheStatusHeader) == CacheStatusUnchanged
+}
+
+func (c *ETagCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	m := cacheablePath.FindStringSubmatch(req.URL.Path)
+	if req.Method != http.MethodGet || m == nil {
+		return c.Base.RoundTrip(req)
+	}
+
+	path := c.entryPath(m[1], m[2], req.URL.String())
+	entry := loadEntry(path)
+	if entry != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		return entry.response(req, resp), nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		storeEntry(path, &cacheEntry{
+			URL:          req.URL.String(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Header:       resp.Header.Clone(),
+			Body:         body,
+		})
+	}
+	return resp, nil
+}
+
+// entryPath groups the entries of a repository in one directory.
+func (c *ETagCache) entryPath(owner, repo, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, "etags", owner, repo, hex.EncodeToString(sum[:])+".json")
+}
+
+// response rebuilds a 200 response from the cached entry, keeping the
+// headers of the 304 response so rate limit information stays current.
+func (e *cacheEntry) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := e.Header.Clone()
+	for k, v := range notModified.Header {
+		header[k] = v
+	}
+	header.Set(CacheStatusHeader, CacheStatusUnchanged)
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func loadEntry(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil || entry.ETag == "" {
+		return nil
+	}
+	return entry
+}
+
+// storeEntry writes the entry atomically. Failures only cost a cache miss on
+// the next run, so they are logged rather than returned.
+func storeEntry(path string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+	}
+	if err == nil {
+		var tmp *os.File
+		if tmp, err = os.CreateTemp(filepath.Dir(path), ".entry-*"); err == nil {
+			_, err = tmp.Write(data)
+			if cerr := tmp.Close(); err == nil {
+				err = cerr
+			}
+			if err == nil {
+				err = os.Rename(tmp.Name(), path)
+			} else {
+				os.Remove(tmp.Name())
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to write ETag cache entry %s: %v\n", path, err)
+	}
+}
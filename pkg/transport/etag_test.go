@@ -0,0 +1,79 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestETagCache(t *testing.T) {
+	var hits, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, `{"enforce_admins":{"enabled":true}}`)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: New(Options{CacheDir: t.TempDir()}, nil)}
+	url := srv.URL + "/repos/octo/api/branches/main/protection"
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || string(body) != `{"enforce_admins":{"enabled":true}}` {
+			t.Errorf("request %d: got %d %q", i, resp.StatusCode, body)
+		}
+		if Unchanged(resp) != (i == 1) {
+			t.Errorf("request %d: Unchanged = %v", i, Unchanged(resp))
+		}
+	}
+	if hits != 2 || notModified != 1 {
+		t.Errorf("got %d hits and %d revalidations, want 2 and 1", hits, notModified)
+	}
+}
+
+func TestETagCacheIgnoresOtherRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("conditional header sent for %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("ETag", `"v1"`)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: New(Options{CacheDir: t.TempDir()}, nil)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL + "/orgs/octo/repos")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+}
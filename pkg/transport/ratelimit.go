@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// RateCache is a round tripper that records the core rate limit reported in
+// the headers of every response, so the remaining requests are known without
+// polling the rate limit endpoint, which costs a request of its own.
+type RateCache struct {
+	Base http.RoundTripper
+	// Pool, when the requests are sent with a token pool, has Core report
+	// the combined rate limit of its tokens.
+	Pool *TokenPool
+
+	mu    sync.Mutex
+	rate  github.Rate
+	known bool
+}
+
+func (c *RateCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.Base.RoundTrip(req)
+	if err == nil {
+		c.record(resp.Header)
+	}
+	return resp, err
+}
+
+// Core returns the core rate limit of the latest response, and false before
+// any response reported one.
+func (c *RateCache) Core() (github.Rate, bool) {
+	if c.Pool != nil {
+		return c.Pool.Core()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rate, c.known
+}
+
+// record keeps the lowest remaining count of the newest rate limit window, as
+// concurrent responses may arrive out of order.
+func (c *RateCache) record(header http.Header) {
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	rate := github.Rate{Limit: limit, Remaining: remaining, Reset: github.Timestamp{Time: time.Unix(reset, 0)}}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case !c.known, rate.Reset.After(c.rate.Reset.Time):
+	case rate.Reset.Equal(c.rate.Reset) && rate.Remaining < c.rate.Remaining:
+	default:
+		return
+	}
+	c.rate, c.known = rate, true
+}
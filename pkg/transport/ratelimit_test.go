@@ -0,0 +1,73 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+type stubRoundTripper []http.Header
+
+func (s *stubRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := (*s)[0]
+	*s = (*s)[1:]
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Request: req}, nil
+}
+
+func rateHeader(resource, remaining, reset string) http.Header {
+	h := http.Header{}
+	if resource != "" {
+		h.Set("X-RateLimit-Resource", resource)
+	}
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", remaining)
+	h.Set("X-RateLimit-Reset", reset)
+	return h
+}
+
+func TestRateCache(t *testing.T) {
+	base := &stubRoundTripper{
+		rateHeader("core", "4000", "1700000000"),
+		// arrived late from the same window
+		rateHeader("core", "4100", "1700000000"),
+		rateHeader("search", "10", "1700000000"),
+		http.Header{},
+		rateHeader("", "3999", "1700000000"),
+		// a new window
+		rateHeader("core", "5000", "1700003600"),
+	}
+	c := &RateCache{Base: base}
+	if _, ok := c.Core(); ok {
+		t.Fatal("no rate should be known before the first response")
+	}
+
+	want := []int{4000, 4000, 4000, 4000, 3999, 5000}
+	for i, remaining := range want {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/octo/api", nil)
+		if _, err := c.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rate, ok := c.Core()
+		if !ok || rate.Remaining != remaining {
+			t.Errorf("response %d: got %d remaining, want %d", i, rate.Remaining, remaining)
+		}
+	}
+	if rate, _ := c.Core(); !rate.Reset.Time.Equal(time.Unix(1700003600, 0)) || rate.Limit != 5000 {
+		t.Errorf("unexpected rate %+v", rate)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Interaction is a request and its response, as recorded to a fixture file
+// with the credentials redacted.
+type Interaction struct {
+	Method      string              `json:"method"`
+	URL         string              `json:"url"`
+	RequestBody string              `json:"request_body,omitempty"`
+	Status      int                 `json:"status"`
+	Header      map[string][]string `json:"header"`
+	Body        string              `json:"body"`
+}
+
+func (i Interaction) key() string {
+	return i.Method + " " + i.URL + "\n" + i.RequestBody
+}
+
+// Recorder is a round tripper saving every request it sends and the
+// response to a numbered JSON file of Dir, so a failing run can be shared
+// and replayed without access to the organization.
+type Recorder struct {
+	Dir  string
+	Base http.RoundTripper
+}
+
+// recorded numbers the interactions of every Recorder of the process, as a
+// run creates several clients.
+var recorded int64
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := Interaction{
+		Method:      req.Method,
+		URL:         redact(req.URL.String()),
+		RequestBody: redactPayload(body),
+		Status:      resp.StatusCode,
+		Header:      make(map[string][]string),
+		Body:        redactPayload(respBody),
+	}
+	for name, values := range resp.Header {
+		if http.CanonicalHeaderKey(name) == "Set-Cookie" {
+			continue
+		}
+		for _, value := range values {
+			recorded.Header[name] = append(recorded.Header[name], redact(value))
+		}
+	}
+	if err := r.save(recorded); err != nil {
+		return nil, fmt.Errorf("recording %s %s: %w", req.Method, recorded.URL, err)
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(i Interaction) error {
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.Dir, 0o700); err != nil {
+		return err
+	}
+	seq := atomic.AddInt64(&recorded, 1)
+	return os.WriteFile(filepath.Join(r.Dir, fmt.Sprintf("%05d.json", seq)), data, 0o600)
+}
+
+// Replay is a round tripper answering requests with the responses recorded
+// by a Recorder instead of sending them. Requests are matched by method,
+// URL and body; a request sent more often than recorded gets the last
+// response recorded for it.
+type Replay struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+}
+
+// NewReplay loads the interactions recorded in dir.
+func NewReplay(dir string) (*Replay, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded interactions in %s", dir)
+	}
+	// The files are numbered in the order they were recorded
+	sort.Strings(files)
+	r := &Replay{interactions: make(map[string][]Interaction)}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var i Interaction
+		if err := json.Unmarshal(data, &i); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+		r.interactions[i.key()] = append(r.interactions[i.key()], i)
+	}
+	return r, nil
+}
+
+func (r *Replay) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	want := Interaction{Method: req.Method, URL: redact(req.URL.String()), RequestBody: redactPayload(body)}
+
+	r.mu.Lock()
+	recorded := r.interactions[want.key()]
+	if len(recorded) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s %s", want.Method, want.URL)
+	}
+	i := recorded[0]
+	if len(recorded) > 1 {
+		r.interactions[want.key()] = recorded[1:]
+	}
+	r.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: i.Status,
+		Status:     fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header(i.Header).Clone(),
+		Body:       io.NopCloser(bytes.NewReader([]byte(i.Body))),
+		Request:    req,
+	}, nil
+}
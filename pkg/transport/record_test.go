@@ -0,0 +1,76 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	token := "ghp_" + strings.Repeat("b", 36)
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		body := `{"required_approving_review_count":` + string(rune('0'+calls)) + `}`
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Etag": {`"abc"`}}, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+	client := &http.Client{Transport: &Recorder{Dir: dir, Base: base}}
+	get := func(client *http.Client) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/octo/api/branches/main/protection", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	first, second := get(client), get(client)
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Fatalf("recorded %d files, want 2", len(files))
+	}
+	for _, f := range files {
+		if data, _ := os.ReadFile(dir + "/" + f.Name()); strings.Contains(string(data), token) {
+			t.Errorf("%s holds the token", f.Name())
+		}
+	}
+
+	replay, err := NewReplay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replay}
+	// Responses are replayed in order, the last one repeatedly
+	for _, want := range []string{first, second, second} {
+		if got := get(client); got != want {
+			t.Errorf("replayed %s, want %s", got, want)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/octo/web", nil)
+	if _, err := client.Do(req); err == nil {
+		t.Error("replayed a request that wasn't recorded")
+	}
+}
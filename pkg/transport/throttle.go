@@ -0,0 +1,70 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Throttle is a round tripper limiting requests to a steady rate with a
+// token bucket holding a single token, so requests are spread out evenly
+// whatever the number of workers. GHES instances often enforce lower abuse
+// thresholds than github.com.
+type Throttle struct {
+	Base http.RoundTripper
+
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// NewThrottle returns a Throttle allowing rps requests per second to base.
+func NewThrottle(rps float64, base http.RoundTripper) *Throttle {
+	return &Throttle{Base: base, rate: rps, tokens: 1, last: time.Now()}
+}
+
+func (t *Throttle) RoundTrip(req *http.Request) (*http.Response, error) {
+	if delay := t.reserve(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return t.Base.RoundTrip(req)
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+func (t *Throttle) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > 1 {
+		t.tokens = 1
+	}
+	t.last = now
+	t.tokens--
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.rate * float64(time.Second))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type okRoundTripper struct{}
+
+func (okRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+}
+
+func TestThrottle(t *testing.T) {
+	th := NewThrottle(100, okRoundTripper{})
+
+	started := time.Now()
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+		if _, err := th.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first request goes out at once, the others 10ms apart
+	if elapsed := time.Since(started); elapsed < 40*time.Millisecond {
+		t.Errorf("5 requests at 100 rps took %s, want at least 40ms", elapsed)
+	}
+}
+
+func TestThrottleCanceled(t *testing.T) {
+	th := NewThrottle(0.1, okRoundTripper{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/rate_limit", nil)
+	if _, err := th.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	if _, err := th.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want the wait to end with the context", err)
+	}
+}
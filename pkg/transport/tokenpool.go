@@ -0,0 +1,140 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/logging"
+	"github.com/google/go-github/v59/github"
+)
+
+// TokenPool is a round tripper authenticating every request with one of
+// several tokens or App installations, switching to the credentials with the
+// most requests left once the rate limit of the current ones runs out, so a
+// sync of a very large organization isn't held up by the limit of a single
+// token. The rate limit of every credentials is tracked from its responses.
+type TokenPool struct {
+	mu      sync.Mutex
+	tokens  []pooledToken
+	current int
+}
+
+type pooledToken struct {
+	// rates wraps the transport authenticating with the credentials.
+	rates *RateCache
+}
+
+// NewTokenPool returns a pool of the tokens sending its requests with base.
+func NewTokenPool(base http.RoundTripper, tokens []string) *TokenPool {
+	transports := make([]http.RoundTripper, 0, len(tokens))
+	for _, token := range tokens {
+		transports = append(transports, bearer{token: token, base: base})
+	}
+	return NewTransportPool(transports)
+}
+
+// NewTransportPool returns a pool of transports authenticating their
+// requests, such as those of App installations.
+func NewTransportPool(transports []http.RoundTripper) *TokenPool {
+	p := &TokenPool{}
+	for _, t := range transports {
+		p.tokens = append(p.tokens, pooledToken{rates: &RateCache{Base: t}})
+	}
+	return p
+}
+
+func (p *TokenPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	return p.pick().rates.RoundTrip(req)
+}
+
+// bearer authenticates the requests it sends with base with a token.
+type bearer struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (b bearer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return b.base.RoundTrip(req)
+}
+
+// pick returns the current credentials, or those with the most requests
+// left when the current ones have none.
+func (p *TokenPool) pick() pooledToken {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if left(p.tokens[p.current], now) > 0 {
+		return p.tokens[p.current]
+	}
+	best := p.current
+	for i, t := range p.tokens {
+		if left(t, now) > left(p.tokens[best], now) {
+			best = i
+		}
+	}
+	if best != p.current {
+		logging.Infof("Rate limit of credentials %d of %d exhausted, switching to credentials %d\n", p.current+1, len(p.tokens), best+1)
+		p.current = best
+	}
+	return p.tokens[best]
+}
+
+// tokenLimit is the hourly rate limit of a personal access token, and the
+// least of an App installation.
+const tokenLimit = 5000
+
+// left returns the requests credentials have left: their whole limit once
+// their window reset, or a full window when none of their responses was seen
+// yet.
+func left(t pooledToken, now time.Time) int {
+	rate, ok := t.rates.Core()
+	switch {
+	case !ok:
+		return tokenLimit
+	case now.After(rate.Reset.Time):
+		return rate.Limit
+	}
+	return rate.Remaining
+}
+
+// Core returns the combined core rate limit of the credentials: the
+// requests they have left together, and when the first of them resets once
+// none has any left.
+func (p *TokenPool) Core() (github.Rate, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var combined github.Rate
+	known := false
+	now := time.Now()
+	for _, t := range p.tokens {
+		combined.Remaining += left(t, now)
+		rate, ok := t.rates.Core()
+		if !ok {
+			continue
+		}
+		known = true
+		combined.Limit += rate.Limit
+		if combined.Reset.IsZero() || rate.Reset.Before(combined.Reset.Time) {
+			combined.Reset = rate.Reset
+		}
+	}
+	return combined, known
+}
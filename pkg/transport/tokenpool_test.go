@@ -0,0 +1,114 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// tokenServer answers every request with the rate limit left to its token.
+type tokenServer struct {
+	remaining map[string]int
+	reset     time.Time
+	used      []string
+}
+
+func (s *tokenServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	auth := req.Header.Get("Authorization")
+	s.used = append(s.used, auth)
+	s.remaining[auth]--
+	h := rateHeader("core", strconv.Itoa(s.remaining[auth]), strconv.FormatInt(s.reset.Unix(), 10))
+	return &http.Response{StatusCode: http.StatusOK, Header: h, Request: req}, nil
+}
+
+func TestTokenPoolRotates(t *testing.T) {
+	srv := &tokenServer{remaining: map[string]int{"Bearer a": 2, "Bearer b": 3}, reset: time.Now().Add(time.Hour)}
+	pool := NewTokenPool(srv, []string{"a", "b"})
+	client := &http.Client{Transport: pool}
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get("https://api.github.com/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	want := []string{"Bearer a", "Bearer a", "Bearer b", "Bearer b"}
+	for i := range want {
+		if srv.used[i] != want[i] {
+			t.Fatalf("authenticated with %v, want %v", srv.used, want)
+		}
+	}
+
+	rate, ok := pool.Core()
+	if !ok || rate.Remaining != 1 || rate.Limit != 10000 {
+		t.Errorf("Core() = %+v, %v; want 1 of 10000 left", rate, ok)
+	}
+}
+
+func TestTokenPoolExhausted(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	srv := &tokenServer{remaining: map[string]int{"Bearer a": 1, "Bearer b": 1}, reset: reset}
+	pool := NewTokenPool(srv, []string{"a", "b"})
+	if _, ok := pool.Core(); ok {
+		t.Error("Core() knows a rate before any response")
+	}
+	client := &http.Client{Transport: pool}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get("https://api.github.com/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	rate, _ := pool.Core()
+	if rate.Remaining != 0 || rate.Reset.Unix() != reset.Unix() {
+		t.Errorf("Core() = %+v, want none left until %v", rate, reset)
+	}
+}
+
+// installation authenticates like an App installation transport.
+type installation struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (i installation) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+i.token)
+	return i.base.RoundTrip(req)
+}
+
+func TestTransportPoolRotates(t *testing.T) {
+	srv := &tokenServer{remaining: map[string]int{"token a": 1, "token b": 5}, reset: time.Now().Add(time.Hour)}
+	pool := NewTransportPool([]http.RoundTripper{installation{"a", srv}, installation{"b", srv}})
+	client := &http.Client{Transport: pool}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get("https://api.github.com/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	want := []string{"token a", "token b", "token b"}
+	for i := range want {
+		if srv.used[i] != want[i] {
+			t.Fatalf("authenticated with %v, want %v", srv.used, want)
+		}
+	}
+}
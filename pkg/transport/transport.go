@@ -0,0 +1,75 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+)
+
+// Options configures the HTTP transport used for GitHub API calls.
+type Options struct {
+	// CacheDir enables conditional requests for protection and ruleset
+	// reads, with the ETags and bodies stored below this directory.
+	CacheDir string
+	// RequestsPerSecond throttles the requests sent to the API, regardless of
+	// the concurrency. Zero doesn't throttle.
+	RequestsPerSecond float64
+	// Audit records every write to the API when set. It wraps the
+	// authenticated transport, as it reads the resources it writes.
+	Audit *AuditLog
+	// DebugHTTP logs every request and response in full, with the
+	// credentials redacted.
+	DebugHTTP bool
+	// Record saves every request and response to this directory when set.
+	Record string
+	// Replay answers the requests with recorded responses instead of
+	// sending them when set.
+	Replay *Replay
+	// UserAgent identifies the tool in every request when set, followed by
+	// the ID of the run.
+	UserAgent string
+}
+
+// New returns the round tripper configured by opts on top of base, or of
+// http.DefaultTransport when base is nil.
+func New(opts Options, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if opts.Replay != nil {
+		base = opts.Replay
+	}
+	rt := base
+	// Innermost, so the requests are recorded and logged as sent
+	if opts.Record != "" {
+		rt = &Recorder{Dir: opts.Record, Base: rt}
+	}
+	if opts.DebugHTTP {
+		rt = &DebugLog{Base: rt}
+	}
+	// Requests answered from the cache are still revalidated, so they are
+	// throttled too
+	if opts.RequestsPerSecond > 0 {
+		rt = NewThrottle(opts.RequestsPerSecond, rt)
+	}
+	if opts.CacheDir != "" {
+		rt = &ETagCache{Dir: opts.CacheDir, Base: rt}
+	}
+	if opts.UserAgent != "" {
+		rt = &UserAgent{Agent: opts.UserAgent, Base: rt}
+	}
+	return rt
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+)
+
+// UserAgent is a round tripper setting the User-Agent of every request to
+// Agent, followed by the ID of the run the request belongs to, so GHES
+// admins and GitHub support can attribute the traffic.
+type UserAgent struct {
+	Agent string
+	Base  http.RoundTripper
+}
+
+func (u *UserAgent) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", u.value(req))
+	return u.Base.RoundTrip(req)
+}
+
+// value returns the User-Agent of req.
+func (u *UserAgent) value(req *http.Request) string {
+	if id := runid.From(req.Context()); id != "" {
+		return u.Agent + " (run " + id + ")"
+	}
+	return u.Agent
+}
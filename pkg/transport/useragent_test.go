@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/runid"
+)
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("User-Agent")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	rt := &UserAgent{Agent: "repo-protection-sync/v1.2.3", Base: base}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	req.Header.Set("User-Agent", "go-github/v59.0.0")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got != "repo-protection-sync/v1.2.3" {
+		t.Errorf("User-Agent is %q", got)
+	}
+	if req.Header.Get("User-Agent") != "go-github/v59.0.0" {
+		t.Error("the request was modified")
+	}
+
+	req = req.WithContext(runid.With(req.Context(), "20261017T120000Z-abcdef"))
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if want := "repo-protection-sync/v1.2.3 (run 20261017T120000Z-abcdef)"; got != want {
+		t.Errorf("User-Agent is %q, want %q", got, want)
+	}
+}
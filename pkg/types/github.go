@@ -0,0 +1,142 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package types
+
+import "github.com/google/go-github/v59/github"
+
+// NewBranchProtection converts branch protection as the GitHub API returns
+// it. It returns nil for nil.
+func NewBranchProtection(p *github.Protection) *BranchProtection {
+	if p == nil {
+		return nil
+	}
+	bp := &BranchProtection{
+		Restrictions:           newActors(p.GetRestrictions()),
+		EnforceAdmins:          p.EnforceAdmins != nil && p.EnforceAdmins.Enabled,
+		LinearHistory:          p.RequireLinearHistory != nil && p.RequireLinearHistory.Enabled,
+		AllowForcePushes:       p.AllowForcePushes != nil && p.AllowForcePushes.Enabled,
+		AllowDeletions:         p.AllowDeletions != nil && p.AllowDeletions.Enabled,
+		ConversationResolution: p.RequiredConversationResolution != nil && p.RequiredConversationResolution.Enabled,
+		BlockCreations:         p.GetBlockCreations().GetEnabled(),
+		LockBranch:             p.GetLockBranch().GetEnabled(),
+		AllowForkSyncing:       p.GetAllowForkSyncing().GetEnabled(),
+		SignedCommits:          p.GetRequiredSignatures().GetEnabled(),
+	}
+	if rsc := p.RequiredStatusChecks; rsc != nil {
+		bp.StatusChecks = &StatusChecks{Strict: rsc.Strict, Contexts: rsc.Contexts}
+		for _, check := range rsc.Checks {
+			bp.StatusChecks.Checks = append(bp.StatusChecks.Checks, StatusCheck{Context: check.Context, AppID: check.AppID})
+		}
+	}
+	if prr := p.RequiredPullRequestReviews; prr != nil {
+		bp.Reviews = &Reviews{
+			RequiredApprovals: prr.RequiredApprovingReviewCount,
+			DismissStale:      prr.DismissStaleReviews,
+			CodeOwners:        prr.RequireCodeOwnerReviews,
+			LastPushApproval:  prr.RequireLastPushApproval,
+		}
+		if dr := prr.DismissalRestrictions; dr != nil {
+			bp.Reviews.DismissalRestrictions = newActors(&github.BranchRestrictions{Users: dr.Users, Teams: dr.Teams, Apps: dr.Apps})
+		}
+	}
+	return bp
+}
+
+func newActors(r *github.BranchRestrictions) *Actors {
+	if r == nil {
+		return nil
+	}
+	a := &Actors{Users: []string{}, Teams: []string{}, Apps: []string{}}
+	for _, user := range r.Users {
+		a.Users = append(a.Users, user.GetLogin())
+	}
+	for _, team := range r.Teams {
+		a.Teams = append(a.Teams, team.GetSlug())
+	}
+	for _, app := range r.Apps {
+		a.Apps = append(a.Apps, app.GetSlug())
+		if app.NodeID != nil {
+			a.AppNodeIDs = append(a.AppNodeIDs, app.GetNodeID())
+		}
+	}
+	return a
+}
+
+// GitHub converts the protection to the shape the GitHub API returns branch
+// protection in. It returns nil for nil.
+func (bp *BranchProtection) GitHub() *github.Protection {
+	if bp == nil {
+		return nil
+	}
+	p := &github.Protection{
+		EnforceAdmins:                  &github.AdminEnforcement{Enabled: bp.EnforceAdmins},
+		RequireLinearHistory:           &github.RequireLinearHistory{Enabled: bp.LinearHistory},
+		AllowForcePushes:               &github.AllowForcePushes{Enabled: bp.AllowForcePushes},
+		AllowDeletions:                 &github.AllowDeletions{Enabled: bp.AllowDeletions},
+		RequiredConversationResolution: &github.RequiredConversationResolution{Enabled: bp.ConversationResolution},
+	}
+	if bp.BlockCreations {
+		p.BlockCreations = &github.BlockCreations{Enabled: github.Bool(true)}
+	}
+	if bp.LockBranch {
+		p.LockBranch = &github.LockBranch{Enabled: github.Bool(true)}
+	}
+	if bp.AllowForkSyncing {
+		p.AllowForkSyncing = &github.AllowForkSyncing{Enabled: github.Bool(true)}
+	}
+	if bp.SignedCommits {
+		p.RequiredSignatures = &github.SignaturesProtectedBranch{Enabled: github.Bool(true)}
+	}
+	if sc := bp.StatusChecks; sc != nil {
+		p.RequiredStatusChecks = &github.RequiredStatusChecks{Strict: sc.Strict, Contexts: sc.Contexts}
+		for _, check := range sc.Checks {
+			p.RequiredStatusChecks.Checks = append(p.RequiredStatusChecks.Checks, &github.RequiredStatusCheck{Context: check.Context, AppID: check.AppID})
+		}
+	}
+	if r := bp.Reviews; r != nil {
+		p.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcement{
+			RequiredApprovingReviewCount: r.RequiredApprovals,
+			DismissStaleReviews:          r.DismissStale,
+			RequireCodeOwnerReviews:      r.CodeOwners,
+			RequireLastPushApproval:      r.LastPushApproval,
+		}
+		if dr := r.DismissalRestrictions; dr != nil {
+			users, teams, apps := dr.github()
+			p.RequiredPullRequestReviews.DismissalRestrictions = &github.DismissalRestrictions{Users: users, Teams: teams, Apps: apps}
+		}
+	}
+	if r := bp.Restrictions; r != nil {
+		users, teams, apps := r.github()
+		p.Restrictions = &github.BranchRestrictions{Users: users, Teams: teams, Apps: apps}
+	}
+	return p
+}
+
+func (a *Actors) github() ([]*github.User, []*github.Team, []*github.App) {
+	users := make([]*github.User, 0, len(a.Users))
+	for _, login := range a.Users {
+		users = append(users, &github.User{Login: github.String(login)})
+	}
+	teams := make([]*github.Team, 0, len(a.Teams))
+	for _, slug := range a.Teams {
+		teams = append(teams, &github.Team{Slug: github.String(slug)})
+	}
+	apps := make([]*github.App, 0, len(a.Apps))
+	for _, slug := range a.Apps {
+		apps = append(apps, &github.App{Slug: github.String(slug)})
+	}
+	return users, teams, apps
+}
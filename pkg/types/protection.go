@@ -0,0 +1,90 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package types
+
+// BranchProtection is the protection of a branch, independent of the forge
+// it is read from or applied to. Actors are named by their login or slug.
+type BranchProtection struct {
+	StatusChecks *StatusChecks `json:"status_checks,omitempty"`
+	Reviews      *Reviews      `json:"reviews,omitempty"`
+	// Restrictions limits pushes to the actors; nil lets everyone with
+	// write access push.
+	Restrictions           *Actors `json:"restrictions,omitempty"`
+	EnforceAdmins          bool    `json:"enforce_admins"`
+	LinearHistory          bool    `json:"linear_history"`
+	AllowForcePushes       bool    `json:"allow_force_pushes"`
+	AllowDeletions         bool    `json:"allow_deletions"`
+	ConversationResolution bool    `json:"conversation_resolution"`
+	BlockCreations         bool    `json:"block_creations"`
+	LockBranch             bool    `json:"lock_branch"`
+	AllowForkSyncing       bool    `json:"allow_fork_syncing"`
+	SignedCommits          bool    `json:"signed_commits"`
+}
+
+// StatusChecks are the checks that must pass before merging.
+type StatusChecks struct {
+	// Strict requires branches to be up to date before merging.
+	Strict   bool          `json:"strict"`
+	Contexts []string      `json:"contexts"`
+	Checks   []StatusCheck `json:"checks,omitempty"`
+}
+
+// StatusCheck is a required check, optionally bound to the app reporting it.
+type StatusCheck struct {
+	Context string `json:"context"`
+	AppID   *int64 `json:"app_id,omitempty"`
+}
+
+// Reviews are the pull request reviews required before merging.
+type Reviews struct {
+	RequiredApprovals int  `json:"required_approvals"`
+	DismissStale      bool `json:"dismiss_stale"`
+	CodeOwners        bool `json:"code_owners"`
+	LastPushApproval  bool `json:"last_push_approval"`
+	// DismissalRestrictions limits who can dismiss reviews; nil lets
+	// everyone with write access dismiss them.
+	DismissalRestrictions *Actors `json:"dismissal_restrictions,omitempty"`
+}
+
+// Actors are users, teams and apps.
+type Actors struct {
+	Users []string `json:"users"`
+	Teams []string `json:"teams"`
+	Apps  []string `json:"apps"`
+	// AppNodeIDs are the GitHub node IDs of the apps, when read from GitHub,
+	// which the Terraform provider references apps by.
+	AppNodeIDs []string `json:"-"`
+}
+
+// Names returns the names of the required checks, from both Contexts and
+// Checks, without duplicates.
+func (s *StatusChecks) Names() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range s.Contexts {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, check := range s.Checks {
+		if !seen[check.Context] {
+			seen[check.Context] = true
+			names = append(names, check.Context)
+		}
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func TestBranchProtectionGitHub(t *testing.T) {
+	p := &github.Protection{
+		RequiredStatusChecks: &github.RequiredStatusChecks{
+			Strict:   true,
+			Contexts: []string{"ci"},
+			Checks:   []*github.RequiredStatusCheck{{Context: "ci", AppID: github.Int64(15368)}},
+		},
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{
+			RequiredApprovingReviewCount: 2,
+			RequireCodeOwnerReviews:      true,
+			DismissalRestrictions: &github.DismissalRestrictions{
+				Users: []*github.User{{Login: github.String("alice")}},
+				Teams: []*github.Team{},
+				Apps:  []*github.App{},
+			},
+		},
+		Restrictions: &github.BranchRestrictions{
+			Users: []*github.User{},
+			Teams: []*github.Team{{Slug: github.String("core")}},
+			Apps:  []*github.App{{Slug: github.String("bot")}},
+		},
+		EnforceAdmins:                  &github.AdminEnforcement{Enabled: true},
+		RequireLinearHistory:           &github.RequireLinearHistory{},
+		AllowForcePushes:               &github.AllowForcePushes{},
+		AllowDeletions:                 &github.AllowDeletions{},
+		RequiredConversationResolution: &github.RequiredConversationResolution{Enabled: true},
+		RequiredSignatures:             &github.SignaturesProtectedBranch{Enabled: github.Bool(true)},
+	}
+
+	bp := NewBranchProtection(p)
+	if bp.Reviews.RequiredApprovals != 2 || bp.Restrictions.Teams[0] != "core" || !bp.SignedCommits {
+		t.Errorf("NewBranchProtection() = %+v", bp)
+	}
+	if got := bp.GitHub(); !reflect.DeepEqual(got, p) {
+		t.Errorf("GitHub() = %+v, want %+v", got, p)
+	}
+
+	if NewBranchProtection(nil) != nil || (*BranchProtection)(nil).GitHub() != nil {
+		t.Error("nil protection should convert to nil")
+	}
+}
+
+func TestStatusChecksNames(t *testing.T) {
+	s := &StatusChecks{Contexts: []string{"ci", "lint"}, Checks: []StatusCheck{{Context: "lint"}, {Context: "e2e"}}}
+	if got, want := s.Names(), []string{"ci", "lint", "e2e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
@@ -18,6 +18,23 @@ package types
 import "github.com/google/go-github/v59/github"
 
 type RepoProtection struct {
-	BranchProtection *github.Protection
+	// Branch is the branch BranchProtection was read from.
+	Branch string
+	// OwnerType is the type of the repository owner, "User" or "Organization".
+	OwnerType        string
+	BranchProtection *BranchProtection
 	Rulesets         []*github.Ruleset
+	// InheritedRulesets apply to the repository from its organization; their
+	// SourceType and Source tell where they are defined.
+	InheritedRulesets []*github.Ruleset
+}
+
+// Environment is a deployment environment along with its protection rules.
+type Environment struct {
+	Name string
+	// Protection holds the reviewers, wait timer and branch policy.
+	Protection *github.CreateUpdateEnvironment
+	// BranchPolicies are the branch and tag patterns allowed to deploy when
+	// the environment uses custom branch policies.
+	BranchPolicies []*github.DeploymentBranchPolicyRequest
 }
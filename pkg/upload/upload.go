@@ -0,0 +1,104 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// command runs the command line tools of the cloud providers. Tests replace
+// it.
+var command = exec.CommandContext
+
+// Data uploads data as the object name below the prefix dest.
+func Data(ctx context.Context, dest, name string, data []byte) error {
+	dir, err := os.MkdirTemp("", "repo-protection-sync-upload-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return err
+	}
+	return File(ctx, file, strings.TrimSuffix(dest, "/")+"/")
+}
+
+// unsafeName matches the characters kept out of object names.
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Name returns the object name of a report made at t, prefixed with the
+// time so the reports of successive runs sort in order.
+func Name(t time.Time, report string) string {
+	return t.UTC().Format("20060102T150405Z") + "-" + unsafeName.ReplaceAllString(report, "-")
+}
+
+// Object returns the URL of the object a file is uploaded to: dest itself,
+// or the base name of file below dest when dest ends with a slash.
+func Object(dest, file string) string {
+	if strings.HasSuffix(dest, "/") {
+		return dest + path.Base(file)
+	}
+	return dest
+}
+
+// File uploads the local file to dest, an s3://bucket/key, gs://bucket/key
+// or az://account/container/blob URL, with the command line tool of the
+// provider. The tools authenticate with the standard credential chain of
+// their SDK: environment variables, shared configuration files and
+// profiles, or the identity of the instance.
+func File(ctx context.Context, file, dest string) error {
+	scheme, location, ok := strings.Cut(dest, "://")
+	if !ok {
+		return fmt.Errorf("%s isn't an s3://, gs:// or az:// URL", dest)
+	}
+	object := Object(dest, file)
+
+	var name string
+	var args []string
+	switch scheme {
+	case "s3":
+		name, args = "aws", []string{"s3", "cp", "--only-show-errors", file, object}
+	case "gs":
+		name, args = "gcloud", []string{"storage", "cp", "--quiet", file, object}
+	case "az":
+		parts := strings.SplitN(strings.TrimPrefix(object, "az://"), "/", 3)
+		if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return fmt.Errorf("%s must name the storage account, the container and the blob: az://account/container/blob", location)
+		}
+		name, args = "az", []string{"storage", "blob", "upload", "--only-show-errors", "--auth-mode", "login", "--overwrite",
+			"--account-name", parts[0], "--container-name", parts[1], "--name", parts[2], "--file", file}
+	default:
+		return fmt.Errorf("unsupported storage %q in %s: use s3://, gs:// or az://", scheme, dest)
+	}
+
+	var stderr bytes.Buffer
+	cmd := command(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("uploading %s to %s: %w: %s", file, object, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
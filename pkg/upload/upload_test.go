@@ -0,0 +1,113 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package upload
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordCommands replaces the cloud tools with echo, recording the command
+// lines.
+func recordCommands(t *testing.T) *[]string {
+	t.Helper()
+	var calls []string
+	command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return exec.CommandContext(ctx, "true")
+	}
+	t.Cleanup(func() { command = exec.CommandContext })
+	return &calls
+}
+
+func TestFile(t *testing.T) {
+	calls := recordCommands(t)
+	ctx := context.Background()
+	if err := File(ctx, "/var/log/audit.jsonl", "s3://bucket/sync/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := File(ctx, "/tmp/report.json", "gs://bucket/reports/latest.json"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"aws s3 cp --only-show-errors /var/log/audit.jsonl s3://bucket/sync/audit.jsonl",
+		"gcloud storage cp --quiet /tmp/report.json gs://bucket/reports/latest.json",
+	}
+	if strings.Join(*calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran %q, want %q", *calls, want)
+	}
+
+	for _, dest := range []string{"bucket/prefix", "ftp://host/prefix"} {
+		if err := File(ctx, "/tmp/report.json", dest); err == nil {
+			t.Errorf("File() accepted %s", dest)
+		}
+	}
+	if len(*calls) != 2 {
+		t.Errorf("ran %q for invalid destinations", (*calls)[2:])
+	}
+}
+
+func TestFileAzure(t *testing.T) {
+	calls := recordCommands(t)
+	if err := File(context.Background(), "/tmp/report.json", "az://account/reports/sync/"); err != nil {
+		t.Fatal(err)
+	}
+	want := "az storage blob upload --only-show-errors --auth-mode login --overwrite --account-name account --container-name reports --name sync/report.json --file /tmp/report.json"
+	if len(*calls) != 1 || (*calls)[0] != want {
+		t.Errorf("ran %q, want %q", *calls, want)
+	}
+	if err := File(context.Background(), "/tmp/report.json", "az://account/"); err == nil {
+		t.Error("File() accepted a URL without a container")
+	}
+}
+
+func TestData(t *testing.T) {
+	var uploaded []string
+	command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		file := args[len(args)-2]
+		data, _ := os.ReadFile(file)
+		uploaded = append(uploaded, args[len(args)-1]+"="+string(data))
+		return exec.CommandContext(ctx, "true")
+	}
+	t.Cleanup(func() { command = exec.CommandContext })
+
+	name := Name(time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), "sync-docs / relaxed.json")
+	if name != "20240501T123000Z-sync-docs-relaxed.json" {
+		t.Errorf("Name() = %q", name)
+	}
+	if err := Data(context.Background(), "s3://bucket/reports", name, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if want := "s3://bucket/reports/" + name + "={}"; len(uploaded) != 1 || uploaded[0] != want {
+		t.Errorf("uploaded %q, want %q", uploaded, want)
+	}
+}
+
+func TestFileFailure(t *testing.T) {
+	command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sh", "-c", "echo access denied >&2; exit 1")
+	}
+	t.Cleanup(func() { command = exec.CommandContext })
+
+	err := File(context.Background(), "/tmp/report.json", "s3://bucket/")
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("File() = %v, want the error of the tool", err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Config checks a loaded configuration for settings that parse but can't
+// work as intended, such as an inline protection with misspelled fields.
+// Every problem found is returned, joined into a single error.
+func Config(cfg *config.Config) error {
+	var problems []error
+
+	c := cfg.Concurrency
+	if c.MaxWorkers < 0 || c.MinWorkers < 0 || c.ScaleDownBelow < 0 {
+		problems = append(problems, errors.New("concurrency: worker counts and scale_down_below can't be negative"))
+	}
+	if c.MaxWorkers > 0 && c.MinWorkers > c.MaxWorkers {
+		problems = append(problems, fmt.Errorf("concurrency: min_workers %d exceeds max_workers %d", c.MinWorkers, c.MaxWorkers))
+	}
+	if c.RepoTimeout < 0 {
+		problems = append(problems, errors.New("concurrency: repo_timeout can't be negative"))
+	}
+
+	if s := cfg.Notifications.Slack; s != nil {
+		if err := webhookURL(s.WebhookURL); err != nil {
+			problems = append(problems, fmt.Errorf("notifications.slack.webhook_url: %w", err))
+		}
+	}
+	for i, w := range cfg.Notifications.Webhooks {
+		if err := webhookURL(w.URL); err != nil {
+			problems = append(problems, fmt.Errorf("notifications.webhooks[%d].url: %w", i, err))
+		}
+	}
+
+	for _, p := range cfg.Policies {
+		if strings.Count(p.Source, "/") > 1 || strings.HasPrefix(p.Source, "/") || strings.HasSuffix(p.Source, "/") {
+			problems = append(problems, fmt.Errorf("policy %q: source %q must be a repository name or owner/repo", p.Name, p.Source))
+		}
+		if p.Protection == nil {
+			continue
+		}
+		protection, err := inline(p.Protection)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("policy %q: %w", p.Name, err))
+			continue
+		}
+		if err := Source(&types.RepoProtection{BranchProtection: types.NewBranchProtection(protection)}); err != nil {
+			problems = append(problems, fmt.Errorf("policy %q: %w", p.Name, err))
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// inline decodes an inline protection, rejecting the fields the API doesn't
+// have, which the sync would ignore.
+func inline(definition map[string]interface{}) (*github.Protection, error) {
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	protection := new(github.Protection)
+	if err := dec.Decode(protection); err != nil {
+		return nil, fmt.Errorf("invalid inline protection: %w", err)
+	}
+	return protection, nil
+}
+
+// webhookURL checks that a notification endpoint is an absolute HTTP URL.
+// Unset environment variables leave it empty.
+func webhookURL(raw string) error {
+	if raw == "" {
+		return errors.New("empty URL")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q isn't an http or https URL", raw)
+	}
+	return nil
+}
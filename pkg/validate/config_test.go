@@ -0,0 +1,71 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/config"
+)
+
+func TestConfig(t *testing.T) {
+	valid := &config.Config{
+		Concurrency:   config.Concurrency{MaxWorkers: 8, MinWorkers: 2},
+		Notifications: config.Notifications{Webhooks: []config.Webhook{{URL: "https://platform.example.com/hooks"}}},
+		Policies: []config.Policy{
+			{Name: "default", Source: "octo/baseline"},
+			{Name: "inline", Protection: map[string]interface{}{
+				"required_pull_request_reviews": map[string]interface{}{"required_approving_review_count": 2},
+				"enforce_admins":                map[string]interface{}{"enabled": true},
+			}},
+		},
+	}
+	if err := Config(valid); err != nil {
+		t.Errorf("Config() = %v, want no problems", err)
+	}
+
+	invalid := &config.Config{
+		Concurrency: config.Concurrency{MaxWorkers: 2, MinWorkers: 4},
+		Notifications: config.Notifications{
+			Slack:    &config.Slack{},
+			Webhooks: []config.Webhook{{URL: "platform.example.com/hooks"}},
+		},
+		Policies: []config.Policy{
+			{Name: "nested", Source: "octo/team/baseline"},
+			{Name: "typo", Protection: map[string]interface{}{"enforce_admin": map[string]interface{}{"enabled": true}}},
+			{Name: "incoherent", Protection: map[string]interface{}{
+				"required_pull_request_reviews": map[string]interface{}{"require_code_owner_reviews": true},
+			}},
+		},
+	}
+	err := Config(invalid)
+	if err == nil {
+		t.Fatal("Config() found no problems")
+	}
+	for _, want := range []string{
+		"min_workers 4 exceeds max_workers 2",
+		"notifications.slack.webhook_url: empty URL",
+		`notifications.webhooks[0].url: "platform.example.com/hooks" isn't an http or https URL`,
+		`policy "nested": source "octo/team/baseline"`,
+		`policy "typo": invalid inline protection: json: unknown field "enforce_admin"`,
+		`policy "incoherent": code owner reviews are required`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Config() = %q, want it to contain %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+// Source checks the protection fetched from the source repository for
+// combinations that can't be applied, or can't be applied as intended, to
+// the targets. Every problem found is returned, joined into a single error.
+func Source(rp *types.RepoProtection) error {
+	if rp == nil || rp.BranchProtection == nil {
+		return errors.New("the source has no branch protection on its default branch; protect it before syncing")
+	}
+
+	var problems []error
+	p := rp.BranchProtection
+
+	if r := p.Restrictions; r != nil && rp.OwnerType == "User" && (len(r.Users) > 0 || len(r.Teams) > 0 || len(r.Apps) > 0) {
+		problems = append(problems, errors.New("push restrictions are only supported on organization repositories; remove them from the source or move it to an organization"))
+	}
+
+	if reviews := p.Reviews; reviews != nil {
+		if reviews.CodeOwners && reviews.RequiredApprovals == 0 {
+			problems = append(problems, errors.New("code owner reviews are required but the required approving review count is 0; set it to at least 1"))
+		}
+		if dr := reviews.DismissalRestrictions; dr != nil && rp.OwnerType == "User" && (len(dr.Users) > 0 || len(dr.Teams) > 0) {
+			problems = append(problems, errors.New("review dismissal restrictions are only supported on organization repositories; remove them from the source"))
+		}
+	}
+
+	if checks := p.StatusChecks; checks != nil {
+		for _, context := range duplicates(checks.Contexts) {
+			problems = append(problems, fmt.Errorf("required status check %q is listed more than once; remove the duplicate from the source", context))
+		}
+		names := make([]string, 0, len(checks.Checks))
+		for _, check := range checks.Checks {
+			names = append(names, check.Context)
+		}
+		for _, context := range duplicates(names) {
+			problems = append(problems, fmt.Errorf("required status check %q is listed more than once; remove the duplicate from the source", context))
+		}
+	}
+
+	problems = append(problems, rulesets(rp.Rulesets)...)
+
+	return errors.Join(problems...)
+}
+
+// rulesets checks that every ruleset can be told apart by name, which is how
+// rulesets are matched on the targets.
+func rulesets(rulesets []*github.Ruleset) []error {
+	var problems []error
+	names := make([]string, 0, len(rulesets))
+	for _, ruleset := range rulesets {
+		if ruleset.Name == "" {
+			problems = append(problems, fmt.Errorf("ruleset %d has no name; give it a name so it can be matched on the targets", ruleset.GetID()))
+			continue
+		}
+		names = append(names, ruleset.Name)
+	}
+	for _, name := range duplicates(names) {
+		problems = append(problems, fmt.Errorf("ruleset %q is defined more than once; rename one of them", name))
+	}
+	return problems
+}
+
+// duplicates returns the values occurring more than once, in order of their
+// second occurrence.
+func duplicates(values []string) []string {
+	seen := make(map[string]int, len(values))
+	var dups []string
+	for _, v := range values {
+		seen[v]++
+		if seen[v] == 2 {
+			dups = append(dups, v)
+		}
+	}
+	return dups
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2024 Arush Salil
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arush-sal/repo-protection-sync/pkg/types"
+	"github.com/google/go-github/v59/github"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name string
+		rp   *types.RepoProtection
+		want []string
+	}{
+		{
+			name: "unprotected",
+			rp:   &types.RepoProtection{},
+			want: []string{"no branch protection"},
+		},
+		{
+			name: "coherent",
+			rp: &types.RepoProtection{
+				OwnerType: "Organization",
+				BranchProtection: &types.BranchProtection{
+					Restrictions: &types.Actors{Teams: []string{"core"}},
+					Reviews:      &types.Reviews{CodeOwners: true, RequiredApprovals: 1},
+					StatusChecks: &types.StatusChecks{Contexts: []string{"ci", "lint"}},
+				},
+				Rulesets: []*github.Ruleset{{Name: "main"}, {Name: "tags"}},
+			},
+		},
+		{
+			name: "incoherent",
+			rp: &types.RepoProtection{
+				OwnerType: "User",
+				BranchProtection: &types.BranchProtection{
+					Restrictions: &types.Actors{Users: []string{"alice"}},
+					Reviews:      &types.Reviews{CodeOwners: true},
+					StatusChecks: &types.StatusChecks{Contexts: []string{"ci", "lint", "ci"}},
+				},
+				Rulesets: []*github.Ruleset{{Name: "main"}, {Name: "main"}, {ID: github.Int64(7)}},
+			},
+			want: []string{
+				"push restrictions",
+				"approving review count is 0",
+				`status check "ci"`,
+				`ruleset "main"`,
+				"ruleset 7 has no name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Source(tt.rp)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't mention %q", err, want)
+				}
+			}
+		})
+	}
+}